@@ -337,6 +337,8 @@ func (a *Aggregator) settleDirect(
 		return false
 	}
 
+	a.storeFinalProof(ctx, proof, inputs.FinalProof)
+
 	// process monitored batch verifications before starting a next cycle
 	a.EthTxManager.ProcessPendingMonitoredTxs(
 		ctx,
@@ -385,6 +387,8 @@ func (a *Aggregator) settleWithAggLayer(
 		return false
 	}
 
+	a.storeFinalProof(ctx, proof, inputs.FinalProof)
+
 	log.Infof("tx %s sent to agglayer, waiting to be mined", txHash.Hex())
 	log.Debugf("Timeout set to %f seconds", a.cfg.AggLayerTxTimeout.Duration.Seconds())
 	waitCtx, cancelFunc := context.WithDeadline(ctx, time.Now().Add(a.cfg.AggLayerTxTimeout.Duration))
@@ -396,10 +400,36 @@ func (a *Aggregator) settleWithAggLayer(
 		return false
 	}
 
+	a.markFinalProofVerified(ctx, proof.BatchNumber, proof.BatchNumberFinal)
+
 	// TODO: wait for synchronizer to catch up
 	return true
 }
 
+// storeFinalProof keeps the final proof sent to L1 so it can be served once
+// the recursive proofs of its batches are cleaned up. It's stored as sent and
+// marked as verified once its verification is confirmed.
+func (a *Aggregator) storeFinalProof(ctx context.Context, proof *state.Proof, finalProof *prover.FinalProof) {
+	finalProofToStore := &state.FinalProof{
+		BatchNumber:      proof.BatchNumber,
+		BatchNumberFinal: proof.BatchNumberFinal,
+		Proof:            finalProof.Proof,
+		ProofID:          proof.ProofID,
+		Status:           state.FinalProofStatusSent,
+	}
+	if err := a.State.AddFinalProof(ctx, finalProofToStore, nil); err != nil {
+		log.Errorf("Failed to store the final proof of batches %d-%d: %v", proof.BatchNumber, proof.BatchNumberFinal, err)
+	}
+}
+
+// markFinalProofVerified marks the stored final proof of the batches as verified
+// once its verification is confirmed.
+func (a *Aggregator) markFinalProofVerified(ctx context.Context, batchNumber, batchNumberFinal uint64) {
+	if err := a.State.UpdateFinalProofStatus(ctx, batchNumber, batchNumberFinal, state.FinalProofStatusVerified, nil); err != nil {
+		log.Errorf("Failed to mark the final proof of batches %d-%d as verified: %v", batchNumber, batchNumberFinal, err)
+	}
+}
+
 func (a *Aggregator) handleFailureToSendToAggLayer(ctx context.Context, proof *state.Proof) {
 	log := log.WithFields("proofId", proof.ProofID, "batches", fmt.Sprintf("%d-%d", proof.BatchNumber, proof.BatchNumberFinal))
 	proof.GeneratingSince = nil
@@ -1288,6 +1318,7 @@ func (a *Aggregator) handleMonitoredTxResult(result ethtxmanager.MonitoredTxResu
 
 	log := log.WithFields("txId", result.ID, "batches", fmt.Sprintf("%d-%d", proofBatchNumber, proofBatchNumberFinal))
 	log.Info("Final proof verified")
+	a.markFinalProofVerified(a.ctx, proofBatchNumber, proofBatchNumberFinal)
 
 	// wait for the synchronizer to catch up the verified batches
 	log.Debug("A final proof has been sent, waiting for the network to be synced")
@@ -162,6 +162,14 @@ func TestSendFinalProof(t *testing.T) {
 				}).Return(&to, data, nil).Once()
 				monitoredTxID := buildMonitoredTxID(batchNum, batchNumFinal)
 				m.ethTxManager.On("Add", mock.Anything, ethTxManagerOwner, monitoredTxID, from, &to, value, data, cfg.GasOffset, nil).Return(nil).Once()
+				expectedFinalProof := &state.FinalProof{
+					BatchNumber:      batchNum,
+					BatchNumberFinal: batchNumFinal,
+					Proof:            finalProof.Proof,
+					ProofID:          &proofID,
+					Status:           state.FinalProofStatusSent,
+				}
+				m.stateMock.On("AddFinalProof", mock.Anything, expectedFinalProof, nil).Return(nil).Once()
 				ethTxManResult := ethtxmanager.MonitoredTxResult{
 					ID:     monitoredTxID,
 					Status: ethtxmanager.MonitoredTxStatusConfirmed,
@@ -170,6 +178,7 @@ func TestSendFinalProof(t *testing.T) {
 				m.ethTxManager.On("ProcessPendingMonitoredTxs", mock.Anything, ethTxManagerOwner, mock.Anything, nil).Run(func(args mock.Arguments) {
 					args[2].(ethtxmanager.ResultHandler)(ethTxManResult, nil) // this calls a.handleMonitoredTxResult
 				}).Once()
+				m.stateMock.On("UpdateFinalProofStatus", mock.Anything, batchNum, batchNumFinal, state.FinalProofStatusVerified, nil).Return(nil).Once()
 				verifiedBatch := state.VerifiedBatch{
 					BatchNumber: batchNumFinal,
 				}
@@ -19,6 +19,24 @@ type StateMock struct {
 	mock.Mock
 }
 
+// AddFinalProof provides a mock function with given fields: ctx, proof, dbTx
+func (_m *StateMock) AddFinalProof(ctx context.Context, proof *state.FinalProof, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, proof, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFinalProof")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *state.FinalProof, pgx.Tx) error); ok {
+		r0 = rf(ctx, proof, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddGeneratedProof provides a mock function with given fields: ctx, proof, dbTx
 func (_m *StateMock) AddGeneratedProof(ctx context.Context, proof *state.Proof, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, proof, dbTx)
@@ -484,6 +502,24 @@ func (_m *StateMock) GetVirtualBatchToProve(ctx context.Context, lastVerfiedBatc
 	return r0, r1
 }
 
+// UpdateFinalProofStatus provides a mock function with given fields: ctx, batchNumber, batchNumberFinal, status, dbTx
+func (_m *StateMock) UpdateFinalProofStatus(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, status state.FinalProofStatus, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, batchNumberFinal, status, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFinalProofStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, state.FinalProofStatus, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, batchNumberFinal, status, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateGeneratedProof provides a mock function with given fields: ctx, proof, dbTx
 func (_m *StateMock) UpdateGeneratedProof(ctx context.Context, proof *state.Proof, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, proof, dbTx)
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	apiKeyNameFlag = cli.StringFlag{
+		Name:     "name",
+		Aliases:  []string{"n"},
+		Usage:    "Name identifying the owner of the API key",
+		Required: true,
+	}
+	apiKeyNamespacesFlag = cli.StringSliceFlag{
+		Name:     "namespaces",
+		Usage:    fmt.Sprintf("Namespaces the API key can access: --namespaces=%v,%v", jsonrpc.APIEth, jsonrpc.APIDebug),
+		Required: false,
+	}
+	apiKeyMethodsFlag = cli.StringSliceFlag{
+		Name:     "methods",
+		Usage:    "Methods the API key can access besides its namespaces: --methods=debug_traceTransaction",
+		Required: false,
+	}
+	apiKeyRequestsQuotaFlag = cli.Uint64Flag{
+		Name:     "requests-quota",
+		Usage:    "Max number of requests within the quota window, zero means no limit",
+		Required: false,
+	}
+	apiKeyComputeQuotaFlag = cli.DurationFlag{
+		Name:     "compute-quota",
+		Usage:    "Max time spent handling the requests within the quota window, zero means no limit",
+		Required: false,
+	}
+	apiKeyQuotaWindowFlag = cli.DurationFlag{
+		Name:     "quota-window",
+		Usage:    "Duration of the window used to account the quotas",
+		Value:    time.Minute,
+		Required: false,
+	}
+	apiKeyMaxBatchSizeFlag = cli.Uint64Flag{
+		Name:     "max-batch-size",
+		Usage:    "Max number of requests in a batch request, zero means the server limit",
+		Required: false,
+	}
+)
+
+var apiKeyCommands = cli.Command{
+	Name:  "apikey",
+	Usage: "Manage the API keys used to authenticate the JSON RPC requests",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "add",
+			Usage:  "Generate a new API key, the key is only printed once",
+			Action: addAPIKey,
+			Flags: []cli.Flag{&configFileFlag, &apiKeyNameFlag, &apiKeyNamespacesFlag, &apiKeyMethodsFlag,
+				&apiKeyRequestsQuotaFlag, &apiKeyComputeQuotaFlag, &apiKeyQuotaWindowFlag, &apiKeyMaxBatchSizeFlag},
+		}, {
+			Name:   "list",
+			Usage:  "List the API keys",
+			Action: listAPIKeys,
+			Flags:  []cli.Flag{&configFileFlag},
+		}, {
+			Name:   "remove",
+			Usage:  "Remove an API key, the running instances keep accepting it until it expires from their cache after RPC.APIKeys.CacheTTL",
+			Action: removeAPIKey,
+			Flags:  []cli.Flag{&configFileFlag, &apiKeyNameFlag},
+		},
+	},
+}
+
+func addAPIKey(cli *cli.Context) error {
+	storage, err := apiKeyStorage(cli)
+	if err != nil {
+		return err
+	}
+
+	key, err := jsonrpc.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+	apiKey := jsonrpc.APIKey{
+		Hash:          jsonrpc.HashAPIKey(key),
+		Name:          cli.String(apiKeyNameFlag.Name),
+		Namespaces:    cli.StringSlice(apiKeyNamespacesFlag.Name),
+		Methods:       cli.StringSlice(apiKeyMethodsFlag.Name),
+		RequestsQuota: cli.Uint64(apiKeyRequestsQuotaFlag.Name),
+		ComputeQuota:  cli.Duration(apiKeyComputeQuotaFlag.Name),
+		QuotaWindow:   cli.Duration(apiKeyQuotaWindowFlag.Name),
+		MaxBatchSize:  cli.Uint64(apiKeyMaxBatchSizeFlag.Name),
+	}
+	if err := storage.AddAPIKey(cli.Context, apiKey); err != nil {
+		return err
+	}
+
+	fmt.Println(key)
+	return nil
+}
+
+func listAPIKeys(cli *cli.Context) error {
+	storage, err := apiKeyStorage(cli)
+	if err != nil {
+		return err
+	}
+
+	apiKeys, err := storage.GetAPIKeys(cli.Context)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range apiKeys {
+		fmt.Printf("%s: namespaces=[%s] methods=[%s] requestsQuota=%d computeQuota=%s quotaWindow=%s maxBatchSize=%d createdAt=%s\n",
+			k.Name, strings.Join(k.Namespaces, ","), strings.Join(k.Methods, ","), k.RequestsQuota,
+			k.ComputeQuota, k.QuotaWindow, k.MaxBatchSize, k.CreatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func removeAPIKey(cli *cli.Context) error {
+	storage, err := apiKeyStorage(cli)
+	if err != nil {
+		return err
+	}
+
+	return storage.DeleteAPIKey(cli.Context, cli.String(apiKeyNameFlag.Name))
+}
+
+func apiKeyStorage(cli *cli.Context) (*jsonrpc.PostgresAPIKeyStorage, error) {
+	c, err := config.Load(cli, false)
+	if err != nil {
+		return nil, err
+	}
+	setupLog(c.Log)
+
+	poolDB, err := db.NewSQLDB(c.Pool.DB)
+	if err != nil {
+		return nil, err
+	}
+	return jsonrpc.NewPostgresAPIKeyStorage(poolDB), nil
+}
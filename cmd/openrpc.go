@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
+	"github.com/urfave/cli/v2"
+)
+
+func genOpenRPC(cli *cli.Context) error {
+	output := cli.String(config.FlagOutputFile)
+	data, err := jsonrpc.GenerateOpenRPCDocument()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, data, 0600) //nolint:gomnd
+}
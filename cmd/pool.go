@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	poolFileFlag = cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "File to export the txs to or import the txs from, stdout/stdin if not set",
+		Required: false,
+	}
+	poolFormatFlag = cli.StringFlag{
+		Name:     "format",
+		Usage:    fmt.Sprintf("Encoding of the exported txs: %v, %v", pool.ExportFormatJSON, pool.ExportFormatRLP),
+		Value:    string(pool.ExportFormatJSON),
+		Required: false,
+	}
+)
+
+var poolCommands = cli.Command{
+	Name:  "pool",
+	Usage: "Export and import the pending and queued txs of the pool",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "export",
+			Usage:  "Export the pending and queued txs of the pool with their metadata",
+			Action: exportPool,
+			Flags:  []cli.Flag{&configFileFlag, &poolFileFlag, &poolFormatFlag},
+		}, {
+			Name:   "import",
+			Usage:  "Validate exported txs against the current state and add the valid ones to the pool",
+			Action: importPool,
+			Flags:  []cli.Flag{&configFileFlag, &networkFlag, &customNetworkFlag, &poolFileFlag, &poolFormatFlag},
+		},
+	},
+}
+
+func exportPool(cli *cli.Context) error {
+	c, err := config.Load(cli, false)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	format, err := resolvePoolFormat(cli)
+	if err != nil {
+		return err
+	}
+
+	poolStorage, err := pgpoolstorage.NewPostgresPoolStorage(c.Pool.DB)
+	if err != nil {
+		return err
+	}
+	txs, err := poolStorage.GetTxsByStatus(cli.Context, pool.TxStatusPending, 0)
+	if err != nil {
+		return err
+	}
+	queuedTxs, err := poolStorage.GetTxsByStatus(cli.Context, pool.TxStatusQueued, 0)
+	if err != nil {
+		return err
+	}
+	txs = append(txs, queuedTxs...)
+
+	var w io.Writer = os.Stdout
+	if cli.IsSet(poolFileFlag.Name) {
+		fd, err := os.Create(cli.String(poolFileFlag.Name))
+		if err != nil {
+			return err
+		}
+		defer func(fd *os.File) {
+			_ = fd.Close()
+		}(fd)
+		w = fd
+	}
+
+	if err = pool.WriteExportedTxs(w, format, txs); err != nil {
+		return err
+	}
+	log.Infof("%d txs exported, %d pending and %d queued", len(txs), len(txs)-len(queuedTxs), len(queuedTxs))
+	return nil
+}
+
+func importPool(cli *cli.Context) error {
+	c, err := config.Load(cli, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	format, err := resolvePoolFormat(cli)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if cli.IsSet(poolFileFlag.Name) {
+		fd, err := os.Open(cli.String(poolFileFlag.Name))
+		if err != nil {
+			return err
+		}
+		defer func(fd *os.File) {
+			_ = fd.Close()
+		}(fd)
+		r = fd
+	}
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		return err
+	}
+	eventLog := event.NewEventLog(c.EventLog, eventStorage)
+
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	ethMan, err := etherman.NewClient(c.Etherman, c.NetworkConfig.L1Config, nil)
+	if err != nil {
+		return err
+	}
+	l2ChainID, err := ethMan.GetL2ChainID()
+	if err != nil {
+		return err
+	}
+	st, currentForkID := newState(cli.Context, c, ethMan, l2ChainID, stateSqlDB, eventLog, false, true, false)
+	c.Pool.ForkID = currentForkID
+
+	poolInstance := createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
+
+	var imported, rejected int
+	err = pool.ReadExportedTxs(r, format, func(exportedTx pool.ExportedTx) error {
+		if err := poolInstance.ImportTx(context.Background(), exportedTx); err != nil {
+			rejected++
+			log.Warnf("tx %v rejected: %v", exportedTx.Hash.String(), err)
+			return nil
+		}
+		imported++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("%d txs imported, %d txs rejected", imported, rejected)
+	return nil
+}
+
+func resolvePoolFormat(cli *cli.Context) (pool.ExportFormat, error) {
+	format := cli.String(poolFormatFlag.Name)
+	if !pool.IsExportFormat(format) {
+		return "", errors.New("invalid format: " + format)
+	}
+	return pool.ExportFormat(format), nil
+}
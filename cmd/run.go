@@ -213,6 +213,12 @@ func start(cliCtx *cli.Context) error {
 				poolInstance.StartPollingMinSuggestedGasPrice(cliCtx.Context)
 			}
 			poolInstance.StartRefreshingBlockedAddressesPeriodically()
+			if err := poolInstance.StartRefreshingPolicyFeedsPeriodically(cliCtx.Context); err != nil {
+				log.Fatal(err)
+			}
+			if err := poolInstance.StartPreExecutingTxs(cliCtx.Context); err != nil {
+				log.Fatal(err)
+			}
 			apis := map[string]bool{}
 			for _, a := range cliCtx.StringSlice(config.FlagHTTPAPI) {
 				apis[a] = true
@@ -407,7 +413,20 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 
 func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64, pool *pool.Pool, st *state.State, apis map[string]bool) {
 	var err error
-	storage := jsonrpc.NewStorage()
+	var storage jsonrpc.FilterStorage = jsonrpc.NewStorage()
+	if c.RPC.Filters.Storage == jsonrpc.FilterStoragePostgres {
+		filtersDB, err := db.NewSQLDB(c.Pool.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		stateDB, err := db.NewSQLDB(c.State.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pgStorage := jsonrpc.NewPostgresStorage(c.RPC.Filters, filtersDB, stateDB)
+		go pgStorage.StartToCleanupExpiredFilters(context.Background())
+		storage = pgStorage
+	}
 	c.RPC.MaxCumulativeGasUsed = c.State.Batch.Constraints.MaxCumulativeGasUsed
 	c.RPC.L2Coinbase = c.SequenceSender.L2Coinbase
 	c.RPC.ZKCountersLimits = jsonrpc.ZKCountersLimits{
@@ -431,50 +450,111 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 		log.Debug("SequencerNodeURI ", c.RPC.SequencerNodeURI)
 	}
 
+	// when the API keys are enabled all the apis are registered, the ones not
+	// exposed via --http.api are restricted to the API keys allowed to access them
+	var apiKeyStorage jsonrpc.APIKeyStorage
+	if c.RPC.APIKeys.Enabled {
+		apiKeysDB, err := db.NewSQLDB(c.Pool.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		apiKeyStorage = jsonrpc.NewPostgresAPIKeyStorage(apiKeysDB)
+	}
+	registerAll := c.RPC.APIKeys.Enabled
+
 	services := []jsonrpc.Service{}
-	if _, ok := apis[jsonrpc.APIEth]; ok {
+	if _, ok := apis[jsonrpc.APIEth]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APIEth,
-			Service: jsonrpc.NewEthEndpoints(c.RPC, chainID, pool, st, etherman, storage),
+			Name:       jsonrpc.APIEth,
+			Service:    jsonrpc.NewEthEndpoints(c.RPC, chainID, pool, st, etherman, storage),
+			Restricted: !ok,
 		})
 	}
 
-	if _, ok := apis[jsonrpc.APINet]; ok {
+	if _, ok := apis[jsonrpc.APINet]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APINet,
-			Service: jsonrpc.NewNetEndpoints(c.RPC, chainID),
+			Name:       jsonrpc.APINet,
+			Service:    jsonrpc.NewNetEndpoints(c.RPC, chainID),
+			Restricted: !ok,
 		})
 	}
 
-	if _, ok := apis[jsonrpc.APIZKEVM]; ok {
+	if _, ok := apis[jsonrpc.APIZKEVM]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APIZKEVM,
-			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, pool, st, etherman),
+			Name:       jsonrpc.APIZKEVM,
+			Service:    jsonrpc.NewZKEVMEndpoints(c.RPC, pool, st, etherman, storage),
+			Restricted: !ok,
 		})
 	}
 
-	if _, ok := apis[jsonrpc.APITxPool]; ok {
+	if _, ok := apis[jsonrpc.APITxPool]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APITxPool,
-			Service: &jsonrpc.TxPoolEndpoints{},
+			Name:       jsonrpc.APITxPool,
+			Service:    &jsonrpc.TxPoolEndpoints{},
+			Restricted: !ok,
 		})
 	}
 
-	if _, ok := apis[jsonrpc.APIDebug]; ok {
+	if _, ok := apis[jsonrpc.APIDebug]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APIDebug,
-			Service: jsonrpc.NewDebugEndpoints(c.RPC, st, etherman),
+			Name:       jsonrpc.APIDebug,
+			Service:    jsonrpc.NewDebugEndpoints(c.RPC, st, etherman),
+			Restricted: !ok,
 		})
 	}
 
-	if _, ok := apis[jsonrpc.APIWeb3]; ok {
+	if _, ok := apis[jsonrpc.APIWeb3]; ok || registerAll {
 		services = append(services, jsonrpc.Service{
-			Name:    jsonrpc.APIWeb3,
-			Service: &jsonrpc.Web3Endpoints{},
+			Name:       jsonrpc.APIWeb3,
+			Service:    &jsonrpc.Web3Endpoints{},
+			Restricted: !ok,
 		})
 	}
 
-	if err := jsonrpc.NewServer(c.RPC, chainID, pool, st, storage, services).Start(); err != nil {
+	if _, ok := apis[jsonrpc.APIOts]; ok || registerAll {
+		services = append(services, jsonrpc.Service{
+			Name:       jsonrpc.APIOts,
+			Service:    jsonrpc.NewOtsEndpoints(c.RPC, st, etherman),
+			Restricted: !ok,
+		})
+
+		// the ots searches by address need the addresses of the
+		// txs stored before they were indexed
+		go func() {
+			if err := st.BackfillTxsAddresses(context.Background()); err != nil {
+				log.Errorf("failed to index the addresses of the txs: %v", err)
+			}
+		}()
+	}
+
+	if _, ok := apis[jsonrpc.APITrace]; ok || registerAll {
+		services = append(services, jsonrpc.Service{
+			Name:       jsonrpc.APITrace,
+			Service:    jsonrpc.NewTraceEndpoints(c.RPC, st, etherman),
+			Restricted: !ok,
+		})
+	}
+
+	if _, ok := apis[jsonrpc.APIRPC]; ok || registerAll {
+		services = append(services, jsonrpc.Service{
+			Name:       jsonrpc.APIRPC,
+			Service:    &jsonrpc.RPCEndpoints{},
+			Restricted: !ok,
+		})
+	}
+
+	var responseCacheStore jsonrpc.ResponseCacheStore
+	if c.RPC.ResponseCache.Enabled && c.RPC.ResponseCache.SharedStore {
+		responseCacheDB, err := db.NewSQLDB(c.Pool.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pgResponseCacheStore := jsonrpc.NewPostgresResponseCacheStore(c.RPC.ResponseCache, responseCacheDB)
+		go pgResponseCacheStore.StartToCleanupExpiredResponses(context.Background())
+		responseCacheStore = pgResponseCacheStore
+	}
+
+	if err := jsonrpc.NewServer(c.RPC, chainID, pool, st, storage, apiKeyStorage, responseCacheStore, services).Start(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -313,6 +313,34 @@ func Test_Defaults(t *testing.T) {
 			path:          "Pool.EffectiveGasPrice.EthTransferL1GasPriceFactor",
 			expectedValue: float64(0),
 		},
+		{
+			path:          "Pool.PolicyFeeds.IntervalToRefresh",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "Pool.PolicyFeeds.HTTPTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Pool.PreExecution.Async",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.PreExecution.Workers",
+			expectedValue: 4,
+		},
+		{
+			path:          "Pool.PreExecution.QueueSize",
+			expectedValue: 1000,
+		},
+		{
+			path:          "Pool.PreExecution.RejectWhenFull",
+			expectedValue: true,
+		},
+		{
+			path:          "Pool.PreExecution.ClaimTimeout",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
 		{
 			path:          "Pool.DB.User",
 			expectedValue: "pool_user",
@@ -389,6 +417,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.MaxNativeBlockHashBlockRange",
 			expectedValue: uint64(60000),
 		},
+		{
+			path:          "RPC.MaxTraceFilterBlockRange",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "RPC.MaxFeeHistoryBlockCount",
+			expectedValue: uint64(1024),
+		},
+		{
+			path:          "RPC.FeeHistoryCacheSize",
+			expectedValue: 2048,
+		},
 		{
 			path:          "RPC.EnableHttpLog",
 			expectedValue: true,
@@ -409,6 +449,125 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.WebSockets.ReadLimit",
 			expectedValue: int64(104857600),
 		},
+		{
+			path:          "RPC.GraphQL.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.GraphQL.Host",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "RPC.GraphQL.Port",
+			expectedValue: int(8547),
+		},
+		{
+			path:          "RPC.GraphQL.MaxQueryDepth",
+			expectedValue: int(10),
+		},
+		{
+			path:          "RPC.GraphQL.MaxQueryCost",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "RPC.GraphQL.MaxBlockRange",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "RPC.Filters.Storage",
+			expectedValue: "memory",
+		},
+		{
+			path:          "RPC.Filters.Timeout",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "RPC.Filters.CleanupInterval",
+			expectedValue: types.NewDuration(time.Minute),
+		},
+		{
+			path:          "RPC.APIKeys.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.APIKeys.Required",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.APIKeys.Header",
+			expectedValue: "X-Api-Key",
+		},
+		{
+			path:          "RPC.APIKeys.CacheTTL",
+			expectedValue: types.NewDuration(time.Minute),
+		},
+		{
+			path:          "RPC.RateLimit.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.RateLimit.Rate",
+			expectedValue: float64(500),
+		},
+		{
+			path:          "RPC.RateLimit.Burst",
+			expectedValue: uint(1000),
+		},
+		{
+			path:          "RPC.RateLimit.DefaultCost",
+			expectedValue: uint(1),
+		},
+		{
+			path:          "RPC.RateLimit.LogsCostBlockRange",
+			expectedValue: uint64(1000),
+		},
+		{
+			path: "RPC.RateLimit.MethodCosts",
+			expectedValue: map[string]uint{
+				"eth_call":                 5,
+				"eth_estimategas":          5,
+				"eth_createaccesslist":     10,
+				"eth_getlogs":              5,
+				"eth_graphql":              10,
+				"debug_tracecall":          20,
+				"debug_tracetransaction":   20,
+				"debug_traceblockbynumber": 50,
+				"debug_traceblockbyhash":   50,
+				"debug_tracebatchbynumber": 100,
+			},
+		},
+		{
+			path:          "RPC.ResponseCache.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.ResponseCache.MaxBytesSize",
+			expectedValue: uint64(104857600),
+		},
+		{
+			path:          "RPC.ResponseCache.MaxEntryBytesSize",
+			expectedValue: uint64(1048576),
+		},
+		{
+			path:          "RPC.ResponseCache.Boundary",
+			expectedValue: "verified",
+		},
+		{
+			path:          "RPC.ResponseCache.BoundaryRefreshInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "RPC.ResponseCache.SharedStore",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.ResponseCache.SharedStoreTTL",
+			expectedValue: types.NewDuration(24 * time.Hour),
+		},
+		{
+			path:          "RPC.ResponseCache.SharedStoreCleanupInterval",
+			expectedValue: types.NewDuration(time.Hour),
+		},
 		{
 			path:          "Executor.URI",
 			expectedValue: "zkevm-prover:50071",
@@ -55,6 +55,16 @@ GlobalQueue = 1024
 	EthTransferGasPrice = 0
 	EthTransferL1GasPriceFactor = 0	
 	L2GasPriceSuggesterFactor = 0.5
+    [Pool.PolicyFeeds]
+	IntervalToRefresh = "5m"
+	HTTPTimeout = "30s"
+	Feeds = []
+    [Pool.PreExecution]
+	Async = false
+	Workers = 4
+	QueueSize = 1000
+	RejectWhenFull = true
+	ClaimTimeout = "5m"
     [Pool.DB]
 	User = "pool_user"
 	Password = "pool_password"
@@ -91,12 +101,57 @@ BatchRequestsLimit = 20
 MaxLogsCount = 10000
 MaxLogsBlockRange = 10000
 MaxNativeBlockHashBlockRange = 60000
+MaxTraceFilterBlockRange = 100
+MaxFeeHistoryBlockCount = 1024
+FeeHistoryCacheSize = 2048
 EnableHttpLog = true
 	[RPC.WebSockets]
 		Enabled = true
 		Host = "0.0.0.0"
 		Port = 8546
 		ReadLimit = 104857600
+	[RPC.GraphQL]
+		Enabled = false
+		Host = "0.0.0.0"
+		Port = 8547
+		MaxQueryDepth = 10
+		MaxQueryCost = 10000
+		MaxBlockRange = 100
+	[RPC.Filters]
+		Storage = "memory"
+		Timeout = "5m"
+		CleanupInterval = "1m"
+	[RPC.APIKeys]
+		Enabled = false
+		Required = false
+		Header = "X-Api-Key"
+		CacheTTL = "1m"
+	[RPC.RateLimit]
+		Enabled = false
+		Rate = 500
+		Burst = 1000
+		DefaultCost = 1
+		LogsCostBlockRange = 1000
+		[RPC.RateLimit.MethodCosts]
+			eth_call = 5
+			eth_estimateGas = 5
+			eth_createAccessList = 10
+			eth_getLogs = 5
+			eth_graphql = 10
+			debug_traceCall = 20
+			debug_traceTransaction = 20
+			debug_traceBlockByNumber = 50
+			debug_traceBlockByHash = 50
+			debug_traceBatchByNumber = 100
+	[RPC.ResponseCache]
+		Enabled = false
+		MaxBytesSize = 104857600
+		MaxEntryBytesSize = 1048576
+		Boundary = "verified"
+		BoundaryRefreshInterval = "5s"
+		SharedStore = false
+		SharedStoreTTL = "24h"
+		SharedStoreCleanupInterval = "1h"
 
 [Synchronizer]
 SyncInterval = "1s"
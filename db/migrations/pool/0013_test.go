@@ -0,0 +1,44 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds a trigger to notify the pool txs inserts and status changes
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getTrigger = `SELECT count(*) FROM pg_trigger WHERE tgname = 'transaction_notify';`
+	row := db.QueryRow(getTrigger)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	const insertTx = `
+		INSERT INTO pool.transaction (hash, ip, received_at, from_address, status)
+		VALUES ('0x0003', '127.0.0.1', '2023-12-07', '0x0033', 'pending')`
+	_, err := db.Exec(insertTx)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE pool.transaction SET status = 'selected' WHERE hash = '0x0003'`)
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTrigger = `SELECT count(*) FROM pg_trigger WHERE tgname = 'transaction_notify';`
+	row := db.QueryRow(getTrigger)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
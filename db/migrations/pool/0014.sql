@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE pool.filter
+(
+    id         VARCHAR PRIMARY KEY,
+    type       VARCHAR NOT NULL,
+    parameters JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_poll  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_filter_last_poll ON pool.filter (last_poll);
+
+-- +migrate Down
+DROP INDEX IF EXISTS pool.idx_filter_last_poll;
+DROP TABLE IF EXISTS pool.filter;
@@ -0,0 +1,44 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table to share the polling filters across the RPC instances
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_filter_last_poll';`
+	row := db.QueryRow(getIndex)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	const insertFilter = `
+		INSERT INTO pool.filter (id, type, parameters)
+		VALUES ('0x0001', 'log', '{"fromBlock":"0x1","topics":[]}')`
+	_, err := db.Exec(insertFilter)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE pool.filter SET last_poll = NOW() WHERE id = '0x0001'`)
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'pool' AND table_name = 'filter';`
+	row := db.QueryRow(getTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
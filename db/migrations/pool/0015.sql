@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE pool.api_key
+(
+    hash           VARCHAR PRIMARY KEY,
+    name           VARCHAR   NOT NULL UNIQUE,
+    namespaces     VARCHAR[] NOT NULL DEFAULT '{}',
+    methods        VARCHAR[] NOT NULL DEFAULT '{}',
+    requests_quota BIGINT    NOT NULL DEFAULT 0,
+    compute_quota  BIGINT    NOT NULL DEFAULT 0, -- milliseconds
+    quota_window   BIGINT    NOT NULL DEFAULT 0, -- seconds
+    max_batch_size BIGINT    NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pool.api_key;
@@ -0,0 +1,39 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table to store the API keys of the RPC
+type migrationTest0015 struct{}
+
+func (m migrationTest0015) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const insertAPIKey = `
+		INSERT INTO pool.api_key (hash, name, namespaces, methods, requests_quota, compute_quota, quota_window, max_batch_size)
+		VALUES ('0x0001', 'partner', '{eth,debug}', '{zkevm_getBatchByNumber}', 100, 1000, 60, 10)`
+	_, err := db.Exec(insertAPIKey)
+	assert.NoError(t, err)
+
+	const insertDuplicatedName = `INSERT INTO pool.api_key (hash, name) VALUES ('0x0002', 'partner')`
+	_, err = db.Exec(insertDuplicatedName)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'pool' AND table_name = 'api_key';`
+	row := db.QueryRow(getTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0015(t *testing.T) {
+	runMigrationTest(t, 15, migrationTest0015{})
+}
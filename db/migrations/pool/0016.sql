@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE pool.response_cache
+(
+    key        VARCHAR PRIMARY KEY,
+    response   BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_response_cache_created_at ON pool.response_cache (created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pool.response_cache;
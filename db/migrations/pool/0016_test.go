@@ -0,0 +1,43 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table to share the cached RPC responses across the RPC instances
+type migrationTest0016 struct{}
+
+func (m migrationTest0016) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_response_cache_created_at';`
+	row := db.QueryRow(getIndex)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	const insertResponse = `INSERT INTO pool.response_cache (key, response) VALUES ('0x0001', '{"number":"0x1"}')`
+	_, err := db.Exec(insertResponse)
+	assert.NoError(t, err)
+
+	const insertDuplicatedResponse = `INSERT INTO pool.response_cache (key, response) VALUES ('0x0001', '{"number":"0x2"}')`
+	_, err = db.Exec(insertDuplicatedResponse)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'pool' AND table_name = 'response_cache';`
+	row := db.QueryRow(getTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0016(t *testing.T) {
+	runMigrationTest(t, 16, migrationTest0016{})
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE pool.transaction ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_transaction_queued_claimed_at ON pool.transaction (claimed_at) WHERE status = 'queued';
+
+-- +migrate Down
+DROP INDEX IF EXISTS pool.idx_transaction_queued_claimed_at;
+
+ALTER TABLE pool.transaction DROP COLUMN IF EXISTS claimed_at;
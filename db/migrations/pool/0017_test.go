@@ -0,0 +1,40 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the time a queued tx was claimed by a node to be pre-executed
+type migrationTest0017 struct{}
+
+func (m migrationTest0017) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'claimed_at';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_transaction_queued_claimed_at';`
+	row = db.QueryRow(getIndex)
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'claimed_at';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0017(t *testing.T) {
+	runMigrationTest(t, 17, migrationTest0017{})
+}
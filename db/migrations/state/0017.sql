@@ -0,0 +1,104 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION state.notify_batch_closed() RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT NEW.wip AND (TG_OP = 'INSERT' OR OLD.wip) THEN
+        PERFORM pg_notify('state_event', json_strip_nulls(json_build_object(
+            'type', 'newBatch',
+            'batchNumber', NEW.batch_num,
+            'stateRoot', NEW.state_root,
+            'localExitRoot', NEW.local_exit_root,
+            'globalExitRoot', NEW.global_exit_root,
+            'accInputHash', NEW.acc_input_hash,
+            'coinbase', NEW.coinbase,
+            'timestamp', NEW.timestamp))::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION state.notify_virtual_batch() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('state_event', json_strip_nulls(json_build_object(
+        'type', 'virtualizedBatch',
+        'batchNumber', NEW.batch_num,
+        'txHash', NEW.tx_hash,
+        'blockNumber', NEW.block_num))::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION state.notify_verified_batch() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('state_event', json_strip_nulls(json_build_object(
+        'type', 'verifiedBatch',
+        'batchNumber', NEW.batch_num,
+        'txHash', NEW.tx_hash,
+        'stateRoot', NEW.state_root,
+        'blockNumber', NEW.block_num))::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION state.notify_exit_root() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('state_event', json_strip_nulls(json_build_object(
+        'type', 'newGlobalExitRoot',
+        'globalExitRoot', '0x' || encode(NEW.global_exit_root, 'hex'),
+        'mainnetExitRoot', '0x' || encode(NEW.mainnet_exit_root, 'hex'),
+        'rollupExitRoot', '0x' || encode(NEW.rollup_exit_root, 'hex'),
+        'l1InfoTreeIndex', NEW.l1_info_tree_index,
+        'blockNumber', NEW.block_num,
+        'timestamp', NEW.timestamp))::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION state.notify_fork_id() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('state_event', json_strip_nulls(json_build_object(
+        'type', 'forkIdChanged',
+        'forkId', NEW.fork_id,
+        'batchNumber', NEW.from_batch_num,
+        'version', NEW.version,
+        'blockNumber', NEW.block_num))::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER batch_closed_notify AFTER INSERT OR UPDATE OF wip ON state.batch
+    FOR EACH ROW EXECUTE FUNCTION state.notify_batch_closed();
+
+CREATE TRIGGER virtual_batch_notify AFTER INSERT ON state.virtual_batch
+    FOR EACH ROW EXECUTE FUNCTION state.notify_virtual_batch();
+
+CREATE TRIGGER verified_batch_notify AFTER INSERT ON state.verified_batch
+    FOR EACH ROW EXECUTE FUNCTION state.notify_verified_batch();
+
+CREATE TRIGGER exit_root_notify AFTER INSERT ON state.exit_root
+    FOR EACH ROW EXECUTE FUNCTION state.notify_exit_root();
+
+CREATE TRIGGER fork_id_notify AFTER INSERT ON state.fork_id
+    FOR EACH ROW EXECUTE FUNCTION state.notify_fork_id();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS batch_closed_notify ON state.batch;
+DROP TRIGGER IF EXISTS virtual_batch_notify ON state.virtual_batch;
+DROP TRIGGER IF EXISTS verified_batch_notify ON state.verified_batch;
+DROP TRIGGER IF EXISTS exit_root_notify ON state.exit_root;
+DROP TRIGGER IF EXISTS fork_id_notify ON state.fork_id;
+
+DROP FUNCTION IF EXISTS state.notify_batch_closed();
+DROP FUNCTION IF EXISTS state.notify_virtual_batch();
+DROP FUNCTION IF EXISTS state.notify_verified_batch();
+DROP FUNCTION IF EXISTS state.notify_exit_root();
+DROP FUNCTION IF EXISTS state.notify_fork_id();
@@ -0,0 +1,68 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the triggers to notify the batch lifecycle changes
+type migrationTest0017 struct{}
+
+var migration0017Triggers = []string{
+	"batch_closed_notify",
+	"virtual_batch_notify",
+	"verified_batch_notify",
+	"exit_root_notify",
+	"fork_id_notify",
+}
+
+func (m migrationTest0017) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	for _, trigger := range migration0017Triggers {
+		const getTrigger = `SELECT count(*) FROM pg_trigger WHERE tgname = $1;`
+		row := db.QueryRow(getTrigger, trigger)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 1, result)
+	}
+
+	const insertBlock = `INSERT INTO state.block (block_num, block_hash, parent_hash, received_at) VALUES (1017, '0x1017', '0x1016', '2024-01-01')`
+	_, err := db.Exec(insertBlock)
+	assert.NoError(t, err)
+
+	const insertBatch = `INSERT INTO state.batch (batch_num, state_root, wip) VALUES (1017, '0x1017', TRUE)`
+	_, err = db.Exec(insertBatch)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE state.batch SET wip = FALSE WHERE batch_num = 1017`)
+	assert.NoError(t, err)
+
+	const insertVirtualBatch = `INSERT INTO state.virtual_batch (batch_num, tx_hash, block_num) VALUES (1017, '0x1017', 1017)`
+	_, err = db.Exec(insertVirtualBatch)
+	assert.NoError(t, err)
+
+	const insertExitRoot = `
+		INSERT INTO state.exit_root (block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root)
+		VALUES (1017, '2024-01-01', decode('01', 'hex'), decode('02', 'hex'), decode('03', 'hex'))`
+	_, err = db.Exec(insertExitRoot)
+	assert.NoError(t, err)
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	for _, trigger := range migration0017Triggers {
+		const getTrigger = `SELECT count(*) FROM pg_trigger WHERE tgname = $1;`
+		row := db.QueryRow(getTrigger, trigger)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 0, result)
+	}
+}
+
+func TestMigration0017(t *testing.T) {
+	runMigrationTest(t, 17, migrationTest0017{})
+}
@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE state.transaction
+    ADD COLUMN IF NOT EXISTS from_address VARCHAR,
+    ADD COLUMN IF NOT EXISTS to_address VARCHAR,
+    ADD COLUMN IF NOT EXISTS nonce BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_transaction_from_address ON state.transaction (from_address, l2_block_num);
+CREATE INDEX IF NOT EXISTS idx_transaction_to_address ON state.transaction (to_address, l2_block_num);
+CREATE INDEX IF NOT EXISTS idx_transaction_from_address_nonce ON state.transaction (from_address, nonce);
+CREATE INDEX IF NOT EXISTS idx_receipt_contract_address ON state.receipt (contract_address);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.idx_transaction_from_address;
+DROP INDEX IF EXISTS state.idx_transaction_to_address;
+DROP INDEX IF EXISTS state.idx_transaction_from_address_nonce;
+DROP INDEX IF EXISTS state.idx_receipt_contract_address;
+
+ALTER TABLE state.transaction
+    DROP COLUMN IF EXISTS from_address,
+    DROP COLUMN IF EXISTS to_address,
+    DROP COLUMN IF EXISTS nonce;
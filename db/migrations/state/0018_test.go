@@ -0,0 +1,56 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the address columns used to index the txs by sender and receiver
+type migrationTest0018 struct{}
+
+var migration0018Indexes = []string{
+	"idx_transaction_from_address",
+	"idx_transaction_to_address",
+	"idx_transaction_from_address_nonce",
+	"idx_receipt_contract_address",
+}
+
+func (m migrationTest0018) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	for _, idx := range migration0018Indexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		row := db.QueryRow(getIndex, idx)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 1, result)
+	}
+
+	const getColumns = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'transaction' AND column_name IN ('from_address', 'to_address', 'nonce');`
+	var columns int
+	assert.NoError(t, db.QueryRow(getColumns).Scan(&columns))
+	assert.Equal(t, 3, columns)
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	for _, idx := range migration0018Indexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		row := db.QueryRow(getIndex, idx)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 0, result)
+	}
+
+	const getColumns = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'transaction' AND column_name IN ('from_address', 'to_address', 'nonce');`
+	var columns int
+	assert.NoError(t, db.QueryRow(getColumns).Scan(&columns))
+	assert.Equal(t, 0, columns)
+}
+
+func TestMigration0018(t *testing.T) {
+	runMigrationTest(t, 18, migrationTest0018{})
+}
@@ -0,0 +1,32 @@
+-- +migrate Up notransaction
+ALTER TABLE state.log ADD COLUMN IF NOT EXISTS block_num BIGINT;
+
+-- the existing logs are backfilled in ranges of blocks, committing each range,
+-- so the log table is not locked nor rewritten by a single full-table update
+-- +migrate StatementBegin
+DO $$
+DECLARE
+    range_size CONSTANT BIGINT := 10000;
+    from_block BIGINT := 0;
+    last_block BIGINT;
+BEGIN
+    SELECT COALESCE(MAX(l2_block_num), -1) INTO last_block FROM state.transaction;
+    WHILE from_block <= last_block LOOP
+        UPDATE state.log l
+           SET block_num = t.l2_block_num
+          FROM state.transaction t
+         WHERE t.hash = l.tx_hash
+           AND t.l2_block_num >= from_block
+           AND t.l2_block_num < from_block + range_size
+           AND l.block_num IS NULL;
+        COMMIT;
+        from_block := from_block + range_size;
+    END LOOP;
+END;
+$$;
+-- +migrate StatementEnd
+
+ALTER TABLE state.log ALTER COLUMN block_num SET NOT NULL;
+
+-- +migrate Down
+ALTER TABLE state.log DROP COLUMN IF EXISTS block_num;
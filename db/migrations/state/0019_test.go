@@ -0,0 +1,63 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration denormalizes the block number into the logs, backfilling the existing ones
+type migrationTest0019 struct{}
+
+func (m migrationTest0019) InsertData(db *sql.DB) error {
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip)
+		VALUES (1, '0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', null, null, false)`
+	if _, err := db.Exec(insertBatch); err != nil {
+		return err
+	}
+
+	const insertL2Block = `
+		INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
+		VALUES (7, '0x0007', '{}', '{}', '0x0006', '0x0000', now(), 1, now())`
+	if _, err := db.Exec(insertL2Block); err != nil {
+		return err
+	}
+
+	const insertTx = `
+		INSERT INTO state.transaction (hash, encoded, decoded, l2_block_num, effective_percentage, l2_hash)
+		VALUES ('0x0001', 'ABCDEF', '{}', 7, 255, '0x0002')`
+	if _, err := db.Exec(insertTx); err != nil {
+		return err
+	}
+
+	const insertLog = `
+		INSERT INTO state.log (tx_hash, log_index, address, data, topic0)
+		VALUES ('0x0001', 0, '0x0003', '0x', '0x0004')`
+	_, err := db.Exec(insertLog)
+	return err
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// the existing logs are backfilled with the block number of their tx
+	var blockNumber uint64
+	assert.NoError(t, db.QueryRow(`SELECT block_num FROM state.log WHERE tx_hash = '0x0001' AND log_index = 0`).Scan(&blockNumber))
+	assert.Equal(t, uint64(7), blockNumber)
+
+	// new logs must provide the block number
+	const insertLogWithoutBlockNumber = `INSERT INTO state.log (tx_hash, log_index, address, data) VALUES ('0x0001', 1, '0x0003', '0x')`
+	_, err := db.Exec(insertLogWithoutBlockNumber)
+	assert.Error(t, err)
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'log' AND column_name = 'block_num';`
+	var columns int
+	assert.NoError(t, db.QueryRow(getColumn).Scan(&columns))
+	assert.Equal(t, 0, columns)
+}
+
+func TestMigration0019(t *testing.T) {
+	runMigrationTest(t, 19, migrationTest0019{})
+}
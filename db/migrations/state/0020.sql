@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.final_proof
+(
+    batch_num       BIGINT NOT NULL REFERENCES state.batch (batch_num) ON DELETE CASCADE,
+    batch_num_final BIGINT NOT NULL REFERENCES state.batch (batch_num) ON DELETE CASCADE,
+    proof           VARCHAR NOT NULL,
+    proof_id        VARCHAR,
+    status          VARCHAR NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (batch_num, batch_num_final)
+);
+
+CREATE INDEX IF NOT EXISTS idx_final_proof_batch_num_final ON state.final_proof (batch_num_final);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.idx_final_proof_batch_num_final;
+DROP TABLE IF EXISTS state.final_proof;
@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table keeping the final proofs sent to L1
+type migrationTest0020 struct{}
+
+func (m migrationTest0020) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'final_proof';`
+	var tables int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&tables))
+	assert.Equal(t, 1, tables)
+
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_final_proof_batch_num_final';`
+	var indexes int
+	assert.NoError(t, db.QueryRow(getIndex).Scan(&indexes))
+	assert.Equal(t, 1, indexes)
+
+	const getStatusColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'final_proof' AND column_name = 'status';`
+	var columns int
+	assert.NoError(t, db.QueryRow(getStatusColumn).Scan(&columns))
+	assert.Equal(t, 1, columns)
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'final_proof';`
+	var tables int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&tables))
+	assert.Equal(t, 0, tables)
+}
+
+func TestMigration0020(t *testing.T) {
+	runMigrationTest(t, 20, migrationTest0020{})
+}
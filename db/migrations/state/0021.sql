@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_exit_root_global_exit_root ON state.exit_root (global_exit_root);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.idx_exit_root_global_exit_root;
@@ -0,0 +1,29 @@
+-- +migrate Up notransaction
+-- the indexes are built concurrently so the logs can still be written while they are built,
+-- an index left invalid by a failed build must be dropped before running this migration again
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_block_num_idx ON state.log (block_num, log_index);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_address_block_num_idx ON state.log (address, block_num);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic0_block_num_idx ON state.log (topic0, block_num);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic1_block_num_idx ON state.log (topic1, block_num);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic2_block_num_idx ON state.log (topic2, block_num);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic3_block_num_idx ON state.log (topic3, block_num);
+
+DROP INDEX CONCURRENTLY IF EXISTS state.log_address_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic0_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic1_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic2_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic3_idx;
+
+-- +migrate Down notransaction
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_address_idx ON state.log (address);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic0_idx ON state.log (topic0);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic1_idx ON state.log (topic1);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic2_idx ON state.log (topic2);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS log_topic3_idx ON state.log (topic3);
+
+DROP INDEX CONCURRENTLY IF EXISTS state.log_block_num_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_address_block_num_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic0_block_num_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic1_block_num_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic2_block_num_idx;
+DROP INDEX CONCURRENTLY IF EXISTS state.log_topic3_block_num_idx;
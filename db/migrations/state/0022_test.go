@@ -0,0 +1,56 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration indexes the logs by block number, address and topics
+type migrationTest0022 struct{}
+
+var migration0022Indexes = []string{
+	"log_block_num_idx",
+	"log_address_block_num_idx",
+	"log_topic0_block_num_idx",
+	"log_topic1_block_num_idx",
+	"log_topic2_block_num_idx",
+	"log_topic3_block_num_idx",
+}
+
+var migration0022DroppedIndexes = []string{
+	"log_address_idx",
+	"log_topic0_idx",
+	"log_topic1_idx",
+	"log_topic2_idx",
+	"log_topic3_idx",
+}
+
+func (m migrationTest0022) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0022) assertIndexes(t *testing.T, db *sql.DB, indexes []string, expected int) {
+	for _, idx := range indexes {
+		const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+		row := db.QueryRow(getIndex, idx)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, expected, result, idx)
+	}
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	m.assertIndexes(t, db, migration0022Indexes, 1)
+	m.assertIndexes(t, db, migration0022DroppedIndexes, 0)
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	m.assertIndexes(t, db, migration0022Indexes, 0)
+	m.assertIndexes(t, db, migration0022DroppedIndexes, 1)
+}
+
+func TestMigration0022(t *testing.T) {
+	runMigrationTest(t, 22, migrationTest0022{})
+}
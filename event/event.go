@@ -45,6 +45,9 @@ const (
 	// EventID_UnsupportedPrecompile is triggered when the executor returns an unsupported precompile error
 	EventID_UnsupportedPrecompile EventID = "UNSUPPORTED PRECOMPILE"
 
+	// EventID_PolicyFeedUpdated is triggered when a policy feed changes the addresses of a policy
+	EventID_PolicyFeedUpdated EventID = "POLICY FEED UPDATED"
+
 	// EventID_NodeOOC is triggered when an OOC at node level is detected
 	EventID_NodeOOC EventID = "NODE OOC"
 	// Source_Node is the source of the event
@@ -0,0 +1,227 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+)
+
+const (
+	apiKeyLength = 32
+	// apiKeyPathPrefix is the prefix of the URL paths providing the API key, e.g. /key/<key>
+	apiKeyPathPrefix = "/key/"
+)
+
+var (
+	// ErrInvalidAPIKey is returned when the provided API key is unknown
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyRequired is returned when the requests must be authenticated
+	// with an API key and none was provided
+	ErrAPIKeyRequired = errors.New("API key required")
+)
+
+// APIKey defines what the requests authenticated with a key are allowed to do
+type APIKey struct {
+	// Hash is the sha256 hash of the key, the key itself is never stored
+	Hash string
+	// Name identifies the owner of the key
+	Name string
+	// Namespaces are the namespaces the key can access, e.g. debug
+	Namespaces []string
+	// Methods are the methods the key can access besides its namespaces, e.g. debug_traceTransaction.
+	// If neither namespaces nor methods are set the key can access the public namespaces
+	Methods []string
+	// RequestsQuota is the max number of requests allowed within the quota window, zero means no limit
+	RequestsQuota uint64
+	// ComputeQuota is the max time spent handling the requests within the quota window, zero means no limit
+	ComputeQuota time.Duration
+	// QuotaWindow is the duration of the window used to account the quotas
+	QuotaWindow time.Duration
+	// MaxBatchSize overrides the batch requests limit if set
+	MaxBatchSize uint64
+	// CreatedAt is the time the key was created
+	CreatedAt time.Time
+}
+
+// allows checks if the key allows to call the method, the public methods
+// are allowed when the key doesn't set any namespace nor method
+func (k *APIKey) allows(method string, public bool) bool {
+	if len(k.Namespaces) == 0 && len(k.Methods) == 0 {
+		return public
+	}
+
+	namespace, _, _ := strings.Cut(method, "_")
+	for _, n := range k.Namespaces {
+		if n == namespace {
+			return true
+		}
+	}
+	for _, m := range k.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAPIKey generates a new random API key
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, apiKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hash used to store and look up an API key
+func HashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToHex(h[:])
+}
+
+type cachedAPIKey struct {
+	apiKey   *APIKey
+	loadedAt time.Time
+}
+
+type apiKeyUsage struct {
+	windowStart time.Time
+	requests    uint64
+	compute     time.Duration
+}
+
+// apiKeyManager authenticates the requests with the API keys and accounts
+// their quotas. The keys are cached for the configured TTL to avoid hitting
+// the database on every request and the usage is accounted by each instance.
+// The unknown keys are not cached, so the cache can't be filled with random keys
+// and the lookups of unknown keys are limited by the rate limit of the requests' IP
+type apiKeyManager struct {
+	cfg     APIKeysConfig
+	storage APIKeyStorage
+
+	cache map[string]cachedAPIKey
+	usage map[string]*apiKeyUsage
+	mutex *sync.Mutex
+}
+
+func newAPIKeyManager(cfg APIKeysConfig, storage APIKeyStorage) *apiKeyManager {
+	return &apiKeyManager{
+		cfg:     cfg,
+		storage: storage,
+		cache:   make(map[string]cachedAPIKey),
+		usage:   make(map[string]*apiKeyUsage),
+		mutex:   &sync.Mutex{},
+	}
+}
+
+// redactAPIKeyPath hides the API key provided by the URL path, e.g. /key/<key>,
+// so it's not written to the logs
+func redactAPIKeyPath(path string) string {
+	rest, found := strings.CutPrefix(path, apiKeyPathPrefix)
+	if !found {
+		return path
+	}
+	_, rest, hasRest := strings.Cut(rest, "/")
+	if !hasRest {
+		return apiKeyPathPrefix + "***"
+	}
+	return apiKeyPathPrefix + "***/" + rest
+}
+
+// authenticate returns the API key provided by the http request via the configured
+// header or the URL path, e.g. /key/<key>, nil is returned if no key is provided
+// and it's not required
+func (m *apiKeyManager) authenticate(req *http.Request) (*APIKey, error) {
+	key := req.Header.Get(m.cfg.Header)
+	if key == "" {
+		if path, found := strings.CutPrefix(req.URL.Path, apiKeyPathPrefix); found {
+			key, _, _ = strings.Cut(path, "/")
+		}
+	}
+	if key == "" {
+		if m.cfg.Required {
+			return nil, ErrAPIKeyRequired
+		}
+		return nil, nil
+	}
+
+	apiKey, err := m.getAPIKey(req.Context(), HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	return apiKey, nil
+}
+
+// getAPIKey loads the API key from the cache or the storage, nil is returned
+// if the key is unknown. The removed keys are dropped from the cache once it expires
+func (m *apiKeyManager) getAPIKey(ctx context.Context, hash string) (*APIKey, error) {
+	m.mutex.Lock()
+	cached, found := m.cache[hash]
+	m.mutex.Unlock()
+	if found && time.Since(cached.loadedAt) < m.cfg.CacheTTL.Duration {
+		return cached.apiKey, nil
+	}
+
+	apiKey, err := m.storage.GetAPIKey(ctx, hash)
+	if errors.Is(err, ErrNotFound) {
+		m.mutex.Lock()
+		delete(m.cache, hash)
+		m.mutex.Unlock()
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	m.mutex.Lock()
+	m.cache[hash] = cachedAPIKey{apiKey: apiKey, loadedAt: time.Now()}
+	m.mutex.Unlock()
+	return apiKey, nil
+}
+
+// reserve accounts a new request for the key, an error is returned
+// if any of the quotas of the key is exhausted in the current window
+func (m *apiKeyManager) reserve(apiKey *APIKey) types.Error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	usage := m.currentUsage(apiKey)
+	if apiKey.RequestsQuota > 0 && usage.requests >= apiKey.RequestsQuota {
+		return types.NewRPCError(types.LimitExceededErrorCode, "requests quota exceeded")
+	}
+	if apiKey.ComputeQuota > 0 && usage.compute >= apiKey.ComputeQuota {
+		return types.NewRPCError(types.LimitExceededErrorCode, "compute quota exceeded")
+	}
+	usage.requests++
+	return nil
+}
+
+// addCompute accounts the time spent handling a request of the key
+func (m *apiKeyManager) addCompute(apiKey *APIKey, elapsed time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.currentUsage(apiKey).compute += elapsed
+}
+
+// currentUsage returns the usage of the key in the current window,
+// the mutex must be held by the caller
+func (m *apiKeyManager) currentUsage(apiKey *APIKey) *apiKeyUsage {
+	usage, found := m.usage[apiKey.Hash]
+	if !found || time.Since(usage.windowStart) >= apiKey.QuotaWindow {
+		usage = &apiKeyUsage{windowStart: time.Now()}
+		m.usage[apiKey.Hash] = usage
+	}
+	return usage
+}
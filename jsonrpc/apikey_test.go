@@ -0,0 +1,163 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node"
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAllows(t *testing.T) {
+	publicKey := &APIKey{}
+	assert.True(t, publicKey.allows("eth_chainId", true))
+	assert.False(t, publicKey.allows("debug_traceTransaction", false))
+
+	partnerKey := &APIKey{Namespaces: []string{APIDebug}, Methods: []string{"eth_chainId"}}
+	assert.True(t, partnerKey.allows("debug_traceTransaction", false))
+	assert.True(t, partnerKey.allows("eth_chainId", true))
+	assert.False(t, partnerKey.allows("eth_blockNumber", true))
+}
+
+func TestAPIKeys(t *testing.T) {
+	const (
+		partnerKey = "partner"
+		basicKey   = "basic"
+		unknownKey = "unknown"
+	)
+
+	cfg := getSequencerDefaultConfig()
+	cfg.Port = 9125
+	cfg.WebSockets.Enabled = false
+	cfg.APIKeys = APIKeysConfig{
+		Enabled:  true,
+		Header:   "X-Api-Key",
+		CacheTTL: cfgTypes.NewDuration(time.Minute),
+	}
+
+	apiKeyStorage := newApiKeyStorageMock(t)
+	apiKeyStorage.On("GetAPIKey", mock.Anything, HashAPIKey(partnerKey)).
+		Return(&APIKey{Hash: HashAPIKey(partnerKey), Namespaces: []string{APINet, APIWeb3}, MaxBatchSize: 1}, nil).
+		Once()
+	apiKeyStorage.On("GetAPIKey", mock.Anything, HashAPIKey(basicKey)).
+		Return(&APIKey{Hash: HashAPIKey(basicKey), RequestsQuota: 1, QuotaWindow: time.Hour}, nil).
+		Once()
+	// the unknown keys are not cached
+	apiKeyStorage.On("GetAPIKey", mock.Anything, HashAPIKey(unknownKey)).
+		Return(nil, ErrNotFound).
+		Twice()
+
+	services := []Service{
+		{Name: APIWeb3, Service: &Web3Endpoints{}},
+		{Name: APINet, Service: NewNetEndpoints(cfg, chainID), Restricted: true},
+	}
+	server := NewServer(cfg, chainID, mocks.NewPoolMock(t), mocks.NewStateMock(t), newStorageMock(t), apiKeyStorage, nil, services)
+	go func() {
+		err := server.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	defer func() {
+		require.NoError(t, server.Stop())
+	}()
+
+	serverURL := fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port)
+	for {
+		res, err := http.Get(serverURL) //nolint:gosec
+		if err == nil && res.StatusCode == http.StatusOK {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	callWithHeader := func(key string, body string) (int, []byte) {
+		req, err := http.NewRequest(http.MethodPost, serverURL, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(cfg.APIKeys.Header, key)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, resBody
+	}
+	netVersionRequest := `{"jsonrpc":"2.0","id":1,"method":"net_version","params":[]}`
+
+	// public namespaces are available without an API key
+	res, err := client.JSONRPCCall(serverURL, "web3_clientVersion")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, `"`+zkevm.Version+`"`, string(res.Result))
+
+	// restricted namespaces are hidden without an API key
+	res, err = client.JSONRPCCall(serverURL, "net_version")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.NotFoundErrorCode, res.Error.Code)
+
+	// unknown API keys are rejected
+	statusCode, _ := callWithHeader(unknownKey, netVersionRequest)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	statusCode, _ = callWithHeader(unknownKey, netVersionRequest)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// restricted namespaces are available to the allowed keys via header and path
+	statusCode, body := callWithHeader(partnerKey, netVersionRequest)
+	require.Equal(t, http.StatusOK, statusCode)
+	var headerRes types.Response
+	require.NoError(t, json.Unmarshal(body, &headerRes))
+	require.Nil(t, headerRes.Error)
+	assert.Equal(t, fmt.Sprintf(`"%d"`, chainID), string(headerRes.Result))
+
+	res, err = client.JSONRPCCall(serverURL+"/key/"+partnerKey, "net_version")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	// the keys are only read from the path under the key prefix
+	res, err = client.JSONRPCCall(serverURL+"/"+partnerKey, "net_version")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.NotFoundErrorCode, res.Error.Code)
+
+	// the batch size limit of the key overrides the server one
+	statusCode, _ = callWithHeader(partnerKey, "["+netVersionRequest+","+netVersionRequest+"]")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+
+	// keys without namespaces can only access the public ones
+	res, err = client.JSONRPCCall(serverURL+"/key/"+basicKey, "net_version")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.AccessDeniedCode, res.Error.Code)
+
+	// the requests quota of the key is enforced
+	res, err = client.JSONRPCCall(serverURL+"/key/"+basicKey, "web3_clientVersion")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	res, err = client.JSONRPCCall(serverURL+"/key/"+basicKey, "web3_clientVersion")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.LimitExceededErrorCode, res.Error.Code)
+	assert.Equal(t, "requests quota exceeded", res.Error.Message)
+}
+
+func TestRedactAPIKeyPath(t *testing.T) {
+	assert.Equal(t, "/", redactAPIKeyPath("/"))
+	assert.Equal(t, "/ws", redactAPIKeyPath("/ws"))
+	assert.Equal(t, "/key/***", redactAPIKeyPath("/key/secret"))
+	assert.Equal(t, "/key/***/ws", redactAPIKeyPath("/key/secret/ws"))
+	assert.NotContains(t, redactAPIKeyPath("/key/secret/"), "secret")
+}
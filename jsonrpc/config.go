@@ -35,6 +35,21 @@ type Config struct {
 	// WebSockets configuration
 	WebSockets WebSocketsConfig `mapstructure:"WebSockets"`
 
+	// GraphQL configuration
+	GraphQL GraphQLConfig `mapstructure:"GraphQL"`
+
+	// Filters configuration
+	Filters FiltersConfig `mapstructure:"Filters"`
+
+	// APIKeys configuration
+	APIKeys APIKeysConfig `mapstructure:"APIKeys"`
+
+	// RateLimit configuration
+	RateLimit RateLimitConfig `mapstructure:"RateLimit"`
+
+	// ResponseCache configuration
+	ResponseCache ResponseCacheConfig `mapstructure:"ResponseCache"`
+
 	// EnableL2SuggestedGasPricePolling enables polling of the L2 gas price to block tx in the RPC with lower gas price.
 	EnableL2SuggestedGasPricePolling bool `mapstructure:"EnableL2SuggestedGasPricePolling"`
 
@@ -59,6 +74,19 @@ type Config struct {
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64 `mapstructure:"MaxNativeBlockHashBlockRange"`
 
+	// MaxTraceFilterBlockRange is a configuration to set the max range for block number when
+	// filtering traces with trace_filter, every tx in the range is traced so it must be kept
+	// small. If zero it means no limit
+	MaxTraceFilterBlockRange uint64 `mapstructure:"MaxTraceFilterBlockRange"`
+
+	// MaxFeeHistoryBlockCount is a configuration to set the max number of blocks returned by
+	// eth_feeHistory, larger ranges are truncated. If zero it means no limit
+	MaxFeeHistoryBlockCount uint64 `mapstructure:"MaxFeeHistoryBlockCount"`
+
+	// FeeHistoryCacheSize is the number of blocks whose fee data is kept in memory to
+	// serve eth_feeHistory, if zero the fee data is not cached
+	FeeHistoryCacheSize int `mapstructure:"FeeHistoryCacheSize"`
+
 	// EnableHttpLog allows the user to enable or disable the logs related to the HTTP
 	// requests to be captured by the server.
 	EnableHttpLog bool `mapstructure:"EnableHttpLog"`
@@ -93,3 +121,128 @@ type WebSocketsConfig struct {
 	// ReadLimit defines the maximum size of a message read from the client (in bytes)
 	ReadLimit int64 `mapstructure:"ReadLimit"`
 }
+
+// GraphQLConfig has parameters to config the GraphQL server, it serves the
+// blocks, txs, logs, accounts, batches and L1InfoTree leaves of the state
+type GraphQLConfig struct {
+	// Enabled defines if the GraphQL requests are enabled or disabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Host defines the network adapter that will be used to serve the GraphQL requests
+	Host string `mapstructure:"Host"`
+
+	// Port defines the port to serve the GraphQL requests
+	Port int `mapstructure:"Port"`
+
+	// MaxQueryDepth is the max depth of the GraphQL queries, if zero it means no limit
+	MaxQueryDepth int `mapstructure:"MaxQueryDepth"`
+
+	// MaxQueryCost is the max number of fields a GraphQL query can load from the state,
+	// the query is aborted once it's exceeded. If zero it means no limit
+	MaxQueryCost uint64 `mapstructure:"MaxQueryCost"`
+
+	// MaxBlockRange is the max number of blocks returned by the blocks query, if zero it means no limit
+	MaxBlockRange uint64 `mapstructure:"MaxBlockRange"`
+}
+
+const (
+	// FilterStorageMemory keeps the filters in the memory of the instance
+	FilterStorageMemory = "memory"
+	// FilterStoragePostgres shares the polling filters across the instances using the pool database
+	FilterStoragePostgres = "postgres"
+)
+
+// FiltersConfig has parameters to config where the filters are stored
+type FiltersConfig struct {
+	// Storage defines where the polling filters are stored, "memory" keeps them in the
+	// instance and "postgres" shares them across all the instances using the pool database.
+	// The subscriptions are always kept in memory since they are bound to a WS connection
+	Storage string `mapstructure:"Storage" jsonschema:"enum=memory,enum=postgres"`
+
+	// Timeout is the time a polling filter stored in postgres is kept without being polled
+	Timeout types.Duration `mapstructure:"Timeout"`
+
+	// CleanupInterval is the frequency the expired filters are removed from postgres
+	CleanupInterval types.Duration `mapstructure:"CleanupInterval"`
+}
+
+// APIKeysConfig has parameters to config the API keys authentication
+type APIKeysConfig struct {
+	// Enabled defines if the requests can be authenticated with the API keys stored in the
+	// pool database. The keys are managed with the apikey command and can access the namespaces
+	// not exposed publicly via --http.api
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Required defines if the requests without an API key are rejected
+	Required bool `mapstructure:"Required"`
+
+	// Header is the HTTP header used to provide the API key, it can also be
+	// provided in the URL path, e.g. http://host:port/key/<key>
+	Header string `mapstructure:"Header"`
+
+	// CacheTTL is the time the API keys loaded from the database are cached. A removed
+	// key keeps working in the running instances until its cache entry expires
+	CacheTTL types.Duration `mapstructure:"CacheTTL"`
+}
+
+// RateLimitConfig has parameters to config the rate limit by the cost of the methods,
+// each IP or API key has a bucket of cost units refilled at a constant rate
+type RateLimitConfig struct {
+	// Enabled defines if the requests are rate limited by their cost
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Rate is the number of cost units refilled per second in the bucket of each client
+	Rate float64 `mapstructure:"Rate"`
+
+	// Burst is the max number of cost units the bucket of each client can hold,
+	// the requests costing more than the burst are always rejected
+	Burst uint `mapstructure:"Burst"`
+
+	// DefaultCost is the cost of the methods not listed in MethodCosts
+	DefaultCost uint `mapstructure:"DefaultCost"`
+
+	// MethodCosts defines the cost of specific methods, the method names are case insensitive
+	MethodCosts map[string]uint `mapstructure:"MethodCosts"`
+
+	// LogsCostBlockRange is the size of the block ranges charged by eth_getLogs, its cost is multiplied
+	// by the number of ranges requested. If zero the cost doesn't depend on the requested blocks
+	LogsCostBlockRange uint64 `mapstructure:"LogsCostBlockRange"`
+}
+
+const (
+	// ResponseCacheBoundaryVirtualized considers immutable the data up to the last virtualized block
+	ResponseCacheBoundaryVirtualized = "virtualized"
+	// ResponseCacheBoundaryVerified considers immutable the data up to the last verified block
+	ResponseCacheBoundaryVerified = "verified"
+)
+
+// ResponseCacheConfig has parameters to config the cache of the responses for immutable chain data
+type ResponseCacheConfig struct {
+	// Enabled defines if the responses of the blocks, txs, receipts, logs and batches below
+	// the boundary are cached. The cache is purged when a trusted state reorg is detected
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxBytesSize is the max total size in bytes of the responses kept in memory, if zero it means no limit
+	MaxBytesSize uint64 `mapstructure:"MaxBytesSize"`
+
+	// MaxEntryBytesSize is the max size in bytes of a cached response, the bigger responses
+	// aren't cached neither in memory nor in the shared store. If zero it means no limit
+	MaxEntryBytesSize uint64 `mapstructure:"MaxEntryBytesSize"`
+
+	// Boundary defines the last block whose data is considered immutable, "virtualized" or "verified".
+	// The batches are only cached once verified since their data is updated by the verification
+	Boundary string `mapstructure:"Boundary" jsonschema:"enum=virtualized,enum=verified"`
+
+	// BoundaryRefreshInterval is the frequency the boundary is loaded from the state
+	BoundaryRefreshInterval types.Duration `mapstructure:"BoundaryRefreshInterval"`
+
+	// SharedStore defines if the responses are also cached in the pool database, so they
+	// are shared by all the instances as a second tier behind the memory cache
+	SharedStore bool `mapstructure:"SharedStore"`
+
+	// SharedStoreTTL is the time a response is kept in the pool database
+	SharedStoreTTL types.Duration `mapstructure:"SharedStoreTTL"`
+
+	// SharedStoreCleanupInterval is the frequency the expired responses are removed from the pool database
+	SharedStoreCleanupInterval types.Duration `mapstructure:"SharedStoreCleanupInterval"`
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -52,6 +53,14 @@ type traceConfig struct {
 	TracerConfig     json.RawMessage `json:"tracerConfig"`
 }
 
+// traceCallConfig is the trace config of debug_traceCall, which also
+// accepts the overrides applied during the execution of the call
+type traceCallConfig struct {
+	traceConfig
+	StateOverrides *types.StateOverride  `json:"stateOverrides"`
+	BlockOverrides *types.BlockOverrides `json:"blockOverrides"`
+}
+
 type traceBlockTransactionResponse struct {
 	Result interface{} `json:"result"`
 }
@@ -69,6 +78,56 @@ func (d *DebugEndpoints) TraceTransaction(hash types.ArgHash, cfg *traceConfig)
 	})
 }
 
+// TraceCall creates a response for debug_traceCall request, executing the
+// given call on top of the state of the given block.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtracecall
+func (d *DebugEndpoints) TraceCall(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, cfg *traceCallConfig) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if arg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+		traceCfg := &traceCallConfig{traceConfig: *defaultTraceConfig}
+		if cfg != nil {
+			traceCfg = cfg
+		}
+		stateOverride, blockOverride, respErr := getCallOverrides(traceCfg.StateOverrides, traceCfg.BlockOverrides)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		block, respErr := getL2BlockByArg(ctx, d.state, d.etherman, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+		if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
+			header, err := d.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
+			}
+
+			gas := types.ArgUint64(header.GasLimit)
+			arg.Gas = &gas
+		}
+
+		defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
+		sender, tx, err := arg.ToTransaction(ctx, d.state, state.MaxTxGasLimit, block.Root(), defaultSenderAddress, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		}
+
+		blockNumber := block.NumberU64()
+		result, err := d.state.DebugCall(ctx, tx, sender, &blockNumber, traceCfg.toStateTraceConfig(), stateOverride, blockOverride, dbTx)
+		if err != nil {
+			errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+			return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+		}
+
+		return result.TraceResult, nil
+	})
+}
+
 // TraceBlockByNumber creates a response for debug_traceBlockByNumber request.
 // See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbynumber
 func (d *DebugEndpoints) TraceBlockByNumber(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
@@ -253,6 +312,164 @@ func (d *DebugEndpoints) TraceBatchByNumber(httpRequest *http.Request, number ty
 	})
 }
 
+// GetRawBlock returns the RLP encoding of the given block.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawblock
+func (d *DebugEndpoints) GetRawBlock(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getL2BlockByArg(ctx, d.state, d.etherman, &blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		data, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to encode block", err, true)
+		}
+
+		return types.ArgBytes(data), nil
+	})
+}
+
+// GetRawTransaction returns the binary encoding of the given tx, the
+// same encoding accepted by eth_sendRawTransaction.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawtransaction
+func (d *DebugEndpoints) GetRawTransaction(hash types.ArgHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		tx, err := d.state.GetTransactionByHash(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transaction", err, true)
+		}
+
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to encode transaction", err, true)
+		}
+
+		return types.ArgBytes(data), nil
+	})
+}
+
+// GetRawReceipts returns the consensus encoding of the receipts of the given block.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debuggetrawreceipts
+func (d *DebugEndpoints) GetRawReceipts(blockArg types.BlockNumberOrHash) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getL2BlockByArg(ctx, d.state, d.etherman, &blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		receipts, err := d.state.GetTransactionReceiptsByL2BlockNumber(ctx, block.NumberU64(), dbTx)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block receipts", err, true)
+		}
+
+		rawReceipts := make([]types.ArgBytes, 0, len(receipts))
+		for _, receipt := range receipts {
+			data, err := receipt.MarshalBinary()
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to encode receipt of tx %v", receipt.TxHash.String()), err, true)
+			}
+			rawReceipts = append(rawReceipts, data)
+		}
+
+		return rawReceipts, nil
+	})
+}
+
+// GetRawBatch returns the BatchL2Data of the given batch in the V2 encoding decoded
+// by state.DecodeBatchV2: each L2 block starts with a changeL2Block marker and each
+// tx is followed by its effective gas price percentage. The data is built from the
+// L2 blocks and txs stored for the batch, so the forced batches have the markers
+// too. The batches sequenced before ETROG are not V2 encoded
+func (d *DebugEndpoints) GetRawBatch(number types.BatchNumber) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := number.GetNumericBatchNumber(ctx, d.state, d.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		batch, err := d.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+
+		if forkID := d.state.GetForkIDByBatchNumber(batchNumber); forkID < state.FORKID_ETROG {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("batch %v has fork id %v, batches are V2 encoded since fork id %v", batchNumber, forkID, state.FORKID_ETROG), nil, false)
+		}
+
+		rawBatch, err := d.buildRawBatchV2(ctx, batch, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the l2 blocks of batch %v", batchNumber), err, true)
+		}
+		batchL2Data, err := state.EncodeBatchV2(rawBatch)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't encode batch %v", batchNumber), err, true)
+		}
+		return types.ArgBytes(batchL2Data), nil
+	})
+}
+
+// buildRawBatchV2 builds the raw batch from the L2 blocks and txs stored for the batch.
+// The index of the L1 info tree used by each L2 block is not stored along the block, so
+// it's read from the BatchL2Data when it has the same blocks, otherwise it's 0 like
+// for the forced batches
+func (d *DebugEndpoints) buildRawBatchV2(ctx context.Context, batch *state.Batch, dbTx pgx.Tx) (*state.BatchRawV2, error) {
+	l2Blocks, err := d.state.GetL2BlocksByBatchNumber(ctx, batch.BatchNumber, dbTx)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, err
+	}
+	// the txs of the batch are only sorted by block, so their effective
+	// percentages are matched with the txs of the blocks by hash
+	txs, effectivePercentages, err := d.state.GetTransactionsByBatchNumber(ctx, batch.BatchNumber, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	txsEffectivePercentages := make(map[common.Hash]uint8, len(txs))
+	for i, tx := range txs {
+		txsEffectivePercentages[tx.Hash()] = effectivePercentages[i]
+	}
+
+	var storedBlocks []state.L2BlockRaw
+	if storedBatch, err := state.DecodeBatchV2(batch.BatchL2Data); err == nil && len(storedBatch.Blocks) == len(l2Blocks) {
+		storedBlocks = storedBatch.Blocks
+	}
+
+	rawBatch := &state.BatchRawV2{Blocks: make([]state.L2BlockRaw, 0, len(l2Blocks))}
+	for i, l2Block := range l2Blocks {
+		var previousTimestamp uint64
+		if i > 0 {
+			previousTimestamp = l2Blocks[i-1].Time()
+		} else if l2Block.NumberU64() > 0 {
+			previousBlock, err := d.state.GetL2BlockByNumber(ctx, l2Block.NumberU64()-1, dbTx)
+			if err != nil {
+				return nil, err
+			}
+			previousTimestamp = previousBlock.Time()
+		}
+
+		rawBlock := state.L2BlockRaw{
+			DeltaTimestamp: uint32(l2Block.Time() - previousTimestamp),
+			Transactions:   make([]state.L2TxRaw, 0, len(l2Block.Transactions())),
+		}
+		if storedBlocks != nil {
+			rawBlock.IndexL1InfoTree = storedBlocks[i].IndexL1InfoTree
+		}
+		for _, tx := range l2Block.Transactions() {
+			effectivePercentage, found := txsEffectivePercentages[tx.Hash()]
+			if !found {
+				return nil, fmt.Errorf("effective percentage of tx %v not found", tx.Hash().String())
+			}
+			rawBlock.Transactions = append(rawBlock.Transactions, state.L2TxRaw{Tx: *tx, EfficiencyPercentage: effectivePercentage})
+		}
+		rawBatch.Blocks = append(rawBatch.Blocks, rawBlock)
+	}
+	return rawBatch, nil
+}
+
 func (d *DebugEndpoints) buildTraceBlock(ctx context.Context, txs []*ethTypes.Transaction, cfg *traceConfig, dbTx pgx.Tx) (interface{}, types.Error) {
 	traces := []traceBlockTransactionResponse{}
 	for _, tx := range txs {
@@ -276,15 +493,7 @@ func (d *DebugEndpoints) buildTraceTransaction(ctx context.Context, hash common.
 		traceCfg = defaultTraceConfig
 	}
 
-	stateTraceConfig := state.TraceConfig{
-		DisableStack:     traceCfg.DisableStack,
-		DisableStorage:   traceCfg.DisableStorage,
-		EnableMemory:     traceCfg.EnableMemory,
-		EnableReturnData: traceCfg.EnableReturnData,
-		Tracer:           traceCfg.Tracer,
-		TracerConfig:     traceCfg.TracerConfig,
-	}
-	result, err := d.state.DebugTransaction(ctx, hash, stateTraceConfig, dbTx)
+	result, err := d.state.DebugTransaction(ctx, hash, traceCfg.toStateTraceConfig(), dbTx)
 	if errors.Is(err, state.ErrNotFound) {
 		return RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
 	} else if err != nil {
@@ -295,6 +504,18 @@ func (d *DebugEndpoints) buildTraceTransaction(ctx context.Context, hash common.
 	return result.TraceResult, nil
 }
 
+// toStateTraceConfig converts the trace config to the state format
+func (cfg *traceConfig) toStateTraceConfig() state.TraceConfig {
+	return state.TraceConfig{
+		DisableStack:     cfg.DisableStack,
+		DisableStorage:   cfg.DisableStorage,
+		EnableMemory:     cfg.EnableMemory,
+		EnableReturnData: cfg.EnableReturnData,
+		Tracer:           cfg.Tracer,
+		TracerConfig:     cfg.TracerConfig,
+	}
+}
+
 // waitTimeout waits for the waitGroup for the specified max timeout.
 // Returns true if waiting timed out.
 func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
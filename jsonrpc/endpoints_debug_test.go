@@ -0,0 +1,292 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceCall(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txArgs := types.TxArgs{
+		From: state.HexToAddressPtr("0x1"),
+		To:   state.HexToAddressPtr("0x2"),
+		Gas:  types.ArgUint64Ptr(24000),
+		Data: types.ArgBytesPtr([]byte("data")),
+	}
+	callTracer := "callTracer"
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult json.RawMessage
+		ExpectedError  *types.RPCError
+
+		SetupMocks func(m *mocksWrapper, tc *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name: "invalid state override",
+			Params: []interface{}{
+				txArgs,
+				hex.EncodeBig(blockNumOne),
+				map[string]interface{}{
+					"stateOverrides": map[string]interface{}{
+						common.HexToAddress("0x2").String(): map[string]interface{}{
+							"state":     map[string]interface{}{},
+							"stateDiff": map[string]interface{}{},
+						},
+					},
+				},
+			},
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "account 0x0000000000000000000000000000000000000002 has both state and stateDiff overrides"),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to trace the call",
+			Params:        []interface{}{txArgs, hex.EncodeBig(blockNumOne)},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to get trace: failed to process"),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(uint64(7), nil).Once()
+
+				m.State.
+					On("DebugCall", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, state.TraceConfig{}, nilStateOverride, nilBlockOverride, m.DbTx).
+					Return(nil, errors.New("failed to process")).
+					Once()
+			},
+		},
+		{
+			Name: "trace call with tracer and state override successfully",
+			Params: []interface{}{
+				txArgs,
+				hex.EncodeBig(blockNumOne),
+				map[string]interface{}{
+					"tracer": callTracer,
+					"stateOverrides": map[string]interface{}{
+						common.HexToAddress("0x2").String(): map[string]interface{}{
+							"code": "0x6001",
+						},
+					},
+				},
+			},
+			ExpectedResult: json.RawMessage(`{"type":"CALL"}`),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(uint64(7), nil).Once()
+
+				traceConfig := state.TraceConfig{Tracer: &callTracer}
+				stateOverride := state.StateOverride{
+					common.HexToAddress("0x2"): state.OverrideAccount{Code: []byte{0x60, 0x01}},
+				}
+				m.State.
+					On("DebugCall", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, traceConfig, stateOverride, nilBlockOverride, m.DbTx).
+					Return(&runtime.ExecutionResult{GasUsed: 21000, TraceResult: tc.ExpectedResult}, nil).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, &tc)
+			res, err := s.JSONRPCCall("debug_traceCall", tc.Params...)
+			require.NoError(t, err)
+			if tc.ExpectedResult != nil {
+				require.NotNil(t, res.Result)
+				require.Nil(t, res.Error)
+				assert.JSONEq(t, string(tc.ExpectedResult), string(res.Result))
+			}
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetRawBlockAndReceipts(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1, To: state.HexToAddressPtr("0x2"), Value: big.NewInt(1), GasPrice: big.NewInt(1)})
+	receipt := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: tx.Hash(), Logs: []*ethTypes.Log{}}
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{tx}, nil, []*ethTypes.Receipt{receipt}, trie.NewStackTrie(nil))
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block, nil).Twice()
+	m.State.On("GetTransactionReceiptsByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return([]*ethTypes.Receipt{receipt}, nil).Once()
+
+	res, err := s.JSONRPCCall("debug_getRawBlock", "0x1")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var rawBlock types.ArgBytes
+	require.NoError(t, json.Unmarshal(res.Result, &rawBlock))
+	var decodedBlock ethTypes.Block
+	require.NoError(t, rlp.DecodeBytes(rawBlock, &decodedBlock))
+	assert.Equal(t, block.Hash(), decodedBlock.Hash())
+	require.Len(t, decodedBlock.Transactions(), 1)
+	assert.Equal(t, tx.Hash(), decodedBlock.Transactions()[0].Hash())
+
+	res, err = s.JSONRPCCall("debug_getRawReceipts", "0x1")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var rawReceipts []types.ArgBytes
+	require.NoError(t, json.Unmarshal(res.Result, &rawReceipts))
+	require.Len(t, rawReceipts, 1)
+	expectedReceipt, err := receipt.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, types.ArgBytes(expectedReceipt), rawReceipts[0])
+}
+
+func TestGetRawTransaction(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := ethTypes.NewTx(&ethTypes.DynamicFeeTx{Nonce: 1, To: state.HexToAddressPtr("0x2"), Value: big.NewInt(1), GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)})
+	unknownHash := common.HexToHash("0x1")
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.On("GetTransactionByHash", context.Background(), tx.Hash(), m.DbTx).Return(tx, nil).Once()
+	m.State.On("GetTransactionByHash", context.Background(), unknownHash, m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+	res, err := s.JSONRPCCall("debug_getRawTransaction", tx.Hash().String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var rawTx types.ArgBytes
+	require.NoError(t, json.Unmarshal(res.Result, &rawTx))
+	expectedTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, types.ArgBytes(expectedTx), rawTx)
+
+	res, err = s.JSONRPCCall("debug_getRawTransaction", unknownHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
+
+func TestGetRawBatch(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	newTx := func(nonce uint64) *ethTypes.Transaction {
+		return ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: nonce, To: state.HexToAddressPtr("0x2"), Value: big.NewInt(1), GasPrice: big.NewInt(1), V: big.NewInt(27), R: big.NewInt(1), S: big.NewInt(1)})
+	}
+	newBlock := func(number, timestamp uint64, txs ...*ethTypes.Transaction) *state.L2Block {
+		receipts := make([]*ethTypes.Receipt, 0, len(txs))
+		for range txs {
+			receipts = append(receipts, ethTypes.NewReceipt([]byte{}, false, 0))
+		}
+		return state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(0).SetUint64(number), Time: timestamp}), txs, nil, receipts, trie.NewStackTrie(nil))
+	}
+	tx1, tx2, tx3, tx4 := newTx(1), newTx(2), newTx(3), newTx(4)
+
+	// the multi block batch keeps the indexes of the L1 info tree of the stored data
+	storedBatchL2Data, err := state.EncodeBatchV2(&state.BatchRawV2{Blocks: []state.L2BlockRaw{
+		{DeltaTimestamp: 2, IndexL1InfoTree: 1, Transactions: []state.L2TxRaw{{Tx: *tx1, EfficiencyPercentage: 255}}},
+		{DeltaTimestamp: 3, IndexL1InfoTree: 0, Transactions: []state.L2TxRaw{{Tx: *tx2, EfficiencyPercentage: 200}, {Tx: *tx3, EfficiencyPercentage: 255}}},
+	}})
+	require.NoError(t, err)
+	m.State.On("GetBatchByNumber", context.Background(), uint64(10), m.DbTx).Return(&state.Batch{BatchNumber: 10, BatchL2Data: storedBatchL2Data}, nil).Once()
+	m.State.On("GetForkIDByBatchNumber", uint64(10)).Return(uint64(state.FORKID_ETROG)).Once()
+	m.State.On("GetL2BlocksByBatchNumber", context.Background(), uint64(10), m.DbTx).Return([]state.L2Block{*newBlock(5, 102, tx1), *newBlock(6, 105, tx2, tx3)}, nil).Once()
+	m.State.On("GetTransactionsByBatchNumber", context.Background(), uint64(10), m.DbTx).Return([]ethTypes.Transaction{*tx1, *tx3, *tx2}, []uint8{255, 255, 200}, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(4), m.DbTx).Return(newBlock(4, 100), nil).Once()
+
+	// the forced batch is stored without the changeL2Block markers
+	forcedBatchL2Data, err := state.EncodeTransactions([]ethTypes.Transaction{*tx4}, []uint8{255}, state.FORKID_ETROG)
+	require.NoError(t, err)
+	m.State.On("GetBatchByNumber", context.Background(), uint64(11), m.DbTx).Return(&state.Batch{BatchNumber: 11, BatchL2Data: forcedBatchL2Data, ForcedBatchNum: state.Ptr(uint64(1))}, nil).Once()
+	m.State.On("GetForkIDByBatchNumber", uint64(11)).Return(uint64(state.FORKID_ETROG)).Once()
+	m.State.On("GetL2BlocksByBatchNumber", context.Background(), uint64(11), m.DbTx).Return([]state.L2Block{*newBlock(7, 110, tx4)}, nil).Once()
+	m.State.On("GetTransactionsByBatchNumber", context.Background(), uint64(11), m.DbTx).Return([]ethTypes.Transaction{*tx4}, []uint8{255}, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(6), m.DbTx).Return(newBlock(6, 105, tx2, tx3), nil).Once()
+
+	m.State.On("GetBatchByNumber", context.Background(), uint64(1), m.DbTx).Return(&state.Batch{BatchNumber: 1}, nil).Once()
+	m.State.On("GetForkIDByBatchNumber", uint64(1)).Return(uint64(state.FORKID_INCABERRY)).Once()
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(3)
+
+	res, err := s.JSONRPCCall("debug_getRawBatch", "0xa")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var rawBatch types.ArgBytes
+	require.NoError(t, json.Unmarshal(res.Result, &rawBatch))
+	assert.Equal(t, types.ArgBytes(storedBatchL2Data), rawBatch)
+
+	res, err = s.JSONRPCCall("debug_getRawBatch", "0xb")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, json.Unmarshal(res.Result, &rawBatch))
+	decoded, err := state.DecodeBatchV2(rawBatch)
+	require.NoError(t, err)
+	require.Len(t, decoded.Blocks, 1)
+	assert.Equal(t, uint32(5), decoded.Blocks[0].DeltaTimestamp)
+	assert.Equal(t, uint32(0), decoded.Blocks[0].IndexL1InfoTree)
+	require.Len(t, decoded.Blocks[0].Transactions, 1)
+	assert.Equal(t, tx4.Hash(), decoded.Blocks[0].Transactions[0].Tx.Hash())
+	assert.Equal(t, uint8(255), decoded.Blocks[0].Transactions[0].EfficiencyPercentage)
+
+	res, err = s.JSONRPCCall("debug_getRawBatch", "0x1")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+}
@@ -36,13 +36,17 @@ type EthEndpoints struct {
 	pool     types.PoolInterface
 	state    types.StateInterface
 	etherman types.EthermanInterface
-	storage  storageInterface
+	storage  FilterStorage
 	txMan    DBTxManager
+
+	feeHistoryCache *feeHistoryCache
+	logsHistory     *logsHistory
 }
 
 // NewEthEndpoints creates an new instance of Eth
-func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.StateInterface, etherman types.EthermanInterface, storage storageInterface) *EthEndpoints {
-	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage}
+func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.StateInterface, etherman types.EthermanInterface, storage FilterStorage) *EthEndpoints {
+	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage,
+		feeHistoryCache: newFeeHistoryCache(cfg.FeeHistoryCacheSize), logsHistory: newLogsHistory(logsHistorySize)}
 	s.RegisterNewL2BlockEventHandler(e.onNewL2Block)
 
 	return e
@@ -64,13 +68,17 @@ func (e *EthEndpoints) BlockNumber() (interface{}, types.Error) {
 // executed contract and potential error.
 // Note, this function doesn't make any changes in the state/blockchain and is
 // useful to execute view/pure methods and retrieve values.
-func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverride *types.StateOverride, blockOverrides *types.BlockOverrides) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		} else if blockArg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 1", nil, false)
 		}
+		stateOverrideToApply, blockOverrideToApply, respErr := getCallOverrides(stateOverride, blockOverrides)
+		if respErr != nil {
+			return nil, respErr
+		}
 		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
 		if respErr != nil {
 			return nil, respErr
@@ -103,7 +111,7 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
 		}
 
-		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, dbTx)
+		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, stateOverrideToApply, blockOverrideToApply, dbTx)
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to execute the unsigned transaction: %v", err.Error())
 			logError := !executor.IsROMOutOfCountersError(executor.RomErrorCode(err)) && !(errors.Is(err, runtime.ErrOutOfGas) || errors.Is(err, runtime.ErrExecutorErrorOOG2))
@@ -122,6 +130,57 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 	})
 }
 
+// CreateAccessList creates the access list of the given transaction executing it
+// in the state of the given block. As the current forks don't support typed
+// transactions, the result reports it in its error field
+func (e *EthEndpoints) CreateAccessList(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if arg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+		if blockArg == nil {
+			blockArg = &types.BlockNumberOrHash{}
+			blockArg.SetNumber(types.LatestBlockNumber)
+		}
+		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+		var blockToProcess *uint64
+		blockNumArg := blockArg.Number()
+		if blockNumArg == nil || (*blockNumArg != types.LatestBlockNumber && *blockNumArg != types.PendingBlockNumber) {
+			n := block.NumberU64()
+			blockToProcess = &n
+		}
+
+		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+		if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
+			header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
+			}
+
+			gas := types.ArgUint64(header.GasLimit)
+			arg.Gas = &gas
+		}
+
+		defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
+		sender, tx, err := arg.ToTransaction(ctx, e.state, state.MaxTxGasLimit, block.Root(), defaultSenderAddress, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		}
+
+		result, err := e.state.CreateAccessList(ctx, tx, sender, blockToProcess, dbTx)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to execute the unsigned transaction: %v", err.Error())
+			logError := !executor.IsROMOutOfCountersError(executor.RomErrorCode(err)) && !(errors.Is(err, runtime.ErrOutOfGas) || errors.Is(err, runtime.ErrExecutorErrorOOG2))
+			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, logError)
+		}
+
+		return types.NewAccessListResult(result), nil
+	})
+}
+
 // ChainId returns the chain id of the client
 func (e *EthEndpoints) ChainId() (interface{}, types.Error) { //nolint:revive
 	return hex.EncodeUint64(e.chainID), nil
@@ -159,11 +218,15 @@ func (e *EthEndpoints) getCoinbaseFromSequencerNode() (interface{}, types.Error)
 // Note that the estimate may be significantly more than the amount of gas actually
 // used by the transaction, for a variety of reasons including EVM mechanics and
 // node performance.
-func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverride *types.StateOverride, blockOverrides *types.BlockOverrides) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		}
+		stateOverrideToApply, blockOverrideToApply, respErr := getCallOverrides(stateOverride, blockOverrides)
+		if respErr != nil {
+			return nil, respErr
+		}
 
 		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
 		if respErr != nil {
@@ -187,7 +250,7 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
 		}
 
-		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, dbTx)
+		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, stateOverrideToApply, blockOverrideToApply, dbTx)
 		if errors.Is(err, runtime.ErrExecutionReverted) {
 			data := make([]byte, len(returnValue))
 			copy(data, returnValue)
@@ -199,6 +262,19 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 	})
 }
 
+// getCallOverrides converts and validates the state and block overrides of a call
+func getCallOverrides(stateOverride *types.StateOverride, blockOverrides *types.BlockOverrides) (state.StateOverride, *state.BlockOverride, types.Error) {
+	stateOverrideToApply := stateOverride.ToStateOverride()
+	if err := stateOverrideToApply.Validate(); err != nil {
+		return nil, nil, types.NewRPCError(types.InvalidParamsErrorCode, err.Error())
+	}
+	blockOverrideToApply := blockOverrides.ToBlockOverride()
+	if err := blockOverrideToApply.Validate(); err != nil {
+		return nil, nil, types.NewRPCError(types.InvalidParamsErrorCode, err.Error())
+	}
+	return stateOverrideToApply, blockOverrideToApply, nil
+}
+
 // GasPrice returns the average gas price based on the last x blocks
 func (e *EthEndpoints) GasPrice() (interface{}, types.Error) {
 	ctx := context.Background()
@@ -267,9 +343,14 @@ func (e *EthEndpoints) GetBalance(address types.ArgAddress, blockArg *types.Bloc
 }
 
 func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, types.Error) {
+	return getL2BlockByArg(ctx, e.state, e.etherman, blockArg, dbTx)
+}
+
+// getL2BlockByArg returns the L2 block identified by the block number or hash argument
+func getL2BlockByArg(ctx context.Context, st types.StateInterface, etherman types.EthermanInterface, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (*state.L2Block, types.Error) {
 	// If no block argument is provided, return the latest block
 	if blockArg == nil {
-		block, err := e.state.GetLastL2Block(ctx, dbTx)
+		block, err := st.GetLastL2Block(ctx, dbTx)
 		if err != nil {
 			return nil, types.NewRPCError(types.DefaultErrorCode, "failed to get the last block number from state")
 		}
@@ -278,7 +359,7 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 
 	// If we have a block hash, try to get the block by hash
 	if blockArg.IsHash() {
-		block, err := e.state.GetL2BlockByHash(ctx, blockArg.Hash().Hash(), dbTx)
+		block, err := st.GetL2BlockByHash(ctx, blockArg.Hash().Hash(), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
 			return nil, types.NewRPCError(types.DefaultErrorCode, "header for hash not found")
 		} else if err != nil {
@@ -288,11 +369,11 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 	}
 
 	// Otherwise, try to get the block by number
-	blockNum, rpcErr := blockArg.Number().GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+	blockNum, rpcErr := blockArg.Number().GetNumericBlockNumber(ctx, st, etherman, dbTx)
 	if rpcErr != nil {
 		return nil, rpcErr
 	}
-	block, err := e.state.GetL2BlockByNumber(context.Background(), blockNum, dbTx)
+	block, err := st.GetL2BlockByNumber(context.Background(), blockNum, dbTx)
 	if errors.Is(err, state.ErrNotFound) || block == nil {
 		return nil, types.NewRPCError(types.DefaultErrorCode, "header not found")
 	} else if err != nil {
@@ -568,6 +649,30 @@ func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr stri
 	})
 }
 
+// GetProof returns the state tree proofs of the balance, nonce, code hash and the
+// requested storage slots of an account. The proofs follow the format of the zkEVM
+// sparse merkle tree and can be verified with merkletree.VerifyAccountProof
+func (e *EthEndpoints) GetProof(address types.ArgAddress, storageKeys []types.ArgHash, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		keys := make([]common.Hash, 0, len(storageKeys))
+		for _, storageKey := range storageKeys {
+			keys = append(keys, storageKey.Hash())
+		}
+
+		proof, err := e.state.GetAccountProof(ctx, address.Address(), keys, block.Root())
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get account proof from state", err, true)
+		}
+
+		return proof, nil
+	})
+}
+
 // GetTransactionByBlockHashAndIndex returns information about a transaction by
 // block hash and transaction index position.
 func (e *EthEndpoints) GetTransactionByBlockHashAndIndex(hash types.ArgHash, index types.Index, includeExtraInfo *bool) (interface{}, types.Error) {
@@ -689,7 +794,7 @@ func (e *EthEndpoints) GetTransactionByHash(hash types.ArgHash, includeExtraInfo
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to load transaction by hash from pool", err, true)
 		}
-		if poolTx.Status == pool.TxStatusPending {
+		if poolTx.Status == pool.TxStatusPending || poolTx.Status == pool.TxStatusQueued {
 			tx = &poolTx.Transaction
 			res, err := types.NewTransaction(*tx, nil, false, nil)
 			if err != nil {
@@ -871,6 +976,59 @@ func (e *EthEndpoints) GetTransactionReceipt(hash types.ArgHash) (interface{}, t
 	})
 }
 
+// GetBlockReceipts returns the receipts of all the transactions of a block,
+// loading them at once instead of one by one
+func (e *EthEndpoints) GetBlockReceipts(blockArg *types.BlockNumberOrHash, includeExtraInfo *bool) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if blockArg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		}
+		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		rs, err := e.state.GetTransactionReceiptsByL2BlockNumber(ctx, block.NumberU64(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", block.NumberU64()), err, true)
+		}
+
+		var l2Hashes map[common.Hash]common.Hash
+		if includeExtraInfo != nil && *includeExtraInfo {
+			l2Hashes, err = e.state.GetL2TxHashesByL2BlockNumber(ctx, block.NumberU64(), dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load l2 tx hashes for block %v", block.NumberU64()), err, true)
+			}
+		}
+
+		txs := make(map[common.Hash]*ethTypes.Transaction, len(block.Transactions()))
+		for _, tx := range block.Transactions() {
+			txs[tx.Hash()] = tx
+		}
+
+		receipts := make([]types.Receipt, 0, len(rs))
+		for _, r := range rs {
+			tx, found := txs[r.TxHash]
+			if !found {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't find tx %v in block %v", r.TxHash.String(), block.NumberU64()), nil, true)
+			}
+
+			var l2Hash *common.Hash
+			if h, found := l2Hashes[r.TxHash]; found {
+				l2Hash = &h
+			}
+
+			receipt, err := types.NewReceipt(*tx, r, l2Hash)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to build the receipt response", err, true)
+			}
+			receipts = append(receipts, receipt)
+		}
+
+		return receipts, nil
+	})
+}
+
 // NewBlockFilter creates a filter in the node, to notify when
 // a new block arrives. To check if the state has changed,
 // call eth_getFilterChanges.
@@ -906,6 +1064,11 @@ func (e *EthEndpoints) newFilter(ctx context.Context, wsConn *concurrentWsConn,
 		}
 	}
 
+	return e.installLogFilter(wsConn, filter)
+}
+
+// installLogFilter persists the log filter and returns its id
+func (e *EthEndpoints) installLogFilter(wsConn *concurrentWsConn, filter LogFilter) (interface{}, types.Error) {
 	id, err := e.storage.NewLogFilter(wsConn, filter)
 	if errors.Is(err, ErrFilterInvalidPayload) {
 		return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
@@ -1113,7 +1276,7 @@ func (e *EthEndpoints) Subscribe(wsConn *concurrentWsConn, name string, logFilte
 			if logFilter != nil {
 				lf = *logFilter
 			}
-			return e.newFilter(ctx, wsConn, lf, dbTx)
+			return e.subscribeLogs(ctx, wsConn, lf, dbTx)
 		})
 	case "pendingTransactions", "newPendingTransactions":
 		return e.newPendingTransactionFilter(wsConn)
@@ -1186,6 +1349,16 @@ func (e *EthEndpoints) notifyNewLogs(wg *sync.WaitGroup, event state.NewL2BlockE
 	defer wg.Done()
 	start := time.Now()
 
+	// the blocks replaced by a trusted state reorg are only detected when a
+	// block above them is added, then their logs are notified as removed and
+	// the logs of the new blocks are notified before the ones of the event
+	removed, added, err := e.logsHistory.add(context.Background(), e.state, event)
+	if err != nil {
+		log.Errorf("failed to check the logs history for block %v: %v", event.Block.NumberU64(), err)
+		removed, added = nil, []state.NewL2BlockEvent{event}
+	}
+	log.Debugf("[notifyNewLogs] took %v to add the block to the logs history", time.Since(start))
+
 	filters := e.storage.GetAllLogFiltersWithWSConn()
 	log.Debugf("[notifyNewLogs] took %v to get log filters with ws connections", time.Since(start))
 
@@ -1193,26 +1366,26 @@ func (e *EthEndpoints) notifyNewLogs(wg *sync.WaitGroup, event state.NewL2BlockE
 	parallelize(maxWorkers, filters, func(worker int, filters []*Filter) {
 		for _, filter := range filters {
 			f := filter
-			start := time.Now()
-			if e.shouldSkipLogFilter(event, filter) {
-				return
+			if len(removed) > 0 {
+				f.enqueueRemovedLogs(e.filterRemovedLogs(removed, f), removed[0].Block.NumberU64())
 			}
-			log.Debugf("[notifyNewLogs] took %v to check if should skip log filter", time.Since(start))
-
-			start = time.Now()
-			// get new logs for this specific filter
-			logs := filterLogs(event.Logs, filter)
-			log.Debugf("[notifyNewLogs] took %v to filter logs", time.Since(start))
 
-			start = time.Now()
-			for _, l := range logs {
-				data, err := json.Marshal(l)
-				if err != nil {
-					log.Errorf("failed to marshal ethLog response to subscription: %v", err)
+			for _, a := range added {
+				start := time.Now()
+				if e.shouldSkipLogFilter(a, f) {
+					continue
 				}
-				f.EnqueueSubscriptionDataToBeSent(data)
+				log.Debugf("[notifyNewLogs] took %v to check if should skip log filter", time.Since(start))
+
+				start = time.Now()
+				// get new logs for this specific filter
+				logs := filterLogs(a.Logs, f)
+				log.Debugf("[notifyNewLogs] took %v to filter logs", time.Since(start))
+
+				start = time.Now()
+				f.enqueueLogs(logs)
+				log.Debugf("[notifyNewLogs] took %v to enqueue log messages", time.Since(start))
 			}
-			log.Debugf("[notifyNewLogs] took %v to enqueue log messages", time.Since(start))
 		}
 	})
 
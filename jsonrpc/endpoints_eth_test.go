@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
@@ -22,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -43,6 +46,8 @@ var (
 	blockHash         = common.HexToHash("0x82ba516e76a4bfaba6d1d95c8ccde96e353ce3c683231d011021f43dee7b2d95")
 	blockRoot         = common.HexToHash("0xce3c683231d011021f43dee7b2d9582ba516e76a4bfaba6d1d95c8ccde96e353")
 	nilUint64         *uint64
+	nilStateOverride  state.StateOverride
+	nilBlockOverride  *state.BlockOverride
 )
 
 func TestBlockNumber(t *testing.T) {
@@ -175,7 +180,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -219,7 +224,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -261,7 +266,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -303,7 +308,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -343,7 +348,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -382,7 +387,7 @@ func TestCall(t *testing.T) {
 				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -421,7 +426,7 @@ func TestCall(t *testing.T) {
 				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -485,7 +490,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{Err: errors.New("failed to process unsigned transaction")}, nil).
 					Once()
 			},
@@ -527,11 +532,85 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(&runtime.ExecutionResult{Err: runtime.ErrExecutionReverted}, nil).
 					Once()
 			},
 		},
+		{
+			name: "Transaction with state and block overrides",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+					Data: types.ArgBytesPtr([]byte("data")),
+				},
+				latest,
+				map[string]interface{}{
+					common.HexToAddress("0x2").String(): map[string]interface{}{
+						"balance":   "0x10",
+						"nonce":     "0x3",
+						"code":      "0x6001",
+						"stateDiff": map[string]interface{}{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+					},
+				},
+				map[string]interface{}{
+					"number": "0x64",
+					"time":   "0x65",
+				},
+			},
+			expectedResult: []byte("hello world"),
+			expectedError:  nil,
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(blockNumOne.Uint64(), nil).Once()
+				txArgs := testCase.params[0].(types.TxArgs)
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				expectedNonce, expectedNumber, expectedTime := uint64(3), uint64(100), uint64(101)
+				expectedStateOverride := state.StateOverride{
+					common.HexToAddress("0x2"): state.OverrideAccount{
+						Nonce:     &expectedNonce,
+						Code:      []byte{0x60, 0x01},
+						Balance:   big.NewInt(16),
+						StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")},
+					},
+				}
+				expectedBlockOverride := &state.BlockOverride{Number: &expectedNumber, Time: &expectedTime}
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), mock.Anything, *txArgs.From, nilUint64, true, expectedStateOverride, expectedBlockOverride, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Transaction with state and stateDiff overrides for the same account",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+					Gas:  types.ArgUint64Ptr(24000),
+					Data: types.ArgBytesPtr([]byte("data")),
+				},
+				latest,
+				map[string]interface{}{
+					common.HexToAddress("0x2").String(): map[string]interface{}{
+						"state":     map[string]interface{}{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+						"stateDiff": map[string]interface{}{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+					},
+				},
+			},
+			expectedResult: nil,
+			expectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "account 0x0000000000000000000000000000000000000002 has both state and stateDiff overrides"),
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -689,7 +768,7 @@ func TestEstimateGas(t *testing.T) {
 					Return(nonce, nil).
 					Once()
 				m.State.
-					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
@@ -730,7 +809,7 @@ func TestEstimateGas(t *testing.T) {
 				m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
 
 				m.State.
-					On("EstimateGas", txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, common.HexToAddress(state.DefaultSenderAddress), nilUint64, nilStateOverride, nilBlockOverride, m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
@@ -800,6 +879,71 @@ func TestGasPrice(t *testing.T) {
 	}
 }
 
+func TestMaxPriorityFeePerGas(t *testing.T) {
+	s, m, c := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	m.Pool.
+		On("GetGasPrices", context.Background()).
+		Return(pool.GasPrices{L2GasPrice: 50, L1GasPrice: 100}, nil).
+		Once()
+
+	tipCap, err := c.SuggestGasTipCap(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50), tipCap.Uint64())
+}
+
+func TestFeeHistory(t *testing.T) {
+	s, m, c := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	emptyBlock := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumTen, GasUsed: 0}))
+
+	txs := []*ethTypes.Transaction{}
+	receipts := []*ethTypes.Receipt{}
+	for i, gasPrice := range []int64{3, 1, 2} {
+		tx := ethTypes.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(0), 21000, big.NewInt(gasPrice*10), nil)
+		txs = append(txs, tx)
+		receipts = append(receipts, &ethTypes.Receipt{TxHash: tx.Hash(), GasUsed: 21000, EffectiveGasPrice: big.NewInt(gasPrice)})
+	}
+	header := &ethTypes.Header{Number: big.NewInt(11), GasUsed: 63000}
+	block := state.NewL2Block(state.NewL2Header(header), txs, nil, receipts, trie.NewStackTrie(nil))
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.On("GetL2BlockByNumber", context.Background(), blockNumTen.Uint64(), m.DbTx).Return(emptyBlock, nil).Twice()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(11), m.DbTx).Return(block, nil).Twice()
+	// the receipts are loaded only once, the second call uses the cached fee data
+	m.State.On("GetTransactionReceiptsByL2BlockNumber", context.Background(), uint64(11), m.DbTx).Return(receipts, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		feeHistory, err := c.FeeHistory(context.Background(), 2, big.NewInt(11), []float64{0, 50, 100})
+		require.NoError(t, err)
+
+		assert.Equal(t, blockNumTen.Uint64(), feeHistory.OldestBlock.Uint64())
+		assert.Equal(t, []float64{0, 0.21}, feeHistory.GasUsedRatio)
+		rewards := [][]uint64{}
+		for _, blockRewards := range feeHistory.Reward {
+			rewards = append(rewards, bigsToUint64s(blockRewards))
+		}
+		assert.Equal(t, [][]uint64{{0, 0, 0}, {1, 2, 3}}, rewards)
+		assert.Equal(t, []uint64{0, 0, 0}, bigsToUint64s(feeHistory.BaseFee))
+	}
+
+	res, err := s.JSONRPCCall("eth_feeHistory", "0x2", "latest", []float64{50, 10})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func bigsToUint64s(values []*big.Int) []uint64 {
+	result := make([]uint64, 0, len(values))
+	for _, value := range values {
+		result = append(result, value.Uint64())
+	}
+	return result
+}
+
 func TestGetBalance(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -1996,6 +2140,259 @@ func TestGetStorageAt(t *testing.T) {
 	}
 }
 
+func TestGetProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult *merkletree.AccountProof
+		ExpectedError  *types.RPCError
+
+		SetupMocks func(m *mocksWrapper, tc *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name: "failed to get account proof",
+			Params: []interface{}{
+				addressArg.String(),
+				[]string{keyArg.String()},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get account proof from state"),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				blockNumber := big.NewInt(1)
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumber, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetAccountProof", context.Background(), addressArg, []common.Hash{keyArg}, blockRoot).
+					Return(nil, errors.New("failed to get account proof")).
+					Once()
+			},
+		},
+		{
+			Name: "get proof successfully",
+			Params: []interface{}{
+				addressArg.String(),
+				[]string{keyArg.String()},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			ExpectedResult: &merkletree.AccountProof{
+				Address:   addressArg,
+				StateRoot: blockRoot,
+				Balance:   (*hexutil.Big)(big.NewInt(1000)),
+				Nonce:     (*hexutil.Big)(big.NewInt(1)),
+				BalanceProof: merkletree.SMTProof{
+					Key:      common.HexToHash("0x1"),
+					Value:    (*hexutil.Big)(big.NewInt(1000)),
+					Siblings: []hexutil.Bytes{common.FromHex("0x01")},
+					InsValue: (*hexutil.Big)(big.NewInt(0)),
+				},
+				StorageProof: []merkletree.StorageProof{{
+					Key:   keyArg,
+					Value: (*hexutil.Big)(big.NewInt(123)),
+				}},
+			},
+			ExpectedError: nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				blockNumber := big.NewInt(1)
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumber, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetAccountProof", context.Background(), addressArg, []common.Hash{keyArg}, blockRoot).
+					Return(tc.ExpectedResult, nil).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, &tc)
+			res, err := s.JSONRPCCall("eth_getProof", tc.Params...)
+			require.NoError(t, err)
+			if tc.ExpectedResult != nil {
+				require.NotNil(t, res.Result)
+				require.Nil(t, res.Error)
+
+				var proof merkletree.AccountProof
+				err = json.Unmarshal(res.Result, &proof)
+				require.NoError(t, err)
+				assert.Equal(t, tc.ExpectedResult.Address, proof.Address)
+				assert.Equal(t, tc.ExpectedResult.StateRoot, proof.StateRoot)
+				assert.Equal(t, tc.ExpectedResult.Balance.String(), proof.Balance.String())
+				assert.Equal(t, tc.ExpectedResult.Nonce.String(), proof.Nonce.String())
+				assert.Equal(t, tc.ExpectedResult.BalanceProof.Siblings, proof.BalanceProof.Siblings)
+				require.Len(t, proof.StorageProof, 1)
+				assert.Equal(t, tc.ExpectedResult.StorageProof[0].Key, proof.StorageProof[0].Key)
+				assert.Equal(t, tc.ExpectedResult.StorageProof[0].Value.String(), proof.StorageProof[0].Value.String())
+			}
+
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestCreateAccessList(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txArgs := types.TxArgs{
+		From: state.HexToAddressPtr("0x1"),
+		To:   state.HexToAddressPtr("0x2"),
+		Gas:  types.ArgUint64Ptr(24000),
+		Data: types.ArgBytesPtr([]byte("data")),
+	}
+	accessList := ethTypes.AccessList{{Address: common.HexToAddress("0x3"), StorageKeys: []common.Hash{common.HexToHash("0x1")}}}
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult *types.AccessListResult
+		ExpectedError  *types.RPCError
+
+		SetupMocks func(m *mocksWrapper, tc *testCase)
+	}
+
+	setupBlock := func(m *mocksWrapper) {
+		m.State.
+			On("BeginStateTransaction", context.Background()).
+			Return(m.DbTx, nil).
+			Once()
+
+		block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+		m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+		m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(uint64(7), nil).Once()
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "failed to execute the transaction",
+			Params:         []interface{}{txArgs, hex.EncodeBig(blockNumOne)},
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to execute the unsigned transaction: failed to process"),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+				setupBlock(m)
+
+				m.State.
+					On("CreateAccessList", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, m.DbTx).
+					Return(nil, errors.New("failed to process")).
+					Once()
+			},
+		},
+		{
+			Name:   "create access list successfully",
+			Params: []interface{}{txArgs, hex.EncodeBig(blockNumOne)},
+			ExpectedResult: &types.AccessListResult{
+				AccessList: accessList,
+				GasUsed:    types.ArgUint64(21000),
+				Notice:     "access list txs are not supported by fork id 7, the access list can't be included in a transaction",
+			},
+			ExpectedError: nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+				setupBlock(m)
+
+				m.State.
+					On("CreateAccessList", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, m.DbTx).
+					Return(&state.AccessListResult{AccessList: accessList, GasUsed: 21000, ForkID: state.FORKID_ETROG}, nil).
+					Once()
+			},
+		},
+		{
+			Name:   "create access list of a reverted transaction",
+			Params: []interface{}{txArgs, hex.EncodeBig(blockNumOne)},
+			ExpectedResult: &types.AccessListResult{
+				AccessList: ethTypes.AccessList{},
+				Error:      "execution reverted",
+				GasUsed:    types.ArgUint64(22000),
+				Notice:     "access list txs are not supported by fork id 7, the access list can't be included in a transaction",
+			},
+			ExpectedError: nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+				setupBlock(m)
+
+				m.State.
+					On("CreateAccessList", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, m.DbTx).
+					Return(&state.AccessListResult{AccessList: ethTypes.AccessList{}, GasUsed: 22000, Err: runtime.ErrExecutionReverted, ForkID: state.FORKID_ETROG}, nil).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, &tc)
+			res, err := s.JSONRPCCall("eth_createAccessList", tc.Params...)
+			require.NoError(t, err)
+			if tc.ExpectedResult != nil {
+				require.NotNil(t, res.Result)
+				require.Nil(t, res.Error)
+
+				var result types.AccessListResult
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.ExpectedResult, result)
+			}
+
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
 func TestGetCompilers(t *testing.T) {
 	s, _, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -3539,6 +3936,129 @@ func TestGetTransactionReceipt(t *testing.T) {
 	}
 }
 
+func TestGetBlockReceipts(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	chainID := big.NewInt(1)
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	blockHash := common.HexToHash("0x1")
+	txs := make([]*ethTypes.Transaction, 0, 2)
+	receipts := make([]*ethTypes.Receipt, 0, 2)
+	for i := 0; i < 2; i++ {
+		tx := ethTypes.NewTransaction(uint64(i), common.HexToAddress("0x111"), big.NewInt(2), 21000, big.NewInt(4), []byte{})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		txs = append(txs, signedTx)
+
+		receipt := &ethTypes.Receipt{
+			Type:              signedTx.Type(),
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			BlockNumber:       blockNumOne,
+			BlockHash:         blockHash,
+			GasUsed:           21000,
+			TxHash:            signedTx.Hash(),
+			TransactionIndex:  uint(i),
+			Logs:              []*ethTypes.Log{{TxHash: signedTx.Hash(), Index: uint(i), Topics: []common.Hash{common.HexToHash("0x1")}, Data: []byte{}}},
+			Status:            ethTypes.ReceiptStatusSuccessful,
+			EffectiveGasPrice: big.NewInt(4),
+		}
+		receipt.Bloom = ethTypes.CreateBloom(ethTypes.Receipts{receipt})
+		receipts = append(receipts, receipt)
+	}
+	block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: blockNumOne}), txs, nil, receipts, trie.NewStackTrie(nil))
+	l2Hash := common.HexToHash("0x987654321")
+
+	type testCase struct {
+		Name             string
+		Params           []interface{}
+		IncludeExtraInfo bool
+		ExpectedError    *types.RPCError
+		SetupMocks       func(m *mocksWrapper, tc testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:   "Get block receipts successfully",
+			Params: []interface{}{hex.EncodeBig(blockNumOne)},
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetTransactionReceiptsByL2BlockNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(receipts, nil).Once()
+			},
+		},
+		{
+			Name:             "Get block receipts with extra info successfully",
+			Params:           []interface{}{hex.EncodeBig(blockNumOne), true},
+			IncludeExtraInfo: true,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetTransactionReceiptsByL2BlockNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(receipts, nil).Once()
+				m.State.
+					On("GetL2TxHashesByL2BlockNumber", context.Background(), blockNumOneUint64, m.DbTx).
+					Return(map[common.Hash]common.Hash{txs[0].Hash(): l2Hash, txs[1].Hash(): l2Hash}, nil).
+					Once()
+			},
+		},
+		{
+			Name:          "Get block receipts fails to load the receipts",
+			Params:        []interface{}{hex.EncodeBig(blockNumOne)},
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load receipts for block 1"),
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetTransactionReceiptsByL2BlockNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(nil, errors.New("failed to load")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, tc)
+
+			res, err := s.JSONRPCCall("eth_getBlockReceipts", tc.Params...)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result []types.Receipt
+			err = json.Unmarshal(res.Result, &result)
+			require.NoError(t, err)
+			require.Len(t, result, len(receipts))
+			for i, receipt := range result {
+				assert.Equal(t, receipts[i].TxHash, receipt.TxHash)
+				assert.Equal(t, types.ArgUint64(receipts[i].TransactionIndex), receipt.TxIndex)
+				assert.Equal(t, types.ArgUint64(receipts[i].CumulativeGasUsed), receipt.CumulativeGasUsed)
+				assert.Equal(t, receipts[i].Bloom, receipt.LogsBloom)
+				assert.Equal(t, auth.From, receipt.FromAddr)
+				assert.Equal(t, txs[i].To(), receipt.ToAddr)
+				require.Len(t, receipt.Logs, 1)
+				assert.Equal(t, receipts[i].Logs[0].TxHash, receipt.Logs[0].TxHash)
+				if tc.IncludeExtraInfo {
+					assert.Equal(t, &l2Hash, receipt.TxL2Hash)
+				} else {
+					assert.Nil(t, receipt.TxL2Hash)
+				}
+			}
+		})
+	}
+}
+
 func TestSendRawTransactionViaGeth(t *testing.T) {
 	s, m, c := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -5291,6 +5811,129 @@ func TestSubscribeNewLogs(t *testing.T) {
 	}
 }
 
+func TestSubscribeNewLogsBackfill(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	var onNewL2Block state.NewL2BlockEventHandler
+	for _, call := range m.State.Calls {
+		if call.Method == "RegisterNewL2BlockEventHandler" {
+			onNewL2Block = call.Arguments.Get(0).(state.NewL2BlockEventHandler)
+		}
+	}
+	require.NotNil(t, onNewL2Block)
+
+	// the subscriptions are kept by a real storage so the logs reach the ws connection
+	storage := NewStorage()
+	m.Storage.
+		On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
+		Return(func(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+			return storage.NewLogFilter(wsConn, filter)
+		}).
+		Once()
+	m.Storage.
+		On("GetFilter", mock.IsType("")).
+		Return(func(filterID string) (*Filter, error) {
+			return storage.GetFilter(filterID)
+		})
+	m.Storage.
+		On("GetAllLogFiltersWithWSConn").
+		Return(func() []*Filter {
+			return storage.GetAllLogFiltersWithWSConn()
+		})
+	m.Storage.
+		On("GetAllBlockFiltersWithWSConn").
+		Return([]*Filter{})
+	m.Storage.
+		On("UninstallFilter", mock.IsType("")).
+		Return(func(filterID string) error {
+			return storage.UninstallFilter(filterID)
+		}).
+		Once()
+
+	m.DbTx.
+		On("Commit", context.Background()).
+		Return(nil).
+		Once()
+	m.State.
+		On("BeginStateTransaction", context.Background()).
+		Return(m.DbTx, nil).
+		Once()
+
+	var lastBlockNumber atomic.Uint64
+	lastBlockNumber.Store(2)
+	m.State.
+		On("GetLastL2BlockNumber", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+			return lastBlockNumber.Load(), nil
+		})
+
+	address := common.HexToAddress("0x1")
+	newBlock := func(number uint64, parentHash common.Hash, fork byte) *state.L2Block {
+		header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(0).SetUint64(number), ParentHash: parentHash, Extra: []byte{fork}})
+		return state.NewL2BlockWithHeader(header)
+	}
+	newLog := func(block *state.L2Block, index uint) *ethTypes.Log {
+		return &ethTypes.Log{Address: address, Topics: []common.Hash{}, Data: []byte{}, BlockNumber: block.NumberU64(), BlockHash: block.Hash(), Index: index}
+	}
+
+	b1 := newBlock(1, common.Hash{}, 0)
+	b2 := newBlock(2, b1.Hash(), 0)
+	b3 := newBlock(3, b2.Hash(), 0)
+	m.State.
+		On("GetLogs", mock.Anything, uint64(1), uint64(2), []common.Address{address}, mock.Anything, (*common.Hash)(nil), (*time.Time)(nil), nil).
+		Return([]*ethTypes.Log{newLog(b1, 0), newLog(b2, 0)}, nil).
+		Once()
+
+	c := s.GetWSClient()
+	logs := make(chan ethTypes.Log, 10)
+	sub, err := c.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(1), Addresses: []common.Address{address}}, logs)
+	require.NoError(t, err)
+
+	receive := func() ethTypes.Log {
+		select {
+		case l := <-logs:
+			return l
+		case err := <-sub.Err():
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "log not received")
+		}
+		return ethTypes.Log{}
+	}
+
+	// the historical logs are sent first
+	assert.Equal(t, *newLog(b1, 0), receive())
+	assert.Equal(t, *newLog(b2, 0), receive())
+
+	// then the new ones
+	lastBlockNumber.Store(3)
+	onNewL2Block(state.NewL2BlockEvent{Block: *b3, Logs: []*ethTypes.Log{newLog(b3, 0)}})
+	assert.Equal(t, *newLog(b3, 0), receive())
+
+	// the block 3 is replaced and the state notifies the block 4
+	newB3 := newBlock(3, b2.Hash(), 1)
+	b4 := newBlock(4, newB3.Hash(), 1)
+	m.State.
+		On("GetL2BlockByNumber", mock.Anything, uint64(3), nil).
+		Return(newB3, nil).
+		Twice()
+	m.State.
+		On("GetLogsByBlockNumber", mock.Anything, uint64(3), nil).
+		Return([]*ethTypes.Log{newLog(newB3, 0)}, nil).
+		Once()
+	lastBlockNumber.Store(4)
+	onNewL2Block(state.NewL2BlockEvent{Block: *b4, Logs: []*ethTypes.Log{newLog(b4, 0)}})
+
+	removedLog := *newLog(b3, 0)
+	removedLog.Removed = true
+	assert.Equal(t, removedLog, receive())
+	assert.Equal(t, *newLog(newB3, 0), receive())
+	assert.Equal(t, *newLog(b4, 0), receive())
+
+	sub.Unsubscribe()
+}
+
 func TestFilterLogs(t *testing.T) {
 	logs := []*ethTypes.Log{{
 		Address: common.HexToAddress("0x1"),
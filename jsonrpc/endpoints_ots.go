@@ -0,0 +1,599 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// otsAPILevel is the version of the ots API implemented, Otterscan
+	// checks it to know if the node supports the features it needs
+	otsAPILevel = 8
+
+	// otsMaxPageSize is the max number of txs returned by a page of the ots searches
+	otsMaxPageSize = 100
+
+	otsCallTracer = "callTracer"
+)
+
+// types of the internal operations returned by ots_getInternalOperations
+const (
+	otsOpTransfer     = 0
+	otsOpSelfDestruct = 1
+	otsOpCreate       = 2
+	otsOpCreate2      = 3
+)
+
+// OtsEndpoints contains implementations for the "ots" RPC endpoints
+// used by the Otterscan block explorer.
+// See https://github.com/otterscan/otterscan/blob/develop/docs/custom-jsonrpc.md
+type OtsEndpoints struct {
+	cfg      Config
+	state    types.StateInterface
+	etherman types.EthermanInterface
+	txMan    DBTxManager
+}
+
+// NewOtsEndpoints returns OtsEndpoints
+func NewOtsEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *OtsEndpoints {
+	return &OtsEndpoints{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+}
+
+// otsCallFrame is a call frame of the callTracer result
+type otsCallFrame struct {
+	Type   string          `json:"type"`
+	From   common.Address  `json:"from"`
+	To     *common.Address `json:"to"`
+	Value  *types.ArgBig   `json:"value"`
+	Input  types.ArgBytes  `json:"input"`
+	Output types.ArgBytes  `json:"output"`
+	Error  string          `json:"error"`
+	Calls  []otsCallFrame  `json:"calls"`
+}
+
+type otsInternalOperation struct {
+	Type  int            `json:"type"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value types.ArgBig   `json:"value"`
+}
+
+type otsTraceEntry struct {
+	Type   string         `json:"type"`
+	Depth  int            `json:"depth"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Value  *types.ArgBig  `json:"value"`
+	Input  types.ArgBytes `json:"input"`
+	Output types.ArgBytes `json:"output"`
+}
+
+// otsBlock is the block returned by the ots endpoints, without
+// the logs bloom and with the number of txs of the block
+type otsBlock struct {
+	*types.Block
+	LogsBloom        *ethTypes.Bloom `json:"logsBloom"`
+	TransactionCount uint64          `json:"transactionCount"`
+}
+
+// otsBlockHeader is the otsBlock without the txs
+type otsBlockHeader struct {
+	otsBlock
+	Transactions []types.TransactionOrHash `json:"transactions,omitempty"`
+}
+
+// otsIssuance is always zero, the L2 blocks don't have rewards
+type otsIssuance struct {
+	BlockReward types.ArgUint64 `json:"blockReward"`
+	UncleReward types.ArgUint64 `json:"uncleReward"`
+	Issuance    types.ArgUint64 `json:"issuance"`
+}
+
+type otsBlockDetails struct {
+	Block     otsBlockHeader `json:"block"`
+	Issuance  otsIssuance    `json:"issuance"`
+	TotalFees types.ArgBig   `json:"totalFees"`
+}
+
+type otsBlockTransactions struct {
+	FullBlock otsBlock     `json:"fullblock"`
+	Receipts  []otsReceipt `json:"receipts"`
+}
+
+// otsReceipt is the receipt returned by the ots endpoints, without the
+// logs and the logs bloom, and with the timestamp of the block in the searches
+type otsReceipt struct {
+	types.Receipt
+	Logs      []types.Log      `json:"logs"`
+	LogsBloom *ethTypes.Bloom  `json:"logsBloom"`
+	Timestamp *types.ArgUint64 `json:"timestamp,omitempty"`
+}
+
+type otsSearchResult struct {
+	Txs       []*types.Transaction `json:"txs"`
+	Receipts  []otsReceipt         `json:"receipts"`
+	FirstPage bool                 `json:"firstPage"`
+	LastPage  bool                 `json:"lastPage"`
+}
+
+type otsContractCreator struct {
+	Hash    common.Hash    `json:"hash"`
+	Creator common.Address `json:"creator"`
+}
+
+// GetApiLevel returns the version of the ots API implemented by the node
+func (o *OtsEndpoints) GetApiLevel() (interface{}, types.Error) { //nolint:revive
+	return otsAPILevel, nil
+}
+
+// HasCode returns true if the given address has code at the given block
+func (o *OtsEndpoints) HasCode(address types.ArgAddress, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		block, rpcErr := getL2BlockByArg(ctx, o.state, o.etherman, blockArg, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		code, err := o.state.GetCode(ctx, address.Address(), block.Root())
+		if errors.Is(err, state.ErrNotFound) {
+			return false, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get code", err, true)
+		}
+
+		return len(code) > 0, nil
+	})
+}
+
+// GetInternalOperations returns the ETH transfers, contract creations and self
+// destructs executed by the internal calls of the given tx, the operations of
+// the reverted calls are not returned as they have no effect
+func (o *OtsEndpoints) GetInternalOperations(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		frame, rpcErr := o.traceCalls(ctx, hash.Hash(), dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		operations := []otsInternalOperation{}
+		var walk func(frame otsCallFrame)
+		walk = func(frame otsCallFrame) {
+			for _, call := range frame.Calls {
+				if call.Error != "" {
+					continue
+				}
+				if operation, ok := call.internalOperation(); ok {
+					operations = append(operations, operation)
+				}
+				walk(call)
+			}
+		}
+		if frame.Error == "" {
+			walk(*frame)
+		}
+
+		return operations, nil
+	})
+}
+
+// GetTransactionError returns the revert data of the given tx, it is
+// empty if the tx didn't revert
+func (o *OtsEndpoints) GetTransactionError(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		receipt, err := o.state.GetTransactionReceipt(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, "transaction not found", nil, false)
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transaction receipt", err, true)
+		}
+		if receipt.Status == ethTypes.ReceiptStatusSuccessful {
+			return types.ArgBytes{}, nil
+		}
+
+		frame, rpcErr := o.traceCalls(ctx, hash.Hash(), dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		return frame.Output, nil
+	})
+}
+
+// TraceTransaction returns the calls executed by the given tx, flattened in
+// the order they were executed along with their depth
+func (o *OtsEndpoints) TraceTransaction(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		frame, rpcErr := o.traceCalls(ctx, hash.Hash(), dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		entries := []otsTraceEntry{}
+		var walk func(frame otsCallFrame, depth int)
+		walk = func(frame otsCallFrame, depth int) {
+			entry := otsTraceEntry{
+				Type:   frame.Type,
+				Depth:  depth,
+				From:   frame.From,
+				Value:  frame.Value,
+				Input:  frame.Input,
+				Output: frame.Output,
+			}
+			if frame.To != nil {
+				entry.To = *frame.To
+			}
+			entries = append(entries, entry)
+			for _, call := range frame.Calls {
+				walk(call, depth+1)
+			}
+		}
+		walk(*frame, 0)
+
+		return entries, nil
+	})
+}
+
+// GetBlockDetails returns the given block without its txs, along with its fees
+func (o *OtsEndpoints) GetBlockDetails(number types.BlockNumber) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, o.state, o.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		l2Block, err := o.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", blockNumber), err, true)
+		}
+
+		return o.getBlockDetails(ctx, l2Block, dbTx)
+	})
+}
+
+// GetBlockDetailsByHash returns the given block without its txs, along with its fees
+func (o *OtsEndpoints) GetBlockDetailsByHash(hash types.ArgHash) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		l2Block, err := o.state.GetL2BlockByHash(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by hash %v", hash.Hash().String()), err, true)
+		}
+
+		return o.getBlockDetails(ctx, l2Block, dbTx)
+	})
+}
+
+// GetBlockTransactions returns a page of the txs of the given block along with
+// their receipts, the pages go from the last tx of the block to the first one
+func (o *OtsEndpoints) GetBlockTransactions(number types.BlockNumber, pageNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	if rpcErr := checkOtsPageSize(pageSize); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, o.state, o.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		l2Block, err := o.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", blockNumber), err, true)
+		}
+
+		receipts, err := o.state.GetTransactionReceiptsByL2BlockNumber(ctx, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", blockNumber), err, true)
+		}
+
+		rpcBlock, err := types.NewBlock(ctx, o.state, state.Ptr(l2Block.Hash()), l2Block, nil, false, false, nil, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by number %v", blockNumber), err, true)
+		}
+
+		txs := l2Block.Transactions()
+		end := uint64(0)
+		if pageNumber*pageSize < uint64(len(txs)) {
+			end = uint64(len(txs)) - pageNumber*pageSize
+		}
+		start := uint64(0)
+		if end > pageSize {
+			start = end - pageSize
+		}
+
+		rpcBlock.Transactions = make([]types.TransactionOrHash, 0, end-start)
+		rpcReceipts := make([]otsReceipt, 0, end-start)
+		for i := start; i < end; i++ {
+			var receipt *ethTypes.Receipt
+			if i < uint64(len(receipts)) {
+				receipt = receipts[i]
+			}
+			rpcTx, rpcReceipt, err := newOtsTransaction(*txs[i], receipt)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build tx response for tx %v", txs[i].Hash().String()), err, true)
+			}
+			rpcBlock.Transactions = append(rpcBlock.Transactions, types.TransactionOrHash{Tx: rpcTx})
+			rpcReceipts = append(rpcReceipts, *rpcReceipt)
+		}
+
+		return otsBlockTransactions{
+			FullBlock: otsBlock{Block: rpcBlock, TransactionCount: uint64(len(txs))},
+			Receipts:  rpcReceipts,
+		}, nil
+	})
+}
+
+// SearchTransactionsBefore returns a page of the txs sent by, sent to or creating the given address
+// before the given block, sorted from the newest to the oldest. A block number of zero starts the
+// search at the last block. The txs of a block are never split across pages, so a page can
+// have more txs than the page size. The internal calls and the deployments made by other
+// contracts are not indexed, so the txs reaching the address only through them are not returned
+func (o *OtsEndpoints) SearchTransactionsBefore(address types.ArgAddress, blockNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	if rpcErr := checkOtsPageSize(pageSize); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		firstPage := blockNumber == 0
+		if firstPage {
+			lastBlockNumber, err := o.state.GetLastL2BlockNumber(ctx, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+			}
+			blockNumber = lastBlockNumber + 1
+		}
+
+		hashes, err := o.state.GetTxsHashesByAddressBefore(ctx, address.Address(), blockNumber, pageSize, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to search txs by address", err, true)
+		}
+
+		result, rpcErr := o.newSearchResult(ctx, hashes, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		result.FirstPage = firstPage
+		result.LastPage = uint64(len(hashes)) < pageSize
+
+		return result, nil
+	})
+}
+
+// SearchTransactionsAfter returns a page of the txs sent by, sent to or creating the given address
+// after the given block, sorted from the newest to the oldest. A block number of zero starts the
+// search at the first block. The txs of a block are never split across pages, so a page can
+// have more txs than the page size. The internal calls and the deployments made by other
+// contracts are not indexed, so the txs reaching the address only through them are not returned
+func (o *OtsEndpoints) SearchTransactionsAfter(address types.ArgAddress, blockNumber uint64, pageSize uint64) (interface{}, types.Error) {
+	if rpcErr := checkOtsPageSize(pageSize); rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		hashes, err := o.state.GetTxsHashesByAddressAfter(ctx, address.Address(), blockNumber, pageSize, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to search txs by address", err, true)
+		}
+
+		// the pages are always sorted from the newest to the oldest tx
+		for i, j := 0, len(hashes)-1; i < j; i, j = i+1, j-1 {
+			hashes[i], hashes[j] = hashes[j], hashes[i]
+		}
+
+		result, rpcErr := o.newSearchResult(ctx, hashes, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		result.FirstPage = uint64(len(hashes)) < pageSize
+		result.LastPage = blockNumber == 0
+
+		return result, nil
+	})
+}
+
+// GetTransactionBySenderAndNonce returns the hash of the tx sent by the given
+// address with the given nonce
+func (o *OtsEndpoints) GetTransactionBySenderAndNonce(address types.ArgAddress, nonce uint64) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		hash, err := o.state.GetTxHashBySenderAndNonce(ctx, address.Address(), nonce, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx by sender and nonce", err, true)
+		}
+
+		return hash, nil
+	})
+}
+
+// GetContractCreator returns the tx that deployed the given contract and its
+// sender. Only the contracts deployed by a tx, not by another contract (e.g. a
+// factory), are found, the others return nil
+func (o *OtsEndpoints) GetContractCreator(address types.ArgAddress) (interface{}, types.Error) {
+	return o.txMan.NewDbTxScope(o.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		hash, err := o.state.GetContractCreationTxHash(ctx, address.Address(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get contract creation tx", err, true)
+		}
+
+		tx, err := o.state.GetTransactionByHash(ctx, hash, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to load contract creation tx", err, true)
+		}
+		creator, err := state.GetSender(*tx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get contract creation tx sender", err, true)
+		}
+
+		return otsContractCreator{Hash: hash, Creator: creator}, nil
+	})
+}
+
+// traceCalls traces the given tx with the callTracer
+func (o *OtsEndpoints) traceCalls(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*otsCallFrame, types.Error) {
+	tracer := otsCallTracer
+	result, err := o.state.DebugTransaction(ctx, hash, state.TraceConfig{Tracer: &tracer}, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "transaction not found")
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+		return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+	}
+
+	var frame otsCallFrame
+	if err := json.Unmarshal(result.TraceResult, &frame); err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+		return nil, rpcErr
+	}
+
+	return &frame, nil
+}
+
+func (o *OtsEndpoints) getBlockDetails(ctx context.Context, l2Block *state.L2Block, dbTx pgx.Tx) (interface{}, types.Error) {
+	receipts, err := o.state.GetTransactionReceiptsByL2BlockNumber(ctx, l2Block.NumberU64(), dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipts for block %v", l2Block.NumberU64()), err, true)
+	}
+
+	rpcBlock, err := types.NewBlock(ctx, o.state, state.Ptr(l2Block.Hash()), l2Block, nil, false, false, nil, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by number %v", l2Block.NumberU64()), err, true)
+	}
+
+	totalFees := big.NewInt(0)
+	for i, tx := range l2Block.Transactions() {
+		if i >= len(receipts) {
+			break
+		}
+		gasPrice := tx.GasPrice()
+		if receipts[i].EffectiveGasPrice != nil {
+			gasPrice = receipts[i].EffectiveGasPrice
+		}
+		fee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipts[i].GasUsed))
+		totalFees.Add(totalFees, fee)
+	}
+
+	return otsBlockDetails{
+		Block: otsBlockHeader{
+			otsBlock: otsBlock{Block: rpcBlock, TransactionCount: uint64(len(l2Block.Transactions()))},
+		},
+		TotalFees: types.ArgBig(*totalFees),
+	}, nil
+}
+
+func (o *OtsEndpoints) newSearchResult(ctx context.Context, hashes []common.Hash, dbTx pgx.Tx) (*otsSearchResult, types.Error) {
+	result := &otsSearchResult{
+		Txs:      make([]*types.Transaction, 0, len(hashes)),
+		Receipts: make([]otsReceipt, 0, len(hashes)),
+	}
+
+	timestamps := map[uint64]types.ArgUint64{}
+	for _, hash := range hashes {
+		tx, err := o.state.GetTransactionByHash(ctx, hash, dbTx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to load tx %v", hash.String()), err, true)
+			return nil, rpcErr
+		}
+		receipt, err := o.state.GetTransactionReceipt(ctx, hash, dbTx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to load receipt of tx %v", hash.String()), err, true)
+			return nil, rpcErr
+		}
+
+		rpcTx, rpcReceipt, err := newOtsTransaction(*tx, receipt)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build tx response for tx %v", hash.String()), err, true)
+			return nil, rpcErr
+		}
+
+		blockNumber := receipt.BlockNumber.Uint64()
+		timestamp, found := timestamps[blockNumber]
+		if !found {
+			header, err := o.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+			if err != nil {
+				_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to load header of block %v", blockNumber), err, true)
+				return nil, rpcErr
+			}
+			timestamp = types.ArgUint64(header.Time)
+			timestamps[blockNumber] = timestamp
+		}
+		rpcReceipt.Timestamp = &timestamp
+
+		result.Txs = append(result.Txs, rpcTx)
+		result.Receipts = append(result.Receipts, *rpcReceipt)
+	}
+
+	return result, nil
+}
+
+// internalOperation returns the internal operation executed by the call, if any
+func (f otsCallFrame) internalOperation() (otsInternalOperation, bool) {
+	operation := otsInternalOperation{From: f.From}
+	if f.To != nil {
+		operation.To = *f.To
+	}
+	if f.Value != nil {
+		operation.Value = *f.Value
+	}
+
+	switch f.Type {
+	case "CALL":
+		if f.Value == nil || (*big.Int)(f.Value).Sign() == 0 {
+			return operation, false
+		}
+		operation.Type = otsOpTransfer
+	case "SELFDESTRUCT":
+		operation.Type = otsOpSelfDestruct
+	case "CREATE":
+		operation.Type = otsOpCreate
+	case "CREATE2":
+		operation.Type = otsOpCreate2
+	default:
+		return operation, false
+	}
+
+	return operation, true
+}
+
+func newOtsTransaction(tx ethTypes.Transaction, receipt *ethTypes.Receipt) (*types.Transaction, *otsReceipt, error) {
+	rpcTx, err := types.NewTransaction(tx, receipt, false, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if receipt == nil {
+		return nil, nil, fmt.Errorf("receipt not found")
+	}
+	rpcReceipt, err := types.NewReceipt(tx, receipt, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rpcTx, &otsReceipt{Receipt: rpcReceipt}, nil
+}
+
+func checkOtsPageSize(pageSize uint64) types.Error {
+	if pageSize == 0 || pageSize > otsMaxPageSize {
+		return types.NewRPCError(types.InvalidParamsErrorCode, fmt.Sprintf("page size must be between 1 and %d", otsMaxPageSize))
+	}
+	return nil
+}
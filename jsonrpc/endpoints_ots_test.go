@@ -0,0 +1,166 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func otsCallTrace(t *testing.T) json.RawMessage {
+	addr := func(n int64) string { return common.BigToAddress(big.NewInt(n)).String() }
+	trace := map[string]interface{}{
+		"type": "CALL", "from": addr(1), "to": addr(2), "value": "0x0", "input": "0x",
+		"calls": []interface{}{
+			map[string]interface{}{"type": "CALL", "from": addr(2), "to": addr(3), "value": "0x5", "input": "0x"},
+			map[string]interface{}{"type": "STATICCALL", "from": addr(2), "to": addr(4), "input": "0x01", "output": "0x02"},
+			map[string]interface{}{"type": "CREATE2", "from": addr(2), "to": addr(5), "value": "0x0", "input": "0x6001",
+				"calls": []interface{}{
+					map[string]interface{}{"type": "SELFDESTRUCT", "from": addr(5), "to": addr(6), "value": "0x1", "input": "0x"},
+				},
+			},
+			map[string]interface{}{"type": "CALL", "from": addr(2), "to": addr(7), "value": "0x9", "input": "0x", "error": "execution reverted"},
+		},
+	}
+	data, err := json.Marshal(trace)
+	require.NoError(t, err)
+	return data
+}
+
+func TestOtsTraces(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	tracer := otsCallTracer
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.
+		On("DebugTransaction", context.Background(), txHash, state.TraceConfig{Tracer: &tracer}, m.DbTx).
+		Return(&runtime.ExecutionResult{TraceResult: otsCallTrace(t)}, nil).
+		Twice()
+
+	addr := func(n int64) common.Address { return common.BigToAddress(big.NewInt(n)) }
+
+	res, err := s.JSONRPCCall("ots_getInternalOperations", txHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	expectedOperations, err := json.Marshal([]otsInternalOperation{
+		{Type: otsOpTransfer, From: addr(2), To: addr(3), Value: types.ArgBig(*big.NewInt(5))},
+		{Type: otsOpCreate2, From: addr(2), To: addr(5), Value: types.ArgBig(*big.NewInt(0))},
+		{Type: otsOpSelfDestruct, From: addr(5), To: addr(6), Value: types.ArgBig(*big.NewInt(1))},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expectedOperations), string(res.Result))
+
+	res, err = s.JSONRPCCall("ots_traceTransaction", txHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var entries []struct {
+		Type  string          `json:"type"`
+		Depth int             `json:"depth"`
+		To    common.Address  `json:"to"`
+		Value json.RawMessage `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &entries))
+	require.Len(t, entries, 6)
+	entryTypes := []string{}
+	depths := []int{}
+	for _, entry := range entries {
+		entryTypes = append(entryTypes, entry.Type)
+		depths = append(depths, entry.Depth)
+	}
+	assert.Equal(t, []string{"CALL", "CALL", "STATICCALL", "CREATE2", "SELFDESTRUCT", "CALL"}, entryTypes)
+	assert.Equal(t, []int{0, 1, 1, 1, 2, 1}, depths)
+	assert.Equal(t, addr(4), entries[2].To)
+	assert.Equal(t, "null", string(entries[2].Value))
+}
+
+func TestOtsSearchTransactionsBefore(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 3, To: state.HexToAddressPtr("0x2"), Value: big.NewInt(1), GasPrice: big.NewInt(1)}), ethTypes.NewEIP155Signer(big.NewInt(int64(chainID))), key)
+	require.NoError(t, err)
+	receipt := &ethTypes.Receipt{TxHash: tx.Hash(), BlockNumber: big.NewInt(7), Status: ethTypes.ReceiptStatusSuccessful}
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(10), nil).Once()
+	m.State.On("GetTxsHashesByAddressBefore", context.Background(), sender, uint64(11), uint64(2), m.DbTx).Return([]common.Hash{tx.Hash()}, nil).Once()
+	m.State.On("GetTransactionByHash", context.Background(), tx.Hash(), m.DbTx).Return(tx, nil).Once()
+	m.State.On("GetTransactionReceipt", context.Background(), tx.Hash(), m.DbTx).Return(receipt, nil).Once()
+	m.State.On("GetL2BlockHeaderByNumber", context.Background(), uint64(7), m.DbTx).Return(state.NewL2Header(&ethTypes.Header{Time: 100}), nil).Once()
+
+	res, err := s.JSONRPCCall("ots_searchTransactionsBefore", sender.String(), 0, 2)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result struct {
+		Txs []struct {
+			Hash common.Hash    `json:"hash"`
+			From common.Address `json:"from"`
+		} `json:"txs"`
+		Receipts []struct {
+			Timestamp types.ArgUint64 `json:"timestamp"`
+			Logs      []interface{}   `json:"logs"`
+		} `json:"receipts"`
+		FirstPage bool `json:"firstPage"`
+		LastPage  bool `json:"lastPage"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	require.Len(t, result.Txs, 1)
+	assert.Equal(t, tx.Hash(), result.Txs[0].Hash)
+	assert.Equal(t, sender, result.Txs[0].From)
+	require.Len(t, result.Receipts, 1)
+	assert.Equal(t, types.ArgUint64(100), result.Receipts[0].Timestamp)
+	assert.Nil(t, result.Receipts[0].Logs)
+	assert.True(t, result.FirstPage)
+	assert.True(t, result.LastPage)
+
+	res, err = s.JSONRPCCall("ots_searchTransactionsBefore", sender.String(), 0, otsMaxPageSize+1)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestOtsGetContractCreator(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := ethTypes.SignTx(ethTypes.NewTx(&ethTypes.LegacyTx{Data: []byte{0x60, 0x01}, GasPrice: big.NewInt(1)}), ethTypes.NewEIP155Signer(big.NewInt(int64(chainID))), key)
+	require.NoError(t, err)
+	contract := common.HexToAddress("0x5")
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.On("GetContractCreationTxHash", context.Background(), contract, m.DbTx).Return(tx.Hash(), nil).Once()
+	m.State.On("GetTransactionByHash", context.Background(), tx.Hash(), m.DbTx).Return(tx, nil).Once()
+	m.State.On("GetContractCreationTxHash", context.Background(), common.HexToAddress("0x6"), m.DbTx).Return(common.Hash{}, state.ErrNotFound).Once()
+
+	res, err := s.JSONRPCCall("ots_getContractCreator", contract.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var creator otsContractCreator
+	require.NoError(t, json.Unmarshal(res.Result, &creator))
+	assert.Equal(t, otsContractCreator{Hash: tx.Hash(), Creator: crypto.PubkeyToAddress(key.PublicKey)}, creator)
+
+	res, err = s.JSONRPCCall("ots_getContractCreator", common.HexToAddress("0x6").String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
@@ -0,0 +1,16 @@
+package jsonrpc
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+)
+
+// RPCEndpoints contains implementations for the "rpc" RPC endpoints
+type RPCEndpoints struct {
+	handler *Handler
+}
+
+// Discover returns the OpenRPC document describing the methods exposed by the server,
+// the restricted namespaces are not included
+func (r *RPCEndpoints) Discover() (interface{}, types.Error) {
+	return r.handler.openRPC.document(), nil
+}
@@ -0,0 +1,440 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	traceFlatCallTracer = "flatCallTracer"
+	tracePrestateTracer = "prestateTracer"
+
+	// modes accepted by trace_replayTransaction
+	traceModeTrace     = "trace"
+	traceModeStateDiff = "stateDiff"
+	traceModeVMTrace   = "vmTrace"
+)
+
+var (
+	traceFlatCallTracerConfig = json.RawMessage(`{"convertParityErrors":true}`)
+	tracePrestateTracerConfig = json.RawMessage(`{"diffMode":true}`)
+)
+
+// TraceEndpoints contains implementations for the "trace" RPC endpoints,
+// compatible with the Parity/OpenEthereum trace module.
+// See https://openethereum.github.io/JSONRPC-trace-module
+type TraceEndpoints struct {
+	cfg      Config
+	state    types.StateInterface
+	etherman types.EthermanInterface
+	txMan    DBTxManager
+}
+
+// NewTraceEndpoints returns TraceEndpoints
+func NewTraceEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *TraceEndpoints {
+	return &TraceEndpoints{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+}
+
+// traceFrame has the fields of a flatCallTracer frame needed to select it
+type traceFrame struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+	TraceAddress []int `json:"traceAddress"`
+}
+
+// traceReplayFrame is a flatCallTracer frame without the block and tx
+// fields, as returned by trace_replayTransaction
+type traceReplayFrame struct {
+	Action       json.RawMessage `json:"action"`
+	Error        string          `json:"error,omitempty"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	Subtraces    int             `json:"subtraces"`
+	TraceAddress []int           `json:"traceAddress"`
+	Type         string          `json:"type"`
+}
+
+type traceFilterRequest struct {
+	FromBlock   *types.BlockNumber `json:"fromBlock,omitempty"`
+	ToBlock     *types.BlockNumber `json:"toBlock,omitempty"`
+	FromAddress []common.Address   `json:"fromAddress,omitempty"`
+	ToAddress   []common.Address   `json:"toAddress,omitempty"`
+	After       *types.ArgUint64   `json:"after,omitempty"`
+	Count       *types.ArgUint64   `json:"count,omitempty"`
+}
+
+type traceReplayResult struct {
+	Output    types.ArgBytes                       `json:"output"`
+	StateDiff map[common.Address]*traceAccountDiff `json:"stateDiff"`
+	Trace     []traceReplayFrame                   `json:"trace"`
+	VMTrace   interface{}                          `json:"vmTrace"`
+}
+
+// traceAccountDiff is the change of an account in the Parity stateDiff
+// format, each field is either "=" when unchanged or an object keyed by
+// "+" (born), "-" (died) or "*" (changed)
+type traceAccountDiff struct {
+	Balance interface{}                 `json:"balance"`
+	Code    interface{}                 `json:"code"`
+	Nonce   interface{}                 `json:"nonce"`
+	Storage map[common.Hash]interface{} `json:"storage"`
+}
+
+type traceDiffChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// tracePrestateAccount is an account of the prestateTracer result in diff mode,
+// the fields of the post state are only set when they changed
+type tracePrestateAccount struct {
+	Balance *types.ArgBig               `json:"balance"`
+	Code    *types.ArgBytes             `json:"code"`
+	Nonce   *uint64                     `json:"nonce"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type tracePrestateDiff struct {
+	Pre  map[common.Address]*tracePrestateAccount `json:"pre"`
+	Post map[common.Address]*tracePrestateAccount `json:"post"`
+}
+
+// Transaction returns the traces of the calls executed by the given tx
+func (t *TraceEndpoints) Transaction(hash types.ArgHash) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		return t.traceTransaction(ctx, hash.Hash(), dbTx)
+	})
+}
+
+// Block returns the traces of the calls executed by all the txs of the given block
+func (t *TraceEndpoints) Block(number types.BlockNumber) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, t.state, t.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		traces, rpcErr := t.traceBlock(ctx, blockNumber, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		if traces == nil {
+			return nil, nil
+		}
+
+		return traces, nil
+	})
+}
+
+// Get returns the trace of the given tx at the given trace address
+func (t *TraceEndpoints) Get(hash types.ArgHash, indices []types.ArgUint64) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		traces, rpcErr := t.traceTransaction(ctx, hash.Hash(), dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		for _, trace := range traces {
+			var frame traceFrame
+			if err := json.Unmarshal(trace, &frame); err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+			}
+			if isTraceAddress(frame.TraceAddress, indices) {
+				return trace, nil
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// Filter returns the traces of the given block range matching the given
+// addresses, the range is limited by the MaxTraceFilterBlockRange config
+func (t *TraceEndpoints) Filter(filter traceFilterRequest) (interface{}, types.Error) {
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		fromBlock := filter.FromBlock
+		if fromBlock == nil {
+			latest := types.LatestBlockNumber
+			fromBlock = &latest
+		}
+		fromBlockNumber, toBlockNumber, rpcErr := getNumericBlockNumbers(ctx, t.state, t.etherman, fromBlock, filter.ToBlock, t.cfg.MaxTraceFilterBlockRange, state.ErrMaxTraceFilterBlockRangeLimitExceeded, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		var after, count uint64
+		if filter.After != nil {
+			after = uint64(*filter.After)
+		}
+		if filter.Count != nil {
+			count = uint64(*filter.Count)
+		}
+
+		traces := []json.RawMessage{}
+		for blockNumber := fromBlockNumber; blockNumber <= toBlockNumber; blockNumber++ {
+			blockTraces, rpcErr := t.traceBlock(ctx, blockNumber, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+
+			for _, trace := range blockTraces {
+				var frame traceFrame
+				if err := json.Unmarshal(trace, &frame); err != nil {
+					return RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+				}
+				if !frame.matches(filter.FromAddress, filter.ToAddress) {
+					continue
+				}
+				if after > 0 {
+					after--
+					continue
+				}
+				traces = append(traces, trace)
+				if count > 0 && uint64(len(traces)) == count {
+					return traces, nil
+				}
+			}
+		}
+
+		return traces, nil
+	})
+}
+
+// ReplayTransaction re-executes the given tx and returns the requested
+// trace types, the supported modes are "trace" and "stateDiff"
+func (t *TraceEndpoints) ReplayTransaction(hash types.ArgHash, modes []string) (interface{}, types.Error) {
+	var withTrace, withStateDiff bool
+	for _, mode := range modes {
+		switch mode {
+		case traceModeTrace:
+			withTrace = true
+		case traceModeStateDiff:
+			withStateDiff = true
+		case traceModeVMTrace:
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "vmTrace mode is not supported", nil, false)
+		default:
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid trace mode: %s", mode), nil, false)
+		}
+	}
+
+	return t.txMan.NewDbTxScope(t.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		result := traceReplayResult{}
+		if withTrace || !withStateDiff {
+			executionResult, rpcErr := t.debugTransaction(ctx, hash.Hash(), traceFlatCallTracer, traceFlatCallTracerConfig, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			result.Output = executionResult.ReturnValue
+			if withTrace {
+				if err := json.Unmarshal(executionResult.TraceResult, &result.Trace); err != nil {
+					return RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+				}
+			}
+		}
+
+		if withStateDiff {
+			executionResult, rpcErr := t.debugTransaction(ctx, hash.Hash(), tracePrestateTracer, tracePrestateTracerConfig, dbTx)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			result.Output = executionResult.ReturnValue
+			var diff tracePrestateDiff
+			if err := json.Unmarshal(executionResult.TraceResult, &diff); err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to read state diff", err, true)
+			}
+			result.StateDiff = diff.toStateDiff()
+		}
+
+		return result, nil
+	})
+}
+
+// traceTransaction traces the given tx with the flatCallTracer
+func (t *TraceEndpoints) traceTransaction(ctx context.Context, hash common.Hash, dbTx pgx.Tx) ([]json.RawMessage, types.Error) {
+	result, rpcErr := t.debugTransaction(ctx, hash, traceFlatCallTracer, traceFlatCallTracerConfig, dbTx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	var traces []json.RawMessage
+	if err := json.Unmarshal(result.TraceResult, &traces); err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to read trace", err, true)
+		return nil, rpcErr
+	}
+
+	return traces, nil
+}
+
+// traceBlock traces all the txs of the given block, nil is returned
+// if the block doesn't exist
+func (t *TraceEndpoints) traceBlock(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]json.RawMessage, types.Error) {
+	block, err := t.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get block by number", err, true)
+		return nil, rpcErr
+	}
+
+	traces := []json.RawMessage{}
+	for _, tx := range block.Transactions() {
+		txTraces, rpcErr := t.traceTransaction(ctx, tx.Hash(), dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		traces = append(traces, txTraces...)
+	}
+
+	return traces, nil
+}
+
+func (t *TraceEndpoints) debugTransaction(ctx context.Context, hash common.Hash, tracer string, tracerConfig json.RawMessage, dbTx pgx.Tx) (*runtime.ExecutionResult, types.Error) {
+	result, err := t.state.DebugTransaction(ctx, hash, state.TraceConfig{Tracer: &tracer, TracerConfig: tracerConfig}, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "transaction not found")
+	} else if err != nil {
+		errorMessage := fmt.Sprintf("failed to get trace: %v", err.Error())
+		return nil, types.NewRPCError(types.DefaultErrorCode, errorMessage)
+	}
+
+	return result, nil
+}
+
+// matches returns true if the frame sender is one of the from addresses and its
+// receiver one of the to addresses, an empty list of addresses matches all frames
+func (f traceFrame) matches(fromAddresses, toAddresses []common.Address) bool {
+	from := f.Action.From
+	if from == nil {
+		// selfdestruct
+		from = f.Action.Address
+	}
+	to := f.Action.To
+	if f.Result != nil && f.Result.Address != nil {
+		// create
+		to = f.Result.Address
+	} else if f.Action.RefundAddress != nil {
+		// selfdestruct
+		to = f.Action.RefundAddress
+	}
+
+	return containsTraceAddress(fromAddresses, from) && containsTraceAddress(toAddresses, to)
+}
+
+func containsTraceAddress(addresses []common.Address, address *common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	if address == nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == *address {
+			return true
+		}
+	}
+	return false
+}
+
+func isTraceAddress(traceAddress []int, indices []types.ArgUint64) bool {
+	if len(traceAddress) != len(indices) {
+		return false
+	}
+	for i, index := range indices {
+		if uint64(traceAddress[i]) != uint64(index) {
+			return false
+		}
+	}
+	return true
+}
+
+// toStateDiff converts the prestateTracer diff to the Parity stateDiff format
+func (d tracePrestateDiff) toStateDiff() map[common.Address]*traceAccountDiff {
+	stateDiff := map[common.Address]*traceAccountDiff{}
+	for addr, pre := range d.Pre {
+		post, ok := d.Post[addr]
+		if !ok {
+			stateDiff[addr] = pre.toAccountDiff("-")
+			continue
+		}
+
+		diff := &traceAccountDiff{Balance: "=", Code: "=", Nonce: "=", Storage: map[common.Hash]interface{}{}}
+		if post.Balance != nil {
+			diff.Balance = map[string]traceDiffChange{"*": {From: pre.balance(), To: post.balance()}}
+		}
+		if post.Code != nil {
+			diff.Code = map[string]traceDiffChange{"*": {From: pre.code(), To: post.code()}}
+		}
+		if post.Nonce != nil {
+			diff.Nonce = map[string]traceDiffChange{"*": {From: pre.nonce(), To: post.nonce()}}
+		}
+		for key, from := range pre.Storage {
+			diff.Storage[key] = map[string]traceDiffChange{"*": {From: from, To: post.Storage[key]}}
+		}
+		for key, to := range post.Storage {
+			diff.Storage[key] = map[string]traceDiffChange{"*": {From: pre.Storage[key], To: to}}
+		}
+		stateDiff[addr] = diff
+	}
+
+	for addr, post := range d.Post {
+		if _, ok := d.Pre[addr]; !ok {
+			stateDiff[addr] = post.toAccountDiff("+")
+		}
+	}
+
+	return stateDiff
+}
+
+// toAccountDiff returns the diff of an account that was born or died
+func (a *tracePrestateAccount) toAccountDiff(op string) *traceAccountDiff {
+	diff := &traceAccountDiff{
+		Balance: map[string]interface{}{op: a.balance()},
+		Code:    map[string]interface{}{op: a.code()},
+		Nonce:   map[string]interface{}{op: a.nonce()},
+		Storage: map[common.Hash]interface{}{},
+	}
+	for key, value := range a.Storage {
+		diff.Storage[key] = map[string]interface{}{op: value}
+	}
+	return diff
+}
+
+func (a *tracePrestateAccount) balance() types.ArgBig {
+	if a.Balance == nil {
+		return types.ArgBig(*big.NewInt(0))
+	}
+	return *a.Balance
+}
+
+func (a *tracePrestateAccount) code() types.ArgBytes {
+	if a.Code == nil {
+		return types.ArgBytes{}
+	}
+	return *a.Code
+}
+
+func (a *tracePrestateAccount) nonce() types.ArgUint64 {
+	if a.Nonce == nil {
+		return 0
+	}
+	return types.ArgUint64(*a.Nonce)
+}
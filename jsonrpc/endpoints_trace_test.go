@@ -0,0 +1,205 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func flatCallTrace(t *testing.T, txHash common.Hash) json.RawMessage {
+	addr := func(n int64) string { return common.BigToAddress(big.NewInt(n)).String() }
+	trace := []interface{}{
+		map[string]interface{}{
+			"type": "call", "action": map[string]interface{}{"callType": "call", "from": addr(1), "to": addr(2), "value": "0x0", "input": "0x"},
+			"result": map[string]interface{}{"gasUsed": "0x1", "output": "0x"}, "subtraces": 2, "traceAddress": []int{},
+			"transactionHash": txHash.String(),
+		},
+		map[string]interface{}{
+			"type": "create", "action": map[string]interface{}{"from": addr(2), "value": "0x0", "init": "0x6001"},
+			"result": map[string]interface{}{"gasUsed": "0x1", "address": addr(3), "code": "0x"}, "subtraces": 0, "traceAddress": []int{0},
+			"transactionHash": txHash.String(),
+		},
+		map[string]interface{}{
+			"type": "suicide", "action": map[string]interface{}{"address": addr(2), "refundAddress": addr(4), "balance": "0x0"},
+			"subtraces": 0, "traceAddress": []int{1},
+			"transactionHash": txHash.String(),
+		},
+	}
+	data, err := json.Marshal(trace)
+	require.NoError(t, err)
+	return data
+}
+
+func flatCallTraceConfig() state.TraceConfig {
+	tracer := traceFlatCallTracer
+	return state.TraceConfig{Tracer: &tracer, TracerConfig: traceFlatCallTracerConfig}
+}
+
+func TestTraceTransactionAndGet(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	m.DbTx.On("Commit", context.Background()).Return(nil).Times(3)
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(3)
+	m.State.
+		On("DebugTransaction", context.Background(), txHash, flatCallTraceConfig(), m.DbTx).
+		Return(&runtime.ExecutionResult{TraceResult: flatCallTrace(t, txHash)}, nil).
+		Times(3)
+
+	res, err := s.JSONRPCCall("trace_transaction", txHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, string(flatCallTrace(t, txHash)), string(res.Result))
+
+	res, err = s.JSONRPCCall("trace_get", txHash.String(), []string{"0x1"})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var frame struct {
+		Type string `json:"type"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &frame))
+	assert.Equal(t, "suicide", frame.Type)
+
+	res, err = s.JSONRPCCall("trace_get", txHash.String(), []string{"0x0", "0x0"})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
+
+func TestTraceFilter(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx1 := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 1})
+	tx2 := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: 2})
+	block1 := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{tx1}, nil, []*ethTypes.Receipt{{}}, trie.NewStackTrie(nil))
+	block2 := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(2)}), []*ethTypes.Transaction{tx2}, nil, []*ethTypes.Receipt{{}}, trie.NewStackTrie(nil))
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(1), m.DbTx).Return(block1, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(block2, nil).Once()
+	m.State.On("DebugTransaction", context.Background(), tx1.Hash(), flatCallTraceConfig(), m.DbTx).Return(&runtime.ExecutionResult{TraceResult: flatCallTrace(t, tx1.Hash())}, nil).Once()
+	m.State.On("DebugTransaction", context.Background(), tx2.Hash(), flatCallTraceConfig(), m.DbTx).Return(&runtime.ExecutionResult{TraceResult: flatCallTrace(t, tx2.Hash())}, nil).Once()
+
+	// the traces sent by 0x2 are the create and the suicide of each tx,
+	// the first one is skipped and only two are returned
+	filter := map[string]interface{}{
+		"fromBlock":   "0x1",
+		"toBlock":     "0x2",
+		"fromAddress": []string{common.HexToAddress("0x2").String()},
+		"after":       "0x1",
+		"count":       "0x2",
+	}
+	res, err := s.JSONRPCCall("trace_filter", filter)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var traces []struct {
+		Type            string      `json:"type"`
+		TransactionHash common.Hash `json:"transactionHash"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &traces))
+	require.Len(t, traces, 2)
+	assert.Equal(t, "suicide", traces[0].Type)
+	assert.Equal(t, tx1.Hash(), traces[0].TransactionHash)
+	assert.Equal(t, "create", traces[1].Type)
+	assert.Equal(t, tx2.Hash(), traces[1].TransactionHash)
+
+	// the range is limited
+	m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	res, err = s.JSONRPCCall("trace_filter", map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x100"})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestTraceReplayTransaction(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	sender := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	created := common.HexToAddress("0x3")
+	slot := common.HexToHash("0x1")
+	prestate := map[string]interface{}{
+		"pre": map[string]interface{}{
+			sender.String():   map[string]interface{}{"balance": "0x10", "nonce": 1},
+			contract.String(): map[string]interface{}{"balance": "0x0", "code": "0x60", "storage": map[string]interface{}{slot.String(): common.HexToHash("0x5").String()}},
+		},
+		"post": map[string]interface{}{
+			sender.String():   map[string]interface{}{"balance": "0x8", "nonce": 2},
+			contract.String(): map[string]interface{}{"storage": map[string]interface{}{slot.String(): common.HexToHash("0x6").String()}},
+			created.String():  map[string]interface{}{"balance": "0x1", "code": "0x61"},
+		},
+	}
+	prestateResult, err := json.Marshal(prestate)
+	require.NoError(t, err)
+	prestateTracer := tracePrestateTracer
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.
+		On("DebugTransaction", context.Background(), txHash, flatCallTraceConfig(), m.DbTx).
+		Return(&runtime.ExecutionResult{ReturnValue: []byte{0x1}, TraceResult: flatCallTrace(t, txHash)}, nil).
+		Once()
+	m.State.
+		On("DebugTransaction", context.Background(), txHash, state.TraceConfig{Tracer: &prestateTracer, TracerConfig: tracePrestateTracerConfig}, m.DbTx).
+		Return(&runtime.ExecutionResult{ReturnValue: []byte{0x1}, TraceResult: prestateResult}, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("trace_replayTransaction", txHash.String(), []string{traceModeTrace, traceModeStateDiff})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result struct {
+		Output    types.ArgBytes             `json:"output"`
+		StateDiff map[string]json.RawMessage `json:"stateDiff"`
+		Trace     []map[string]interface{}   `json:"trace"`
+		VMTrace   interface{}                `json:"vmTrace"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgBytes{0x1}, result.Output)
+	assert.Nil(t, result.VMTrace)
+	require.Len(t, result.Trace, 3)
+	assert.NotContains(t, result.Trace[0], "transactionHash")
+	assert.NotContains(t, result.Trace[0], "blockNumber")
+
+	require.Len(t, result.StateDiff, 3)
+	assert.JSONEq(t, `{
+		"balance": {"*": {"from": "0x10", "to": "0x8"}},
+		"code": "=",
+		"nonce": {"*": {"from": "0x1", "to": "0x2"}},
+		"storage": {}
+	}`, string(result.StateDiff[sender.String()]))
+	assert.JSONEq(t, `{
+		"balance": "=",
+		"code": "=",
+		"nonce": "=",
+		"storage": {"`+slot.String()+`": {"*": {"from": "`+common.HexToHash("0x5").String()+`", "to": "`+common.HexToHash("0x6").String()+`"}}}
+	}`, string(result.StateDiff[contract.String()]))
+	assert.JSONEq(t, `{
+		"balance": {"+": "0x1"},
+		"code": {"+": "0x61"},
+		"nonce": {"+": "0x0"},
+		"storage": {}
+	}`, string(result.StateDiff[created.String()]))
+
+	res, err = s.JSONRPCCall("trace_replayTransaction", txHash.String(), []string{traceModeVMTrace})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
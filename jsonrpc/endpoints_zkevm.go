@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"time"
 
@@ -27,17 +28,30 @@ type ZKEVMEndpoints struct {
 	pool     types.PoolInterface
 	state    types.StateInterface
 	etherman types.EthermanInterface
+	storage  FilterStorage
 	txMan    DBTxManager
 }
 
+// stateEventFilters maps the state events to the zkevm subscriptions notified by them
+var stateEventFilters = map[state.StateEventType]FilterType{
+	state.StateEventNewBatch:          FilterTypeNewBatches,
+	state.StateEventVirtualizedBatch:  FilterTypeVirtualizedBatches,
+	state.StateEventVerifiedBatch:     FilterTypeVerifiedBatches,
+	state.StateEventNewGlobalExitRoot: FilterTypeNewGlobalExitRoot,
+	state.StateEventForkIDChanged:     FilterTypeForkIDChanged,
+}
+
 // NewZKEVMEndpoints returns ZKEVMEndpoints
-func NewZKEVMEndpoints(cfg Config, pool types.PoolInterface, state types.StateInterface, etherman types.EthermanInterface) *ZKEVMEndpoints {
-	return &ZKEVMEndpoints{
+func NewZKEVMEndpoints(cfg Config, pool types.PoolInterface, state types.StateInterface, etherman types.EthermanInterface, storage FilterStorage) *ZKEVMEndpoints {
+	z := &ZKEVMEndpoints{
 		cfg:      cfg,
 		pool:     pool,
 		state:    state,
 		etherman: etherman,
+		storage:  storage,
 	}
+	state.RegisterStateEventHandler(z.onStateEvent)
+	return z
 }
 
 // ConsolidatedBlockNumber returns last block number related to the last verified batch
@@ -354,7 +368,7 @@ func (z *ZKEVMEndpoints) GetTransactionByL2Hash(hash types.ArgHash) (interface{}
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to load transaction by l2 hash from pool", err, true)
 		}
-		if poolTx.Status == pool.TxStatusPending {
+		if poolTx.Status == pool.TxStatusPending || poolTx.Status == pool.TxStatusQueued {
 			tx = &poolTx.Transaction
 			res, err := types.NewTransaction(*tx, nil, false, nil)
 			if err != nil {
@@ -415,6 +429,42 @@ func (z *ZKEVMEndpoints) getTransactionByL2HashFromSequencerNode(hash common.Has
 	return tx, nil
 }
 
+// GetTransactionPoolStatus returns the status of a tx in the pool, including the
+// reason of the failure of the txs that failed the pre-execution
+func (z *ZKEVMEndpoints) GetTransactionPoolStatus(hash types.ArgHash) (interface{}, types.Error) {
+	if z.cfg.SequencerNodeURI != "" {
+		return z.getTransactionPoolStatusFromSequencerNode(hash.Hash())
+	}
+	poolTx, err := z.pool.GetTransactionByHash(context.Background(), hash.Hash())
+	if errors.Is(err, pool.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to load transaction by hash from pool", err, true)
+	}
+	return types.PoolTransactionStatus{
+		Status:       poolTx.Status.String(),
+		FailedReason: poolTx.FailedReason,
+	}, nil
+}
+
+func (z *ZKEVMEndpoints) getTransactionPoolStatusFromSequencerNode(hash common.Hash) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(z.cfg.SequencerNodeURI, "zkevm_getTransactionPoolStatus", hash.String())
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get tx pool status from sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+	}
+
+	var status *types.PoolTransactionStatus
+	err = json.Unmarshal(res.Result, &status)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to read tx pool status from sequencer node", err, true)
+	}
+	return status, nil
+}
+
 // GetExitRootsByGER returns the exit roots accordingly to the provided Global Exit Root
 func (z *ZKEVMEndpoints) GetExitRootsByGER(globalExitRoot common.Hash) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -434,6 +484,200 @@ func (z *ZKEVMEndpoints) GetExitRootsByGER(globalExitRoot common.Hash) (interfac
 	})
 }
 
+// GetL1InfoTreeLeaf returns the leaf of the L1InfoTree with the provided index
+func (z *ZKEVMEndpoints) GetL1InfoTreeLeaf(index types.ArgUint64) (interface{}, types.Error) {
+	if uint64(index) > math.MaxUint32 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid L1InfoTree index", nil, false)
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		entry, err := z.state.GetL1InfoRootLeafByIndex(ctx, uint32(index), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1InfoTree leaf from state by index %v", index), err, true)
+		}
+		// the storage returns an empty entry when the leaf is not found
+		if entry.L1InfoTreeRoot == state.ZeroHash {
+			return nil, nil
+		}
+
+		return types.NewL1InfoTreeLeaf(entry), nil
+	})
+}
+
+// GetL1InfoTreeLeafByGER returns the first leaf of the L1InfoTree added for the
+// provided Global Exit Root, with its index and the L1 block it came from
+func (z *ZKEVMEndpoints) GetL1InfoTreeLeafByGER(globalExitRoot common.Hash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		entry, err := z.state.GetL1InfoTreeLeafByGlobalExitRoot(ctx, globalExitRoot, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "couldn't load L1InfoTree leaf from state by global exit root", err, true)
+		}
+
+		return types.NewL1InfoTreeLeaf(entry), nil
+	})
+}
+
+// GetL1InfoTreeProof returns the merkle proof of the leaf of the L1InfoTree with
+// the provided index, against the provided root or the latest one
+func (z *ZKEVMEndpoints) GetL1InfoTreeProof(index types.ArgUint64, l1InfoRoot *types.L1InfoRootOrLatest) (interface{}, types.Error) {
+	if uint64(index) > math.MaxUint32 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid L1InfoTree index", nil, false)
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		entry, err := z.state.GetL1InfoRootLeafByIndex(ctx, uint32(index), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1InfoTree leaf from state by index %v", index), err, true)
+		}
+		if entry.L1InfoTreeRoot == state.ZeroHash {
+			return nil, nil
+		}
+
+		proof, root, err := z.state.GetL1InfoTreeMerkleProof(ctx, uint32(index), l1InfoRoot.Root(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't compute the L1InfoTree proof of the leaf %v", index), err, true)
+		}
+
+		siblings := make([]common.Hash, 0, len(proof))
+		for _, sibling := range proof {
+			siblings = append(siblings, sibling)
+		}
+
+		return types.L1InfoTreeProof{
+			Index:      index,
+			Leaf:       entry.Hash(),
+			L1InfoRoot: root,
+			Proof:      siblings,
+		}, nil
+	})
+}
+
+// GetBatchProofStatus returns the status of the proof of a batch, read from
+// the proofs stored by the aggregator and the batches verified on L1
+func (z *ZKEVMEndpoints) GetBatchProofStatus(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		_, err := z.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+
+		verifiedBatch, err := z.state.GetVerifiedBatchContaining(ctx, batchNumber, dbTx)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load verified batch from state by number %v", batchNumber), err, true)
+		}
+
+		finalProof, err := z.state.GetFinalProofByBatchNumber(ctx, batchNumber, dbTx)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load final proof from state by batch number %v", batchNumber), err, true)
+		}
+
+		if finalProof != nil && finalProof.Status == state.FinalProofStatusVerified {
+			status := types.BatchProofStatus{
+				Status:           types.BatchProofStatusVerified,
+				BatchNumber:      types.ArgUint64Ptr(types.ArgUint64(finalProof.BatchNumber)),
+				BatchNumberFinal: types.ArgUint64Ptr(types.ArgUint64(finalProof.BatchNumberFinal)),
+			}
+			// the tx is only known once the synchronizer reads the verification from L1
+			if verifiedBatch != nil && verifiedBatch.BatchNumber == finalProof.BatchNumberFinal {
+				status.VerifyBatchTxHash = &verifiedBatch.TxHash
+			}
+			return status, nil
+		}
+
+		// verified by another aggregator, before the final proofs were stored or
+		// before the aggregator got the confirmation of its final proof
+		if verifiedBatch != nil {
+			return types.BatchProofStatus{
+				Status:            types.BatchProofStatusVerified,
+				BatchNumberFinal:  types.ArgUint64Ptr(types.ArgUint64(verifiedBatch.BatchNumber)),
+				VerifyBatchTxHash: &verifiedBatch.TxHash,
+			}, nil
+		}
+
+		if finalProof != nil {
+			return types.BatchProofStatus{
+				Status:           types.BatchProofStatusFinalProofSent,
+				BatchNumber:      types.ArgUint64Ptr(types.ArgUint64(finalProof.BatchNumber)),
+				BatchNumberFinal: types.ArgUint64Ptr(types.ArgUint64(finalProof.BatchNumberFinal)),
+			}, nil
+		}
+
+		proof, err := z.state.GetGeneratedProofByBatchNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return types.BatchProofStatus{Status: types.BatchProofStatusNotStarted}, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load proof from state by batch number %v", batchNumber), err, true)
+		}
+
+		status := types.BatchProofStatus{
+			Status:           types.BatchProofStatusAggregated,
+			BatchNumber:      types.ArgUint64Ptr(types.ArgUint64(proof.BatchNumber)),
+			BatchNumberFinal: types.ArgUint64Ptr(types.ArgUint64(proof.BatchNumberFinal)),
+		}
+		if proof.BatchNumber == proof.BatchNumberFinal {
+			status.Status = types.BatchProofStatusBatchProof
+		}
+		if proof.GeneratingSince != nil {
+			status.Status = types.BatchProofStatusGenerating
+			status.Prover = proof.Prover
+			status.ProverID = proof.ProverID
+			status.GeneratingSince = types.ArgUint64Ptr(types.ArgUint64(proof.GeneratingSince.Unix()))
+		}
+		return status, nil
+	})
+}
+
+// GetFinalProof returns the final proof sent to L1 for the range of batches,
+// once its verification has been confirmed
+func (z *ZKEVMEndpoints) GetFinalProof(batchNumber types.ArgUint64, batchNumberFinal types.ArgUint64) (interface{}, types.Error) {
+	if batchNumberFinal < batchNumber {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid batch range", nil, false)
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		verifiedBatch, err := z.state.GetVerifiedBatch(ctx, uint64(batchNumberFinal), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load verified batch from state by number %v", batchNumberFinal), err, true)
+		}
+
+		finalProof, err := z.state.GetFinalProof(ctx, uint64(batchNumber), uint64(batchNumberFinal), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load final proof from state for batches %v-%v", batchNumber, batchNumberFinal), err, true)
+		}
+		// the range may have been verified by another proof
+		if finalProof.Status != state.FinalProofStatusVerified {
+			return nil, nil
+		}
+
+		return types.FinalProof{
+			BatchNumber:       types.ArgUint64(finalProof.BatchNumber),
+			BatchNumberFinal:  types.ArgUint64(finalProof.BatchNumberFinal),
+			ProofID:           finalProof.ProofID,
+			Proof:             common.FromHex(finalProof.Proof),
+			VerifyBatchTxHash: verifiedBatch.TxHash,
+		}, nil
+	})
+}
+
 // EstimateGasPrice returns an estimate gas price for the transaction.
 func (z *ZKEVMEndpoints) EstimateGasPrice(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -484,7 +728,7 @@ func (z *ZKEVMEndpoints) internalEstimateGasPriceAndFee(ctx context.Context, arg
 		return nil, nil, types.NewRPCError(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction")
 	}
 
-	gasEstimation, returnValue, err := z.state.EstimateGas(tx, sender, blockToProcess, dbTx)
+	gasEstimation, returnValue, err := z.state.EstimateGas(tx, sender, blockToProcess, nil, nil, dbTx)
 	if errors.Is(err, runtime.ErrExecutionReverted) {
 		data := make([]byte, len(returnValue))
 		copy(data, returnValue)
@@ -657,3 +901,61 @@ func (z *ZKEVMEndpoints) GetLatestGlobalExitRoot() (interface{}, types.Error) {
 		return ger.String(), nil
 	})
 }
+
+// Subscribe creates a new subscription to the batch lifecycle events: newBatches,
+// virtualizedBatches, verifiedBatches, newGlobalExitRoot and forkIdChanged.
+// The node will return a subscription id and the events are notified
+// together with the subscription id as zkevm_subscription messages.
+func (z *ZKEVMEndpoints) Subscribe(wsConn *concurrentWsConn, name string) (interface{}, types.Error) {
+	filterType := FilterType(name)
+	if !stateEventFilterTypes[filterType] {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "invalid subscription name")
+	}
+	if wsConn == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, ErrFilterRequiresWSConn.Error())
+	}
+
+	id, err := z.storage.NewStateEventFilter(wsConn, filterType)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new subscription", err, true)
+	}
+
+	return id, nil
+}
+
+// Unsubscribe uninstalls the subscription based on the provided filterID
+func (z *ZKEVMEndpoints) Unsubscribe(wsConn *concurrentWsConn, filterID string) (interface{}, types.Error) {
+	err := z.storage.UninstallFilter(filterID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to uninstall subscription", err, true)
+	}
+
+	return true, nil
+}
+
+// onStateEvent is triggered when the state notifies a change of the batch lifecycle
+func (z *ZKEVMEndpoints) onStateEvent(event state.StateEvent) {
+	filterType, found := stateEventFilters[event.Type]
+	if !found {
+		log.Warnf("[onStateEvent] unknown state event type %s", event.Type)
+		return
+	}
+
+	filters := z.storage.GetAllStateEventFiltersWithWSConn(filterType)
+	if len(filters) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(types.NewStateEventResult(event))
+	if err != nil {
+		log.Errorf("failed to marshal %s event response to subscription: %v", event.Type, err)
+		return
+	}
+
+	for _, filter := range filters {
+		filter.EnqueueSubscriptionDataToBeSent(data)
+	}
+	log.Debugf("[onStateEvent] %s event for batch %d enqueued to %d subscriptions", event.Type, event.BatchNumber, len(filters))
+}
@@ -2628,6 +2628,318 @@ func TestGetExitRootsByGER(t *testing.T) {
 	}
 }
 
+func TestGetL1InfoTreeLeafAndProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	ger := common.HexToHash("0x1")
+	entry := state.L1InfoTreeExitRootStorageEntry{
+		L1InfoTreeLeaf: state.L1InfoTreeLeaf{
+			GlobalExitRoot: state.GlobalExitRoot{
+				BlockNumber:     100,
+				Timestamp:       time.Unix(1700000000, 0),
+				MainnetExitRoot: common.HexToHash("0x2"),
+				RollupExitRoot:  common.HexToHash("0x3"),
+				GlobalExitRoot:  ger,
+			},
+			PreviousBlockHash: common.HexToHash("0x4"),
+		},
+		L1InfoTreeRoot:  common.HexToHash("0x5"),
+		L1InfoTreeIndex: 3,
+	}
+	expectedLeaf := `{
+		"index": "0x3",
+		"hash": "` + entry.Hash().String() + `",
+		"l1InfoRoot": "` + entry.L1InfoTreeRoot.String() + `",
+		"globalExitRoot": "` + ger.String() + `",
+		"mainnetExitRoot": "` + entry.MainnetExitRoot.String() + `",
+		"rollupExitRoot": "` + entry.RollupExitRoot.String() + `",
+		"previousBlockHash": "` + entry.PreviousBlockHash.String() + `",
+		"timestamp": "0x6553f100",
+		"l1BlockNumber": "0x64"
+	}`
+
+	// leaf by index
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(3), m.DbTx).Return(entry, nil).Once()
+
+	res, err := s.JSONRPCCall("zkevm_getL1InfoTreeLeaf", "0x3")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, expectedLeaf, string(res.Result))
+
+	// leaf not found
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(4), m.DbTx).Return(state.L1InfoTreeExitRootStorageEntry{}, nil).Once()
+
+	res, err = s.JSONRPCCall("zkevm_getL1InfoTreeLeaf", "0x4")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	// leaf by global exit root
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL1InfoTreeLeafByGlobalExitRoot", context.Background(), ger, m.DbTx).Return(entry, nil).Once()
+
+	res, err = s.JSONRPCCall("zkevm_getL1InfoTreeLeafByGER", ger.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, expectedLeaf, string(res.Result))
+
+	// proof against the latest root
+	siblings := make([][32]byte, 32)
+	siblings[0] = common.HexToHash("0x6")
+	latestRoot := common.HexToHash("0x7")
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(3), m.DbTx).Return(entry, nil).Once()
+	m.State.On("GetL1InfoTreeMerkleProof", context.Background(), uint32(3), (*common.Hash)(nil), m.DbTx).Return(siblings, latestRoot, nil).Once()
+
+	res, err = s.JSONRPCCall("zkevm_getL1InfoTreeProof", "0x3", "latest")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var proof types.L1InfoTreeProof
+	require.NoError(t, json.Unmarshal(res.Result, &proof))
+	assert.Equal(t, types.ArgUint64(3), proof.Index)
+	assert.Equal(t, entry.Hash(), proof.Leaf)
+	assert.Equal(t, latestRoot, proof.L1InfoRoot)
+	require.Len(t, proof.Proof, 32)
+	assert.Equal(t, common.HexToHash("0x6"), proof.Proof[0])
+
+	// the leaf is not in the tree of the provided root
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL1InfoRootLeafByIndex", context.Background(), uint32(3), m.DbTx).Return(entry, nil).Once()
+	m.State.On("GetL1InfoTreeMerkleProof", context.Background(), uint32(3), &entry.L1InfoTreeRoot, m.DbTx).Return(nil, state.ZeroHash, state.ErrNotFound).Once()
+
+	res, err = s.JSONRPCCall("zkevm_getL1InfoTreeProof", "0x3", entry.L1InfoTreeRoot.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	// invalid index
+	res, err = s.JSONRPCCall("zkevm_getL1InfoTreeProof", "0x100000000")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestGetBatchProofStatus(t *testing.T) {
+	const batchNumber = uint64(7)
+	proverName, proverID := "prover", "proverId"
+	generatingSince := time.Unix(1700000000, 0)
+	verifiedBatch := &state.VerifiedBatch{BatchNumber: 10, TxHash: common.HexToHash("0x1")}
+	sentFinalProof := &state.FinalProof{BatchNumber: 5, BatchNumberFinal: 10, Proof: "0x01", Status: state.FinalProofStatusSent}
+	verifiedFinalProof := &state.FinalProof{BatchNumber: 5, BatchNumberFinal: 10, Proof: "0x01", Status: state.FinalProofStatusVerified}
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "batch not found",
+			ExpectedResult: `null`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:           "not started",
+			ExpectedResult: `{"status":"notStarted"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetGeneratedProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:           "generating",
+			ExpectedResult: `{"status":"generating","batchNumber":"0x7","batchNumberFinal":"0x7","prover":"prover","proverId":"proverId","generatingSince":"0x6553f100"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.
+					On("GetGeneratedProofByBatchNumber", context.Background(), batchNumber, m.DbTx).
+					Return(&state.Proof{BatchNumber: 7, BatchNumberFinal: 7, Prover: &proverName, ProverID: &proverID, GeneratingSince: &generatingSince}, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "batch proof",
+			ExpectedResult: `{"status":"batchProof","batchNumber":"0x7","batchNumberFinal":"0x7"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.
+					On("GetGeneratedProofByBatchNumber", context.Background(), batchNumber, m.DbTx).
+					Return(&state.Proof{BatchNumber: 7, BatchNumberFinal: 7, Prover: &proverName, ProverID: &proverID}, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "aggregated",
+			ExpectedResult: `{"status":"aggregated","batchNumber":"0x5","batchNumberFinal":"0x8"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.
+					On("GetGeneratedProofByBatchNumber", context.Background(), batchNumber, m.DbTx).
+					Return(&state.Proof{BatchNumber: 5, BatchNumberFinal: 8, Prover: &proverName, ProverID: &proverID}, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "final proof sent and not verified yet",
+			ExpectedResult: `{"status":"finalProofSent","batchNumber":"0x5","batchNumberFinal":"0xa"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(sentFinalProof, nil).Once()
+			},
+		},
+		{
+			Name:           "final proof verified and not synchronized yet",
+			ExpectedResult: `{"status":"verified","batchNumber":"0x5","batchNumberFinal":"0xa"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(verifiedFinalProof, nil).Once()
+			},
+		},
+		{
+			Name:           "final proof verified",
+			ExpectedResult: `{"status":"verified","batchNumber":"0x5","batchNumberFinal":"0xa","verifyBatchTxHash":"` + verifiedBatch.TxHash.String() + `"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(verifiedBatch, nil).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(verifiedFinalProof, nil).Once()
+			},
+		},
+		{
+			Name:           "final proof sent and the batches verified by another proof",
+			ExpectedResult: `{"status":"verified","batchNumberFinal":"0xa","verifyBatchTxHash":"` + verifiedBatch.TxHash.String() + `"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(verifiedBatch, nil).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(sentFinalProof, nil).Once()
+			},
+		},
+		{
+			Name:           "verified without a stored final proof",
+			ExpectedResult: `{"status":"verified","batchNumberFinal":"0xa","verifyBatchTxHash":"` + verifiedBatch.TxHash.String() + `"}`,
+			SetupMocks: func(m *mocksWrapper) {
+				m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(&state.Batch{}, nil).Once()
+				m.State.On("GetVerifiedBatchContaining", context.Background(), batchNumber, m.DbTx).Return(verifiedBatch, nil).Once()
+				m.State.On("GetFinalProofByBatchNumber", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getBatchProofStatus", hex.EncodeUint64(batchNumber))
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+			assert.JSONEq(t, tc.ExpectedResult, string(res.Result))
+		})
+	}
+}
+
+func TestGetFinalProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	proofID := "proofId"
+	verifiedBatch := &state.VerifiedBatch{BatchNumber: 10, TxHash: common.HexToHash("0x1")}
+
+	// not verified yet
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetVerifiedBatch", context.Background(), uint64(10), m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+	res, err := s.JSONRPCCall("zkevm_getFinalProof", "0x5", "0xa")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	// verified
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetVerifiedBatch", context.Background(), uint64(10), m.DbTx).Return(verifiedBatch, nil).Once()
+	m.State.
+		On("GetFinalProof", context.Background(), uint64(5), uint64(10), m.DbTx).
+		Return(&state.FinalProof{BatchNumber: 5, BatchNumberFinal: 10, Proof: "0x0102", ProofID: &proofID, Status: state.FinalProofStatusVerified}, nil).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getFinalProof", "0x5", "0xa")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, `{"batchNumber":"0x5","batchNumberFinal":"0xa","proofId":"proofId","proof":"0x0102","verifyBatchTxHash":"`+verifiedBatch.TxHash.String()+`"}`, string(res.Result))
+
+	// verified by another proof
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetVerifiedBatch", context.Background(), uint64(10), m.DbTx).Return(verifiedBatch, nil).Once()
+	m.State.
+		On("GetFinalProof", context.Background(), uint64(5), uint64(10), m.DbTx).
+		Return(&state.FinalProof{BatchNumber: 5, BatchNumberFinal: 10, Proof: "0x0102", ProofID: &proofID, Status: state.FinalProofStatusSent}, nil).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getFinalProof", "0x5", "0xa")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	// invalid range
+	res, err = s.JSONRPCCall("zkevm_getFinalProof", "0xa", "0x5")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
+func TestGetTransactionPoolStatus(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	hash := common.HexToHash("0x1")
+	failedReason := "failed to add tx to the pool: out of counters"
+
+	m.Pool.On("GetTransactionByHash", context.Background(), hash).Return(nil, pool.ErrNotFound).Once()
+	res, err := s.JSONRPCCall("zkevm_getTransactionPoolStatus", hash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	m.Pool.On("GetTransactionByHash", context.Background(), hash).Return(&pool.Transaction{Status: pool.TxStatusQueued}, nil).Once()
+	res, err = s.JSONRPCCall("zkevm_getTransactionPoolStatus", hash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, `{"status":"queued"}`, string(res.Result))
+
+	m.Pool.On("GetTransactionByHash", context.Background(), hash).Return(&pool.Transaction{Status: pool.TxStatusFailed, FailedReason: &failedReason}, nil).Once()
+	res, err = s.JSONRPCCall("zkevm_getTransactionPoolStatus", hash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, `{"status":"failed","failedReason":"`+failedReason+`"}`, string(res.Result))
+}
+
 func TestGetLatestGlobalExitRoot(t *testing.T) {
 	type testCase struct {
 		Name           string
@@ -2705,3 +3017,73 @@ func TestGetLatestGlobalExitRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestZKEVMSubscribe(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	var onStateEvent state.StateEventHandler
+	for _, call := range m.State.Calls {
+		if call.Method == "RegisterStateEventHandler" {
+			onStateEvent = call.Arguments.Get(0).(state.StateEventHandler)
+		}
+	}
+	require.NotNil(t, onStateEvent)
+
+	// the subscriptions are kept by a real storage so the events reach the ws connection
+	storage := NewStorage()
+	m.Storage.
+		On("NewStateEventFilter", mock.IsType(&concurrentWsConn{}), FilterType(FilterTypeVerifiedBatches)).
+		Return(func(wsConn *concurrentWsConn, filterType FilterType) (string, error) {
+			return storage.NewStateEventFilter(wsConn, filterType)
+		}).
+		Once()
+	m.Storage.
+		On("GetAllStateEventFiltersWithWSConn", FilterType(FilterTypeVerifiedBatches)).
+		Return(func(filterType FilterType) []*Filter {
+			return storage.GetAllStateEventFiltersWithWSConn(filterType)
+		}).
+		Once()
+
+	m.Storage.
+		On("UninstallFilter", mock.IsType("")).
+		Return(func(filterID string) error {
+			return storage.UninstallFilter(filterID)
+		}).
+		Once()
+
+	c := s.GetWSClient().Client()
+
+	_, err := c.Subscribe(context.Background(), "zkevm", make(chan interface{}), "unknown")
+	require.Error(t, err)
+	assert.Equal(t, "invalid subscription name", err.Error())
+
+	verifiedBatches := make(chan types.VerifiedBatch, 1)
+	sub, err := c.Subscribe(context.Background(), "zkevm", verifiedBatches, FilterTypeVerifiedBatches)
+	require.NoError(t, err)
+
+	onStateEvent(state.StateEvent{
+		Type:        state.StateEventVerifiedBatch,
+		BatchNumber: 7,
+		TxHash:      common.HexToHash("0x7"),
+		StateRoot:   common.HexToHash("0x8"),
+		BlockNumber: 100,
+	})
+
+	select {
+	case verifiedBatch := <-verifiedBatches:
+		assert.Equal(t, types.VerifiedBatch{
+			Number:            7,
+			StateRoot:         common.HexToHash("0x8"),
+			VerifyBatchTxHash: common.HexToHash("0x7"),
+			L1BlockNumber:     100,
+		}, verifiedBatch)
+	case err := <-sub.Err():
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "verified batch not notified")
+	}
+
+	sub.Unsubscribe()
+	assert.Empty(t, storage.GetAllStateEventFiltersWithWSConn(FilterTypeVerifiedBatches))
+}
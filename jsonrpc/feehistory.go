@@ -0,0 +1,218 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// maxFeeHistoryRewardPercentiles is the max number of percentiles accepted by eth_feeHistory
+	maxFeeHistoryRewardPercentiles = 100
+)
+
+// feeHistoryTx is the fee data of a tx used to compute the reward percentiles
+type feeHistoryTx struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+// feeHistoryBlock is the fee data of a block
+type feeHistoryBlock struct {
+	gasUsedRatio float64
+	// txs are sorted by reward in ascending order
+	txs []feeHistoryTx
+}
+
+// rewards returns the rewards at the provided percentiles of the gas used by the block txs
+func (b *feeHistoryBlock) rewards(percentiles []float64, gasUsed uint64) []types.ArgBig {
+	rewards := make([]types.ArgBig, len(percentiles))
+	if len(b.txs) == 0 {
+		for i := range rewards {
+			rewards[i] = types.ArgBig(*big.NewInt(0))
+		}
+		return rewards
+	}
+
+	txIndex := 0
+	sumGasUsed := b.txs[0].gasUsed
+	for i, percentile := range percentiles {
+		thresholdGasUsed := uint64(float64(gasUsed) * percentile / 100) //nolint:gomnd
+		for sumGasUsed < thresholdGasUsed && txIndex < len(b.txs)-1 {
+			txIndex++
+			sumGasUsed += b.txs[txIndex].gasUsed
+		}
+		rewards[i] = types.ArgBig(*b.txs[txIndex].reward)
+	}
+	return rewards
+}
+
+// feeHistoryCache keeps the fee data of the last queried blocks indexed by block hash,
+// so it remains valid if the blocks are reorganized
+type feeHistoryCache struct {
+	size   int
+	mu     sync.Mutex
+	blocks map[common.Hash]*feeHistoryBlock
+	hashes []common.Hash
+}
+
+func newFeeHistoryCache(size int) *feeHistoryCache {
+	return &feeHistoryCache{
+		size:   size,
+		blocks: make(map[common.Hash]*feeHistoryBlock, size),
+		hashes: make([]common.Hash, 0, size),
+	}
+}
+
+func (c *feeHistoryCache) get(hash common.Hash) (*feeHistoryBlock, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block, found := c.blocks[hash]
+	return block, found
+}
+
+func (c *feeHistoryCache) add(hash common.Hash, block *feeHistoryBlock) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.blocks[hash]; found {
+		return
+	}
+	if len(c.hashes) >= c.size {
+		delete(c.blocks, c.hashes[0])
+		c.hashes = c.hashes[1:]
+	}
+	c.blocks[hash] = block
+	c.hashes = append(c.hashes, hash)
+}
+
+// FeeHistory returns the gas used ratio and the rewards paid at the requested percentiles
+// for a range of blocks. The L2 has no base fee, so the base fees are always zero and the
+// rewards are the effective gas prices paid by the txs
+func (e *EthEndpoints) FeeHistory(blockCount types.ArgUint64, newestBlock types.BlockNumber, rewardPercentiles []float64) (interface{}, types.Error) {
+	if len(rewardPercentiles) > maxFeeHistoryRewardPercentiles {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("too many reward percentiles, max allowed is %d", maxFeeHistoryRewardPercentiles), nil, false)
+	}
+	for i, percentile := range rewardPercentiles {
+		if percentile < 0 || percentile > 100 {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid reward percentile: %f", percentile), nil, false)
+		}
+		if i > 0 && percentile < rewardPercentiles[i-1] {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid reward percentile: #%d:%f > #%d:%f", i-1, rewardPercentiles[i-1], i, percentile), nil, false)
+		}
+	}
+
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if blockCount == 0 {
+			return types.FeeHistory{GasUsedRatio: []float64{}}, nil
+		}
+		if e.cfg.MaxFeeHistoryBlockCount > 0 && uint64(blockCount) > e.cfg.MaxFeeHistoryBlockCount {
+			blockCount = types.ArgUint64(e.cfg.MaxFeeHistoryBlockCount)
+		}
+
+		newestBlockNumber, rpcErr := newestBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		oldestBlockNumber := uint64(0)
+		if newestBlockNumber+1 > uint64(blockCount) {
+			oldestBlockNumber = newestBlockNumber + 1 - uint64(blockCount)
+		}
+
+		count := newestBlockNumber - oldestBlockNumber + 1
+		result := types.FeeHistory{
+			OldestBlock:  types.ArgUint64(oldestBlockNumber),
+			BaseFee:      make([]types.ArgBig, count+1),
+			GasUsedRatio: make([]float64, 0, count),
+		}
+		if len(rewardPercentiles) > 0 {
+			result.Reward = make([][]types.ArgBig, 0, count)
+		}
+		for i := range result.BaseFee {
+			result.BaseFee[i] = types.ArgBig(*big.NewInt(0))
+		}
+
+		for blockNumber := oldestBlockNumber; blockNumber <= newestBlockNumber; blockNumber++ {
+			l2Block, err := e.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+			if errors.Is(err, state.ErrNotFound) {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("block %d not found", blockNumber), nil, false)
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get block %d from state", blockNumber), err, true)
+			}
+
+			feeBlock, err := e.getFeeHistoryBlock(ctx, l2Block, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get the fee data of block %d", blockNumber), err, true)
+			}
+
+			result.GasUsedRatio = append(result.GasUsedRatio, feeBlock.gasUsedRatio)
+			if len(rewardPercentiles) > 0 {
+				result.Reward = append(result.Reward, feeBlock.rewards(rewardPercentiles, l2Block.GasUsed()))
+			}
+		}
+
+		return result, nil
+	})
+}
+
+// getFeeHistoryBlock returns the fee data of the block, loading the receipts
+// of its txs from the state if it's not cached yet
+func (e *EthEndpoints) getFeeHistoryBlock(ctx context.Context, l2Block *state.L2Block, dbTx pgx.Tx) (*feeHistoryBlock, error) {
+	if feeBlock, found := e.feeHistoryCache.get(l2Block.Hash()); found {
+		return feeBlock, nil
+	}
+
+	feeBlock := &feeHistoryBlock{
+		txs: make([]feeHistoryTx, 0, len(l2Block.Transactions())),
+	}
+	if e.cfg.MaxCumulativeGasUsed > 0 {
+		feeBlock.gasUsedRatio = float64(l2Block.GasUsed()) / float64(e.cfg.MaxCumulativeGasUsed)
+	}
+
+	if len(l2Block.Transactions()) > 0 {
+		receipts, err := e.state.GetTransactionReceiptsByL2BlockNumber(ctx, l2Block.NumberU64(), dbTx)
+		if err != nil {
+			return nil, err
+		}
+		receiptsByTxHash := make(map[common.Hash]*ethTypes.Receipt, len(receipts))
+		for _, receipt := range receipts {
+			receiptsByTxHash[receipt.TxHash] = receipt
+		}
+		for _, tx := range l2Block.Transactions() {
+			receipt, found := receiptsByTxHash[tx.Hash()]
+			if !found {
+				return nil, fmt.Errorf("receipt of tx %v not found", tx.Hash().String())
+			}
+			reward := tx.GasPrice()
+			if receipt.EffectiveGasPrice != nil {
+				reward = receipt.EffectiveGasPrice
+			}
+			feeBlock.txs = append(feeBlock.txs, feeHistoryTx{gasUsed: receipt.GasUsed, reward: reward})
+		}
+	}
+	sort.SliceStable(feeBlock.txs, func(i, j int) bool {
+		return feeBlock.txs[i].reward.Cmp(feeBlock.txs[j].reward) < 0
+	})
+
+	e.feeHistoryCache.add(l2Block.Hash(), feeBlock)
+	return feeBlock, nil
+}
+
+// MaxPriorityFeePerGas returns the suggested priority fee for the txs. The L2 has no base
+// fee, so the priority fee is the whole gas price suggested by eth_gasPrice. Returning
+// zero instead would make the wallets computing the max fee as twice the base fee plus
+// the priority fee send txs with a zero gas price, that the pool rejects
+func (e *EthEndpoints) MaxPriorityFeePerGas() (interface{}, types.Error) {
+	return e.GasPrice()
+}
@@ -0,0 +1,1211 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/graph-gophers/graphql-go/trace"
+)
+
+const (
+	batchStatusTrusted     = "TRUSTED"
+	batchStatusVirtualized = "VIRTUALIZED"
+	batchStatusVerified    = "VERIFIED"
+)
+
+var (
+	// ErrBlockRangeTooLarge is returned when the blocks query exceeds the max block range
+	ErrBlockRangeTooLarge = errors.New("block range too large")
+	// ErrInvalidBlockRange is returned when the first block of a range is greater than the last one
+	ErrInvalidBlockRange = errors.New("invalid block range")
+	// ErrQueryCostExceeded is returned when a query resolves more fields than the max query cost
+	ErrQueryCostExceeded = errors.New("query cost exceeded")
+)
+
+// Config has the limits applied to the GraphQL queries
+type Config struct {
+	// MaxQueryDepth is the max depth of the queries, if zero it means no limit
+	MaxQueryDepth int
+	// MaxQueryCost is the max number of fields loaded from the state by a query, if zero it means no limit
+	MaxQueryCost uint64
+	// MaxBlockRange is the max number of blocks returned by the blocks query, if zero it means no limit
+	MaxBlockRange uint64
+	// MaxLogsCount is the max number of logs returned by the logs query
+	MaxLogsCount uint64
+	// MaxLogsBlockRange is the max block range of the logs query
+	MaxLogsBlockRange uint64
+}
+
+// Request is a GraphQL query sent over http
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response is the response of a GraphQL query
+type Response = graphql.Response
+
+// ErrorResponse returns the response of a query rejected before being executed,
+// the code of the error is provided in the extensions of the error
+func ErrorResponse(message string, code int) *Response {
+	return &Response{Errors: []*gqlErrors.QueryError{{
+		Message:    message,
+		Extensions: map[string]interface{}{"code": code},
+	}}}
+}
+
+// Handler executes the GraphQL queries over the state
+type Handler struct {
+	cfg    Config
+	schema *graphql.Schema
+}
+
+// NewHandler returns the handler that executes the GraphQL queries over the state
+func NewHandler(cfg Config, chainID uint64, s types.StateInterface) (*Handler, error) {
+	r := &Resolver{cfg: cfg, chainID: chainID, state: s}
+	parsed, err := graphql.ParseSchema(schema, r, graphql.MaxDepth(cfg.MaxQueryDepth), graphql.Tracer(costTracer{}))
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{cfg: cfg, schema: parsed}, nil
+}
+
+// Exec executes the query, the query is aborted once it exceeds the max query cost
+func (h *Handler) Exec(ctx context.Context, req Request) *Response {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cost := &queryCost{max: h.cfg.MaxQueryCost, cancel: cancel}
+	res := h.schema.Exec(context.WithValue(ctx, queryCostKey{}, cost), req.Query, req.OperationName, req.Variables)
+	if cost.exceeded.Load() {
+		return ErrorResponse(fmt.Sprintf("%v, the max cost is %d", ErrQueryCostExceeded, h.cfg.MaxQueryCost), http.StatusRequestEntityTooLarge)
+	}
+	return res
+}
+
+type queryCostKey struct{}
+
+// queryCost counts the fields of a query loaded from the state
+type queryCost struct {
+	max      uint64
+	used     atomic.Uint64
+	exceeded atomic.Bool
+	cancel   context.CancelFunc
+}
+
+// costTracer charges the cost of the fields resolved by a query, the trivial fields
+// are read from the objects already loaded so only the other ones are charged. The
+// query context is cancelled once the cost is exceeded, so no more fields are resolved
+type costTracer struct{}
+
+// TraceQuery is called when a query starts to be executed
+func (costTracer) TraceQuery(ctx context.Context, _ string, _ string, _ map[string]interface{}, _ map[string]*introspection.Type) (context.Context, trace.TraceQueryFinishFunc) {
+	return ctx, func([]*gqlErrors.QueryError) {}
+}
+
+// TraceField is called when a field of the query is resolved
+func (costTracer) TraceField(ctx context.Context, _, _, _ string, trivial bool, _ map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
+	noop := func(*gqlErrors.QueryError) {}
+	cost, ok := ctx.Value(queryCostKey{}).(*queryCost)
+	if trivial || !ok || cost.max == 0 {
+		return ctx, noop
+	}
+	if cost.used.Add(1) > cost.max {
+		cost.exceeded.Store(true)
+		cost.cancel()
+	}
+	return ctx, noop
+}
+
+// Long is a 64 bit unsigned integer argument, accepted as a
+// JSON number or as a decimal or 0x-prefixed hexadecimal string
+type Long uint64
+
+// ImplementsGraphQLType returns true if Long implements the specified GraphQL type.
+func (Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL unmarshals the provided GraphQL query data.
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		if strings.HasPrefix(input, "0x") {
+			value, err := hexutil.DecodeUint64(input)
+			*l = Long(value)
+			return err
+		}
+		value, err := strconv.ParseUint(input, 10, 64) // nolint:gomnd
+		*l = Long(value)
+		return err
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("negative value %d for Long", input)
+		}
+		*l = Long(input)
+	case int64:
+		if input < 0 {
+			return fmt.Errorf("negative value %d for Long", input)
+		}
+		*l = Long(input)
+	case float64:
+		if input < 0 {
+			return fmt.Errorf("negative value %v for Long", input)
+		}
+		*l = Long(input)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+// stateError logs the error returned by the state and hides
+// its details to the client behind the provided message
+func stateError(msg string, err error) error {
+	log.Errorf("%s: %v", msg, err)
+	return errors.New(msg)
+}
+
+// Resolver is the root resolver of the queries
+type Resolver struct {
+	cfg     Config
+	chainID uint64
+	state   types.StateInterface
+}
+
+// Block fetches a block by number or by hash, the latest block is returned if neither is provided
+func (r *Resolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *common.Hash
+}) (*Block, error) {
+	switch {
+	case args.Hash != nil:
+		return r.getBlockByHash(ctx, *args.Hash)
+	case args.Number != nil:
+		return r.getBlockByNumber(ctx, uint64(*args.Number))
+	default:
+		block, err := r.state.GetLastL2Block(ctx, nil)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, stateError("failed to load the last block", err)
+		}
+		return &Block{r: r, block: block}, nil
+	}
+}
+
+// Blocks returns the blocks between two numbers, inclusive, the last block is the latest block if not provided
+func (r *Resolver) Blocks(ctx context.Context, args struct {
+	From *Long
+	To   *Long
+}) ([]*Block, error) {
+	lastBlockNumber, err := r.state.GetLastL2BlockNumber(ctx, nil)
+	if err != nil {
+		return nil, stateError("failed to load the last block number", err)
+	}
+
+	var from, to uint64
+	if args.From != nil {
+		from = uint64(*args.From)
+	}
+	to = lastBlockNumber
+	if args.To != nil && uint64(*args.To) < lastBlockNumber {
+		to = uint64(*args.To)
+	}
+	if from > to {
+		return []*Block{}, nil
+	}
+	if r.cfg.MaxBlockRange > 0 && to-from+1 > r.cfg.MaxBlockRange {
+		return nil, fmt.Errorf("%w, the max range is %d blocks", ErrBlockRangeTooLarge, r.cfg.MaxBlockRange)
+	}
+
+	blocks := make([]*Block, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		block, err := r.getBlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		} else if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// Transaction returns a transaction by its hash
+func (r *Resolver) Transaction(ctx context.Context, args struct{ Hash common.Hash }) (*Transaction, error) {
+	return r.getTransactionByHash(ctx, args.Hash)
+}
+
+// Logs returns the logs matching the filter, the range defaults to the latest block
+func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) ([]*Log, error) {
+	lastBlockNumber, err := r.state.GetLastL2BlockNumber(ctx, nil)
+	if err != nil {
+		return nil, stateError("failed to load the last block number", err)
+	}
+
+	from, to := lastBlockNumber, lastBlockNumber
+	if args.Filter.FromBlock != nil {
+		from = uint64(*args.Filter.FromBlock)
+	}
+	if args.Filter.ToBlock != nil {
+		to = uint64(*args.Filter.ToBlock)
+	}
+	if from > to {
+		return nil, ErrInvalidBlockRange
+	}
+	return r.getLogs(ctx, from, to, args.Filter.Addresses, args.Filter.Topics, nil)
+}
+
+// Syncing returns the synchronization state, nil is returned if the node is synced
+func (r *Resolver) Syncing(ctx context.Context) (*SyncState, error) {
+	info, err := r.state.GetSyncingInfo(ctx, nil)
+	if err != nil {
+		return nil, stateError("failed to get syncing info", err)
+	}
+	if !info.IsSynchronizing {
+		return nil, nil
+	}
+	return &SyncState{info: info}, nil
+}
+
+// ChainID returns the chain ID
+func (r *Resolver) ChainID() hexutil.Big {
+	return hexutil.Big(*new(big.Int).SetUint64(r.chainID))
+}
+
+// Batch fetches a batch by number, the latest batch is returned if the number isn't provided
+func (r *Resolver) Batch(ctx context.Context, args struct{ Number *Long }) (*Batch, error) {
+	if args.Number != nil {
+		return r.getBatch(ctx, uint64(*args.Number))
+	}
+	batchNumber, err := r.state.GetLastBatchNumber(ctx, nil)
+	if err != nil {
+		return nil, stateError("failed to load the last batch number", err)
+	}
+	return r.getBatch(ctx, batchNumber)
+}
+
+// ForcedBatch fetches a forced batch by number
+func (r *Resolver) ForcedBatch(ctx context.Context, args struct{ Number Long }) (*ForcedBatch, error) {
+	return r.getForcedBatch(ctx, uint64(args.Number))
+}
+
+// L1InfoTreeLeaf fetches a leaf of the L1InfoTree by index
+func (r *Resolver) L1InfoTreeLeaf(ctx context.Context, args struct{ Index Long }) (*L1InfoTreeLeaf, error) {
+	if uint64(args.Index) > uint64(^uint32(0)) {
+		return nil, nil
+	}
+	leaf, err := r.state.GetL1InfoRootLeafByIndex(ctx, uint32(args.Index), nil)
+	if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load the L1InfoTree leaf %d", args.Index), err)
+	}
+	// the storage returns an empty leaf if the index isn't found
+	if leaf.L1InfoTreeRoot == (common.Hash{}) {
+		return nil, nil
+	}
+	return &L1InfoTreeLeaf{leaf: leaf}, nil
+}
+
+func (r *Resolver) getBlockByNumber(ctx context.Context, number uint64) (*Block, error) {
+	block, err := r.state.GetL2BlockByNumber(ctx, number, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load block %d", number), err)
+	}
+	return &Block{r: r, block: block}, nil
+}
+
+func (r *Resolver) getBlockByHash(ctx context.Context, hash common.Hash) (*Block, error) {
+	block, err := r.state.GetL2BlockByHash(ctx, hash, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load block %s", hash.String()), err)
+	}
+	return &Block{r: r, block: block}, nil
+}
+
+func (r *Resolver) getTransactionByHash(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	tx, err := r.state.GetTransactionByHash(ctx, hash, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load tx %s", hash.String()), err)
+	}
+	return &Transaction{r: r, tx: tx}, nil
+}
+
+func (r *Resolver) getLogs(ctx context.Context, from, to uint64, addresses *[]common.Address, topics *[][]common.Hash, blockHash *common.Hash) ([]*Log, error) {
+	var filterAddresses []common.Address
+	if addresses != nil {
+		filterAddresses = *addresses
+	}
+	var filterTopics [][]common.Hash
+	if topics != nil {
+		filterTopics = *topics
+	}
+
+	logs, err := r.state.GetLogs(ctx, from, to, filterAddresses, filterTopics, blockHash, nil, nil)
+	if errors.Is(err, state.ErrMaxLogsCountLimitExceeded) {
+		return nil, fmt.Errorf(state.ErrMaxLogsCountLimitExceeded.Error(), r.cfg.MaxLogsCount)
+	} else if errors.Is(err, state.ErrMaxLogsBlockRangeLimitExceeded) {
+		return nil, fmt.Errorf(state.ErrMaxLogsBlockRangeLimitExceeded.Error(), r.cfg.MaxLogsBlockRange)
+	} else if err != nil {
+		return nil, stateError("failed to get logs from state", err)
+	}
+
+	// the logs of the same tx share its resolver so it's loaded once
+	txs := make(map[common.Hash]*Transaction)
+	result := make([]*Log, 0, len(logs))
+	for _, l := range logs {
+		tx, found := txs[l.TxHash]
+		if !found {
+			tx = &Transaction{r: r, hash: l.TxHash}
+			txs[l.TxHash] = tx
+		}
+		result = append(result, &Log{r: r, tx: tx, log: l})
+	}
+	return result, nil
+}
+
+func (r *Resolver) getBatch(ctx context.Context, batchNumber uint64) (*Batch, error) {
+	batch, err := r.state.GetBatchByNumber(ctx, batchNumber, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load batch %d", batchNumber), err)
+	}
+
+	batchTimestamp, err := r.state.GetBatchTimestamp(ctx, batchNumber, nil, nil)
+	if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load the timestamp of batch %d", batchNumber), err)
+	}
+	var timestamp uint64
+	if batchTimestamp != nil {
+		timestamp = uint64(batchTimestamp.Unix())
+	}
+
+	virtualBatch, err := r.state.GetVirtualBatch(ctx, batchNumber, nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, stateError(fmt.Sprintf("failed to load virtual batch %d", batchNumber), err)
+	}
+
+	verifiedBatch, err := r.state.GetVerifiedBatch(ctx, batchNumber, nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, stateError(fmt.Sprintf("failed to load verified batch %d", batchNumber), err)
+	}
+
+	return &Batch{r: r, batch: batch, timestamp: timestamp, virtualBatch: virtualBatch, verifiedBatch: verifiedBatch}, nil
+}
+
+func (r *Resolver) getForcedBatch(ctx context.Context, forcedBatchNumber uint64) (*ForcedBatch, error) {
+	forcedBatch, err := r.state.GetForcedBatch(ctx, forcedBatchNumber, nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load forced batch %d", forcedBatchNumber), err)
+	}
+	return &ForcedBatch{forcedBatch: forcedBatch}, nil
+}
+
+// FilterCriteria is the filter of the logs query
+type FilterCriteria struct {
+	FromBlock *Long
+	ToBlock   *Long
+	Addresses *[]common.Address
+	Topics    *[][]common.Hash
+}
+
+// BlockFilterCriteria is the filter of the logs of a block
+type BlockFilterCriteria struct {
+	Addresses *[]common.Address
+	Topics    *[][]common.Hash
+}
+
+// SyncState is the synchronization state
+type SyncState struct {
+	info state.SyncingInfo
+}
+
+// StartingBlock is the first block synchronized
+func (s *SyncState) StartingBlock() hexutil.Uint64 {
+	return hexutil.Uint64(s.info.InitialSyncingBlock)
+}
+
+// CurrentBlock is the last block synchronized
+func (s *SyncState) CurrentBlock() hexutil.Uint64 {
+	return hexutil.Uint64(s.info.CurrentBlockNumber)
+}
+
+// HighestBlock is the estimated last block
+func (s *SyncState) HighestBlock() hexutil.Uint64 {
+	return hexutil.Uint64(s.info.EstimatedHighestBlock)
+}
+
+// Account is an account at the state of a block, the latest
+// block is used if the block isn't provided
+type Account struct {
+	r       *Resolver
+	address common.Address
+	block   *state.L2Block
+}
+
+func (a *Account) root(ctx context.Context) (common.Hash, error) {
+	if a.block != nil {
+		return a.block.Root(), nil
+	}
+	block, err := a.r.state.GetLastL2Block(ctx, nil)
+	if err != nil {
+		return common.Hash{}, stateError("failed to load the last block", err)
+	}
+	return block.Root(), nil
+}
+
+// Address is the address of the account
+func (a *Account) Address() common.Address {
+	return a.address
+}
+
+// Balance is the balance of the account
+func (a *Account) Balance(ctx context.Context) (hexutil.Big, error) {
+	root, err := a.root(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	balance, err := a.r.state.GetBalance(ctx, a.address, root)
+	if errors.Is(err, state.ErrNotFound) {
+		return hexutil.Big{}, nil
+	} else if err != nil {
+		return hexutil.Big{}, stateError("failed to get balance from state", err)
+	}
+	return hexutil.Big(*balance), nil
+}
+
+// TransactionCount is the nonce of the account
+func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error) {
+	root, err := a.root(ctx)
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := a.r.state.GetNonce(ctx, a.address, root)
+	if errors.Is(err, state.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, stateError("failed to count transactions", err)
+	}
+	return hexutil.Uint64(nonce), nil
+}
+
+// Code is the code of the account
+func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
+	root, err := a.root(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code, err := a.r.state.GetCode(ctx, a.address, root)
+	if errors.Is(err, state.ErrNotFound) {
+		return hexutil.Bytes{}, nil
+	} else if err != nil {
+		return nil, stateError("failed to get code", err)
+	}
+	return code, nil
+}
+
+// Storage is the value of a storage slot of the account
+func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash }) (common.Hash, error) {
+	root, err := a.root(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	value, err := a.r.state.GetStorageAt(ctx, a.address, args.Slot.Big(), root)
+	if errors.Is(err, state.ErrNotFound) {
+		return common.Hash{}, nil
+	} else if err != nil {
+		return common.Hash{}, stateError("failed to get storage value from state", err)
+	}
+	return common.BigToHash(value), nil
+}
+
+// Block is a L2 block
+type Block struct {
+	r     *Resolver
+	block *state.L2Block
+}
+
+// Number is the number of the block
+func (b *Block) Number() hexutil.Uint64 {
+	return hexutil.Uint64(b.block.NumberU64())
+}
+
+// Hash is the hash of the block
+func (b *Block) Hash() common.Hash {
+	return b.block.Hash()
+}
+
+// Parent is the parent block
+func (b *Block) Parent(ctx context.Context) (*Block, error) {
+	if b.block.NumberU64() == 0 {
+		return nil, nil
+	}
+	return b.r.getBlockByHash(ctx, b.block.ParentHash())
+}
+
+// Nonce is the nonce of the block
+func (b *Block) Nonce() hexutil.Bytes {
+	nonce := b.block.Nonce()
+	return new(big.Int).SetUint64(nonce).FillBytes(make([]byte, 8)) // nolint:gomnd
+}
+
+// TransactionsRoot is the root of the txs trie
+func (b *Block) TransactionsRoot() common.Hash {
+	return b.block.TxHash()
+}
+
+// TransactionCount is the number of txs of the block
+func (b *Block) TransactionCount() *hexutil.Uint64 {
+	count := hexutil.Uint64(len(b.block.Transactions()))
+	return &count
+}
+
+// StateRoot is the state root after processing the block
+func (b *Block) StateRoot() common.Hash {
+	return b.block.Root()
+}
+
+// ReceiptsRoot is the root of the receipts trie
+func (b *Block) ReceiptsRoot() common.Hash {
+	return b.block.ReceiptHash()
+}
+
+// Miner is the account receiving the fees of the block
+func (b *Block) Miner() *Account {
+	return &Account{r: b.r, address: b.block.Coinbase(), block: b.block}
+}
+
+// ExtraData is the extra data of the block
+func (b *Block) ExtraData() hexutil.Bytes {
+	return b.block.Extra()
+}
+
+// GasLimit is the gas limit of the block
+func (b *Block) GasLimit() hexutil.Uint64 {
+	return hexutil.Uint64(b.block.GasLimit())
+}
+
+// GasUsed is the gas used by the block
+func (b *Block) GasUsed() hexutil.Uint64 {
+	return hexutil.Uint64(b.block.GasUsed())
+}
+
+// Timestamp is the timestamp of the block
+func (b *Block) Timestamp() hexutil.Uint64 {
+	return hexutil.Uint64(b.block.Time())
+}
+
+// LogsBloom is the bloom filter of the logs of the block
+func (b *Block) LogsBloom() hexutil.Bytes {
+	bloom := b.block.Bloom()
+	return bloom.Bytes()
+}
+
+// MixHash is the mix hash of the block
+func (b *Block) MixHash() common.Hash {
+	return b.block.MixDigest()
+}
+
+// Difficulty is the difficulty of the block
+func (b *Block) Difficulty() hexutil.Big {
+	difficulty := b.block.Difficulty()
+	if difficulty == nil {
+		return hexutil.Big{}
+	}
+	return hexutil.Big(*difficulty)
+}
+
+// Transactions are the txs of the block
+func (b *Block) Transactions() *[]*Transaction {
+	txs := b.block.Transactions()
+	result := make([]*Transaction, 0, len(txs))
+	for i, tx := range txs {
+		index := uint64(i)
+		result = append(result, &Transaction{r: b.r, tx: tx, block: b.block, index: &index})
+	}
+	return &result
+}
+
+// TransactionAt returns the tx of the block at the index
+func (b *Block) TransactionAt(args struct{ Index Long }) *Transaction {
+	txs := b.block.Transactions()
+	if uint64(args.Index) >= uint64(len(txs)) {
+		return nil
+	}
+	index := uint64(args.Index)
+	return &Transaction{r: b.r, tx: txs[index], block: b.block, index: &index}
+}
+
+// Logs returns the logs of the block matching the filter
+func (b *Block) Logs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) ([]*Log, error) {
+	hash := b.block.Hash()
+	return b.r.getLogs(ctx, b.block.NumberU64(), b.block.NumberU64(), args.Filter.Addresses, args.Filter.Topics, &hash)
+}
+
+// Account returns an account at the state of the block
+func (b *Block) Account(args struct{ Address common.Address }) *Account {
+	return &Account{r: b.r, address: args.Address, block: b.block}
+}
+
+// Raw is the RLP encoding of the block
+func (b *Block) Raw() (hexutil.Bytes, error) {
+	return rlp.EncodeToBytes(b.block)
+}
+
+// GlobalExitRoot is the global exit root used by the block
+func (b *Block) GlobalExitRoot() common.Hash {
+	return b.block.GlobalExitRoot()
+}
+
+// BlockInfoRoot is the root of the block info tree of the block
+func (b *Block) BlockInfoRoot() common.Hash {
+	return b.block.BlockInfoRoot()
+}
+
+// Batch is the batch containing the block
+func (b *Block) Batch(ctx context.Context) (*Batch, error) {
+	batchNumber, err := b.r.state.BatchNumberByL2BlockNumber(ctx, b.block.NumberU64(), nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load the batch of block %d", b.block.NumberU64()), err)
+	}
+	return b.r.getBatch(ctx, batchNumber)
+}
+
+// Transaction is a L2 tx, the tx and its receipt are loaded on demand
+// when the resolver is created from the hash of the tx
+type Transaction struct {
+	r     *Resolver
+	hash  common.Hash
+	tx    *ethTypes.Transaction
+	block *state.L2Block
+	index *uint64
+
+	receipt       *ethTypes.Receipt
+	receiptLoaded bool
+	mutex         sync.Mutex
+}
+
+func (t *Transaction) resolve(ctx context.Context) (*ethTypes.Transaction, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.tx != nil {
+		return t.tx, nil
+	}
+	tx, err := t.r.state.GetTransactionByHash(ctx, t.hash, nil)
+	if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load tx %s", t.hash.String()), err)
+	}
+	t.tx = tx
+	return tx, nil
+}
+
+func (t *Transaction) getReceipt(ctx context.Context) (*ethTypes.Receipt, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.receiptLoaded {
+		return t.receipt, nil
+	}
+	receipt, err := t.r.state.GetTransactionReceipt(ctx, tx.Hash(), nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, stateError(fmt.Sprintf("failed to load the receipt of tx %s", tx.Hash().String()), err)
+	}
+	t.receipt = receipt
+	t.receiptLoaded = true
+	return receipt, nil
+}
+
+// Hash is the hash of the tx
+func (t *Transaction) Hash(ctx context.Context) (common.Hash, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// L2Hash is the hash of the tx computed by the zkEVM
+func (t *Transaction) L2Hash(ctx context.Context) (*common.Hash, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l2Hash, err := t.r.state.GetL2TxHashByTxHash(ctx, tx.Hash(), nil)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, stateError(fmt.Sprintf("failed to load the l2 hash of tx %s", tx.Hash().String()), err)
+	}
+	return l2Hash, nil
+}
+
+// Nonce is the nonce of the tx
+func (t *Transaction) Nonce(ctx context.Context) (hexutil.Uint64, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(tx.Nonce()), nil
+}
+
+// Index is the index of the tx in its block
+func (t *Transaction) Index(ctx context.Context) (*hexutil.Uint64, error) {
+	if t.index != nil {
+		index := hexutil.Uint64(*t.index)
+		return &index, nil
+	}
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	index := hexutil.Uint64(receipt.TransactionIndex)
+	return &index, nil
+}
+
+// From is the account that sent the tx
+func (t *Transaction) From(ctx context.Context) (*Account, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := state.GetSender(*tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the sender of tx %s: %w", tx.Hash().String(), err)
+	}
+	return &Account{r: t.r, address: from}, nil
+}
+
+// To is the account receiving the tx, nil for the contract creations
+func (t *Transaction) To(ctx context.Context) (*Account, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx.To() == nil {
+		return nil, err
+	}
+	return &Account{r: t.r, address: *tx.To()}, nil
+}
+
+// Value is the value sent by the tx
+func (t *Transaction) Value(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*tx.Value()), nil
+}
+
+// GasPrice is the gas price of the tx
+func (t *Transaction) GasPrice(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*tx.GasPrice()), nil
+}
+
+// EffectiveGasPrice is the gas price paid by the tx
+func (t *Transaction) EffectiveGasPrice(ctx context.Context) (*hexutil.Big, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil || receipt.EffectiveGasPrice == nil {
+		return nil, err
+	}
+	effectiveGasPrice := hexutil.Big(*receipt.EffectiveGasPrice)
+	return &effectiveGasPrice, nil
+}
+
+// Gas is the gas limit of the tx
+func (t *Transaction) Gas(ctx context.Context) (hexutil.Uint64, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(tx.Gas()), nil
+}
+
+// InputData is the data of the tx
+func (t *Transaction) InputData(ctx context.Context) (hexutil.Bytes, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Data(), nil
+}
+
+// Block is the block containing the tx
+func (t *Transaction) Block(ctx context.Context) (*Block, error) {
+	if t.block != nil {
+		return &Block{r: t.r, block: t.block}, nil
+	}
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return t.r.getBlockByNumber(ctx, receipt.BlockNumber.Uint64())
+}
+
+// Status is the status of the tx execution
+func (t *Transaction) Status(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	status := hexutil.Uint64(receipt.Status)
+	return &status, nil
+}
+
+// GasUsed is the gas used by the tx
+func (t *Transaction) GasUsed(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	gasUsed := hexutil.Uint64(receipt.GasUsed)
+	return &gasUsed, nil
+}
+
+// CumulativeGasUsed is the gas used by the block up to the tx
+func (t *Transaction) CumulativeGasUsed(ctx context.Context) (*hexutil.Uint64, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	cumulativeGasUsed := hexutil.Uint64(receipt.CumulativeGasUsed)
+	return &cumulativeGasUsed, nil
+}
+
+// CreatedContract is the account created by the tx, nil if the tx isn't a contract creation
+func (t *Transaction) CreatedContract(ctx context.Context) (*Account, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx.To() != nil {
+		return nil, err
+	}
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return &Account{r: t.r, address: receipt.ContractAddress}, nil
+}
+
+// Logs are the logs emitted by the tx
+func (t *Transaction) Logs(ctx context.Context) (*[]*Log, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	logs := make([]*Log, 0, len(receipt.Logs))
+	for _, l := range receipt.Logs {
+		logs = append(logs, &Log{r: t.r, tx: t, log: l})
+	}
+	return &logs, nil
+}
+
+// R is the R value of the signature
+func (t *Transaction) R(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	_, r, _ := tx.RawSignatureValues()
+	return hexutil.Big(*r), nil
+}
+
+// S is the S value of the signature
+func (t *Transaction) S(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	_, _, s := tx.RawSignatureValues()
+	return hexutil.Big(*s), nil
+}
+
+// V is the V value of the signature
+func (t *Transaction) V(ctx context.Context) (hexutil.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	v, _, _ := tx.RawSignatureValues()
+	return hexutil.Big(*v), nil
+}
+
+// Type is the type of the tx
+func (t *Transaction) Type(ctx context.Context) (*hexutil.Uint64, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txType := hexutil.Uint64(tx.Type())
+	return &txType, nil
+}
+
+// Raw is the canonical encoding of the tx
+func (t *Transaction) Raw(ctx context.Context) (hexutil.Bytes, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.MarshalBinary()
+}
+
+// RawReceipt is the canonical encoding of the receipt of the tx
+func (t *Transaction) RawReceipt(ctx context.Context) (hexutil.Bytes, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil {
+		return hexutil.Bytes{}, err
+	}
+	return receipt.MarshalBinary()
+}
+
+// Log is a log emitted by a tx
+type Log struct {
+	r   *Resolver
+	tx  *Transaction
+	log *ethTypes.Log
+}
+
+// Index is the index of the log in its block
+func (l *Log) Index() hexutil.Uint64 {
+	return hexutil.Uint64(l.log.Index)
+}
+
+// Account is the account emitting the log
+func (l *Log) Account() *Account {
+	return &Account{r: l.r, address: l.log.Address}
+}
+
+// Topics are the topics of the log
+func (l *Log) Topics() []common.Hash {
+	return l.log.Topics
+}
+
+// Data is the data of the log
+func (l *Log) Data() hexutil.Bytes {
+	return l.log.Data
+}
+
+// Transaction is the tx emitting the log
+func (l *Log) Transaction() *Transaction {
+	return l.tx
+}
+
+// Batch is a batch of L2 blocks with its virtualization and verification
+type Batch struct {
+	r             *Resolver
+	batch         *state.Batch
+	timestamp     uint64
+	virtualBatch  *state.VirtualBatch
+	verifiedBatch *state.VerifiedBatch
+}
+
+// Number is the number of the batch
+func (b *Batch) Number() hexutil.Uint64 {
+	return hexutil.Uint64(b.batch.BatchNumber)
+}
+
+// Status is the stage of the lifecycle reached by the batch
+func (b *Batch) Status() string {
+	switch {
+	case b.verifiedBatch != nil:
+		return batchStatusVerified
+	case b.virtualBatch != nil:
+		return batchStatusVirtualized
+	default:
+		return batchStatusTrusted
+	}
+}
+
+// Closed defines if the batch is closed
+func (b *Batch) Closed() bool {
+	return !b.batch.WIP
+}
+
+// Coinbase is the address receiving the fees of the batch
+func (b *Batch) Coinbase() common.Address {
+	return b.batch.Coinbase
+}
+
+// StateRoot is the state root after processing the batch
+func (b *Batch) StateRoot() common.Hash {
+	return b.batch.StateRoot
+}
+
+// GlobalExitRoot is the global exit root of the batch
+func (b *Batch) GlobalExitRoot() common.Hash {
+	return b.batch.GlobalExitRoot
+}
+
+// LocalExitRoot is the local exit root after processing the batch
+func (b *Batch) LocalExitRoot() common.Hash {
+	return b.batch.LocalExitRoot
+}
+
+// AccInputHash is the accumulated input hash of the batch
+func (b *Batch) AccInputHash() common.Hash {
+	return b.batch.AccInputHash
+}
+
+// Timestamp is the timestamp of the batch
+func (b *Batch) Timestamp() hexutil.Uint64 {
+	return hexutil.Uint64(b.timestamp)
+}
+
+// BatchL2Data is the encoded data of the batch
+func (b *Batch) BatchL2Data() hexutil.Bytes {
+	return b.batch.BatchL2Data
+}
+
+// SendSequencesTxHash is the hash of the L1 tx virtualizing the batch
+func (b *Batch) SendSequencesTxHash() *common.Hash {
+	if b.virtualBatch == nil {
+		return nil
+	}
+	return &b.virtualBatch.TxHash
+}
+
+// VerifyBatchTxHash is the hash of the L1 tx verifying the batch
+func (b *Batch) VerifyBatchTxHash() *common.Hash {
+	if b.verifiedBatch == nil {
+		return nil
+	}
+	return &b.verifiedBatch.TxHash
+}
+
+// L1InfoRoot is the L1InfoTree root used to virtualize the batch
+func (b *Batch) L1InfoRoot() *common.Hash {
+	if b.virtualBatch == nil {
+		return nil
+	}
+	return b.virtualBatch.L1InfoRoot
+}
+
+// ForcedBatch is the forced batch sequenced as the batch
+func (b *Batch) ForcedBatch(ctx context.Context) (*ForcedBatch, error) {
+	if b.batch.ForcedBatchNum == nil {
+		return nil, nil
+	}
+	return b.r.getForcedBatch(ctx, *b.batch.ForcedBatchNum)
+}
+
+// Blocks are the blocks of the batch
+func (b *Batch) Blocks(ctx context.Context) ([]*Block, error) {
+	blocks, err := b.r.state.GetL2BlocksByBatchNumber(ctx, b.batch.BatchNumber, nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, stateError(fmt.Sprintf("failed to load the blocks of batch %d", b.batch.BatchNumber), err)
+	}
+	result := make([]*Block, 0, len(blocks))
+	for i := range blocks {
+		result = append(result, &Block{r: b.r, block: &blocks[i]})
+	}
+	return result, nil
+}
+
+// ForcedBatch is a batch forced on L1
+type ForcedBatch struct {
+	forcedBatch *state.ForcedBatch
+}
+
+// Number is the number of the forced batch
+func (f *ForcedBatch) Number() hexutil.Uint64 {
+	return hexutil.Uint64(f.forcedBatch.ForcedBatchNumber)
+}
+
+// GlobalExitRoot is the global exit root when the batch was forced
+func (f *ForcedBatch) GlobalExitRoot() common.Hash {
+	return f.forcedBatch.GlobalExitRoot
+}
+
+// RawTxsData is the encoded txs of the forced batch
+func (f *ForcedBatch) RawTxsData() hexutil.Bytes {
+	return f.forcedBatch.RawTxsData
+}
+
+// Sequencer is the address forcing the batch
+func (f *ForcedBatch) Sequencer() common.Address {
+	return f.forcedBatch.Sequencer
+}
+
+// ForcedAt is the timestamp when the batch was forced
+func (f *ForcedBatch) ForcedAt() hexutil.Uint64 {
+	return hexutil.Uint64(f.forcedBatch.ForcedAt.Unix())
+}
+
+// L1BlockNumber is the L1 block forcing the batch
+func (f *ForcedBatch) L1BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(f.forcedBatch.BlockNumber)
+}
+
+// L1InfoTreeLeaf is a leaf of the L1InfoTree
+type L1InfoTreeLeaf struct {
+	leaf state.L1InfoTreeExitRootStorageEntry
+}
+
+// Index is the index of the leaf
+func (l *L1InfoTreeLeaf) Index() hexutil.Uint64 {
+	return hexutil.Uint64(l.leaf.L1InfoTreeIndex)
+}
+
+// L1InfoTreeRoot is the root of the tree after adding the leaf
+func (l *L1InfoTreeLeaf) L1InfoTreeRoot() common.Hash {
+	return l.leaf.L1InfoTreeRoot
+}
+
+// GlobalExitRoot is the global exit root of the leaf
+func (l *L1InfoTreeLeaf) GlobalExitRoot() common.Hash {
+	return l.leaf.GlobalExitRoot.GlobalExitRoot
+}
+
+// MainnetExitRoot is the mainnet exit root of the leaf
+func (l *L1InfoTreeLeaf) MainnetExitRoot() common.Hash {
+	return l.leaf.MainnetExitRoot
+}
+
+// RollupExitRoot is the rollup exit root of the leaf
+func (l *L1InfoTreeLeaf) RollupExitRoot() common.Hash {
+	return l.leaf.RollupExitRoot
+}
+
+// PreviousBlockHash is the hash of the L1 block before the one adding the leaf
+func (l *L1InfoTreeLeaf) PreviousBlockHash() common.Hash {
+	return l.leaf.PreviousBlockHash
+}
+
+// L1BlockNumber is the L1 block adding the leaf
+func (l *L1InfoTreeLeaf) L1BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(l.leaf.BlockNumber)
+}
+
+// Timestamp is the timestamp of the L1 block adding the leaf
+func (l *L1InfoTreeLeaf) Timestamp() hexutil.Uint64 {
+	return hexutil.Uint64(l.leaf.Timestamp.Unix())
+}
@@ -0,0 +1,178 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const chainID = uint64(1000)
+
+func query(t *testing.T, s *mocks.StateMock, q string) (map[string]interface{}, []interface{}) {
+	return queryWithConfig(t, s, Config{MaxQueryDepth: 10, MaxQueryCost: 100, MaxBlockRange: 2}, q)
+}
+
+func queryWithConfig(t *testing.T, s *mocks.StateMock, cfg Config, q string) (map[string]interface{}, []interface{}) {
+	handler, err := NewHandler(cfg, chainID, s)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(handler.Exec(context.Background(), Request{Query: q}))
+	require.NoError(t, err)
+
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []interface{}          `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	return response.Data, response.Errors
+}
+
+func TestBlockQuery(t *testing.T) {
+	s := mocks.NewStateMock(t)
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x2"), big.NewInt(10), 21000, big.NewInt(1), []byte{})
+	header := state.NewL2Header(&ethTypes.Header{
+		Number:   big.NewInt(5),
+		Root:     common.HexToHash("0x3"),
+		Coinbase: common.HexToAddress("0x4"),
+		Time:     100,
+	})
+	header.GlobalExitRoot = common.HexToHash("0x5")
+	block := state.NewL2Block(header, []*ethTypes.Transaction{tx}, nil, nil, trie.NewStackTrie(nil))
+	receipt := &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, GasUsed: 21000, BlockNumber: big.NewInt(5), TxHash: tx.Hash()}
+
+	s.On("GetL2BlockByNumber", mock.Anything, uint64(5), nil).Return(block, nil)
+	s.On("GetBalance", mock.Anything, common.HexToAddress("0x4"), common.HexToHash("0x3")).Return(big.NewInt(255), nil)
+	s.On("GetTransactionReceipt", mock.Anything, tx.Hash(), nil).Return(receipt, nil)
+	s.On("BatchNumberByL2BlockNumber", mock.Anything, uint64(5), nil).Return(uint64(2), nil)
+	s.On("GetBatchByNumber", mock.Anything, uint64(2), nil).Return(&state.Batch{BatchNumber: 2}, nil)
+	s.On("GetBatchTimestamp", mock.Anything, uint64(2), (*uint64)(nil), nil).Return(nil, nil)
+	s.On("GetVirtualBatch", mock.Anything, uint64(2), nil).Return(&state.VirtualBatch{BatchNumber: 2, TxHash: common.HexToHash("0x6")}, nil)
+	s.On("GetVerifiedBatch", mock.Anything, uint64(2), nil).Return(nil, state.ErrNotFound)
+
+	data, errs := query(t, s, `{
+		block(number: 5) {
+			number
+			hash
+			globalExitRoot
+			miner { address balance }
+			transactions { hash index value status gasUsed }
+			batch { number status closed sendSequencesTxHash verifyBatchTxHash }
+		}
+	}`)
+	require.Empty(t, errs)
+
+	result := data["block"].(map[string]interface{})
+	assert.Equal(t, "0x5", result["number"])
+	assert.Equal(t, block.Hash().String(), result["hash"])
+	assert.Equal(t, common.HexToHash("0x5").String(), result["globalExitRoot"])
+	assert.Equal(t, map[string]interface{}{"address": common.HexToAddress("0x4").String(), "balance": "0xff"}, result["miner"])
+
+	txs := result["transactions"].([]interface{})
+	require.Len(t, txs, 1)
+	assert.Equal(t, map[string]interface{}{"hash": tx.Hash().String(), "index": "0x0", "value": "0xa", "status": "0x1", "gasUsed": "0x5208"}, txs[0])
+
+	assert.Equal(t, map[string]interface{}{
+		"number":              "0x2",
+		"status":              batchStatusVirtualized,
+		"closed":              true,
+		"sendSequencesTxHash": common.HexToHash("0x6").String(),
+		"verifyBatchTxHash":   nil,
+	}, result["batch"])
+}
+
+func TestBlockQueryNotFound(t *testing.T) {
+	s := mocks.NewStateMock(t)
+	s.On("GetL2BlockByHash", mock.Anything, common.HexToHash("0x1"), nil).Return(nil, state.ErrNotFound)
+
+	data, errs := query(t, s, `{ block(hash: "0x0000000000000000000000000000000000000000000000000000000000000001") { number } }`)
+	require.Empty(t, errs)
+	assert.Nil(t, data["block"])
+}
+
+func TestBlocksQueryMaxBlockRange(t *testing.T) {
+	s := mocks.NewStateMock(t)
+	s.On("GetLastL2BlockNumber", mock.Anything, nil).Return(uint64(10), nil)
+
+	_, errs := query(t, s, `{ blocks(from: 1, to: 5) { number } }`)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].(map[string]interface{})["message"], ErrBlockRangeTooLarge.Error())
+}
+
+func TestQueryMaxCost(t *testing.T) {
+	s := mocks.NewStateMock(t)
+	header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(5), Coinbase: common.HexToAddress("0x4")})
+	block := state.NewL2Block(header, nil, nil, nil, trie.NewStackTrie(nil))
+	s.On("GetL2BlockByNumber", mock.Anything, uint64(5), nil).Return(block, nil)
+	s.On("GetBalance", mock.Anything, common.HexToAddress("0x4"), mock.Anything).Return(big.NewInt(1), nil).Maybe()
+
+	// the block, the miner and its balance are charged
+	cfg := Config{MaxQueryDepth: 10, MaxQueryCost: 3}
+	_, errs := queryWithConfig(t, s, cfg, `{ block(number: 5) { number miner { balance } } }`)
+	require.Empty(t, errs)
+
+	cfg.MaxQueryCost = 2
+	data, errs := queryWithConfig(t, s, cfg, `{ block(number: 5) { number miner { balance } } }`)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].(map[string]interface{})["message"], ErrQueryCostExceeded.Error())
+	assert.Nil(t, data)
+}
+
+func TestZKEVMQueries(t *testing.T) {
+	s := mocks.NewStateMock(t)
+
+	forcedBatchNum := uint64(3)
+	s.On("GetBatchByNumber", mock.Anything, uint64(7), nil).Return(&state.Batch{BatchNumber: 7, ForcedBatchNum: &forcedBatchNum, WIP: true}, nil)
+	s.On("GetBatchTimestamp", mock.Anything, uint64(7), (*uint64)(nil), nil).Return(nil, nil)
+	s.On("GetVirtualBatch", mock.Anything, uint64(7), nil).Return(nil, state.ErrNotFound)
+	s.On("GetVerifiedBatch", mock.Anything, uint64(7), nil).Return(nil, state.ErrNotFound)
+	s.On("GetForcedBatch", mock.Anything, uint64(3), nil).Return(&state.ForcedBatch{ForcedBatchNumber: 3, BlockNumber: 20, RawTxsData: []byte{0x1}, ForcedAt: time.Unix(50, 0)}, nil)
+	s.On("GetL1InfoRootLeafByIndex", mock.Anything, uint32(4), nil).Return(state.L1InfoTreeExitRootStorageEntry{
+		L1InfoTreeLeaf: state.L1InfoTreeLeaf{
+			GlobalExitRoot: state.GlobalExitRoot{BlockNumber: 30, GlobalExitRoot: common.HexToHash("0x8"), Timestamp: time.Unix(60, 0)},
+		},
+		L1InfoTreeRoot:  common.HexToHash("0x9"),
+		L1InfoTreeIndex: 4,
+	}, nil)
+	s.On("GetL1InfoRootLeafByIndex", mock.Anything, uint32(5), nil).Return(state.L1InfoTreeExitRootStorageEntry{}, nil)
+
+	data, errs := query(t, s, `{
+		batch(number: "0x7") { number status closed forcedBatch { number rawTxsData forcedAt l1BlockNumber } }
+		l1InfoTreeLeaf(index: 4) { index l1InfoTreeRoot globalExitRoot l1BlockNumber timestamp }
+		missingLeaf: l1InfoTreeLeaf(index: 5) { index }
+		chainID
+	}`)
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string]interface{}{
+		"number": "0x7",
+		"status": batchStatusTrusted,
+		"closed": false,
+		"forcedBatch": map[string]interface{}{
+			"number":        "0x3",
+			"rawTxsData":    "0x01",
+			"forcedAt":      "0x32",
+			"l1BlockNumber": "0x14",
+		},
+	}, data["batch"])
+	assert.Equal(t, map[string]interface{}{
+		"index":          "0x4",
+		"l1InfoTreeRoot": common.HexToHash("0x9").String(),
+		"globalExitRoot": common.HexToHash("0x8").String(),
+		"l1BlockNumber":  "0x1e",
+		"timestamp":      "0x3c",
+	}, data["l1InfoTreeLeaf"])
+	assert.Nil(t, data["missingLeaf"])
+	assert.Equal(t, "0x3e8", data["chainID"])
+}
@@ -0,0 +1,312 @@
+package graphql
+
+// schema is the Ethereum GraphQL schema defined by EIP-1767 for the
+// blocks, transactions, logs and accounts, extended with the zkEVM batches,
+// forced batches and L1InfoTree leaves. The pending state, the calls and
+// the mutations aren't supported since they are served by the JSON RPC
+const schema string = `
+    # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
+    scalar Bytes32
+    # Address is a 20 byte Ethereum address, represented as 0x-prefixed hexadecimal.
+    scalar Address
+    # Bytes is an arbitrary length binary string, represented as 0x-prefixed hexadecimal.
+    # An empty byte string is represented as '0x'. Byte strings must have an even number of hexadecimal nybbles.
+    scalar Bytes
+    # BigInt is a large integer. Input is accepted as either a JSON number or as a string.
+    # Strings may be either decimal or 0x-prefixed hexadecimal. Output values are all
+    # 0x-prefixed hexadecimal.
+    scalar BigInt
+    # Long is a 64 bit unsigned integer. Input is accepted as either a JSON number or as a string.
+    # Strings may be either decimal or 0x-prefixed hexadecimal. Output values are all
+    # 0x-prefixed hexadecimal.
+    scalar Long
+
+    schema {
+        query: Query
+    }
+
+    # Account is an Ethereum account at a particular block.
+    type Account {
+        # Address is the address owning the account.
+        address: Address!
+        # Balance is the balance of the account, in wei.
+        balance: BigInt!
+        # TransactionCount is the number of transactions sent from this account,
+        # or in the case of a contract, the number of contracts created. Otherwise
+        # known as the nonce.
+        transactionCount: Long!
+        # Code contains the smart contract code for this account, if the account
+        # is a (non-self-destructed) contract.
+        code: Bytes!
+        # Storage provides access to the storage of a contract account, indexed
+        # by its 32 byte slot identifier.
+        storage(slot: Bytes32!): Bytes32!
+    }
+
+    # Log is an Ethereum event log.
+    type Log {
+        # Index is the index of this log in the block.
+        index: Long!
+        # Account is the account which generated this log - this will always
+        # be a contract account.
+        account: Account!
+        # Topics is a list of 0-4 indexed topics for the log.
+        topics: [Bytes32!]!
+        # Data is unindexed data for this log.
+        data: Bytes!
+        # Transaction is the transaction that generated this log entry.
+        transaction: Transaction!
+    }
+
+    # Transaction is an Ethereum transaction.
+    type Transaction {
+        # Hash is the hash of this transaction.
+        hash: Bytes32!
+        # L2Hash is the hash of this transaction computed by the zkEVM.
+        l2Hash: Bytes32
+        # Nonce is the nonce of the account this transaction was generated with.
+        nonce: Long!
+        # Index is the index of this transaction in the parent block.
+        index: Long
+        # From is the account that sent this transaction.
+        from: Account!
+        # To is the account the transaction was sent to. This is null for
+        # contract-creating transactions.
+        to: Account
+        # Value is the value, in wei, sent along with this transaction.
+        value: BigInt!
+        # GasPrice is the price offered to miners for gas, in wei per unit.
+        gasPrice: BigInt!
+        # EffectiveGasPrice is actual value per gas deducted from the sender's
+        # account.
+        effectiveGasPrice: BigInt
+        # Gas is the maximum amount of gas this transaction can consume.
+        gas: Long!
+        # InputData is the data supplied to the target of the transaction.
+        inputData: Bytes!
+        # Block is the block this transaction was mined in.
+        block: Block
+        # Status is the return status of the transaction. This will be 1 if the
+        # transaction succeeded, or 0 if it failed (due to a revert, or due to
+        # running out of gas).
+        status: Long
+        # GasUsed is the amount of gas that was used processing this transaction.
+        gasUsed: Long
+        # CumulativeGasUsed is the total gas used in the block up to and including
+        # this transaction.
+        cumulativeGasUsed: Long
+        # CreatedContract is the account that was created by a contract creation
+        # transaction. If the transaction was not a contract creation transaction,
+        # or it has not yet been mined, this field will be null.
+        createdContract: Account
+        # Logs is a list of log entries emitted by this transaction.
+        logs: [Log!]
+        r: BigInt!
+        s: BigInt!
+        v: BigInt!
+        # Envelope transaction support
+        type: Long
+        # Raw is the canonical encoding of the transaction.
+        raw: Bytes!
+        # RawReceipt is the canonical encoding of the receipt.
+        rawReceipt: Bytes!
+    }
+
+    # BlockFilterCriteria encapsulates log filter criteria for a filter applied
+    # to a single block.
+    input BlockFilterCriteria {
+        # Addresses is list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # Block is an Ethereum block.
+    type Block {
+        # Number is the number of this block, starting at 0 for the genesis block.
+        number: Long!
+        # Hash is the block hash of this block.
+        hash: Bytes32!
+        # Parent is the parent block of this block.
+        parent: Block
+        # Nonce is the block nonce, an 8 byte sequence determined by the miner.
+        nonce: Bytes!
+        # TransactionsRoot is the keccak256 hash of the root of the trie of transactions in this block.
+        transactionsRoot: Bytes32!
+        # TransactionCount is the number of transactions in this block.
+        transactionCount: Long
+        # StateRoot is the keccak256 hash of the state trie after this block was processed.
+        stateRoot: Bytes32!
+        # ReceiptsRoot is the keccak256 hash of the trie of transaction receipts in this block.
+        receiptsRoot: Bytes32!
+        # Miner is the account that mined this block.
+        miner: Account!
+        # ExtraData is an arbitrary data field supplied by the miner.
+        extraData: Bytes!
+        # GasLimit is the maximum amount of gas that was available to transactions in this block.
+        gasLimit: Long!
+        # GasUsed is the amount of gas that was used executing transactions in this block.
+        gasUsed: Long!
+        # Timestamp is the unix timestamp at which this block was mined.
+        timestamp: Long!
+        # LogsBloom is a bloom filter that can be used to check if a block may
+        # contain log entries matching a filter.
+        logsBloom: Bytes!
+        # MixHash is the hash that was used as an input to the PoW process.
+        mixHash: Bytes32!
+        # Difficulty is a measure of the difficulty of mining this block.
+        difficulty: BigInt!
+        # Transactions is a list of transactions associated with this block.
+        transactions: [Transaction!]
+        # TransactionAt returns the transaction at the specified index.
+        transactionAt(index: Long!): Transaction
+        # Logs returns a filtered set of logs from this block.
+        logs(filter: BlockFilterCriteria!): [Log!]!
+        # Account fetches an Ethereum account at the current block's state.
+        account(address: Address!): Account!
+        # Raw is the RLP encoding of the block.
+        raw: Bytes!
+        # GlobalExitRoot is the global exit root used by this block.
+        globalExitRoot: Bytes32!
+        # BlockInfoRoot is the root of the block info tree of this block.
+        blockInfoRoot: Bytes32!
+        # Batch is the batch containing this block.
+        batch: Batch
+    }
+
+    # FilterCriteria encapsulates log filter criteria for searching log entries.
+    input FilterCriteria {
+        # FromBlock is the block at which to start searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        fromBlock: Long
+        # ToBlock is the block at which to stop searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        toBlock: Long
+        # Addresses is a list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # SyncState contains the current synchronisation state of the client.
+    type SyncState {
+        # StartingBlock is the block number at which synchronisation started.
+        startingBlock: Long!
+        # CurrentBlock is the point at which synchronisation has presently reached.
+        currentBlock: Long!
+        # HighestBlock is the latest known block number.
+        highestBlock: Long!
+    }
+
+    # BatchStatus is the stage of the lifecycle reached by a batch.
+    enum BatchStatus {
+        # TRUSTED batches were closed by the trusted sequencer.
+        TRUSTED
+        # VIRTUALIZED batches were sequenced on L1.
+        VIRTUALIZED
+        # VERIFIED batches were proven on L1.
+        VERIFIED
+    }
+
+    # Batch is a zkEVM batch of L2 blocks.
+    type Batch {
+        # Number is the number of this batch.
+        number: Long!
+        # Status is the stage of the lifecycle reached by this batch.
+        status: BatchStatus!
+        # Closed is true if the sequencer won't add more blocks to this batch.
+        closed: Boolean!
+        # Coinbase is the address receiving the fees of this batch.
+        coinbase: Address!
+        # StateRoot is the state root after processing this batch.
+        stateRoot: Bytes32!
+        # GlobalExitRoot is the global exit root of this batch.
+        globalExitRoot: Bytes32!
+        # LocalExitRoot is the local exit root after processing this batch.
+        localExitRoot: Bytes32!
+        # AccInputHash is the accumulated input hash of this batch.
+        accInputHash: Bytes32!
+        # Timestamp is the unix timestamp of this batch.
+        timestamp: Long!
+        # BatchL2Data is the encoded data of the blocks of this batch.
+        batchL2Data: Bytes!
+        # SendSequencesTxHash is the hash of the L1 transaction that virtualized this batch.
+        sendSequencesTxHash: Bytes32
+        # VerifyBatchTxHash is the hash of the L1 transaction that verified this batch.
+        verifyBatchTxHash: Bytes32
+        # L1InfoRoot is the L1InfoTree root used when this batch was virtualized.
+        l1InfoRoot: Bytes32
+        # ForcedBatch is the forced batch sequenced as this batch.
+        forcedBatch: ForcedBatch
+        # Blocks is the list of the blocks of this batch.
+        blocks: [Block!]!
+    }
+
+    # ForcedBatch is a batch forced on L1.
+    type ForcedBatch {
+        # Number is the number of this forced batch.
+        number: Long!
+        # GlobalExitRoot is the global exit root when this batch was forced.
+        globalExitRoot: Bytes32!
+        # RawTxsData is the encoded transactions of this batch.
+        rawTxsData: Bytes!
+        # Sequencer is the address that forced this batch.
+        sequencer: Address!
+        # ForcedAt is the unix timestamp at which this batch was forced.
+        forcedAt: Long!
+        # L1BlockNumber is the L1 block in which this batch was forced.
+        l1BlockNumber: Long!
+    }
+
+    # L1InfoTreeLeaf is a leaf of the L1InfoTree.
+    type L1InfoTreeLeaf {
+        # Index is the index of this leaf in the tree.
+        index: Long!
+        # L1InfoTreeRoot is the root of the tree after adding this leaf.
+        l1InfoTreeRoot: Bytes32!
+        # GlobalExitRoot is the global exit root of this leaf.
+        globalExitRoot: Bytes32!
+        # MainnetExitRoot is the mainnet exit root of this leaf.
+        mainnetExitRoot: Bytes32!
+        # RollupExitRoot is the rollup exit root of this leaf.
+        rollupExitRoot: Bytes32!
+        # PreviousBlockHash is the hash of the L1 block before the one that added this leaf.
+        previousBlockHash: Bytes32!
+        # L1BlockNumber is the L1 block that added this leaf.
+        l1BlockNumber: Long!
+        # Timestamp is the unix timestamp of the L1 block that added this leaf.
+        timestamp: Long!
+    }
+
+    type Query {
+        # Block fetches an Ethereum block by number or by hash. If neither is
+        # supplied, the most recent known block is returned.
+        block(number: Long, hash: Bytes32): Block
+        # Blocks returns all the blocks between two numbers, inclusive. If
+        # to is not supplied, it defaults to the most recent known block.
+        blocks(from: Long, to: Long): [Block!]!
+        # Transaction returns a transaction specified by its hash.
+        transaction(hash: Bytes32!): Transaction
+        # Logs returns log entries matching the provided filter.
+        logs(filter: FilterCriteria!): [Log!]!
+        # Syncing returns information on the current synchronisation state.
+        syncing: SyncState
+        # ChainID returns the current chain ID for transaction replay protection.
+        chainID: BigInt!
+        # Batch fetches a zkEVM batch by number. If the number isn't supplied,
+        # the most recent batch is returned.
+        batch(number: Long): Batch
+        # ForcedBatch fetches a forced batch by number.
+        forcedBatch(number: Long!): ForcedBatch
+        # L1InfoTreeLeaf fetches a leaf of the L1InfoTree by index.
+        l1InfoTreeLeaf(index: Long!): L1InfoTreeLeaf
+    }
+`
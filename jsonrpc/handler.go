@@ -1,11 +1,13 @@
 package jsonrpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
@@ -17,8 +19,9 @@ const (
 )
 
 type serviceData struct {
-	sv      reflect.Value
-	funcMap map[string]*funcData
+	sv         reflect.Value
+	funcMap    map[string]*funcData
+	restricted bool
 }
 
 type funcData struct {
@@ -36,6 +39,7 @@ type handleRequest struct {
 	types.Request
 	wsConn      *concurrentWsConn
 	HttpRequest *http.Request
+	apiKey      *APIKey
 }
 
 // Handler manage services to handle jsonrpc requests
@@ -62,11 +66,15 @@ type handleRequest struct {
 // check the `eth.go` file for more example on how the methods are implemented
 type Handler struct {
 	serviceMap map[string]*serviceData
+	apiKeys    *apiKeyManager
+	cache      *responseCache
+	openRPC    *openRPCBuilder
 }
 
 func newJSONRpcHandler() *Handler {
 	handler := &Handler{
 		serviceMap: map[string]*serviceData{},
+		openRPC:    newOpenRPCBuilder(),
 	}
 	return handler
 }
@@ -82,6 +90,31 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 		return types.NewResponse(req.Request, nil, err)
 	}
 
+	if err := h.authorize(req, service); err != nil {
+		return types.NewResponse(req.Request, nil, err)
+	}
+
+	if req.apiKey != nil && h.apiKeys != nil {
+		if err := h.apiKeys.reserve(req.apiKey); err != nil {
+			return types.NewResponse(req.Request, nil, err)
+		}
+		start := time.Now()
+		defer func() { h.apiKeys.addCompute(req.apiKey, time.Since(start)) }()
+	}
+
+	var (
+		cacheKey        string
+		cacheGeneration uint64
+	)
+	if h.cache != nil && h.cache.isCacheable(req.Method, req.Params) {
+		cacheKey = responseCacheKey(req.Method, req.Params)
+		cached, generation, found := h.cache.get(context.Background(), cacheKey)
+		if found {
+			return types.NewResponse(req.Request, cached, nil)
+		}
+		cacheGeneration = generation
+	}
+
 	inArgsOffset := 0
 	inArgs := make([]reflect.Value, fd.inNum)
 	inArgs[0] = service.sv
@@ -137,11 +170,15 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 		data = d
 	}
 
+	if cacheKey != "" {
+		h.cache.add(context.Background(), req.Method, req.Params, cacheKey, cacheGeneration, data)
+	}
+
 	return types.NewResponse(req.Request, data, nil)
 }
 
 // HandleWs handle websocket requests
-func (h *Handler) HandleWs(reqBody []byte, wsConn *concurrentWsConn, httpReq *http.Request) ([]byte, error) {
+func (h *Handler) HandleWs(reqBody []byte, wsConn *concurrentWsConn, httpReq *http.Request, apiKey *APIKey) ([]byte, error) {
 	log.Debugf("WS message received: %v", string(reqBody))
 	var req types.Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
@@ -152,6 +189,7 @@ func (h *Handler) HandleWs(reqBody []byte, wsConn *concurrentWsConn, httpReq *ht
 		Request:     req,
 		wsConn:      wsConn,
 		HttpRequest: httpReq,
+		apiKey:      apiKey,
 	}
 
 	return h.Handle(handleReq).Bytes()
@@ -213,16 +251,23 @@ func (h *Handler) registerService(service Service) {
 			}
 		}
 		funcMap[name] = fd
+
+		// the restricted services are hidden from the OpenRPC document
+		// as they are hidden to the requests without an API key
+		if !service.Restricted {
+			h.openRPC.addMethod(funcName, fd)
+		}
 	}
 
 	h.serviceMap[service.Name] = &serviceData{
-		sv:      reflect.ValueOf(service.Service),
-		funcMap: funcMap,
+		sv:         reflect.ValueOf(service.Service),
+		funcMap:    funcMap,
+		restricted: service.Restricted,
 	}
 }
 
 func (h *Handler) getFnHandler(req types.Request) (*serviceData, *funcData, types.Error) {
-	methodNotFoundErrorMessage := fmt.Sprintf("the method %s does not exist/is not available", req.Method)
+	methodNotFoundErrorMessage := methodNotFoundMessage(req.Method)
 
 	serviceName, funcName, found := strings.Cut(req.Method, "_")
 	if !found {
@@ -241,6 +286,26 @@ func (h *Handler) getFnHandler(req types.Request) (*serviceData, *funcData, type
 	return service, fd, nil
 }
 
+// authorize checks if the request is allowed to call the methods of the service,
+// the restricted services are hidden to the requests without an API key
+func (h *Handler) authorize(req handleRequest, service *serviceData) types.Error {
+	if req.apiKey == nil {
+		if service.restricted {
+			return types.NewRPCError(types.NotFoundErrorCode, methodNotFoundMessage(req.Method))
+		}
+		return nil
+	}
+
+	if !req.apiKey.allows(req.Method, !service.restricted) {
+		return types.NewRPCError(types.AccessDeniedCode, fmt.Sprintf("the method %s is not allowed for the API key", req.Method))
+	}
+	return nil
+}
+
+func methodNotFoundMessage(method string) string {
+	return fmt.Sprintf("the method %s does not exist/is not available", method)
+}
+
 func validateFunc(funcName string, fv reflect.Value, isMethod bool) (inNum int, reqt []reflect.Type, err error) {
 	if funcName == "" {
 		err = fmt.Errorf("getBlockNumByArg cannot be empty")
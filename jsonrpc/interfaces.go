@@ -1,14 +1,32 @@
 package jsonrpc
 
-// storageInterface json rpc internal storage to persist data
-type storageInterface interface {
+import "context"
+
+// FilterStorage json rpc storage to persist the filters, it's implemented
+// in memory by Storage and shared across instances by PostgresStorage
+type FilterStorage interface {
 	GetAllBlockFiltersWithWSConn() []*Filter
 	GetAllLogFiltersWithWSConn() []*Filter
+	GetAllStateEventFiltersWithWSConn(filterType FilterType) []*Filter
 	GetFilter(filterID string) (*Filter, error)
 	NewBlockFilter(wsConn *concurrentWsConn) (string, error)
 	NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error)
 	NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error)
+	NewStateEventFilter(wsConn *concurrentWsConn, filterType FilterType) (string, error)
 	UninstallFilter(filterID string) error
 	UninstallFilterByWSConn(wsConn *concurrentWsConn) error
 	UpdateFilterLastPoll(filterID string) error
 }
+
+// APIKeyStorage json rpc storage of the API keys
+type APIKeyStorage interface {
+	GetAPIKey(ctx context.Context, hash string) (*APIKey, error)
+}
+
+// ResponseCacheStore json rpc storage of the cached responses, shared
+// by all the instances as the second tier of the response cache
+type ResponseCacheStore interface {
+	GetResponse(ctx context.Context, key string) ([]byte, error)
+	AddResponse(ctx context.Context, key string, response []byte) error
+	ClearResponses(ctx context.Context) error
+}
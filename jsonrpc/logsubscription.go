@@ -0,0 +1,532 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// logsHistorySize is the number of the last l2 blocks kept to notify
+	// their logs as removed when the trusted state is reorganized, the
+	// logs of the blocks replaced by a deeper reorg are not notified
+	logsHistorySize = 128
+
+	// defaultLogsBackfillBlockRange is the block range loaded at once to backfill
+	// the logs of a subscription when the logs block range is not limited
+	defaultLogsBackfillBlockRange = 10000
+
+	// logsBackfillRetryInterval is the time to wait before retrying a failed backfill
+	logsBackfillRetryInterval = time.Second
+
+	// logsBackfillMaxQueuedLogs is the number of logs waiting to be sent to the
+	// subscription above which the backfill waits before loading more logs
+	logsBackfillMaxQueuedLogs = 10000
+
+	// logsBackfillQueueCheckInterval is the time to wait before checking again
+	// if the logs waiting to be sent to the subscription are below the max
+	logsBackfillQueueCheckInterval = 100 * time.Millisecond
+
+	// logsBackfillMaxHeldLogs is the number of new logs held while backfilling
+	// above which they are dropped, so the backfill loads them again instead
+	logsBackfillMaxHeldLogs = 10000
+)
+
+// subscribeLogs creates a logs subscription. When the filter starts at a past
+// block or resumes from a cursor, the historical logs are backfilled once the
+// subscription id is sent and the new logs are held until they are sent
+func (e *EthEndpoints) subscribeLogs(ctx context.Context, wsConn *concurrentWsConn, filter LogFilter, dbTx pgx.Tx) (interface{}, types.Error) {
+	if !filter.ShouldBackfill() {
+		return e.newFilter(ctx, wsConn, filter, dbTx)
+	}
+
+	// the logs are backfilled by chunks, so only the subscriptions
+	// with a toBlock are limited to the max logs block range
+	if filter.FromBlock != nil && filter.ToBlock != nil {
+		if _, _, rpcErr := filter.GetNumericBlockNumbers(ctx, e.cfg, e.state, e.etherman, dbTx); rpcErr != nil {
+			return nil, rpcErr
+		}
+	} else if filter.FromBlock != nil {
+		if _, rpcErr := filter.FromBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx); rpcErr != nil {
+			return nil, rpcErr
+		}
+	}
+
+	id, rpcErr := e.installLogFilter(wsConn, filter)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	f, err := e.storage.GetFilter(id.(string))
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new log filter", err, true)
+	}
+	wsConn.runAfterResponse(func() { e.backfillLogs(f) })
+
+	return id, nil
+}
+
+// backfillLogs sends the historical logs of the subscription and then lets the
+// new logs held meanwhile through. It retries until the logs are backfilled or
+// the subscription is uninstalled, and backfills again up to the last block
+// when the new logs held were dropped
+func (e *EthEndpoints) backfillLogs(f *Filter) {
+	for {
+		if _, err := e.storage.GetFilter(f.ID); err != nil {
+			return
+		}
+
+		err := e.sendHistoricalLogs(f)
+		if errors.Is(err, ErrNotFound) {
+			return
+		} else if err != nil {
+			log.Errorf("failed to backfill the logs of subscription %v, retrying: %v", f.ID, err)
+			time.Sleep(logsBackfillRetryInterval)
+			continue
+		}
+
+		if f.endLogsBackfill() {
+			return
+		}
+		log.Debugf("new logs held while backfilling subscription %v were dropped, backfilling them", f.ID)
+	}
+}
+
+// sendHistoricalLogs sends the logs matching the subscription filter from the
+// next log to be sent, or the filter fromBlock, up to the last l2 block in the
+// logs history, the newer blocks are sent by the events held meanwhile
+func (e *EthEndpoints) sendHistoricalLogs(f *Filter) error {
+	ctx := context.Background()
+	logFilter := f.Parameters.(LogFilter)
+
+	var fromBlockNumber uint64
+	if logFilter.FromBlock != nil {
+		blockNumber, rpcErr := logFilter.FromBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, nil)
+		if rpcErr != nil {
+			return rpcErr
+		}
+		fromBlockNumber = blockNumber
+	}
+	if next := f.nextLogCursor(); next != nil && uint64(next.BlockNumber) > fromBlockNumber {
+		fromBlockNumber = uint64(next.BlockNumber)
+	}
+	// the genesis block has no logs
+	if fromBlockNumber == 0 {
+		fromBlockNumber = 1
+	}
+
+	toBlockNumber, err := e.state.GetLastL2BlockNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if lastBlockNumber, ok := e.logsHistory.lastBlockNumber(); ok && lastBlockNumber < toBlockNumber {
+		toBlockNumber = lastBlockNumber
+	}
+	if logFilter.ToBlock != nil {
+		blockNumber, rpcErr := logFilter.ToBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, nil)
+		if rpcErr != nil {
+			return rpcErr
+		}
+		if blockNumber < toBlockNumber {
+			toBlockNumber = blockNumber
+		}
+	}
+
+	maxBlockRange := e.cfg.MaxLogsBlockRange
+	if maxBlockRange == 0 {
+		maxBlockRange = defaultLogsBackfillBlockRange
+	}
+	blockRange := maxBlockRange
+	for fromBlockNumber <= toBlockNumber {
+		// wait for the subscription to send the logs already queued, so a slow
+		// client doesn't make the node keep the logs of the whole chain in memory
+		for f.wsQueue.Len() >= logsBackfillMaxQueuedLogs {
+			if _, err := e.storage.GetFilter(f.ID); err != nil {
+				return err
+			}
+			time.Sleep(logsBackfillQueueCheckInterval)
+		}
+
+		// stop as soon as the subscription is uninstalled
+		if _, err := e.storage.GetFilter(f.ID); err != nil {
+			return err
+		}
+
+		chunkToBlockNumber := fromBlockNumber + blockRange
+		if chunkToBlockNumber > toBlockNumber {
+			chunkToBlockNumber = toBlockNumber
+		}
+
+		var logs []types.Log
+		stateLogs, err := e.state.GetLogs(ctx, fromBlockNumber, chunkToBlockNumber, logFilter.Addresses, logFilter.Topics, nil, nil, nil)
+		if errors.Is(err, state.ErrMaxLogsCountLimitExceeded) && chunkToBlockNumber > fromBlockNumber {
+			// split the range until the logs fit the max logs count
+			blockRange = (chunkToBlockNumber - fromBlockNumber) / 2 //nolint:gomnd
+			continue
+		} else if errors.Is(err, state.ErrMaxLogsCountLimitExceeded) {
+			// a single block with more logs than the max logs count is filtered here
+			blockLogs, err := e.state.GetLogsByBlockNumber(ctx, fromBlockNumber, nil)
+			if err != nil {
+				return err
+			}
+			logs = filterLogs(blockLogs, f)
+		} else if err != nil {
+			return err
+		} else {
+			logs = make([]types.Log, 0, len(stateLogs))
+			for _, l := range stateLogs {
+				logs = append(logs, types.NewLog(*l))
+			}
+		}
+
+		f.enqueueBackfilledLogs(logs, chunkToBlockNumber)
+		fromBlockNumber = chunkToBlockNumber + 1
+		blockRange = maxBlockRange
+	}
+
+	return nil
+}
+
+// initLogsSubscription prepares the filter of a logs subscription to resume
+// from the filter cursor and to hold the new logs if the historical ones
+// have to be backfilled first
+func (f *Filter) initLogsSubscription(logFilter LogFilter) {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	f.logsNext = logFilter.Cursor
+	f.logsBackfilling = logFilter.ShouldBackfill()
+}
+
+// enqueueLogs enqueues the new logs to be sent to the logs subscription,
+// they are held while the historical logs are being backfilled. When too many
+// logs are held they are dropped, and the backfill loads them again later
+func (f *Filter) enqueueLogs(logs []types.Log) {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	if !f.logsBackfilling {
+		f.sendLogs(logs)
+		return
+	}
+	if f.logsBackfillDropped || f.logsBackfillHeldCount+len(logs) > logsBackfillMaxHeldLogs {
+		f.dropHeldLogs()
+		return
+	}
+	f.logsBackfillHeld = append(f.logsBackfillHeld, heldLogs{send: func() { f.sendLogs(logs) }})
+	f.logsBackfillHeldCount += len(logs)
+}
+
+// dropHeldLogs drops the new logs held while backfilling, the removed logs are
+// kept to rewind the subscription before the backfill loads the new logs again
+func (f *Filter) dropHeldLogs() {
+	held := f.logsBackfillHeld[:0]
+	for _, h := range f.logsBackfillHeld {
+		if h.removed {
+			held = append(held, h)
+		}
+	}
+	f.logsBackfillHeld = held
+	f.logsBackfillHeldCount = 0
+	f.logsBackfillDropped = true
+}
+
+// enqueueRemovedLogs enqueues the logs removed by a reorg of the trusted state
+// from the provided block to be sent to the logs subscription
+func (f *Filter) enqueueRemovedLogs(logs []types.Log, fromBlockNumber uint64) {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	if f.logsBackfilling {
+		f.logsBackfillHeld = append(f.logsBackfillHeld, heldLogs{send: func() { f.sendRemovedLogs(logs, fromBlockNumber) }, removed: true})
+		return
+	}
+	f.sendRemovedLogs(logs, fromBlockNumber)
+}
+
+// enqueueBackfilledLogs enqueues the historical logs up to the provided block
+// to be sent to the logs subscription. The logs of the last blocks are kept to
+// be notified as removed if the blocks are replaced by a reorg, because they
+// may have been loaded from blocks other than the ones in the logs history
+func (f *Filter) enqueueBackfilledLogs(logs []types.Log, toBlockNumber uint64) {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	f.sendLogs(logs)
+
+	f.logsBackfilled = append(f.logsBackfilled, logs...)
+	f.logsBackfilledTo = toBlockNumber
+	if toBlockNumber >= logsHistorySize {
+		i := 0
+		for i < len(f.logsBackfilled) && uint64(f.logsBackfilled[i].BlockNumber) <= toBlockNumber-logsHistorySize {
+			i++
+		}
+		f.logsBackfilled = f.logsBackfilled[i:]
+	}
+}
+
+// endLogsBackfill sends the logs held while the historical ones were backfilled.
+// It returns false if new logs were dropped meanwhile, then the subscription keeps
+// holding the new logs until they are backfilled again
+func (f *Filter) endLogsBackfill() bool {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	for _, h := range f.logsBackfillHeld {
+		h.send()
+	}
+	f.logsBackfillHeld = nil
+	f.logsBackfillHeldCount = 0
+	if f.logsBackfillDropped {
+		f.logsBackfillDropped = false
+		return false
+	}
+	f.logsBackfilling = false
+	return true
+}
+
+// heldLogs are the logs held while the historical ones are backfilled
+type heldLogs struct {
+	send    func()
+	removed bool
+}
+
+// nextLogCursor returns the position of the next log to be sent
+func (f *Filter) nextLogCursor() *types.LogCursor {
+	f.logsMutex.Lock()
+	defer f.logsMutex.Unlock()
+
+	return f.logsNext
+}
+
+// sendLogs enqueues the logs from the position of the next log to be sent,
+// skipping the ones that were already sent
+func (f *Filter) sendLogs(logs []types.Log) {
+	for _, l := range logs {
+		cursor := types.NewLogCursor(l)
+		if f.logsNext != nil && !f.logsNext.Before(cursor) {
+			continue
+		}
+		f.sendLog(l, cursor)
+		f.logsNext = &cursor
+	}
+}
+
+// sendRemovedLogs enqueues the removed logs that were sent before and rewinds
+// the subscription to the first block replaced by the reorg, which is also
+// the cursor to resume from for all the removed logs
+func (f *Filter) sendRemovedLogs(logs []types.Log, fromBlockNumber uint64) {
+	logs = f.replaceBackfilledRemovedLogs(logs, fromBlockNumber)
+
+	cursor := types.LogCursor{BlockNumber: types.ArgUint64(fromBlockNumber)}
+	if f.logsNext == nil || !cursor.Before(*f.logsNext) {
+		// nothing was sent from the first replaced block
+		return
+	}
+
+	for _, l := range logs {
+		if f.logsNext.Before(types.NewLogCursor(l)) {
+			continue
+		}
+		l.Removed = true
+		f.sendLog(l, cursor)
+	}
+	f.logsNext = &cursor
+}
+
+// replaceBackfilledRemovedLogs replaces the removed logs of the backfilled
+// blocks with the logs that were actually backfilled from them and forgets
+// the backfilled logs from the first block replaced by the reorg
+func (f *Filter) replaceBackfilledRemovedLogs(logs []types.Log, fromBlockNumber uint64) []types.Log {
+	if f.logsBackfilledTo < fromBlockNumber {
+		return logs
+	}
+
+	removed := []types.Log{}
+	kept := 0
+	for _, l := range f.logsBackfilled {
+		if uint64(l.BlockNumber) < fromBlockNumber {
+			kept++
+			continue
+		}
+		removed = append(removed, l)
+	}
+	for _, l := range logs {
+		if uint64(l.BlockNumber) > f.logsBackfilledTo {
+			removed = append(removed, l)
+		}
+	}
+
+	f.logsBackfilled = f.logsBackfilled[:kept]
+	f.logsBackfilledTo = 0
+	if fromBlockNumber > 0 {
+		f.logsBackfilledTo = fromBlockNumber - 1
+	}
+	return removed
+}
+
+func (f *Filter) sendLog(l types.Log, cursor types.LogCursor) {
+	var data []byte
+	var err error
+	if f.Parameters.(LogFilter).WithCursor {
+		data, err = json.Marshal(types.SubscriptionLog{Log: l, Cursor: cursor})
+	} else {
+		data, err = json.Marshal(l)
+	}
+	if err != nil {
+		log.Errorf("failed to marshal ethLog response to subscription: %v", err)
+		return
+	}
+	f.EnqueueSubscriptionDataToBeSent(data)
+}
+
+// logsHistory keeps the last l2 blocks notified to the logs subscriptions with
+// their logs, so the logs of the blocks replaced by a reorg of the trusted state
+// can be notified as removed. Only the reorgs up to its size are fully notified,
+// the logs of the older blocks replaced by a deeper reorg are not known anymore
+type logsHistory struct {
+	size   int
+	mu     sync.Mutex
+	blocks []state.NewL2BlockEvent
+}
+
+func newLogsHistory(size int) *logsHistory {
+	return &logsHistory{
+		size:   size,
+		blocks: make([]state.NewL2BlockEvent, 0, size),
+	}
+}
+
+// lastBlockNumber returns the number of the last block in the history
+func (h *logsHistory) lastBlockNumber() (uint64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.blocks) == 0 {
+		return 0, false
+	}
+	return h.blocks[len(h.blocks)-1].Block.NumberU64(), true
+}
+
+// add adds the block of the event to the history. The state only notifies the
+// blocks above the last one notified, so when the block doesn't follow the last
+// one the blocks that are not in the state anymore are returned as removed, and
+// the blocks after the fork block are loaded to be notified before the event one.
+// The loaded blocks must be chained by their parent hash up to the event block,
+// otherwise the state changed again while they were loaded
+func (h *logsHistory) add(ctx context.Context, s types.StateInterface, event state.NewL2BlockEvent) ([]state.NewL2BlockEvent, []state.NewL2BlockEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	blockNumber := event.Block.NumberU64()
+	if len(h.blocks) == 0 {
+		h.push(event)
+		return nil, []state.NewL2BlockEvent{event}, nil
+	}
+	last := h.blocks[len(h.blocks)-1]
+	if blockNumber == last.Block.NumberU64()+1 && event.Block.ParentHash() == last.Block.Hash() {
+		h.push(event)
+		return nil, []state.NewL2BlockEvent{event}, nil
+	}
+
+	// walk back the history up to the last block that is still in the state
+	kept := 0
+	for i := len(h.blocks) - 1; i >= 0; i-- {
+		b := h.blocks[i]
+		if b.Block.NumberU64() >= blockNumber {
+			continue
+		}
+		stateBlock, err := s.GetL2BlockByNumber(ctx, b.Block.NumberU64(), nil)
+		if errors.Is(err, state.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if stateBlock.Hash() == b.Block.Hash() {
+			kept = i + 1
+			break
+		}
+	}
+
+	var fromBlockNumber uint64
+	if kept > 0 {
+		fromBlockNumber = h.blocks[kept-1].Block.NumberU64() + 1
+	} else {
+		fromBlockNumber = h.blocks[0].Block.NumberU64()
+	}
+	if fromBlockNumber > blockNumber {
+		fromBlockNumber = blockNumber
+	}
+
+	// the parent of the first loaded block is only known if it is in the history
+	var parentHash *common.Hash
+	if kept > 0 {
+		parentHash = state.Ptr(h.blocks[kept-1].Block.Hash())
+	}
+	added := make([]state.NewL2BlockEvent, 0, blockNumber-fromBlockNumber+1)
+	for n := fromBlockNumber; n < blockNumber; n++ {
+		block, err := s.GetL2BlockByNumber(ctx, n, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if parentHash != nil && block.ParentHash() != *parentHash {
+			return nil, nil, fmt.Errorf("block %d doesn't follow the block %d, the trusted state changed while loading the blocks", n, n-1)
+		}
+		logs, err := s.GetLogsByBlockNumber(ctx, n, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		added = append(added, state.NewL2BlockEvent{Block: *block, Logs: logs})
+		parentHash = state.Ptr(block.Hash())
+	}
+	if parentHash != nil && event.Block.ParentHash() != *parentHash {
+		return nil, nil, fmt.Errorf("block %d doesn't follow the block %d, the trusted state changed while loading the blocks", blockNumber, blockNumber-1)
+	}
+	added = append(added, event)
+
+	removed := make([]state.NewL2BlockEvent, len(h.blocks)-kept)
+	copy(removed, h.blocks[kept:])
+	if len(removed) > 0 {
+		log.Warnf("trusted state reorg detected at block %d, notifying the logs of %d blocks as removed", blockNumber, len(removed))
+	}
+	if kept == 0 && len(removed) == h.size {
+		log.Warnf("trusted state reorg at block %d may be deeper than the %d blocks of the logs history, the logs of the older replaced blocks are not notified as removed", blockNumber, h.size)
+	}
+
+	h.blocks = h.blocks[:kept]
+	for _, a := range added {
+		h.push(a)
+	}
+	return removed, added, nil
+}
+
+func (h *logsHistory) push(event state.NewL2BlockEvent) {
+	if h.size <= 0 {
+		return
+	}
+	if len(h.blocks) >= h.size {
+		h.blocks = h.blocks[1:]
+	}
+	h.blocks = append(h.blocks, event)
+}
+
+// filterRemovedLogs filters the logs of the removed blocks for the subscription
+func (e *EthEndpoints) filterRemovedLogs(removed []state.NewL2BlockEvent, filter *Filter) []types.Log {
+	logs := []types.Log{}
+	for _, r := range removed {
+		if e.shouldSkipLogFilter(r, filter) {
+			continue
+		}
+		logs = append(logs, filterLogs(r.Logs, filter)...)
+	}
+	return logs
+}
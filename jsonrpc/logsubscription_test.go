@@ -0,0 +1,313 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newLogsSubscriptionFilter(logFilter LogFilter) *Filter {
+	f := &Filter{
+		ID:            "0x1",
+		Type:          FilterTypeLog,
+		Parameters:    logFilter,
+		wsQueue:       state.NewQueue[[]byte](),
+		wsQueueSignal: sync.NewCond(&sync.Mutex{}),
+	}
+	f.initLogsSubscription(logFilter)
+	return f
+}
+
+func popSubscriptionLogs(t *testing.T, f *Filter) []types.SubscriptionLog {
+	logs := []types.SubscriptionLog{}
+	for {
+		data, err := f.wsQueue.Pop()
+		if err == state.ErrQueueEmpty {
+			return logs
+		}
+		require.NoError(t, err)
+		var l types.SubscriptionLog
+		require.NoError(t, json.Unmarshal(data, &l))
+		logs = append(logs, l)
+	}
+}
+
+func newSubscriptionTestLog(blockNumber, logIndex uint64) types.Log {
+	return types.Log{BlockNumber: types.ArgUint64(blockNumber), LogIndex: types.ArgUint64(logIndex), Topics: []common.Hash{}, Data: types.ArgBytes{}}
+}
+
+func TestLogsSubscriptionBackfill(t *testing.T) {
+	fromBlock := types.BlockNumber(1)
+	f := newLogsSubscriptionFilter(LogFilter{FromBlock: &fromBlock, WithCursor: true})
+
+	// the new logs are held while backfilling
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(3, 0), newSubscriptionTestLog(4, 0)})
+	assert.Empty(t, popSubscriptionLogs(t, f))
+
+	f.enqueueBackfilledLogs([]types.Log{newSubscriptionTestLog(1, 0), newSubscriptionTestLog(1, 1), newSubscriptionTestLog(3, 0)}, 3)
+	f.endLogsBackfill()
+
+	// the logs held are sent after the backfilled ones, without the ones already sent
+	logs := popSubscriptionLogs(t, f)
+	require.Len(t, logs, 4)
+	assert.Equal(t, newSubscriptionTestLog(1, 0), logs[0].Log)
+	assert.Equal(t, types.LogCursor{BlockNumber: 1, LogIndex: 1}, logs[0].Cursor)
+	assert.Equal(t, newSubscriptionTestLog(1, 1), logs[1].Log)
+	assert.Equal(t, newSubscriptionTestLog(3, 0), logs[2].Log)
+	assert.Equal(t, newSubscriptionTestLog(4, 0), logs[3].Log)
+	assert.Equal(t, types.LogCursor{BlockNumber: 4, LogIndex: 1}, logs[3].Cursor)
+
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(5, 0)})
+	logs = popSubscriptionLogs(t, f)
+	require.Len(t, logs, 1)
+	assert.Equal(t, newSubscriptionTestLog(5, 0), logs[0].Log)
+}
+
+func TestLogsSubscriptionResumeFromCursor(t *testing.T) {
+	f := newLogsSubscriptionFilter(LogFilter{Cursor: &types.LogCursor{BlockNumber: 2, LogIndex: 1}})
+	f.enqueueBackfilledLogs([]types.Log{newSubscriptionTestLog(2, 0), newSubscriptionTestLog(2, 1), newSubscriptionTestLog(3, 0)}, 3)
+	f.endLogsBackfill()
+
+	// the cursor is only added to the logs when requested
+	logs := popSubscriptionLogs(t, f)
+	require.Len(t, logs, 2)
+	assert.Equal(t, newSubscriptionTestLog(2, 1), logs[0].Log)
+	assert.Equal(t, types.LogCursor{}, logs[0].Cursor)
+	assert.Equal(t, newSubscriptionTestLog(3, 0), logs[1].Log)
+}
+
+func TestLogsSubscriptionShouldBackfill(t *testing.T) {
+	for _, fromBlock := range []types.BlockNumber{types.EarliestBlockNumber, 0, 1} {
+		assert.True(t, (&LogFilter{FromBlock: &fromBlock}).ShouldBackfill())
+	}
+	for _, fromBlock := range []types.BlockNumber{types.LatestBlockNumber, types.PendingBlockNumber} {
+		assert.False(t, (&LogFilter{FromBlock: &fromBlock}).ShouldBackfill())
+	}
+	assert.False(t, (&LogFilter{}).ShouldBackfill())
+	assert.True(t, (&LogFilter{Cursor: &types.LogCursor{}}).ShouldBackfill())
+}
+
+func TestLogsSubscriptionRemovedLogs(t *testing.T) {
+	f := newLogsSubscriptionFilter(LogFilter{WithCursor: true})
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(1, 0), newSubscriptionTestLog(2, 0), newSubscriptionTestLog(3, 0)})
+	popSubscriptionLogs(t, f)
+
+	// the removed logs that were not sent are skipped
+	f.enqueueRemovedLogs([]types.Log{newSubscriptionTestLog(2, 0), newSubscriptionTestLog(3, 0), newSubscriptionTestLog(3, 1)}, 2)
+	logs := popSubscriptionLogs(t, f)
+	require.Len(t, logs, 2)
+	for i, blockNumber := range []uint64{2, 3} {
+		expected := newSubscriptionTestLog(blockNumber, 0)
+		expected.Removed = true
+		assert.Equal(t, expected, logs[i].Log)
+		assert.Equal(t, types.LogCursor{BlockNumber: 2}, logs[i].Cursor)
+	}
+
+	// the new logs of the replaced blocks are sent again
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(2, 0)})
+	logs = popSubscriptionLogs(t, f)
+	require.Len(t, logs, 1)
+	assert.Equal(t, newSubscriptionTestLog(2, 0), logs[0].Log)
+
+	// nothing is sent when no log was sent from the first replaced block
+	f.enqueueRemovedLogs([]types.Log{newSubscriptionTestLog(5, 0)}, 5)
+	assert.Empty(t, popSubscriptionLogs(t, f))
+}
+
+func TestLogsSubscriptionHeldLogsDropped(t *testing.T) {
+	fromBlock := types.BlockNumber(1)
+	f := newLogsSubscriptionFilter(LogFilter{FromBlock: &fromBlock})
+	f.enqueueBackfilledLogs([]types.Log{newSubscriptionTestLog(1, 0), newSubscriptionTestLog(2, 0)}, 2)
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(3, 0)})
+	f.enqueueRemovedLogs([]types.Log{newSubscriptionTestLog(2, 0)}, 2)
+
+	// the new logs above the max are dropped, the removed ones are kept
+	tooManyLogs := make([]types.Log, 0, logsBackfillMaxHeldLogs)
+	for i := 0; i < logsBackfillMaxHeldLogs; i++ {
+		tooManyLogs = append(tooManyLogs, newSubscriptionTestLog(4, uint64(i)))
+	}
+	f.enqueueLogs(tooManyLogs)
+	assert.False(t, f.endLogsBackfill())
+	logs := popSubscriptionLogs(t, f)
+	require.Len(t, logs, 3)
+	removedLog := newSubscriptionTestLog(2, 0)
+	removedLog.Removed = true
+	assert.Equal(t, removedLog, logs[2].Log)
+
+	// the new logs are held until the dropped ones are backfilled again
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(5, 0)})
+	assert.Empty(t, popSubscriptionLogs(t, f))
+	f.enqueueBackfilledLogs([]types.Log{newSubscriptionTestLog(2, 0), newSubscriptionTestLog(3, 0)}, 4)
+	assert.True(t, f.endLogsBackfill())
+	logs = popSubscriptionLogs(t, f)
+	require.Len(t, logs, 3)
+	for i, blockNumber := range []uint64{2, 3, 5} {
+		assert.Equal(t, newSubscriptionTestLog(blockNumber, 0), logs[i].Log)
+	}
+}
+
+func TestLogsSubscriptionBackfillWaitsForQueuedLogs(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	storage := newStorageMock(t)
+	e := &EthEndpoints{state: st, storage: storage, logsHistory: newLogsHistory(logsHistorySize)}
+
+	fromBlock := types.BlockNumber(1)
+	f := newLogsSubscriptionFilter(LogFilter{FromBlock: &fromBlock})
+	for i := 0; i < logsBackfillMaxQueuedLogs; i++ {
+		f.wsQueue.Push([]byte{})
+	}
+	storage.On("GetFilter", f.ID).Return(f, nil)
+	st.On("GetLastL2BlockNumber", mock.Anything, nil).Return(uint64(1), nil).Once()
+	st.On("GetLogs", mock.Anything, uint64(1), uint64(1), []common.Address(nil), [][]common.Hash(nil), (*common.Hash)(nil), (*time.Time)(nil), nil).
+		Return([]*ethTypes.Log{{BlockNumber: 1}}, nil).Once()
+
+	done := make(chan error)
+	go func() { done <- e.sendHistoricalLogs(f) }()
+
+	// no logs are loaded until the subscription sends the queued ones
+	time.Sleep(3 * logsBackfillQueueCheckInterval)
+	st.AssertNotCalled(t, "GetLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	_, err := f.wsQueue.Pop()
+	require.NoError(t, err)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "logs not backfilled")
+	}
+	assert.Equal(t, logsBackfillMaxQueuedLogs, f.wsQueue.Len())
+}
+
+func TestLogsSubscriptionBackfillStopsWhenUninstalled(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	storage := newStorageMock(t)
+	e := &EthEndpoints{state: st, storage: storage, logsHistory: newLogsHistory(logsHistorySize)}
+
+	fromBlock := types.BlockNumber(1)
+	f := newLogsSubscriptionFilter(LogFilter{FromBlock: &fromBlock})
+	storage.On("GetFilter", f.ID).Return(f, nil).Once()
+	st.On("GetLastL2BlockNumber", mock.Anything, nil).Return(uint64(0), errors.New("state unavailable")).Once()
+	storage.On("GetFilter", f.ID).Return(nil, ErrNotFound).Once()
+
+	// the failed backfill is not retried once the subscription is uninstalled
+	done := make(chan struct{})
+	go func() {
+		e.backfillLogs(f)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "backfill not stopped")
+	}
+}
+
+func TestLogsSubscriptionRemovedBackfilledLogs(t *testing.T) {
+	fromBlock := types.BlockNumber(1)
+	f := newLogsSubscriptionFilter(LogFilter{FromBlock: &fromBlock})
+	backfilledLog := newSubscriptionTestLog(2, 0)
+	backfilledLog.BlockHash = common.HexToHash("0x2")
+	f.enqueueBackfilledLogs([]types.Log{newSubscriptionTestLog(1, 0), backfilledLog}, 2)
+	f.endLogsBackfill()
+	f.enqueueLogs([]types.Log{newSubscriptionTestLog(3, 0)})
+	popSubscriptionLogs(t, f)
+
+	// the logs backfilled from the replaced blocks are removed instead of the
+	// ones in the history, which may come from other versions of the blocks
+	historyLog := newSubscriptionTestLog(2, 0)
+	historyLog.BlockHash = common.HexToHash("0x22")
+	f.enqueueRemovedLogs([]types.Log{historyLog, newSubscriptionTestLog(3, 0)}, 2)
+	logs := popSubscriptionLogs(t, f)
+	require.Len(t, logs, 2)
+	backfilledLog.Removed = true
+	assert.Equal(t, backfilledLog, logs[0].Log)
+	expected := newSubscriptionTestLog(3, 0)
+	expected.Removed = true
+	assert.Equal(t, expected, logs[1].Log)
+
+	// the backfilled logs of the replaced blocks are forgotten
+	f.enqueueLogs([]types.Log{historyLog})
+	popSubscriptionLogs(t, f)
+	f.enqueueRemovedLogs([]types.Log{historyLog}, 2)
+	logs = popSubscriptionLogs(t, f)
+	require.Len(t, logs, 1)
+	historyLog.Removed = true
+	assert.Equal(t, historyLog, logs[0].Log)
+}
+
+func newLogsHistoryEvent(number uint64, parentHash common.Hash, fork byte) state.NewL2BlockEvent {
+	header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(0).SetUint64(number), ParentHash: parentHash, Extra: []byte{fork}})
+	block := state.NewL2BlockWithHeader(header)
+	return state.NewL2BlockEvent{Block: *block, Logs: []*ethTypes.Log{{BlockNumber: number, BlockHash: block.Hash()}}}
+}
+
+func TestLogsHistory(t *testing.T) {
+	ctx := context.Background()
+	st := mocks.NewStateMock(t)
+	h := newLogsHistory(3)
+
+	b1 := newLogsHistoryEvent(1, common.Hash{}, 0)
+	b2 := newLogsHistoryEvent(2, b1.Block.Hash(), 0)
+	b3 := newLogsHistoryEvent(3, b2.Block.Hash(), 0)
+	for _, b := range []state.NewL2BlockEvent{b1, b2, b3} {
+		removed, added, err := h.add(ctx, st, b)
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+		assert.Equal(t, []state.NewL2BlockEvent{b}, added)
+	}
+
+	// the blocks 2 and 3 are replaced, but the state only notifies the block 4
+	newB2 := newLogsHistoryEvent(2, b1.Block.Hash(), 1)
+	newB3 := newLogsHistoryEvent(3, newB2.Block.Hash(), 1)
+	b4 := newLogsHistoryEvent(4, newB3.Block.Hash(), 1)
+	st.On("GetL2BlockByNumber", ctx, uint64(3), nil).Return(&newB3.Block, nil).Once()
+	st.On("GetL2BlockByNumber", ctx, uint64(2), nil).Return(&newB2.Block, nil).Twice()
+	st.On("GetL2BlockByNumber", ctx, uint64(1), nil).Return(&b1.Block, nil).Once()
+	st.On("GetLogsByBlockNumber", ctx, uint64(2), nil).Return(newB2.Logs, nil).Once()
+	st.On("GetL2BlockByNumber", ctx, uint64(3), nil).Return(&newB3.Block, nil).Once()
+	st.On("GetLogsByBlockNumber", ctx, uint64(3), nil).Return(newB3.Logs, nil).Once()
+
+	removed, added, err := h.add(ctx, st, b4)
+	require.NoError(t, err)
+	assert.Equal(t, []state.NewL2BlockEvent{b2, b3}, removed)
+	require.Len(t, added, 3)
+	assert.Equal(t, newB2.Block.Hash(), added[0].Block.Hash())
+	assert.Equal(t, newB3.Block.Hash(), added[1].Block.Hash())
+	assert.Equal(t, b4, added[2])
+
+	// the history keeps the new blocks up to its size
+	b5 := newLogsHistoryEvent(5, b4.Block.Hash(), 1)
+	removed, added, err = h.add(ctx, st, b5)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, []state.NewL2BlockEvent{b5}, added)
+	require.Len(t, h.blocks, 3)
+	assert.Equal(t, newB3.Block.Hash(), h.blocks[0].Block.Hash())
+	lastBlockNumber, ok := h.lastBlockNumber()
+	require.True(t, ok)
+	assert.Equal(t, uint64(5), lastBlockNumber)
+
+	// the loaded blocks must follow the kept ones by their parent hash
+	otherB5 := newLogsHistoryEvent(5, newB3.Block.Hash(), 2)
+	b6 := newLogsHistoryEvent(6, otherB5.Block.Hash(), 2)
+	st.On("GetL2BlockByNumber", ctx, uint64(5), nil).Return(&otherB5.Block, nil).Once()
+	st.On("GetL2BlockByNumber", ctx, uint64(4), nil).Return(&b4.Block, nil).Once()
+	unchainedB5 := newLogsHistoryEvent(5, common.Hash{}, 3)
+	st.On("GetL2BlockByNumber", ctx, uint64(5), nil).Return(&unchainedB5.Block, nil).Once()
+	_, _, err = h.add(ctx, st, b6)
+	require.Error(t, err)
+}
@@ -13,8 +13,10 @@ const (
 	requestsHandledName = requestPrefix + "handled"
 	requestDurationName = requestPrefix + "duration"
 	connName            = requestPrefix + "connection"
+	responseCacheName   = prefix + "response_cache"
 
-	requestHandledTypeLabelName = "type"
+	requestHandledTypeLabelName  = "type"
+	responseCacheResultLabelName = "result"
 )
 
 // RequestHandledLabel represents the possible values for the
@@ -25,6 +27,10 @@ type RequestHandledLabel string
 // `jsonrpc_request_connection` metric `type` label.
 type ConnLabel string
 
+// ResponseCacheLabel represents the possible values for the
+// `jsonrpc_response_cache` metric `result` label.
+type ResponseCacheLabel string
+
 const (
 	// RequestHandledLabelInvalid represents an request of type invalid
 	RequestHandledLabelInvalid RequestHandledLabel = "invalid"
@@ -39,6 +45,11 @@ const (
 	HTTPConnLabel ConnLabel = "HTTP"
 	// WSConnLabel represents a WS connection
 	WSConnLabel ConnLabel = "WS"
+
+	// ResponseCacheLabelHit represents a response found in the cache
+	ResponseCacheLabelHit ResponseCacheLabel = "hit"
+	// ResponseCacheLabelMiss represents a cacheable response not found in the cache
+	ResponseCacheLabelMiss ResponseCacheLabel = "miss"
 )
 
 // Register the metrics for the jsonrpc package.
@@ -56,6 +67,13 @@ func Register() {
 			},
 			Labels: []string{requestHandledTypeLabelName},
 		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: responseCacheName,
+				Help: "[JSONRPC] number of cacheable requests by cache result",
+			},
+			Labels: []string{responseCacheResultLabelName},
+		},
 	}
 
 	start := 0.1
@@ -90,3 +108,9 @@ func RequestHandled(label RequestHandledLabel) {
 func RequestDuration(start time.Time) {
 	metrics.HistogramObserve(requestDurationName, time.Since(start).Seconds())
 }
+
+// ResponseCache increments the response cache counter vector by one for the
+// given label.
+func ResponseCache(label ResponseCacheLabel) {
+	metrics.CounterVecInc(responseCacheName, string(label))
+}
@@ -4,7 +4,7 @@ package jsonrpc
 
 import mock "github.com/stretchr/testify/mock"
 
-// storageMock is an autogenerated mock type for the storageInterface type
+// storageMock is an autogenerated mock type for the FilterStorage type
 type storageMock struct {
 	mock.Mock
 }
@@ -49,6 +49,26 @@ func (_m *storageMock) GetAllLogFiltersWithWSConn() []*Filter {
 	return r0
 }
 
+// GetAllStateEventFiltersWithWSConn provides a mock function with given fields: filterType
+func (_m *storageMock) GetAllStateEventFiltersWithWSConn(filterType FilterType) []*Filter {
+	ret := _m.Called(filterType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllStateEventFiltersWithWSConn")
+	}
+
+	var r0 []*Filter
+	if rf, ok := ret.Get(0).(func(FilterType) []*Filter); ok {
+		r0 = rf(filterType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	return r0
+}
+
 // GetFilter provides a mock function with given fields: filterID
 func (_m *storageMock) GetFilter(filterID string) (*Filter, error) {
 	ret := _m.Called(filterID)
@@ -163,6 +183,34 @@ func (_m *storageMock) NewPendingTransactionFilter(wsConn *concurrentWsConn) (st
 	return r0, r1
 }
 
+// NewStateEventFilter provides a mock function with given fields: wsConn, filterType
+func (_m *storageMock) NewStateEventFilter(wsConn *concurrentWsConn, filterType FilterType) (string, error) {
+	ret := _m.Called(wsConn, filterType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewStateEventFilter")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, FilterType) (string, error)); ok {
+		return rf(wsConn, filterType)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, FilterType) string); ok {
+		r0 = rf(wsConn, filterType)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn, FilterType) error); ok {
+		r1 = rf(wsConn, filterType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UninstallFilter provides a mock function with given fields: filterID
 func (_m *storageMock) UninstallFilter(filterID string) error {
 	ret := _m.Called(filterID)
@@ -10,6 +10,8 @@ import (
 
 	coretypes "github.com/ethereum/go-ethereum/core/types"
 
+	merkletree "github.com/0xPolygonHermez/zkevm-node/merkletree"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pgx "github.com/jackc/pgx/v4"
@@ -84,6 +86,66 @@ func (_m *StateMock) BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 	return r0, r1
 }
 
+// CreateAccessList provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, dbTx
+func (_m *StateMock) CreateAccessList(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*state.AccessListResult, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccessList")
+	}
+
+	var r0 *state.AccessListResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, pgx.Tx) (*state.AccessListResult, error)); ok {
+		return rf(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, pgx.Tx) *state.AccessListResult); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.AccessListResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DebugCall provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, traceConfig, stateOverride, blockOverride, dbTx
+func (_m *StateMock) DebugCall(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig state.TraceConfig, stateOverride state.StateOverride, blockOverride *state.BlockOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, traceConfig, stateOverride, blockOverride, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebugCall")
+	}
+
+	var r0 *runtime.ExecutionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, state.StateOverride, *state.BlockOverride, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
+		return rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, stateOverride, blockOverride, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, state.StateOverride, *state.BlockOverride, pgx.Tx) *runtime.ExecutionResult); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, stateOverride, blockOverride, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*runtime.ExecutionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, state.TraceConfig, state.StateOverride, *state.BlockOverride, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, traceConfig, stateOverride, blockOverride, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DebugTransaction provides a mock function with given fields: ctx, transactionHash, traceConfig, dbTx
 func (_m *StateMock) DebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	ret := _m.Called(ctx, transactionHash, traceConfig, dbTx)
@@ -114,9 +176,9 @@ func (_m *StateMock) DebugTransaction(ctx context.Context, transactionHash commo
 	return r0, r1
 }
 
-// EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, dbTx
-func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
-	ret := _m.Called(transaction, senderAddress, l2BlockNumber, dbTx)
+// EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx
+func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride state.StateOverride, blockOverride *state.BlockOverride, dbTx pgx.Tx) (uint64, []byte, error) {
+	ret := _m.Called(transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for EstimateGas")
@@ -125,25 +187,25 @@ func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddre
 	var r0 uint64
 	var r1 []byte
 	var r2 error
-	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) (uint64, []byte, error)); ok {
-		return rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, *state.BlockOverride, pgx.Tx) (uint64, []byte, error)); ok {
+		return rf(transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) uint64); ok {
-		r0 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, *state.BlockOverride, pgx.Tx) uint64); ok {
+		r0 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
-	if rf, ok := ret.Get(1).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) []byte); ok {
-		r1 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(1).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, *state.BlockOverride, pgx.Tx) []byte); ok {
+		r1 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) error); ok {
-		r2 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(2).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, *state.BlockOverride, pgx.Tx) error); ok {
+		r2 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, blockOverride, dbTx)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -151,6 +213,36 @@ func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddre
 	return r0, r1, r2
 }
 
+// GetAccountProof provides a mock function with given fields: ctx, address, storageKeys, root
+func (_m *StateMock) GetAccountProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root common.Hash) (*merkletree.AccountProof, error) {
+	ret := _m.Called(ctx, address, storageKeys, root)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountProof")
+	}
+
+	var r0 *merkletree.AccountProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []common.Hash, common.Hash) (*merkletree.AccountProof, error)); ok {
+		return rf(ctx, address, storageKeys, root)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []common.Hash, common.Hash) *merkletree.AccountProof); ok {
+		r0 = rf(ctx, address, storageKeys, root)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merkletree.AccountProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, []common.Hash, common.Hash) error); ok {
+		r1 = rf(ctx, address, storageKeys, root)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, root)
@@ -271,6 +363,34 @@ func (_m *StateMock) GetCode(ctx context.Context, address common.Address, root c
 	return r0, r1
 }
 
+// GetContractCreationTxHash provides a mock function with given fields: ctx, address, dbTx
+func (_m *StateMock) GetContractCreationTxHash(ctx context.Context, address common.Address, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, address, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractCreationTxHash")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, address, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, address, dbTx)
+	} else {
+		r0 = ret.Get(0).(common.Hash)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExitRootByGlobalExitRoot provides a mock function with given fields: ctx, ger, dbTx
 func (_m *StateMock) GetExitRootByGlobalExitRoot(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*state.GlobalExitRoot, error) {
 	ret := _m.Called(ctx, ger, dbTx)
@@ -301,6 +421,237 @@ func (_m *StateMock) GetExitRootByGlobalExitRoot(ctx context.Context, ger common
 	return r0, r1
 }
 
+// GetFinalProof provides a mock function with given fields: ctx, batchNumber, batchNumberFinal, dbTx
+func (_m *StateMock) GetFinalProof(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	ret := _m.Called(ctx, batchNumber, batchNumberFinal, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFinalProof")
+	}
+
+	var r0 *state.FinalProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) (*state.FinalProof, error)); ok {
+		return rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) *state.FinalProof); ok {
+		r0 = rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.FinalProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFinalProofByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetFinalProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFinalProofByBatchNumber")
+	}
+
+	var r0 *state.FinalProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.FinalProof, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.FinalProof); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.FinalProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForcedBatch provides a mock function with given fields: ctx, forcedBatchNumber, dbTx
+func (_m *StateMock) GetForcedBatch(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*state.ForcedBatch, error) {
+	ret := _m.Called(ctx, forcedBatchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForcedBatch")
+	}
+
+	var r0 *state.ForcedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.ForcedBatch, error)); ok {
+		return rf(ctx, forcedBatchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.ForcedBatch); ok {
+		r0 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.ForcedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForkIDByBatchNumber provides a mock function with given fields: batchNumber
+func (_m *StateMock) GetForkIDByBatchNumber(batchNumber uint64) uint64 {
+	ret := _m.Called(batchNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForkIDByBatchNumber")
+	}
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(uint64) uint64); ok {
+		r0 = rf(batchNumber)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetGeneratedProofByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetGeneratedProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Proof, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGeneratedProofByBatchNumber")
+	}
+
+	var r0 *state.Proof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.Proof, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.Proof); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.Proof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetL1InfoRootLeafByIndex provides a mock function with given fields: ctx, l1InfoTreeIndex, dbTx
+func (_m *StateMock) GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	ret := _m.Called(ctx, l1InfoTreeIndex, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1InfoRootLeafByIndex")
+	}
+
+	var r0 state.L1InfoTreeExitRootStorageEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error)); ok {
+		return rf(ctx, l1InfoTreeIndex, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, pgx.Tx) state.L1InfoTreeExitRootStorageEntry); ok {
+		r0 = rf(ctx, l1InfoTreeIndex, dbTx)
+	} else {
+		r0 = ret.Get(0).(state.L1InfoTreeExitRootStorageEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, pgx.Tx) error); ok {
+		r1 = rf(ctx, l1InfoTreeIndex, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetL1InfoTreeLeafByGlobalExitRoot provides a mock function with given fields: ctx, globalExitRoot, dbTx
+func (_m *StateMock) GetL1InfoTreeLeafByGlobalExitRoot(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	ret := _m.Called(ctx, globalExitRoot, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1InfoTreeLeafByGlobalExitRoot")
+	}
+
+	var r0 state.L1InfoTreeExitRootStorageEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error)); ok {
+		return rf(ctx, globalExitRoot, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) state.L1InfoTreeExitRootStorageEntry); ok {
+		r0 = rf(ctx, globalExitRoot, dbTx)
+	} else {
+		r0 = ret.Get(0).(state.L1InfoTreeExitRootStorageEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, globalExitRoot, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetL1InfoTreeMerkleProof provides a mock function with given fields: ctx, l1InfoTreeIndex, l1InfoRoot, dbTx
+func (_m *StateMock) GetL1InfoTreeMerkleProof(ctx context.Context, l1InfoTreeIndex uint32, l1InfoRoot *common.Hash, dbTx pgx.Tx) ([][32]byte, common.Hash, error) {
+	ret := _m.Called(ctx, l1InfoTreeIndex, l1InfoRoot, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1InfoTreeMerkleProof")
+	}
+
+	var r0 [][32]byte
+	var r1 common.Hash
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, *common.Hash, pgx.Tx) ([][32]byte, common.Hash, error)); ok {
+		return rf(ctx, l1InfoTreeIndex, l1InfoRoot, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint32, *common.Hash, pgx.Tx) [][32]byte); ok {
+		r0 = rf(ctx, l1InfoTreeIndex, l1InfoRoot, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][32]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint32, *common.Hash, pgx.Tx) common.Hash); ok {
+		r1 = rf(ctx, l1InfoTreeIndex, l1InfoRoot, dbTx)
+	} else {
+		r1 = ret.Get(1).(common.Hash)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint32, *common.Hash, pgx.Tx) error); ok {
+		r2 = rf(ctx, l1InfoTreeIndex, l1InfoRoot, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetL2BlockByHash provides a mock function with given fields: ctx, hash, dbTx
 func (_m *StateMock) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, hash, dbTx)
@@ -537,6 +888,36 @@ func (_m *StateMock) GetL2TxHashByTxHash(ctx context.Context, hash common.Hash,
 	return r0, r1
 }
 
+// GetL2TxHashesByL2BlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StateMock) GetL2TxHashesByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (map[common.Hash]common.Hash, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL2TxHashesByL2BlockNumber")
+	}
+
+	var r0 map[common.Hash]common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (map[common.Hash]common.Hash, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) map[common.Hash]common.Hash); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Hash]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -881,6 +1262,36 @@ func (_m *StateMock) GetLogs(ctx context.Context, fromBlock uint64, toBlock uint
 	return r0, r1
 }
 
+// GetLogsByBlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StateMock) GetLogsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*coretypes.Log, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogsByBlockNumber")
+	}
+
+	var r0 []*coretypes.Log
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*coretypes.Log, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*coretypes.Log); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*coretypes.Log)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNativeBlockHashesInRange provides a mock function with given fields: ctx, fromBlockNumber, toBlockNumber, dbTx
 func (_m *StateMock) GetNativeBlockHashesInRange(ctx context.Context, fromBlockNumber uint64, toBlockNumber uint64, dbTx pgx.Tx) ([]common.Hash, error) {
 	ret := _m.Called(ctx, fromBlockNumber, toBlockNumber, dbTx)
@@ -1147,6 +1558,36 @@ func (_m *StateMock) GetTransactionReceipt(ctx context.Context, transactionHash
 	return r0, r1
 }
 
+// GetTransactionReceiptsByL2BlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StateMock) GetTransactionReceiptsByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*coretypes.Receipt, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionReceiptsByL2BlockNumber")
+	}
+
+	var r0 []*coretypes.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*coretypes.Receipt, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*coretypes.Receipt); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*coretypes.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]coretypes.Transaction, []uint8, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1186,6 +1627,94 @@ func (_m *StateMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumb
 	return r0, r1, r2
 }
 
+// GetTxHashBySenderAndNonce provides a mock function with given fields: ctx, sender, nonce, dbTx
+func (_m *StateMock) GetTxHashBySenderAndNonce(ctx context.Context, sender common.Address, nonce uint64, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, sender, nonce, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxHashBySenderAndNonce")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, sender, nonce, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, sender, nonce, dbTx)
+	} else {
+		r0 = ret.Get(0).(common.Hash)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, sender, nonce, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTxsHashesByAddressAfter provides a mock function with given fields: ctx, address, blockNumber, limit, dbTx
+func (_m *StateMock) GetTxsHashesByAddressAfter(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	ret := _m.Called(ctx, address, blockNumber, limit, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsHashesByAddressAfter")
+	}
+
+	var r0 []common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)); ok {
+		return rf(ctx, address, blockNumber, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) []common.Hash); ok {
+		r0 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTxsHashesByAddressBefore provides a mock function with given fields: ctx, address, blockNumber, limit, dbTx
+func (_m *StateMock) GetTxsHashesByAddressBefore(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	ret := _m.Called(ctx, address, blockNumber, limit, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsHashesByAddressBefore")
+	}
+
+	var r0 []common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)); ok {
+		return rf(ctx, address, blockNumber, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) []common.Hash); ok {
+		r0 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVerifiedBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1216,6 +1745,36 @@ func (_m *StateMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, d
 	return r0, r1
 }
 
+// GetVerifiedBatchContaining provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetVerifiedBatchContaining(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVerifiedBatchContaining")
+	}
+
+	var r0 *state.VerifiedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.VerifiedBatch, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.VerifiedBatch); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.VerifiedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVirtualBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VirtualBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1332,9 +1891,9 @@ func (_m *StateMock) PreProcessUnsignedTransaction(ctx context.Context, tx *core
 	return r0, r1
 }
 
-// ProcessUnsignedTransaction provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx
-func (_m *StateMock) ProcessUnsignedTransaction(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
-	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+// ProcessUnsignedTransaction provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, blockOverride, dbTx
+func (_m *StateMock) ProcessUnsignedTransaction(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride state.StateOverride, blockOverride *state.BlockOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, blockOverride, dbTx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ProcessUnsignedTransaction")
@@ -1342,19 +1901,19 @@ func (_m *StateMock) ProcessUnsignedTransaction(ctx context.Context, tx *coretyp
 
 	var r0 *runtime.ExecutionResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
-		return rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, *state.BlockOverride, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
+		return rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, blockOverride, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) *runtime.ExecutionResult); ok {
-		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, *state.BlockOverride, pgx.Tx) *runtime.ExecutionResult); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, blockOverride, dbTx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*runtime.ExecutionResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) error); ok {
-		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, *state.BlockOverride, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, blockOverride, dbTx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1367,11 +1926,21 @@ func (_m *StateMock) RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandl
 	_m.Called(h)
 }
 
+// RegisterStateEventHandler provides a mock function with given fields: h
+func (_m *StateMock) RegisterStateEventHandler(h state.StateEventHandler) {
+	_m.Called(h)
+}
+
 // StartToMonitorNewL2Blocks provides a mock function with given fields:
 func (_m *StateMock) StartToMonitorNewL2Blocks() {
 	_m.Called()
 }
 
+// StartToMonitorStateEvents provides a mock function with given fields:
+func (_m *StateMock) StartToMonitorStateEvents() {
+	_m.Called()
+}
+
 // NewStateMock creates a new instance of StateMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStateMock(t interface {
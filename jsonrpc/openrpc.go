@@ -0,0 +1,367 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/invopop/jsonschema"
+)
+
+const (
+	openRPCVersion = "1.2.6"
+	openRPCTitle   = "zkEVM JSON RPC"
+	// openRPCAPIVersion is the version of the API described by the document, it's
+	// not the node version so the generated document only changes with the API
+	openRPCAPIVersion = "1.0.0"
+
+	jsonSchemaDefsPrefix    = "#/$defs/"
+	openRPCComponentsPrefix = "#/components/schemas/"
+	hexPattern              = "^0x[0-9a-fA-F]*$"
+	uintPattern             = "^0x([1-9a-fA-F][0-9a-fA-F]*|0)$"
+	hashPattern             = "^0x[0-9a-fA-F]{64}$"
+	addressPattern          = "^0x[0-9a-fA-F]{40}$"
+)
+
+// OpenRPCDocument describes the methods served by the handler following
+// the OpenRPC specification, see https://spec.open-rpc.org
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo is the metadata of the API
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a method, its params and its result
+type OpenRPCMethod struct {
+	Name   string                     `json:"name"`
+	Params []OpenRPCContentDescriptor `json:"params"`
+	Result OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a param or a result with its json schema
+type OpenRPCContentDescriptor struct {
+	Name     string             `json:"name"`
+	Required bool               `json:"required,omitempty"`
+	Schema   *jsonschema.Schema `json:"schema"`
+}
+
+// OpenRPCComponents has the schemas referenced by the methods
+type OpenRPCComponents struct {
+	Schemas map[string]*jsonschema.Schema `json:"schemas"`
+}
+
+// openRPCMethodSpec provides the data of a method that can't be obtained by reflection:
+// the names of the params and the type of the result, since all the methods return
+// an interface{}. A nil result means the method can return any json value
+type openRPCMethodSpec struct {
+	params []string
+	result interface{}
+}
+
+// openRPCMethods are the specs of the methods exposed by the services, a method
+// without spec is documented with generic param names and any result
+var openRPCMethods = map[string]openRPCMethodSpec{
+	"debug_getRawBatch":        {params: []string{"batchNumber"}, result: types.ArgBytes{}},
+	"debug_getRawBlock":        {params: []string{"block"}, result: types.ArgBytes{}},
+	"debug_getRawReceipts":     {params: []string{"block"}, result: []types.ArgBytes{}},
+	"debug_getRawTransaction":  {params: []string{"transactionHash"}, result: types.ArgBytes{}},
+	"debug_traceBatchByNumber": {params: []string{"batchNumber", "traceConfig"}, result: []traceBatchTransactionResponse{}},
+	"debug_traceBlockByHash":   {params: []string{"blockHash", "traceConfig"}, result: []traceBlockTransactionResponse{}},
+	"debug_traceBlockByNumber": {params: []string{"blockNumber", "traceConfig"}, result: []traceBlockTransactionResponse{}},
+	"debug_traceCall":          {params: []string{"transaction", "block", "traceConfig"}},
+	"debug_traceTransaction":   {params: []string{"transactionHash", "traceConfig"}},
+
+	"eth_blockNumber":                         {result: types.ArgUint64(0)},
+	"eth_call":                                {params: []string{"transaction", "block", "stateOverride", "blockOverrides"}, result: types.ArgBytes{}},
+	"eth_chainId":                             {result: types.ArgUint64(0)},
+	"eth_coinbase":                            {result: common.Address{}},
+	"eth_createAccessList":                    {params: []string{"transaction", "block"}, result: types.AccessListResult{}},
+	"eth_estimateGas":                         {params: []string{"transaction", "block", "stateOverride", "blockOverrides"}, result: types.ArgUint64(0)},
+	"eth_feeHistory":                          {params: []string{"blockCount", "newestBlock", "rewardPercentiles"}, result: types.FeeHistory{}},
+	"eth_gasPrice":                            {result: types.ArgUint64(0)},
+	"eth_getBalance":                          {params: []string{"address", "block"}, result: types.ArgBig{}},
+	"eth_getBlockByHash":                      {params: []string{"blockHash", "fullTransactions", "includeExtraInfo"}, result: types.Block{}},
+	"eth_getBlockByNumber":                    {params: []string{"blockNumber", "fullTransactions", "includeExtraInfo"}, result: types.Block{}},
+	"eth_getBlockReceipts":                    {params: []string{"block", "includeExtraInfo"}, result: []types.Receipt{}},
+	"eth_getBlockTransactionCountByHash":      {params: []string{"blockHash"}, result: types.ArgUint64(0)},
+	"eth_getBlockTransactionCountByNumber":    {params: []string{"blockNumber"}, result: types.ArgUint64(0)},
+	"eth_getCode":                             {params: []string{"address", "block"}, result: types.ArgBytes{}},
+	"eth_getCompilers":                        {result: []interface{}{}},
+	"eth_getFilterChanges":                    {params: []string{"filterId"}},
+	"eth_getFilterLogs":                       {params: []string{"filterId"}, result: []types.Log{}},
+	"eth_getLogs":                             {params: []string{"filter"}, result: []types.Log{}},
+	"eth_getProof":                            {params: []string{"address", "storageKeys", "block"}},
+	"eth_getStorageAt":                        {params: []string{"address", "storageKey", "block"}, result: types.ArgBytes{}},
+	"eth_getTransactionByBlockHashAndIndex":   {params: []string{"blockHash", "index", "includeExtraInfo"}, result: types.Transaction{}},
+	"eth_getTransactionByBlockNumberAndIndex": {params: []string{"blockNumber", "index", "includeExtraInfo"}, result: types.Transaction{}},
+	"eth_getTransactionByHash":                {params: []string{"transactionHash", "includeExtraInfo"}, result: types.Transaction{}},
+	"eth_getTransactionCount":                 {params: []string{"address", "block"}, result: types.ArgUint64(0)},
+	"eth_getTransactionReceipt":               {params: []string{"transactionHash"}, result: types.Receipt{}},
+	"eth_getUncleByBlockHashAndIndex":         {params: []string{"blockHash", "index"}},
+	"eth_getUncleByBlockNumberAndIndex":       {params: []string{"blockNumber", "index"}},
+	"eth_getUncleCountByBlockHash":            {params: []string{"blockHash"}, result: types.ArgUint64(0)},
+	"eth_getUncleCountByBlockNumber":          {params: []string{"blockNumber"}, result: types.ArgUint64(0)},
+	"eth_maxPriorityFeePerGas":                {result: types.ArgUint64(0)},
+	"eth_newBlockFilter":                      {result: ""},
+	"eth_newFilter":                           {params: []string{"filter"}, result: ""},
+	"eth_newPendingTransactionFilter":         {result: ""},
+	"eth_protocolVersion":                     {result: types.ArgUint64(0)},
+	"eth_sendRawTransaction":                  {params: []string{"data"}, result: common.Hash{}},
+	"eth_subscribe":                           {params: []string{"subscriptionName", "filter"}, result: ""},
+	"eth_syncing":                             {},
+	"eth_uninstallFilter":                     {params: []string{"filterId"}, result: false},
+	"eth_unsubscribe":                         {params: []string{"subscriptionId"}, result: false},
+
+	"net_version": {result: ""},
+
+	"ots_getApiLevel":                    {result: 0},
+	"ots_getBlockDetails":                {params: []string{"blockNumber"}, result: otsBlockDetails{}},
+	"ots_getBlockDetailsByHash":          {params: []string{"blockHash"}, result: otsBlockDetails{}},
+	"ots_getBlockTransactions":           {params: []string{"blockNumber", "pageNumber", "pageSize"}, result: otsBlockTransactions{}},
+	"ots_getContractCreator":             {params: []string{"address"}, result: otsContractCreator{}},
+	"ots_getInternalOperations":          {params: []string{"transactionHash"}, result: []otsInternalOperation{}},
+	"ots_getTransactionBySenderAndNonce": {params: []string{"address", "nonce"}, result: common.Hash{}},
+	"ots_getTransactionError":            {params: []string{"transactionHash"}, result: types.ArgBytes{}},
+	"ots_hasCode":                        {params: []string{"address", "block"}, result: false},
+	"ots_searchTransactionsAfter":        {params: []string{"address", "blockNumber", "pageSize"}, result: otsSearchResult{}},
+	"ots_searchTransactionsBefore":       {params: []string{"address", "blockNumber", "pageSize"}, result: otsSearchResult{}},
+	"ots_traceTransaction":               {params: []string{"transactionHash"}, result: []otsTraceEntry{}},
+
+	"rpc_discover": {result: OpenRPCDocument{}},
+
+	"trace_block":             {params: []string{"blockNumber"}, result: []json.RawMessage{}},
+	"trace_filter":            {params: []string{"filter"}, result: []json.RawMessage{}},
+	"trace_get":               {params: []string{"transactionHash", "indices"}},
+	"trace_replayTransaction": {params: []string{"transactionHash", "traceTypes"}, result: traceReplayResult{}},
+	"trace_transaction":       {params: []string{"transactionHash"}, result: []json.RawMessage{}},
+
+	"txpool_content": {},
+
+	"web3_clientVersion": {result: ""},
+	"web3_sha3":          {params: []string{"data"}, result: types.ArgBytes{}},
+
+	"zkevm_batchNumber":                   {result: types.ArgUint64(0)},
+	"zkevm_batchNumberByBlockNumber":      {params: []string{"blockNumber"}, result: types.ArgUint64(0)},
+	"zkevm_consolidatedBlockNumber":       {result: types.ArgUint64(0)},
+	"zkevm_estimateCounters":              {params: []string{"transaction", "block"}, result: types.ZKCountersResponse{}},
+	"zkevm_estimateFee":                   {params: []string{"transaction", "block"}, result: types.ArgBig{}},
+	"zkevm_estimateGasPrice":              {params: []string{"transaction", "block"}, result: types.ArgBig{}},
+	"zkevm_getBatchByNumber":              {params: []string{"batchNumber", "fullTransactions"}, result: types.Batch{}},
+	"zkevm_getBatchProofStatus":           {params: []string{"batchNumber"}, result: types.BatchProofStatus{}},
+	"zkevm_getExitRootsByGER":             {params: []string{"globalExitRoot"}, result: types.ExitRoots{}},
+	"zkevm_getFinalProof":                 {params: []string{"batchNumber", "batchNumberFinal"}, result: types.FinalProof{}},
+	"zkevm_getFullBlockByHash":            {params: []string{"blockHash", "fullTransactions"}, result: types.Block{}},
+	"zkevm_getFullBlockByNumber":          {params: []string{"blockNumber", "fullTransactions"}, result: types.Block{}},
+	"zkevm_getL1InfoTreeLeaf":             {params: []string{"index"}, result: types.L1InfoTreeLeaf{}},
+	"zkevm_getL1InfoTreeLeafByGER":        {params: []string{"globalExitRoot"}, result: types.L1InfoTreeLeaf{}},
+	"zkevm_getL1InfoTreeProof":            {params: []string{"index", "l1InfoRoot"}, result: types.L1InfoTreeProof{}},
+	"zkevm_getLatestGlobalExitRoot":       {result: common.Hash{}},
+	"zkevm_getNativeBlockHashesInRange":   {params: []string{"filter"}, result: []common.Hash{}},
+	"zkevm_getTransactionByL2Hash":        {params: []string{"transactionHash"}, result: types.Transaction{}},
+	"zkevm_getTransactionPoolStatus":      {params: []string{"transactionHash"}, result: types.PoolTransactionStatus{}},
+	"zkevm_getTransactionReceiptByL2Hash": {params: []string{"transactionHash"}, result: types.Receipt{}},
+	"zkevm_isBlockConsolidated":           {params: []string{"blockNumber"}, result: false},
+	"zkevm_isBlockVirtualized":            {params: []string{"blockNumber"}, result: false},
+	"zkevm_subscribe":                     {params: []string{"subscriptionName"}, result: ""},
+	"zkevm_unsubscribe":                   {params: []string{"subscriptionId"}, result: false},
+	"zkevm_verifiedBatchNumber":           {result: types.ArgUint64(0)},
+	"zkevm_virtualBatchNumber":            {result: types.ArgUint64(0)},
+}
+
+// openRPCParamTypes replaces the types whose json encoding is customized
+// by the type describing the encoding
+var openRPCParamTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(LogFilter{}): reflect.TypeOf(types.LogFilterRequest{}),
+}
+
+var (
+	wsConnType      = reflect.TypeOf(&concurrentWsConn{})
+	httpRequestType = reflect.TypeOf(&http.Request{})
+)
+
+// openRPCBuilder builds the OpenRPC document of the services registered in the handler
+type openRPCBuilder struct {
+	reflector *jsonschema.Reflector
+	methods   map[string]OpenRPCMethod
+	schemas   map[string]*jsonschema.Schema
+	// schemaTypes are the types of the schemas by name, the schemas are named after
+	// their types so two types with the same name would share the same schema
+	schemaTypes map[string][]reflect.Type
+}
+
+func newOpenRPCBuilder() *openRPCBuilder {
+	b := &openRPCBuilder{
+		methods:     make(map[string]OpenRPCMethod),
+		schemas:     make(map[string]*jsonschema.Schema),
+		schemaTypes: make(map[string][]reflect.Type),
+	}
+	b.reflector = &jsonschema.Reflector{
+		Anonymous:                 true,
+		AllowAdditionalProperties: true,
+		Mapper:                    openRPCSchemaMapper,
+		Namer:                     b.schemaName,
+	}
+	return b
+}
+
+// schemaName names the schemas after their types and records the types of each name,
+// the types provided by the mapper are skipped since they don't have their own schema
+func (b *openRPCBuilder) schemaName(t reflect.Type) string {
+	if openRPCSchemaMapper(t) != nil {
+		return t.Name()
+	}
+	for _, named := range b.schemaTypes[t.Name()] {
+		if named == t {
+			return t.Name()
+		}
+	}
+	b.schemaTypes[t.Name()] = append(b.schemaTypes[t.Name()], t)
+	return t.Name()
+}
+
+// addMethod describes a method from the types of its params, the first one
+// is the service receiver, the params injected by the handler are skipped
+func (b *openRPCBuilder) addMethod(name string, fd *funcData) {
+	spec := openRPCMethods[name]
+
+	method := OpenRPCMethod{Name: name, Params: []OpenRPCContentDescriptor{}}
+	paramTypes := fd.reqt[1:]
+	if len(paramTypes) > 0 && (paramTypes[0] == wsConnType || paramTypes[0] == httpRequestType) {
+		paramTypes = paramTypes[1:]
+	}
+	for i, t := range paramTypes {
+		paramName := fmt.Sprintf("param%d", i+1)
+		if i < len(spec.params) {
+			paramName = spec.params[i]
+		}
+		method.Params = append(method.Params, OpenRPCContentDescriptor{
+			Name:     paramName,
+			Required: t.Kind() != reflect.Ptr,
+			Schema:   b.schema(t),
+		})
+	}
+
+	method.Result = OpenRPCContentDescriptor{Name: "result", Schema: &jsonschema.Schema{}}
+	if spec.result != nil {
+		method.Result.Schema = b.schema(reflect.TypeOf(spec.result))
+	}
+	b.methods[name] = method
+}
+
+// schema reflects the json schema of the type, the definitions of the
+// structs are moved to the components so they are shared by the methods
+func (b *openRPCBuilder) schema(t reflect.Type) *jsonschema.Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if replacement, found := openRPCParamTypes[t]; found {
+		t = replacement
+	}
+
+	s := b.reflector.ReflectFromType(t)
+	for name, definition := range s.Definitions {
+		b.schemas[name] = definition
+	}
+	s.Definitions = nil
+	s.Version = ""
+	return s
+}
+
+// document returns the OpenRPC document with the methods sorted by name
+func (b *openRPCBuilder) document() OpenRPCDocument {
+	doc := OpenRPCDocument{
+		OpenRPC:    openRPCVersion,
+		Info:       OpenRPCInfo{Title: openRPCTitle, Version: openRPCAPIVersion},
+		Methods:    make([]OpenRPCMethod, 0, len(b.methods)),
+		Components: OpenRPCComponents{Schemas: b.schemas},
+	}
+	for _, method := range b.methods {
+		doc.Methods = append(doc.Methods, method)
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool { return doc.Methods[i].Name < doc.Methods[j].Name })
+	return doc
+}
+
+// MarshalJSON encodes the document pointing the references to the components
+func (d OpenRPCDocument) MarshalJSON() ([]byte, error) {
+	type document OpenRPCDocument
+	data, err := json.Marshal(document(d))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.ReplaceAll(string(data), jsonSchemaDefsPrefix, openRPCComponentsPrefix)), nil
+}
+
+// openRPCSchemaMapper provides the schema of the types whose json encoding is customized
+func openRPCSchemaMapper(t reflect.Type) *jsonschema.Schema {
+	uintSchema := &jsonschema.Schema{Type: "string", Pattern: uintPattern}
+	hashSchema := &jsonschema.Schema{Type: "string", Pattern: hashPattern}
+	blockTagSchema := &jsonschema.Schema{Type: "string", Enum: []interface{}{types.Earliest, types.Latest, types.Pending, types.Safe, types.Finalized}}
+
+	switch t {
+	case reflect.TypeOf(types.ArgUint64(0)), reflect.TypeOf(types.Index(0)), reflect.TypeOf(hexutil.Uint64(0)),
+		reflect.TypeOf(hexutil.Uint(0)), reflect.TypeOf(types.ArgBig{}), reflect.TypeOf(big.Int{}), reflect.TypeOf(hexutil.Big{}):
+		return uintSchema
+	case reflect.TypeOf(types.ArgBytes{}), reflect.TypeOf(hexutil.Bytes{}), reflect.TypeOf(ethTypes.Bloom{}):
+		return &jsonschema.Schema{Type: "string", Pattern: hexPattern}
+	case reflect.TypeOf(types.ArgHash{}), reflect.TypeOf(common.Hash{}):
+		return hashSchema
+	case reflect.TypeOf(types.ArgAddress{}), reflect.TypeOf(common.Address{}):
+		return &jsonschema.Schema{Type: "string", Pattern: addressPattern}
+	case reflect.TypeOf(types.BlockNumber(0)), reflect.TypeOf(types.BatchNumber(0)):
+		return &jsonschema.Schema{OneOf: []*jsonschema.Schema{uintSchema, blockTagSchema}}
+	case reflect.TypeOf(types.L1InfoRootOrLatest{}):
+		return &jsonschema.Schema{OneOf: []*jsonschema.Schema{hashSchema, {Type: "string", Enum: []interface{}{types.Latest}}}}
+	case reflect.TypeOf(types.BlockNumberOrHash{}):
+		return &jsonschema.Schema{OneOf: []*jsonschema.Schema{uintSchema, blockTagSchema, hashSchema}}
+	case reflect.TypeOf(types.TransactionOrHash{}), reflect.TypeOf(types.BlockOrHash{}):
+		return &jsonschema.Schema{OneOf: []*jsonschema.Schema{hashSchema, {Type: "object"}}}
+	case reflect.TypeOf(ethTypes.Log{}):
+		// the logs of the receipts are encoded like the logs of the api
+		reflector := &jsonschema.Reflector{Anonymous: true, AllowAdditionalProperties: true, DoNotReference: true, Mapper: openRPCSchemaMapper}
+		s := reflector.ReflectFromType(reflect.TypeOf(types.Log{}))
+		s.Version = ""
+		return s
+	case reflect.TypeOf(json.RawMessage{}):
+		return &jsonschema.Schema{}
+	case reflect.TypeOf(jsonschema.Schema{}):
+		return &jsonschema.Schema{Type: "object"}
+	}
+	return nil
+}
+
+// GenerateOpenRPCDocument returns the OpenRPC document of all the services
+// the node can expose, it's the document committed in the repository
+func GenerateOpenRPCDocument() ([]byte, error) {
+	handler := newJSONRpcHandler()
+	handler.registerService(Service{Name: APIEth, Service: &EthEndpoints{}})
+	handler.registerService(Service{Name: APINet, Service: &NetEndpoints{}})
+	handler.registerService(Service{Name: APIDebug, Service: &DebugEndpoints{}})
+	handler.registerService(Service{Name: APIZKEVM, Service: &ZKEVMEndpoints{}})
+	handler.registerService(Service{Name: APITxPool, Service: &TxPoolEndpoints{}})
+	handler.registerService(Service{Name: APIWeb3, Service: &Web3Endpoints{}})
+	handler.registerService(Service{Name: APIOts, Service: &OtsEndpoints{}})
+	handler.registerService(Service{Name: APITrace, Service: &TraceEndpoints{}})
+	handler.registerService(Service{Name: APIRPC, Service: &RPCEndpoints{handler: handler}})
+
+	data, err := json.MarshalIndent(handler.openRPC.document(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
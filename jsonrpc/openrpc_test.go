@@ -0,0 +1,223 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/invopop/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertOpenRPCResult checks the result of a method against the schema of its result in the OpenRPC
+// document, so the result types declared for the methods are checked by the endpoint tests
+func assertOpenRPCResult(t *testing.T, b *openRPCBuilder, method string, result json.RawMessage) {
+	spec, found := b.methods[method]
+	if !found || len(result) == 0 {
+		return
+	}
+	var value interface{}
+	require.NoError(t, json.Unmarshal(result, &value))
+	assert.NoError(t, validateOpenRPCSchema(b.schemas, spec.Result.Schema, value, method), "the result of %s doesn't match its OpenRPC schema", method)
+}
+
+// validateOpenRPCSchema validates a json value against the subset of the json schema used by the
+// OpenRPC document. The null values are valid since the pointers are not described as nullable
+func validateOpenRPCSchema(schemas map[string]*jsonschema.Schema, schema *jsonschema.Schema, value interface{}, path string) error {
+	if schema == nil || value == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		definition, found := schemas[strings.TrimPrefix(schema.Ref, jsonSchemaDefsPrefix)]
+		if !found {
+			return fmt.Errorf("%s: unknown schema %s", path, schema.Ref)
+		}
+		return validateOpenRPCSchema(schemas, definition, value, path)
+	}
+	if len(schema.OneOf) > 0 {
+		for _, s := range schema.OneOf {
+			if validateOpenRPCSchema(schemas, s, value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v doesn't match any of the schemas", path, value)
+	}
+
+	switch schema.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", path, value)
+		}
+		if schema.Pattern != "" && !regexp.MustCompile(schema.Pattern).MatchString(s) {
+			return fmt.Errorf("%s: %s doesn't match %s", path, s, schema.Pattern)
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: %v is not a number", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", path, value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, value)
+		}
+		for i, item := range items {
+			if err := validateOpenRPCSchema(schemas, schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, value)
+		}
+		for name, field := range fields {
+			fieldSchema := schema.AdditionalProperties
+			if schema.Properties != nil {
+				property, found := schema.Properties.Get(name)
+				if !found && fieldSchema == nil {
+					return fmt.Errorf("%s: unexpected field %s", path, name)
+				}
+				if found {
+					fieldSchema = property
+				}
+			}
+			if err := validateOpenRPCSchema(schemas, fieldSchema, field, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// openRPCResultsTransport checks the results of the http responses against the OpenRPC document
+type openRPCResultsTransport struct {
+	t       *testing.T
+	openRPC *openRPCBuilder
+}
+
+func (rt *openRPCResultsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(reqBody))
+
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	var request types.Request
+	var response types.Response
+	if json.Unmarshal(reqBody, &request) == nil && json.Unmarshal(resBody, &response) == nil && response.Error == nil {
+		assertOpenRPCResult(rt.t, rt.openRPC, request.Method, response.Result)
+	}
+	return res, nil
+}
+
+func TestOpenRPCDocumentIsUpToDate(t *testing.T) {
+	generated, err := GenerateOpenRPCDocument()
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("openrpc.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(generated), "the OpenRPC document is not up to date, run `make openrpc-doc-gen` to update it")
+}
+
+func TestOpenRPCMethodSpecs(t *testing.T) {
+	handler := newJSONRpcHandler()
+	handler.registerService(Service{Name: APIEth, Service: &EthEndpoints{}})
+	handler.registerService(Service{Name: APINet, Service: &NetEndpoints{}})
+	handler.registerService(Service{Name: APIDebug, Service: &DebugEndpoints{}})
+	handler.registerService(Service{Name: APIZKEVM, Service: &ZKEVMEndpoints{}})
+	handler.registerService(Service{Name: APITxPool, Service: &TxPoolEndpoints{}})
+	handler.registerService(Service{Name: APIWeb3, Service: &Web3Endpoints{}})
+	handler.registerService(Service{Name: APIOts, Service: &OtsEndpoints{}})
+	handler.registerService(Service{Name: APITrace, Service: &TraceEndpoints{}})
+	handler.registerService(Service{Name: APIRPC, Service: &RPCEndpoints{handler: handler}})
+
+	// every method must be described with the names of all its params
+	for name, method := range handler.openRPC.methods {
+		spec, found := openRPCMethods[name]
+		if assert.True(t, found, "missing OpenRPC spec for %s", name) {
+			assert.Len(t, spec.params, len(method.Params), "unexpected number of param names for %s", name)
+		}
+	}
+	for name := range openRPCMethods {
+		assert.Contains(t, handler.openRPC.methods, name, "OpenRPC spec for the unknown method %s", name)
+	}
+
+	// the schemas are named after their types, so the names must be unique
+	for name, schemaTypes := range handler.openRPC.schemaTypes {
+		if name != "" {
+			assert.Len(t, schemaTypes, 1, "the types %v share the schema %s", schemaTypes, name)
+		}
+	}
+}
+
+func TestValidateOpenRPCSchema(t *testing.T) {
+	b := newOpenRPCBuilder()
+	schema := b.schema(reflect.TypeOf(types.Receipt{}))
+
+	valid := map[string]interface{}{"blockNumber": "0x1", "status": "0x1", "logs": []interface{}{map[string]interface{}{"address": "0x0000000000000000000000000000000000000001"}}}
+	assert.NoError(t, validateOpenRPCSchema(b.schemas, schema, valid, "receipt"))
+
+	assert.Error(t, validateOpenRPCSchema(b.schemas, schema, map[string]interface{}{"blockNumber": 1}, "receipt"))
+	assert.Error(t, validateOpenRPCSchema(b.schemas, schema, map[string]interface{}{"unknown": "0x1"}, "receipt"))
+	assert.Error(t, validateOpenRPCSchema(b.schemas, schema, map[string]interface{}{"logs": []interface{}{map[string]interface{}{"address": "0x1"}}}, "receipt"))
+}
+
+func TestRPCDiscover(t *testing.T) {
+	handler := newJSONRpcHandler()
+	handler.registerService(Service{Name: APIWeb3, Service: &Web3Endpoints{}})
+	handler.registerService(Service{Name: APIDebug, Service: &DebugEndpoints{}, Restricted: true})
+	handler.registerService(Service{Name: APIRPC, Service: &RPCEndpoints{handler: handler}})
+
+	res := handler.Handle(handleRequest{Request: types.Request{JSONRPC: "2.0", ID: 1, Method: "rpc_discover", Params: json.RawMessage(`[]`)}})
+	require.Nil(t, res.Error)
+
+	var doc struct {
+		Methods []struct {
+			Name   string `json:"name"`
+			Params []struct {
+				Name     string          `json:"name"`
+				Required bool            `json:"required"`
+				Schema   json.RawMessage `json:"schema"`
+			} `json:"params"`
+		} `json:"methods"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &doc))
+
+	// the restricted namespaces aren't documented
+	names := make([]string, 0, len(doc.Methods))
+	for _, method := range doc.Methods {
+		names = append(names, method.Name)
+	}
+	assert.Equal(t, []string{"rpc_discover", "web3_clientVersion", "web3_sha3"}, names)
+
+	sha3 := doc.Methods[2]
+	require.Len(t, sha3.Params, 1)
+	assert.Equal(t, "data", sha3.Params[0].Name)
+	assert.True(t, sha3.Params[0].Required)
+	assert.JSONEq(t, `{"type":"string","pattern":"^0x([1-9a-fA-F][0-9a-fA-F]*|0)$"}`, string(sha3.Params[0].Schema))
+}
@@ -0,0 +1,110 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostgresAPIKeyStorage uses a postgres database to store the API keys
+type PostgresAPIKeyStorage struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresAPIKeyStorage creates and initializes an instance of PostgresAPIKeyStorage
+func NewPostgresAPIKeyStorage(db *pgxpool.Pool) *PostgresAPIKeyStorage {
+	return &PostgresAPIKeyStorage{
+		db: db,
+	}
+}
+
+// AddAPIKey persists a new API key
+func (s *PostgresAPIKeyStorage) AddAPIKey(ctx context.Context, apiKey APIKey) error {
+	const addAPIKeySQL = `
+		INSERT INTO pool.api_key (hash, name, namespaces, methods, requests_quota, compute_quota, quota_window, max_batch_size)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := s.db.Exec(ctx, addAPIKeySQL, apiKey.Hash, apiKey.Name, nonNilStrings(apiKey.Namespaces), nonNilStrings(apiKey.Methods),
+		apiKey.RequestsQuota, apiKey.ComputeQuota.Milliseconds(), int64(apiKey.QuotaWindow.Seconds()), apiKey.MaxBatchSize)
+	return err
+}
+
+// GetAPIKey gets an API key by its hash
+func (s *PostgresAPIKeyStorage) GetAPIKey(ctx context.Context, hash string) (*APIKey, error) {
+	const getAPIKeySQL = `
+		SELECT hash, name, namespaces, methods, requests_quota, compute_quota, quota_window, max_batch_size, created_at
+		  FROM pool.api_key
+		 WHERE hash = $1`
+
+	apiKey, err := scanAPIKey(s.db.QueryRow(ctx, getAPIKeySQL, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// GetAPIKeys gets all the API keys sorted by name
+func (s *PostgresAPIKeyStorage) GetAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	const getAPIKeysSQL = `
+		SELECT hash, name, namespaces, methods, requests_quota, compute_quota, quota_window, max_batch_size, created_at
+		  FROM pool.api_key
+		 ORDER BY name`
+
+	rows, err := s.db.Query(ctx, getAPIKeysSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	apiKeys := []*APIKey{}
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+	return apiKeys, rows.Err()
+}
+
+// DeleteAPIKey deletes an API key by its name
+func (s *PostgresAPIKeyStorage) DeleteAPIKey(ctx context.Context, name string) error {
+	const deleteAPIKeySQL = "DELETE FROM pool.api_key WHERE name = $1"
+
+	commandTag, err := s.db.Exec(ctx, deleteAPIKeySQL, name)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*APIKey, error) {
+	var (
+		apiKey       APIKey
+		computeQuota int64
+		quotaWindow  int64
+	)
+	err := row.Scan(&apiKey.Hash, &apiKey.Name, &apiKey.Namespaces, &apiKey.Methods, &apiKey.RequestsQuota,
+		&computeQuota, &quotaWindow, &apiKey.MaxBatchSize, &apiKey.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.ComputeQuota = time.Duration(computeQuota) * time.Millisecond
+	apiKey.QuotaWindow = time.Duration(quotaWindow) * time.Second
+	return &apiKey, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,93 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostgresResponseCacheStore uses a postgres database to share the cached
+// responses across all the instances of the json rpc server
+type PostgresResponseCacheStore struct {
+	cfg ResponseCacheConfig
+	db  *pgxpool.Pool
+}
+
+// NewPostgresResponseCacheStore creates and initializes an instance of PostgresResponseCacheStore
+func NewPostgresResponseCacheStore(cfg ResponseCacheConfig, db *pgxpool.Pool) *PostgresResponseCacheStore {
+	return &PostgresResponseCacheStore{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// GetResponse gets a cached response by its key, the expired responses are not returned
+func (s *PostgresResponseCacheStore) GetResponse(ctx context.Context, key string) ([]byte, error) {
+	const getResponseSQL = `
+		SELECT response
+		  FROM pool.response_cache
+		 WHERE key = $1
+		   AND created_at >= NOW() - $2 * INTERVAL '1 second'`
+
+	var response []byte
+	err := s.db.QueryRow(ctx, getResponseSQL, key, s.cfg.SharedStoreTTL.Seconds()).Scan(&response)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// AddResponse persists a response, if another instance already added
+// the same response the existing one is kept
+func (s *PostgresResponseCacheStore) AddResponse(ctx context.Context, key string, response []byte) error {
+	const addResponseSQL = "INSERT INTO pool.response_cache (key, response) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING"
+	_, err := s.db.Exec(ctx, addResponseSQL, key, response)
+	return err
+}
+
+// ClearResponses deletes all the cached responses
+func (s *PostgresResponseCacheStore) ClearResponses(ctx context.Context) error {
+	const clearResponsesSQL = "DELETE FROM pool.response_cache"
+	_, err := s.db.Exec(ctx, clearResponsesSQL)
+	return err
+}
+
+// DeleteExpiredResponses deletes the responses cached for longer than the
+// configured TTL and returns how many responses were deleted
+func (s *PostgresResponseCacheStore) DeleteExpiredResponses(ctx context.Context) (int64, error) {
+	const deleteExpiredResponsesSQL = "DELETE FROM pool.response_cache WHERE created_at < NOW() - $1 * INTERVAL '1 second'"
+	commandTag, err := s.db.Exec(ctx, deleteExpiredResponsesSQL, s.cfg.SharedStoreTTL.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// StartToCleanupExpiredResponses periodically deletes the expired responses until the
+// context is done. The deletion is done in a single statement, so it's safe to run
+// it in all the instances sharing the database
+func (s *PostgresResponseCacheStore) StartToCleanupExpiredResponses(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SharedStoreCleanupInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.DeleteExpiredResponses(ctx)
+			if err != nil {
+				log.Errorf("failed to delete expired cached responses: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Debugf("%d expired cached responses deleted", deleted)
+			}
+		}
+	}
+}
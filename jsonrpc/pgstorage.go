@@ -0,0 +1,232 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostgresStorage uses a postgres database to store the polling filters, so
+// they are shared by all the instances of the json rpc server running behind
+// a load balancer. The filters bound to a web socket connection are kept in
+// memory since the connection only lives in the instance that accepted it.
+//
+// The changes of the block and log filters are read from the creation time of
+// the blocks, set by the state database, so their last poll is read from the
+// clock of the state database too. The pending tx filters use the clock of the
+// pool database the txs are read from
+type PostgresStorage struct {
+	*Storage
+
+	cfg     FiltersConfig
+	db      *pgxpool.Pool
+	stateDB *pgxpool.Pool
+}
+
+// NewPostgresStorage creates and initializes an instance of PostgresStorage
+func NewPostgresStorage(cfg FiltersConfig, db *pgxpool.Pool, stateDB *pgxpool.Pool) *PostgresStorage {
+	return &PostgresStorage{
+		Storage: NewStorage(),
+		cfg:     cfg,
+		db:      db,
+		stateDB: stateDB,
+	}
+}
+
+// stateNow returns the current time of the state database
+func (s *PostgresStorage) stateNow(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	err := s.stateDB.QueryRow(ctx, "SELECT NOW()").Scan(&now)
+	return now, err
+}
+
+// NewLogFilter persists a new log filter
+func (s *PostgresStorage) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewLogFilter(wsConn, filter)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+
+	return s.insertFilter(FilterTypeLog, &filter)
+}
+
+// NewBlockFilter persists a new block log filter
+func (s *PostgresStorage) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewBlockFilter(wsConn)
+	}
+
+	return s.insertFilter(FilterTypeBlock, nil)
+}
+
+// NewPendingTransactionFilter persists a new pending transaction filter
+func (s *PostgresStorage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.Storage.NewPendingTransactionFilter(wsConn)
+	}
+
+	return s.insertFilter(FilterTypePendingTx, nil)
+}
+
+// insertFilter persists the filter to the database and provides the filter id. The
+// last poll is read from the clock the changes of the filter are compared with
+func (s *PostgresStorage) insertFilter(t FilterType, parameters *LogFilter) (string, error) {
+	ctx := context.Background()
+	id, err := s.generateFilterID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate filter ID: %w", err)
+	}
+
+	var encodedParameters *string
+	if parameters != nil {
+		b, err := json.Marshal(parameters)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode filter parameters: %w", err)
+		}
+		encoded := string(b)
+		encodedParameters = &encoded
+	}
+
+	if t == FilterTypePendingTx {
+		const insertFilterSQL = "INSERT INTO pool.filter (id, type, parameters, last_poll) VALUES ($1, $2, $3, NOW())"
+		if _, err := s.db.Exec(ctx, insertFilterSQL, id, string(t), encodedParameters); err != nil {
+			return "", err
+		}
+		return id, nil
+	}
+
+	lastPoll, err := s.stateNow(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the time of the state db: %w", err)
+	}
+	const insertFilterSQL = "INSERT INTO pool.filter (id, type, parameters, last_poll) VALUES ($1, $2, $3, $4)"
+	if _, err := s.db.Exec(ctx, insertFilterSQL, id, string(t), encodedParameters, lastPoll); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetFilter gets a filter by its id
+func (s *PostgresStorage) GetFilter(filterID string) (*Filter, error) {
+	filter, err := s.Storage.GetFilter(filterID)
+	if !errors.Is(err, ErrNotFound) {
+		return filter, err
+	}
+
+	const getFilterSQL = "SELECT type, parameters::TEXT, last_poll FROM pool.filter WHERE id = $1"
+
+	var (
+		filterType string
+		parameters *string
+		lastPoll   time.Time
+	)
+	err = s.db.QueryRow(context.Background(), getFilterSQL, filterID).Scan(&filterType, &parameters, &lastPoll)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	filter = &Filter{
+		ID:       filterID,
+		Type:     FilterType(filterType),
+		LastPoll: lastPoll.UTC(),
+	}
+	if filter.Type == FilterTypeLog && parameters != nil {
+		var logFilter LogFilter
+		if err := json.Unmarshal([]byte(*parameters), &logFilter); err != nil {
+			return nil, fmt.Errorf("failed to decode filter parameters: %w", err)
+		}
+		filter.Parameters = logFilter
+	}
+
+	return filter, nil
+}
+
+// UpdateFilterLastPoll updates the last poll to now, read from the clock the
+// changes of the filter are compared with
+func (s *PostgresStorage) UpdateFilterLastPoll(filterID string) error {
+	err := s.Storage.UpdateFilterLastPoll(filterID)
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	ctx := context.Background()
+	stateNow, err := s.stateNow(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the time of the state db: %w", err)
+	}
+	const updateFilterLastPollSQL = "UPDATE pool.filter SET last_poll = CASE WHEN type = $3 THEN NOW() ELSE $2 END WHERE id = $1"
+	commandTag, err := s.db.Exec(ctx, updateFilterLastPollSQL, filterID, stateNow, string(FilterTypePendingTx))
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UninstallFilter deletes a filter by its id
+func (s *PostgresStorage) UninstallFilter(filterID string) error {
+	err := s.Storage.UninstallFilter(filterID)
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	const deleteFilterSQL = "DELETE FROM pool.filter WHERE id = $1"
+	commandTag, err := s.db.Exec(context.Background(), deleteFilterSQL, filterID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredFilters deletes the filters that were not polled within the
+// configured timeout and returns how many filters were deleted. The last poll
+// of some filters is read from the clock of the state db, a drift between the
+// databases only shifts the expiration by the drift
+func (s *PostgresStorage) DeleteExpiredFilters(ctx context.Context) (int64, error) {
+	const deleteExpiredFiltersSQL = "DELETE FROM pool.filter WHERE last_poll < NOW() - $1 * INTERVAL '1 second'"
+	commandTag, err := s.db.Exec(ctx, deleteExpiredFiltersSQL, s.cfg.Timeout.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// StartToCleanupExpiredFilters periodically deletes the expired filters until the
+// context is done. The deletion is done in a single statement, so it's safe to
+// run it in all the instances sharing the database
+func (s *PostgresStorage) StartToCleanupExpiredFilters(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CleanupInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.DeleteExpiredFilters(ctx)
+			if err != nil {
+				log.Errorf("failed to delete expired filters: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Debugf("%d expired filters deleted", deleted)
+			}
+		}
+	}
+}
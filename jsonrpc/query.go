@@ -7,7 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
@@ -23,8 +22,27 @@ const (
 	FilterTypeBlock = "block"
 	// FilterTypePendingTx represent a filter of type pending Tx.
 	FilterTypePendingTx = "pendingTx"
+	// FilterTypeNewBatches represents a zkevm subscription to the closed trusted batches.
+	FilterTypeNewBatches = "newBatches"
+	// FilterTypeVirtualizedBatches represents a zkevm subscription to the virtualized batches.
+	FilterTypeVirtualizedBatches = "virtualizedBatches"
+	// FilterTypeVerifiedBatches represents a zkevm subscription to the verified batches.
+	FilterTypeVerifiedBatches = "verifiedBatches"
+	// FilterTypeNewGlobalExitRoot represents a zkevm subscription to the new global exit roots.
+	FilterTypeNewGlobalExitRoot = "newGlobalExitRoot"
+	// FilterTypeForkIDChanged represents a zkevm subscription to the fork id changes.
+	FilterTypeForkIDChanged = "forkIdChanged"
 )
 
+// stateEventFilterTypes are the types of the zkevm subscriptions to the state events
+var stateEventFilterTypes = map[FilterType]bool{
+	FilterTypeNewBatches:         true,
+	FilterTypeVirtualizedBatches: true,
+	FilterTypeVerifiedBatches:    true,
+	FilterTypeNewGlobalExitRoot:  true,
+	FilterTypeForkIDChanged:      true,
+}
+
 // Filter represents a filter.
 type Filter struct {
 	ID         string
@@ -35,12 +53,25 @@ type Filter struct {
 
 	wsQueue       *state.Queue[[]byte]
 	wsQueueSignal *sync.Cond
+
+	// logs subscriptions keep the position of the next log to be sent and
+	// hold the new logs while the historical ones are backfilled
+	logsMutex             sync.Mutex
+	logsNext              *types.LogCursor
+	logsBackfilling       bool
+	logsBackfillHeld      []heldLogs
+	logsBackfillHeldCount int
+	logsBackfillDropped   bool
+	logsBackfilled        []types.Log
+	logsBackfilledTo      uint64
 }
 
 // EnqueueSubscriptionDataToBeSent enqueues subscription data to be sent
 // via web sockets connection
 func (f *Filter) EnqueueSubscriptionDataToBeSent(data []byte) {
+	f.wsQueueSignal.L.Lock()
 	f.wsQueue.Push(data)
+	f.wsQueueSignal.L.Unlock()
 	f.wsQueueSignal.Broadcast()
 }
 
@@ -48,11 +79,13 @@ func (f *Filter) EnqueueSubscriptionDataToBeSent(data []byte) {
 // and sends it via web sockets connection.
 func (f *Filter) SendEnqueuedSubscriptionData() {
 	for {
-		// wait for a signal that a new item was
-		// added to the queue
+		// wait for a signal that a new item was added to the queue, unless
+		// the items were added before waiting, like the backfilled logs
 		log.Debugf("waiting subscription data signal")
 		f.wsQueueSignal.L.Lock()
-		f.wsQueueSignal.Wait()
+		for f.wsQueue.IsEmpty() {
+			f.wsQueueSignal.Wait()
+		}
 		f.wsQueueSignal.L.Unlock()
 		log.Debugf("subscription data signal received, sending enqueued data")
 		for {
@@ -68,6 +101,14 @@ func (f *Filter) SendEnqueuedSubscriptionData() {
 	}
 }
 
+// subscriptionMethod returns the method of the notifications sent to the filter
+func (f *Filter) subscriptionMethod() string {
+	if stateEventFilterTypes[f.Type] {
+		return "zkevm_subscription"
+	}
+	return "eth_subscription"
+}
+
 // sendSubscriptionResponse send data as subscription response via
 // web sockets connection controlled by a mutex
 func (f *Filter) sendSubscriptionResponse(data []byte) {
@@ -76,7 +117,7 @@ func (f *Filter) sendSubscriptionResponse(data []byte) {
 	start := time.Now()
 	res := types.SubscriptionResponse{
 		JSONRPC: "2.0",
-		Method:  "eth_subscription",
+		Method:  f.subscriptionMethod(),
 		Params: types.SubscriptionResponseParams{
 			Subscription: f.ID,
 			Result:       data,
@@ -108,6 +149,13 @@ type LogFilter struct {
 	Addresses []common.Address
 	Topics    [][]common.Hash
 	Since     *time.Time
+
+	// Cursor is only used by the logs subscriptions to resume
+	// after the last log notified by a previous subscription
+	Cursor *types.LogCursor
+	// WithCursor is only used by the logs subscriptions to add to
+	// every log notified the cursor to resume after it
+	WithCursor bool
 }
 
 // addTopic adds specific topics to the log filter topics
@@ -159,7 +207,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		fromBlock := ""
 		obj.FromBlock = &fromBlock
 	} else if f.FromBlock != nil {
-		fromBlock := hex.EncodeUint64(uint64(*f.FromBlock))
+		fromBlock := f.FromBlock.StringOrHex()
 		obj.FromBlock = &fromBlock
 	}
 
@@ -167,7 +215,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		toBlock := ""
 		obj.ToBlock = &toBlock
 	} else if f.ToBlock != nil {
-		toBlock := hex.EncodeUint64(uint64(*f.ToBlock))
+		toBlock := f.ToBlock.StringOrHex()
 		obj.ToBlock = &toBlock
 	}
 
@@ -179,6 +227,9 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		}
 	}
 
+	obj.Cursor = f.Cursor
+	obj.WithCursor = f.WithCursor
+
 	obj.Topics = make([]interface{}, 0, len(f.Topics))
 	for _, topic := range f.Topics {
 		if len(topic) == 0 {
@@ -204,6 +255,8 @@ func (f *LogFilter) UnmarshalJSON(data []byte) error {
 	}
 
 	f.BlockHash = obj.BlockHash
+	f.Cursor = obj.Cursor
+	f.WithCursor = obj.WithCursor
 	lbb := types.LatestBlockNumber
 
 	if obj.FromBlock != nil && *obj.FromBlock == "" {
@@ -350,9 +403,27 @@ func (f *LogFilter) ShouldFilterByBlockRange() bool {
 	return f.FromBlock != nil || f.ToBlock != nil
 }
 
+// ShouldBackfill if a logs subscription with this filter has to send the
+// historical logs before the new ones, which is the case for any fromBlock
+// below the latest one, including the earliest one
+func (f *LogFilter) ShouldBackfill() bool {
+	if f.Cursor != nil {
+		return true
+	}
+	if f.FromBlock == nil {
+		return false
+	}
+	switch *f.FromBlock {
+	case types.LatestBlockNumber, types.PendingBlockNumber:
+		return false
+	default:
+		return true
+	}
+}
+
 // Validate check if the filter instance is valid
 func (f *LogFilter) Validate() error {
-	if f.ShouldFilterByBlockHash() && f.ShouldFilterByBlockRange() {
+	if f.ShouldFilterByBlockHash() && (f.ShouldFilterByBlockRange() || f.Cursor != nil) {
 		return ErrFilterInvalidPayload
 	}
 	return nil
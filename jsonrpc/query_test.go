@@ -0,0 +1,56 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilterJSONRoundTrip(t *testing.T) {
+	blockNumber := func(n types.BlockNumber) *types.BlockNumber { return &n }
+	blockHash := common.HexToHash("0x1")
+
+	testCases := []struct {
+		Name   string
+		Filter LogFilter
+	}{
+		{
+			Name: "block numbers",
+			Filter: LogFilter{
+				FromBlock: blockNumber(1),
+				ToBlock:   blockNumber(types.LatestBlockNumber),
+				Addresses: []common.Address{common.HexToAddress("0x2")},
+				Topics:    [][]common.Hash{{common.HexToHash("0x3")}, {}, {common.HexToHash("0x4"), common.HexToHash("0x5")}},
+			},
+		},
+		{
+			Name: "special block numbers",
+			Filter: LogFilter{
+				FromBlock: blockNumber(types.EarliestBlockNumber),
+				ToBlock:   blockNumber(types.SafeBlockNumber),
+				Addresses: []common.Address{common.HexToAddress("0x2"), common.HexToAddress("0x3")},
+			},
+		},
+		{
+			Name: "block hash",
+			Filter: LogFilter{
+				BlockHash: &blockHash,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			b, err := json.Marshal(&testCase.Filter)
+			require.NoError(t, err)
+
+			var filter LogFilter
+			require.NoError(t, json.Unmarshal(b, &filter))
+			assert.Equal(t, testCase.Filter, filter)
+		})
+	}
+}
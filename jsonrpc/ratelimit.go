@@ -0,0 +1,178 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimiterIdleTimeout is the time a client limiter is kept without being used
+	rateLimiterIdleTimeout = 10 * time.Minute
+	getLogsMethod          = "eth_getlogs"
+)
+
+type clientRateLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter limits the cost of the requests sent by each client using a token bucket,
+// the clients are identified by their API key or by their IP if they don't provide a key
+type rateLimiter struct {
+	cfg               RateLimitConfig
+	costs             map[string]uint
+	maxLogsBlockRange uint64
+
+	limiters  map[string]*clientRateLimiter
+	lastPrune time.Time
+	mutex     *sync.Mutex
+}
+
+func newRateLimiter(cfg RateLimitConfig, maxLogsBlockRange uint64) *rateLimiter {
+	// the method names are compared in lower case because the
+	// config keys are case insensitive
+	costs := make(map[string]uint, len(cfg.MethodCosts))
+	for method, cost := range cfg.MethodCosts {
+		costs[strings.ToLower(method)] = cost
+	}
+
+	return &rateLimiter{
+		cfg:               cfg,
+		costs:             costs,
+		maxLogsBlockRange: maxLogsBlockRange,
+		limiters:          make(map[string]*clientRateLimiter),
+		lastPrune:         time.Now(),
+		mutex:             &sync.Mutex{},
+	}
+}
+
+// cost returns the cost of a request, the cost of eth_getLogs is multiplied
+// by the number of LogsCostBlockRange blocks in the requested range
+func (l *rateLimiter) cost(req types.Request) uint {
+	method := strings.ToLower(req.Method)
+	cost, found := l.costs[method]
+	if !found {
+		cost = l.cfg.DefaultCost
+	}
+
+	if method == getLogsMethod && l.cfg.LogsCostBlockRange > 0 {
+		blockRange := l.logsBlockRange(req.Params)
+		units := (blockRange + l.cfg.LogsCostBlockRange - 1) / l.cfg.LogsCostBlockRange
+		if units > 1 {
+			cost *= uint(units)
+		}
+	}
+	return cost
+}
+
+// logsBlockRange returns the number of blocks requested by the eth_getLogs params. The ranges from
+// a block number to a block tag are considered as wide as the max logs block range since the tag
+// can't be resolved here, any other range using block tags or a block hash is a single block
+func (l *rateLimiter) logsBlockRange(params json.RawMessage) uint64 {
+	var filters []struct {
+		FromBlock *string `json:"fromBlock"`
+		ToBlock   *string `json:"toBlock"`
+	}
+	if err := json.Unmarshal(params, &filters); err != nil || len(filters) == 0 {
+		return 1
+	}
+
+	var fromBlock, toBlock *types.BlockNumber
+	if filters[0].FromBlock != nil {
+		if bn, err := types.StringToBlockNumber(*filters[0].FromBlock); err == nil {
+			fromBlock = &bn
+		}
+	}
+	if filters[0].ToBlock != nil {
+		if bn, err := types.StringToBlockNumber(*filters[0].ToBlock); err == nil {
+			toBlock = &bn
+		}
+	}
+
+	isNumber := func(bn *types.BlockNumber) bool { return bn != nil && *bn >= 0 }
+	switch {
+	case isNumber(fromBlock) && isNumber(toBlock):
+		if *toBlock < *fromBlock {
+			return 1
+		}
+		return uint64(*toBlock-*fromBlock) + 1
+	case isNumber(fromBlock) && l.maxLogsBlockRange > 0:
+		return l.maxLogsBlockRange
+	default:
+		return 1
+	}
+}
+
+// allow consumes the cost from the bucket of the client, if the bucket doesn't
+// have enough tokens nothing is consumed and the time to wait is returned
+func (l *rateLimiter) allow(client string, cost uint) (bool, time.Duration) {
+	l.mutex.Lock()
+	now := time.Now()
+	l.prune(now)
+	c, found := l.limiters[client]
+	if !found {
+		c = &clientRateLimiter{limiter: rate.NewLimiter(rate.Limit(l.cfg.Rate), int(l.cfg.Burst))}
+		l.limiters[client] = c
+	}
+	c.lastSeen = now
+	l.mutex.Unlock()
+
+	reservation := c.limiter.ReserveN(now, int(cost))
+	if !reservation.OK() {
+		// the cost is greater than the burst, so it can never be allowed
+		return false, 0
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// prune removes the limiters of the idle clients,
+// the mutex must be held by the caller
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimiterIdleTimeout {
+		return
+	}
+	for client, c := range l.limiters {
+		if now.Sub(c.lastSeen) >= rateLimiterIdleTimeout {
+			delete(l.limiters, client)
+		}
+	}
+	l.lastPrune = now
+}
+
+// rateLimitClient identifies the client of a request by its API key or its IP
+func rateLimitClient(req *http.Request, apiKey *APIKey) string {
+	if apiKey != nil {
+		return "key:" + apiKey.Hash
+	}
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+// newRateLimitError returns the error for the requests rejected by the rate limiter
+func newRateLimitError(retryAfter time.Duration) types.Error {
+	if retryAfter <= 0 {
+		return types.NewRPCError(types.LimitExceededErrorCode, "rate limit exceeded, the request cost is greater than the allowed burst")
+	}
+	return types.NewRPCError(types.LimitExceededErrorCode, fmt.Sprintf("rate limit exceeded, retry after %s", retryAfter.Round(time.Millisecond)))
+}
+
+// retryAfterSeconds formats the wait time as the value of a Retry-After header
+func retryAfterSeconds(retryAfter time.Duration) string {
+	return fmt.Sprintf("%d", int64(math.Ceil(retryAfter.Seconds())))
+}
@@ -0,0 +1,119 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterCost(t *testing.T) {
+	limiter := newRateLimiter(RateLimitConfig{
+		DefaultCost:        1,
+		LogsCostBlockRange: 1000,
+		MethodCosts: map[string]uint{
+			"eth_getlogs":            2,
+			"debug_tracetransaction": 20,
+		},
+	}, 10000)
+
+	testCases := []struct {
+		Name         string
+		Method       string
+		Params       string
+		ExpectedCost uint
+	}{
+		{Name: "default cost", Method: "eth_chainId", ExpectedCost: 1},
+		{Name: "method cost", Method: "debug_traceTransaction", Params: `["0x1"]`, ExpectedCost: 20},
+		{Name: "logs by block hash", Method: "eth_getLogs", Params: `[{"blockHash":"0x1"}]`, ExpectedCost: 2},
+		{Name: "logs within a block range", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1","toBlock":"0x3e8"}]`, ExpectedCost: 2},
+		{Name: "logs over several block ranges", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1","toBlock":"0xbb9"}]`, ExpectedCost: 8},
+		{Name: "logs from a number to a tag", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1","toBlock":"latest"}]`, ExpectedCost: 20},
+		{Name: "logs between tags", Method: "eth_getLogs", Params: `[{"fromBlock":"latest","toBlock":"latest"}]`, ExpectedCost: 2},
+		{Name: "logs with invalid params", Method: "eth_getLogs", Params: `["0x1"]`, ExpectedCost: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := types.Request{Method: tc.Method, Params: json.RawMessage(tc.Params)}
+			assert.Equal(t, tc.ExpectedCost, limiter.cost(req))
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.Port = 9126
+	cfg.WebSockets.Enabled = false
+	cfg.RateLimit = RateLimitConfig{
+		Enabled:     true,
+		Rate:        0.1,
+		Burst:       10,
+		DefaultCost: 1,
+		MethodCosts: map[string]uint{"web3_sha3": 6},
+	}
+
+	services := []Service{{Name: APIWeb3, Service: &Web3Endpoints{}}}
+	server := NewServer(cfg, chainID, mocks.NewPoolMock(t), mocks.NewStateMock(t), newStorageMock(t), nil, nil, services)
+	go func() {
+		err := server.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	defer func() {
+		require.NoError(t, server.Stop())
+	}()
+
+	serverURL := fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port)
+	for {
+		res, err := http.Get(serverURL) //nolint:gosec
+		if err == nil && res.StatusCode == http.StatusOK {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	call := func(body string) (*http.Response, []byte) {
+		res, err := http.Post(serverURL, contentType, bytes.NewBufferString(body)) //nolint:gosec
+		require.NoError(t, err)
+		defer res.Body.Close()
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, resBody
+	}
+	sha3Request := `{"jsonrpc":"2.0","id":1,"method":"web3_sha3","params":["0x1"]}`
+	clientVersionRequest := `{"jsonrpc":"2.0","id":2,"method":"web3_clientVersion","params":[]}`
+
+	// 6 of the 10 units are consumed
+	res, body := call(sha3Request)
+	var response types.Response
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Nil(t, response.Error)
+
+	// the batch costs 7 units, so it's rejected as a whole with a retry hint
+	res, body = call("[" + sha3Request + "," + clientVersionRequest + "]")
+	var responses []types.Response
+	require.NoError(t, json.Unmarshal(body, &responses))
+	require.Len(t, responses, 2)
+	for _, response := range responses {
+		require.NotNil(t, response.Error)
+		assert.Equal(t, types.LimitExceededErrorCode, response.Error.Code)
+		assert.Contains(t, response.Error.Message, "rate limit exceeded, retry after")
+	}
+	assert.NotEmpty(t, res.Header.Get("Retry-After"))
+
+	// the remaining units are enough for a cheaper request
+	_, body = call(clientVersionRequest)
+	response = types.Response{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Nil(t, response.Error)
+}
@@ -0,0 +1,369 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// responseCachePolicy defines how a cached method checks if its response is immutable
+type responseCachePolicy int
+
+const (
+	// cacheByResultBlock caches the responses whose blocks, txs or receipts
+	// belong to a block below the boundary
+	cacheByResultBlock responseCachePolicy = iota
+	// cacheByLogsFilter caches the logs of a numeric block range below the
+	// boundary or the logs of a block hash below the boundary
+	cacheByLogsFilter
+	// cacheByBatchNumber caches the batches below the last verified batch, the
+	// virtualized batches aren't immutable since they are updated once verified
+	cacheByBatchNumber
+)
+
+// responseCacheMethods are the methods whose responses are cached once the data they return is immutable
+var responseCacheMethods = map[string]responseCachePolicy{
+	"eth_getBlockByHash":                      cacheByResultBlock,
+	"eth_getBlockByNumber":                    cacheByResultBlock,
+	"eth_getBlockReceipts":                    cacheByResultBlock,
+	"eth_getTransactionByBlockHashAndIndex":   cacheByResultBlock,
+	"eth_getTransactionByBlockNumberAndIndex": cacheByResultBlock,
+	"eth_getTransactionByHash":                cacheByResultBlock,
+	"eth_getTransactionReceipt":               cacheByResultBlock,
+	"zkevm_getFullBlockByHash":                cacheByResultBlock,
+	"zkevm_getFullBlockByNumber":              cacheByResultBlock,
+	"zkevm_getTransactionByL2Hash":            cacheByResultBlock,
+	"zkevm_getTransactionReceiptByL2Hash":     cacheByResultBlock,
+	"eth_getLogs":                             cacheByLogsFilter,
+	"zkevm_getBatchByNumber":                  cacheByBatchNumber,
+}
+
+// mutableBlockTags are the block tags resolved to a different block over time,
+// the requests using them are never cached
+var mutableBlockTags = [][]byte{
+	[]byte(`"latest"`), []byte(`"pending"`), []byte(`"safe"`), []byte(`"finalized"`),
+}
+
+type responseCacheEntry struct {
+	key      string
+	response []byte
+}
+
+type responseCacheBlock struct {
+	number uint64
+	hash   common.Hash
+}
+
+// responseCache keeps the responses of the queries for immutable chain data, which is the
+// data below the virtualized or verified boundary. The responses are kept in an in memory
+// LRU bounded by their total size and optionally in a store shared by all the instances
+// of the server, the responses bigger than the max entry size are never cached.
+// The cache is purged when a reorg of the trusted state is detected
+type responseCache struct {
+	cfg   ResponseCacheConfig
+	state types.StateInterface
+	store ResponseCacheStore
+
+	entries    map[string]*list.Element
+	lru        *list.List
+	size       uint64
+	generation uint64
+	lastBlock  *responseCacheBlock
+	mutex      *sync.Mutex
+
+	boundaryBlock     *uint64
+	boundaryBatch     *uint64
+	boundaryUpdatedAt time.Time
+	boundaryMutex     *sync.Mutex
+}
+
+func newResponseCache(cfg ResponseCacheConfig, s types.StateInterface, store ResponseCacheStore) *responseCache {
+	c := &responseCache{
+		cfg:           cfg,
+		state:         s,
+		store:         store,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
+		mutex:         &sync.Mutex{},
+		boundaryMutex: &sync.Mutex{},
+	}
+	s.RegisterNewL2BlockEventHandler(c.onNewL2Block)
+	return c
+}
+
+// isCacheable checks if the response of the request could be cached
+func (c *responseCache) isCacheable(method string, params json.RawMessage) bool {
+	if _, found := responseCacheMethods[method]; !found {
+		return false
+	}
+	lowerParams := bytes.ToLower(params)
+	for _, tag := range mutableBlockTags {
+		if bytes.Contains(lowerParams, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// responseCacheKey identifies a response by the method and the params of the request
+func responseCacheKey(method string, params json.RawMessage) string {
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, params); err != nil {
+		compacted.Reset()
+		compacted.Write(params)
+	}
+	hash := sha256.Sum256(append([]byte(method+":"), compacted.Bytes()...))
+	return hex.EncodeToString(hash[:])
+}
+
+// get returns the cached response for the key looking for it in memory first and then in
+// the shared store, it also returns the cache generation to provide it back when adding
+// the response so the responses computed before a purge are discarded
+func (c *responseCache) get(ctx context.Context, key string) ([]byte, uint64, bool) {
+	c.mutex.Lock()
+	generation := c.generation
+	if e, found := c.entries[key]; found {
+		c.lru.MoveToFront(e)
+		response := e.Value.(*responseCacheEntry).response
+		c.mutex.Unlock()
+		metrics.ResponseCache(metrics.ResponseCacheLabelHit)
+		return response, generation, true
+	}
+	c.mutex.Unlock()
+
+	if c.store != nil {
+		response, err := c.store.GetResponse(ctx, key)
+		if err == nil {
+			c.addToMemory(key, response, generation)
+			metrics.ResponseCache(metrics.ResponseCacheLabelHit)
+			return response, generation, true
+		} else if !errors.Is(err, ErrNotFound) {
+			log.Errorf("failed to get response from the shared cache: %v", err)
+		}
+	}
+
+	metrics.ResponseCache(metrics.ResponseCacheLabelMiss)
+	return nil, generation, false
+}
+
+// add caches the response if the data it contains is immutable
+func (c *responseCache) add(ctx context.Context, method string, params json.RawMessage, key string, generation uint64, response []byte) {
+	if c.isTooBig(response) || !c.isImmutable(ctx, method, params, response) {
+		return
+	}
+	if !c.addToMemory(key, response, generation) {
+		return
+	}
+	if c.store != nil {
+		if err := c.store.AddResponse(ctx, key, response); err != nil {
+			log.Errorf("failed to add response to the shared cache: %v", err)
+		}
+	}
+}
+
+// isTooBig checks if the response exceeds the max size of a cached response
+func (c *responseCache) isTooBig(response []byte) bool {
+	return c.cfg.MaxEntryBytesSize > 0 && uint64(len(response)) > c.cfg.MaxEntryBytesSize
+}
+
+// addToMemory adds the response to the LRU evicting the least recently used responses
+// until the total size fits, nothing is added if the response is too big or the cache
+// was purged in the meantime
+func (c *responseCache) addToMemory(key string, response []byte, generation uint64) bool {
+	if c.isTooBig(response) {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return false
+	}
+	if e, found := c.entries[key]; found {
+		c.lru.MoveToFront(e)
+		return true
+	}
+	c.entries[key] = c.lru.PushFront(&responseCacheEntry{key: key, response: response})
+	c.size += uint64(len(response))
+	for c.cfg.MaxBytesSize > 0 && c.size > c.cfg.MaxBytesSize {
+		oldest := c.lru.Back()
+		entry := oldest.Value.(*responseCacheEntry)
+		c.lru.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= uint64(len(entry.response))
+	}
+	return true
+}
+
+// purge removes all the cached responses
+func (c *responseCache) purge(ctx context.Context) {
+	c.mutex.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.size = 0
+	c.generation++
+	c.mutex.Unlock()
+
+	if c.store != nil {
+		if err := c.store.ClearResponses(ctx); err != nil {
+			log.Errorf("failed to clear the shared cache: %v", err)
+		}
+	}
+}
+
+// onNewL2Block detects the trusted state reorgs, the blocks are notified in order and
+// without gaps, so a block whose parent isn't the previous one means it was reorganized
+func (c *responseCache) onNewL2Block(event state.NewL2BlockEvent) {
+	block := &responseCacheBlock{number: event.Block.NumberU64(), hash: event.Block.Hash()}
+
+	c.mutex.Lock()
+	lastBlock := c.lastBlock
+	c.lastBlock = block
+	c.mutex.Unlock()
+
+	if lastBlock == nil {
+		return
+	}
+	if block.number == lastBlock.number+1 && event.Block.ParentHash() != lastBlock.hash {
+		log.Warnf("trusted state reorg detected at block %d, purging the response cache", block.number)
+		c.purge(context.Background())
+	}
+}
+
+// isImmutable checks if the response contains data below the boundary
+func (c *responseCache) isImmutable(ctx context.Context, method string, params json.RawMessage, response []byte) bool {
+	if len(response) == 0 || bytes.Equal(response, []byte("null")) {
+		return false
+	}
+
+	blockBoundary, batchBoundary, err := c.boundaries(ctx)
+	if err != nil {
+		log.Errorf("failed to get the response cache boundaries: %v", err)
+		return false
+	}
+
+	switch responseCacheMethods[method] {
+	case cacheByResultBlock:
+		blockNumber, found := maxResultBlockNumber(response)
+		return found && blockBoundary != nil && blockNumber <= *blockBoundary
+	case cacheByLogsFilter:
+		var filters []LogFilter
+		if err := json.Unmarshal(params, &filters); err != nil || len(filters) == 0 {
+			return false
+		}
+		filter := filters[0]
+		if filter.BlockHash != nil {
+			blockNumber, found := maxResultBlockNumber(response)
+			return found && blockBoundary != nil && blockNumber <= *blockBoundary
+		}
+		if filter.FromBlock == nil || filter.ToBlock == nil || *filter.FromBlock < 0 || *filter.ToBlock < 0 {
+			return false
+		}
+		return blockBoundary != nil && uint64(*filter.ToBlock) <= *blockBoundary
+	case cacheByBatchNumber:
+		var batchParams []json.RawMessage
+		if err := json.Unmarshal(params, &batchParams); err != nil || len(batchParams) == 0 {
+			return false
+		}
+		var batchNumber types.BatchNumber
+		if err := json.Unmarshal(batchParams[0], &batchNumber); err != nil || batchNumber < 0 {
+			return false
+		}
+		return batchBoundary != nil && uint64(batchNumber) <= *batchBoundary
+	default:
+		return false
+	}
+}
+
+// boundaries returns the last immutable block and batch, they are
+// loaded from the state once per boundary refresh interval
+func (c *responseCache) boundaries(ctx context.Context) (*uint64, *uint64, error) {
+	c.boundaryMutex.Lock()
+	defer c.boundaryMutex.Unlock()
+
+	if c.boundaryBlock != nil && time.Since(c.boundaryUpdatedAt) < c.cfg.BoundaryRefreshInterval.Duration {
+		return c.boundaryBlock, c.boundaryBatch, nil
+	}
+
+	var (
+		blockNumber uint64
+		err         error
+	)
+	if c.cfg.Boundary == ResponseCacheBoundaryVirtualized {
+		blockNumber, err = c.state.GetLastVirtualizedL2BlockNumber(ctx, nil)
+	} else {
+		blockNumber, err = c.state.GetLastConsolidatedL2BlockNumber(ctx, nil)
+	}
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var batchNumber *uint64
+	verifiedBatch, err := c.state.GetLastVerifiedBatch(ctx, nil)
+	if err != nil && !errors.Is(err, state.ErrNotFound) {
+		return nil, nil, err
+	} else if verifiedBatch != nil {
+		batchNumber = &verifiedBatch.BatchNumber
+	}
+
+	c.boundaryBlock = &blockNumber
+	c.boundaryBatch = batchNumber
+	c.boundaryUpdatedAt = time.Now()
+	return c.boundaryBlock, c.boundaryBatch, nil
+}
+
+// maxResultBlockNumber returns the highest block number found in a response containing a
+// block, a tx, a receipt or a list of them. Nothing is found if any of them is pending
+func maxResultBlockNumber(response []byte) (uint64, bool) {
+	type blockNumbers struct {
+		Number      *types.ArgUint64 `json:"number"`
+		BlockNumber *types.ArgUint64 `json:"blockNumber"`
+	}
+
+	var results []blockNumbers
+	if bytes.HasPrefix(bytes.TrimSpace(response), []byte("[")) {
+		if err := json.Unmarshal(response, &results); err != nil {
+			return 0, false
+		}
+	} else {
+		var result blockNumbers
+		if err := json.Unmarshal(response, &result); err != nil {
+			return 0, false
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return 0, false
+	}
+
+	var max uint64
+	for _, result := range results {
+		var number *types.ArgUint64
+		if result.Number != nil {
+			number = result.Number
+		} else if result.BlockNumber != nil {
+			number = result.BlockNumber
+		}
+		if number == nil {
+			return 0, false
+		}
+		if uint64(*number) > max {
+			max = uint64(*number)
+		}
+	}
+	return max, true
+}
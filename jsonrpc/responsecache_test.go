@@ -0,0 +1,181 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type blockEndpointsMock struct {
+	calls int
+}
+
+func (e *blockEndpointsMock) GetBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
+	e.calls++
+	return map[string]interface{}{"number": types.ArgUint64(number)}, nil
+}
+
+func newResponseCacheStateMock(t *testing.T, blockBoundary, batchBoundary uint64) *mocks.StateMock {
+	st := mocks.NewStateMock(t)
+	st.On("RegisterNewL2BlockEventHandler", mock.Anything).Once()
+	st.On("GetLastConsolidatedL2BlockNumber", mock.Anything, nil).Return(blockBoundary, nil).Once()
+	st.On("GetLastVerifiedBatch", mock.Anything, nil).Return(&state.VerifiedBatch{BatchNumber: batchBoundary}, nil).Once()
+	return st
+}
+
+func newResponseCacheConfig() ResponseCacheConfig {
+	return ResponseCacheConfig{
+		Enabled:                 true,
+		MaxBytesSize:            1024,
+		MaxEntryBytesSize:       64,
+		Boundary:                ResponseCacheBoundaryVerified,
+		BoundaryRefreshInterval: cfgTypes.NewDuration(time.Hour),
+	}
+}
+
+func newL2BlockEvent(number uint64, parentHash common.Hash) state.NewL2BlockEvent {
+	header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(0).SetUint64(number), ParentHash: parentHash})
+	return state.NewL2BlockEvent{Block: *state.NewL2BlockWithHeader(header)}
+}
+
+func TestResponseCacheIsCacheable(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	st.On("RegisterNewL2BlockEventHandler", mock.Anything).Once()
+	cache := newResponseCache(newResponseCacheConfig(), st, nil)
+
+	assert.True(t, cache.isCacheable("eth_getBlockByNumber", json.RawMessage(`["0x1",true]`)))
+	assert.True(t, cache.isCacheable("eth_getLogs", json.RawMessage(`[{"fromBlock":"0x1","toBlock":"0x2"}]`)))
+	assert.False(t, cache.isCacheable("eth_getBlockByNumber", json.RawMessage(`["latest",true]`)))
+	assert.False(t, cache.isCacheable("eth_getLogs", json.RawMessage(`[{"fromBlock":"0x1","toBlock":"Finalized"}]`)))
+	assert.False(t, cache.isCacheable("eth_blockNumber", json.RawMessage(`[]`)))
+}
+
+func TestResponseCacheImmutableData(t *testing.T) {
+	st := newResponseCacheStateMock(t, 10, 3)
+	cache := newResponseCache(newResponseCacheConfig(), st, nil)
+
+	testCases := []struct {
+		Name      string
+		Method    string
+		Params    string
+		Response  string
+		Immutable bool
+	}{
+		{Name: "block below the boundary", Method: "eth_getBlockByNumber", Params: `["0xa",false]`, Response: `{"number":"0xa"}`, Immutable: true},
+		{Name: "block above the boundary", Method: "eth_getBlockByNumber", Params: `["0xb",false]`, Response: `{"number":"0xb"}`, Immutable: false},
+		{Name: "block not found", Method: "eth_getBlockByHash", Params: `["0x1",false]`, Response: `null`, Immutable: false},
+		{Name: "mined tx", Method: "eth_getTransactionByHash", Params: `["0x1"]`, Response: `{"blockNumber":"0x5"}`, Immutable: true},
+		{Name: "pending tx", Method: "eth_getTransactionByHash", Params: `["0x1"]`, Response: `{"blockNumber":null}`, Immutable: false},
+		{Name: "receipts below the boundary", Method: "eth_getBlockReceipts", Params: `["0x5"]`, Response: `[{"blockNumber":"0x5"}]`, Immutable: true},
+		{Name: "logs range below the boundary", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1","toBlock":"0xa"}]`, Response: `[]`, Immutable: true},
+		{Name: "logs range above the boundary", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1","toBlock":"0xb"}]`, Response: `[]`, Immutable: false},
+		{Name: "logs range to the latest block", Method: "eth_getLogs", Params: `[{"fromBlock":"0x1"}]`, Response: `[]`, Immutable: false},
+		{Name: "logs by block hash", Method: "eth_getLogs", Params: `[{"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}]`, Response: `[{"blockNumber":"0x2"}]`, Immutable: true},
+		{Name: "verified batch", Method: "zkevm_getBatchByNumber", Params: `["0x3",false]`, Response: `{"number":"0x3"}`, Immutable: true},
+		{Name: "not verified batch", Method: "zkevm_getBatchByNumber", Params: `["0x4",false]`, Response: `{"number":"0x4"}`, Immutable: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Immutable, cache.isImmutable(context.Background(), tc.Method, json.RawMessage(tc.Params), []byte(tc.Response)))
+		})
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	cfg := newResponseCacheConfig()
+	// room for two blocks
+	cfg.MaxBytesSize = uint64(2 * len(`{"number":"0x1"}`))
+	st := newResponseCacheStateMock(t, 10, 3)
+
+	handler := newJSONRpcHandler()
+	endpoints := &blockEndpointsMock{}
+	handler.registerService(Service{Name: APIEth, Service: endpoints})
+	handler.cache = newResponseCache(cfg, st, nil)
+
+	getBlock := func(number string) types.Response {
+		req := types.Request{JSONRPC: "2.0", ID: 1, Method: "eth_getBlockByNumber", Params: json.RawMessage(`["` + number + `", false]`)}
+		res := handler.Handle(handleRequest{Request: req})
+		require.Nil(t, res.Error)
+		return res
+	}
+
+	// the immutable blocks are only loaded once
+	res := getBlock("0x1")
+	assert.Equal(t, `{"number":"0x1"}`, string(res.Result))
+	getBlock("0x1")
+	assert.Equal(t, 1, endpoints.calls)
+
+	// the mutable blocks are always loaded
+	getBlock("0xb")
+	getBlock("0xb")
+	assert.Equal(t, 3, endpoints.calls)
+
+	// the least recently used block is evicted
+	getBlock("0x2")
+	getBlock("0x3")
+	getBlock("0x1")
+	assert.Equal(t, 6, endpoints.calls)
+
+	// the responses bigger than the max entry size aren't cached
+	handler.cache.cfg.MaxEntryBytesSize = 8
+	getBlock("0x4")
+	getBlock("0x4")
+	assert.Equal(t, 8, endpoints.calls)
+	handler.cache.cfg.MaxEntryBytesSize = 0
+
+	// the cache is purged by the reorgs
+	handler.cache.onNewL2Block(newL2BlockEvent(11, common.HexToHash("0x1")))
+	handler.cache.onNewL2Block(newL2BlockEvent(12, common.HexToHash("0x2")))
+	getBlock("0x3")
+	assert.Equal(t, 9, endpoints.calls)
+}
+
+func TestResponseCacheSharedStore(t *testing.T) {
+	st := newResponseCacheStateMock(t, 10, 3)
+	store := newResponseCacheStoreMock(t)
+	cache := newResponseCache(newResponseCacheConfig(), st, store)
+
+	params := json.RawMessage(`["0x1",false]`)
+	key := responseCacheKey("eth_getBlockByNumber", params)
+	sharedKey := responseCacheKey("eth_getBlockByNumber", json.RawMessage(`["0x2",false]`))
+	response := []byte(`{"number":"0x1"}`)
+
+	// a miss in both tiers
+	store.On("GetResponse", mock.Anything, key).Return(nil, ErrNotFound).Once()
+	_, generation, found := cache.get(context.Background(), key)
+	assert.False(t, found)
+
+	// the responses are added to both tiers
+	store.On("AddResponse", mock.Anything, key, response).Return(nil).Once()
+	cache.add(context.Background(), "eth_getBlockByNumber", params, key, generation, response)
+	cached, _, found := cache.get(context.Background(), key)
+	require.True(t, found)
+	assert.Equal(t, response, cached)
+
+	// the responses cached by other instances are found in the shared tier
+	store.On("GetResponse", mock.Anything, sharedKey).Return([]byte(`{"number":"0x2"}`), nil).Once()
+	cached, _, found = cache.get(context.Background(), sharedKey)
+	require.True(t, found)
+	assert.Equal(t, `{"number":"0x2"}`, string(cached))
+
+	// both tiers are purged, and the responses computed before the purge are discarded
+	store.On("ClearResponses", mock.Anything).Return(nil).Once()
+	cache.purge(context.Background())
+	cache.add(context.Background(), "eth_getBlockByNumber", params, key, generation, response)
+	store.On("GetResponse", mock.Anything, key).Return(nil, ErrNotFound).Once()
+	_, _, found = cache.get(context.Background(), key)
+	assert.False(t, found)
+}
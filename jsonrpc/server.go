@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/graphql"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
@@ -33,6 +34,16 @@ const (
 	APITxPool = "txpool"
 	// APIWeb3 represents the web3 API prefix.
 	APIWeb3 = "web3"
+	// APIOts represents the Otterscan API prefix.
+	APIOts = "ots"
+	// APITrace represents the Parity trace API prefix.
+	APITrace = "trace"
+	// APIRPC represents the rpc API prefix.
+	APIRPC = "rpc"
+
+	// graphQLMethod is the method used to authorize, rate limit and account the GraphQL
+	// queries, they are part of the eth namespace since they serve the same data
+	graphQLMethod = "eth_graphql"
 
 	wsBufferSizeLimitInBytes = 1024
 	maxRequestContentLength  = 1024 * 1024 * 5
@@ -44,12 +55,16 @@ var acceptedContentTypes = []string{contentType, "application/json-rpc", "applic
 
 // Server is an API backend to handle RPC requests
 type Server struct {
-	config     Config
-	chainID    uint64
-	handler    *Handler
-	srv        *http.Server
-	wsSrv      *http.Server
-	wsUpgrader websocket.Upgrader
+	config      Config
+	chainID     uint64
+	handler     *Handler
+	srv         *http.Server
+	wsSrv       *http.Server
+	wsUpgrader  websocket.Upgrader
+	gqlSrv      *http.Server
+	gqlHandler  *graphql.Handler
+	apiKeys     *apiKeyManager
+	rateLimiter *rateLimiter
 }
 
 // Service defines a struct that will provide public methods to be exposed
@@ -63,6 +78,9 @@ type Server struct {
 type Service struct {
 	Name    string
 	Service interface{}
+	// Restricted services are only available to the requests authenticated
+	// with an API key allowed to access them
+	Restricted bool
 }
 
 // NewServer returns the JsonRPC server
@@ -71,16 +89,25 @@ func NewServer(
 	chainID uint64,
 	p types.PoolInterface,
 	s types.StateInterface,
-	storage storageInterface,
+	storage FilterStorage,
+	apiKeyStorage APIKeyStorage,
+	responseCacheStore ResponseCacheStore,
 	services []Service,
 ) *Server {
-	if cfg.WebSockets.Enabled {
+	if cfg.WebSockets.Enabled || cfg.ResponseCache.Enabled {
 		s.StartToMonitorNewL2Blocks()
 	}
+	if cfg.WebSockets.Enabled {
+		s.StartToMonitorStateEvents()
+	}
 
 	handler := newJSONRpcHandler()
 
 	for _, service := range services {
+		// the rpc endpoints describe the services registered in the handler
+		if endpoints, ok := service.Service.(*RPCEndpoints); ok {
+			endpoints.handler = handler
+		}
 		handler.registerService(service)
 	}
 
@@ -89,6 +116,30 @@ func NewServer(
 		handler: handler,
 		chainID: chainID,
 	}
+	if cfg.APIKeys.Enabled {
+		srv.apiKeys = newAPIKeyManager(cfg.APIKeys, apiKeyStorage)
+		handler.apiKeys = srv.apiKeys
+	}
+	if cfg.RateLimit.Enabled {
+		srv.rateLimiter = newRateLimiter(cfg.RateLimit, cfg.MaxLogsBlockRange)
+	}
+	if cfg.ResponseCache.Enabled {
+		handler.cache = newResponseCache(cfg.ResponseCache, s, responseCacheStore)
+	}
+	if cfg.GraphQL.Enabled {
+		gqlCfg := graphql.Config{
+			MaxQueryDepth:     cfg.GraphQL.MaxQueryDepth,
+			MaxQueryCost:      cfg.GraphQL.MaxQueryCost,
+			MaxBlockRange:     cfg.GraphQL.MaxBlockRange,
+			MaxLogsCount:      cfg.MaxLogsCount,
+			MaxLogsBlockRange: cfg.MaxLogsBlockRange,
+		}
+		gqlHandler, err := graphql.NewHandler(gqlCfg, chainID, s)
+		if err != nil {
+			log.Fatalf("failed to create the GraphQL handler: %v", err)
+		}
+		srv.gqlHandler = gqlHandler
+	}
 	return srv
 }
 
@@ -100,6 +151,10 @@ func (s *Server) Start() error {
 		go s.startWS()
 	}
 
+	if s.config.GraphQL.Enabled {
+		go s.startGraphQL()
+	}
+
 	return s.startHTTP()
 }
 
@@ -181,6 +236,45 @@ func (s *Server) startWS() {
 	}
 }
 
+// startGraphQL starts a server to respond GraphQL requests
+func (s *Server) startGraphQL() {
+	log.Infof("starting graphql server")
+
+	if s.gqlSrv != nil {
+		log.Errorf("graphql server already started")
+		return
+	}
+
+	address := fmt.Sprintf("%s:%d", s.config.GraphQL.Host, s.config.GraphQL.Port)
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("failed to create tcp listener: %v", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+
+	lmt := tollbooth.NewLimiter(s.config.MaxRequestsPerIPAndSecond, nil)
+	mux.Handle("/", tollbooth.LimitFuncHandler(lmt, s.handleGraphQL))
+
+	s.gqlSrv = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: s.config.ReadTimeout.Duration,
+		ReadTimeout:       s.config.ReadTimeout.Duration,
+		WriteTimeout:      s.config.WriteTimeout.Duration,
+	}
+	log.Infof("graphql server started: %s", address)
+	if err := s.gqlSrv.Serve(lis); err != nil {
+		if err == http.ErrServerClosed {
+			log.Infof("graphql server stopped")
+			return
+		}
+		log.Errorf("closed graphql connection: %v", err)
+		return
+	}
+}
+
 // Stop shutdown the rpc server
 func (s *Server) Stop() error {
 	if s.srv != nil {
@@ -205,14 +299,169 @@ func (s *Server) Stop() error {
 		s.wsSrv = nil
 	}
 
+	if s.gqlSrv != nil {
+		if err := s.gqlSrv.Shutdown(context.Background()); err != nil {
+			return err
+		}
+
+		if err := s.gqlSrv.Close(); err != nil {
+			return err
+		}
+		s.gqlSrv = nil
+	}
+
 	return nil
 }
 
+// handleGraphQL serves the GraphQL queries with the same checks as the JSON RPC requests, the queries
+// are authorized, rate limited and accounted in the quotas of the API keys as the graphQLMethod and
+// a list of queries is handled like a batch request
+func (s *Server) handleGraphQL(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	allowedHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"
+	if s.apiKeys != nil {
+		allowedHeaders += ", " + s.config.APIKeys.Header
+	}
+	w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+
+	if req.Method == http.MethodOptions {
+		return
+	}
+
+	if req.Method == http.MethodGet {
+		_, err := w.Write([]byte("zkEVM GraphQL Server"))
+		if err != nil {
+			log.Error(err)
+		}
+		return
+	}
+
+	start := time.Now()
+	rw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	defer func() { s.combinedLog(req, start, rw.status, rw.written) }()
+
+	if code, err := validateRequest(req); err != nil {
+		handleInvalidRequest(rw, err, code)
+		return
+	}
+
+	apiKey, err := s.authenticate(req)
+	if errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, ErrAPIKeyRequired) {
+		handleInvalidRequest(rw, err, http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		handleError(rw, err)
+		return
+	}
+
+	body := io.LimitReader(req.Body, maxRequestContentLength)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		handleError(rw, err)
+		return
+	}
+
+	single, err := s.isSingleRequest(data)
+	if err != nil {
+		handleInvalidRequest(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	var queries []graphql.Request
+	if single {
+		var query graphql.Request
+		if err := json.Unmarshal(data, &query); err != nil {
+			handleInvalidRequest(rw, fmt.Errorf("invalid json object request body"), http.StatusBadRequest)
+			return
+		}
+		queries = append(queries, query)
+	} else {
+		if !s.config.BatchRequestsEnabled {
+			handleInvalidRequest(rw, types.ErrBatchRequestsDisabled, http.StatusBadRequest)
+			return
+		}
+		if err := json.Unmarshal(data, &queries); err != nil {
+			handleInvalidRequest(rw, fmt.Errorf("invalid json array request body"), http.StatusBadRequest)
+			return
+		}
+		if limit := s.batchRequestsLimit(apiKey); limit > 0 && len(queries) > int(limit) {
+			handleInvalidRequest(rw, types.ErrBatchRequestsLimitExceeded, http.StatusRequestEntityTooLarge)
+			return
+		}
+	}
+
+	responses := s.execGraphQL(rw, req, apiKey, queries)
+
+	var respBytes []byte
+	if single {
+		respBytes, err = json.Marshal(responses[0])
+	} else {
+		respBytes, err = json.Marshal(responses)
+	}
+	if err != nil {
+		handleError(rw, err)
+		return
+	}
+	if _, err := rw.Write(respBytes); err != nil {
+		log.Error(err)
+	}
+}
+
+// execGraphQL executes the GraphQL queries once they are authorized and allowed by the rate
+// limiter as a whole, each query is accounted as a request in the quotas of the API key
+func (s *Server) execGraphQL(w http.ResponseWriter, httpRequest *http.Request, apiKey *APIKey, queries []graphql.Request) []*graphql.Response {
+	responses := make([]*graphql.Response, 0, len(queries))
+	rejectAll := func(err types.Error) []*graphql.Response {
+		for range queries {
+			responses = append(responses, graphql.ErrorResponse(err.Error(), err.ErrorCode()))
+		}
+		return responses
+	}
+
+	service, found := s.handler.serviceMap[APIEth]
+	if !found {
+		return rejectAll(types.NewRPCError(types.NotFoundErrorCode, methodNotFoundMessage(graphQLMethod)))
+	}
+	request := types.Request{JSONRPC: "2.0", Method: graphQLMethod}
+	if err := s.handler.authorize(handleRequest{Request: request, HttpRequest: httpRequest, apiKey: apiKey}, service); err != nil {
+		return rejectAll(err)
+	}
+
+	requests := make([]types.Request, len(queries))
+	for i := range requests {
+		requests[i] = request
+	}
+	if err := s.rateLimit(w, httpRequest, apiKey, requests...); err != nil {
+		return rejectAll(err)
+	}
+
+	for _, query := range queries {
+		if apiKey != nil && s.apiKeys != nil {
+			if err := s.apiKeys.reserve(apiKey); err != nil {
+				responses = append(responses, graphql.ErrorResponse(err.Error(), err.ErrorCode()))
+				continue
+			}
+		}
+		start := time.Now()
+		responses = append(responses, s.gqlHandler.Exec(httpRequest.Context(), query))
+		if apiKey != nil && s.apiKeys != nil {
+			s.apiKeys.addCompute(apiKey, time.Since(start))
+		}
+	}
+	return responses
+}
+
 func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+	allowedHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"
+	if s.apiKeys != nil {
+		allowedHeaders += ", " + s.config.APIKeys.Header
+	}
+	w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
 
 	if req.Method == http.MethodOptions {
 		return
@@ -231,6 +480,15 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	apiKey, err := s.authenticate(req)
+	if errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, ErrAPIKeyRequired) {
+		handleInvalidRequest(w, err, http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		handleError(w, err)
+		return
+	}
+
 	body := io.LimitReader(req.Body, maxRequestContentLength)
 	data, err := io.ReadAll(body)
 	if err != nil {
@@ -247,14 +505,15 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 	s.increaseHttpConnCounter()
 
 	start := time.Now()
+	rw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
 	var respLen int
 	if single {
-		respLen = s.handleSingleRequest(req, w, data)
+		respLen = s.handleSingleRequest(req, rw, data, apiKey)
 	} else {
-		respLen = s.handleBatchRequest(req, w, data)
+		respLen = s.handleBatchRequest(req, rw, data, apiKey)
 	}
 	metrics.RequestDuration(start)
-	s.combinedLog(req, start, http.StatusOK, respLen)
+	s.combinedLog(req, start, rw.status, respLen)
 }
 
 // validateRequest returns a non-zero response code and error message if the
@@ -283,6 +542,15 @@ func validateRequest(req *http.Request) (int, error) {
 	return http.StatusUnsupportedMediaType, err
 }
 
+// authenticate returns the API key provided by the request,
+// nil is returned if the API keys are disabled or no key is provided
+func (s *Server) authenticate(req *http.Request) (*APIKey, error) {
+	if s.apiKeys == nil {
+		return nil, nil
+	}
+	return s.apiKeys.authenticate(req)
+}
+
 func (s *Server) isSingleRequest(data []byte) (bool, error) {
 	x := bytes.TrimLeft(data, " \t\r\n")
 
@@ -293,15 +561,20 @@ func (s *Server) isSingleRequest(data []byte) (bool, error) {
 	return x[0] != '[', nil
 }
 
-func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte, apiKey *APIKey) int {
 	defer metrics.RequestHandled(metrics.RequestHandledLabelSingle)
 	request, err := s.parseRequest(data)
 	if err != nil {
 		handleInvalidRequest(w, err, http.StatusBadRequest)
 		return 0
 	}
-	req := handleRequest{Request: request, HttpRequest: httpRequest}
-	response := s.handler.Handle(req)
+	var response types.Response
+	if err := s.rateLimit(w, httpRequest, apiKey, request); err != nil {
+		response = types.NewResponse(request, nil, err)
+	} else {
+		req := handleRequest{Request: request, HttpRequest: httpRequest, apiKey: apiKey}
+		response = s.handler.Handle(req)
+	}
 
 	respBytes, err := json.Marshal(response)
 	if err != nil {
@@ -317,7 +590,7 @@ func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseW
 	return len(respBytes)
 }
 
-func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte, apiKey *APIKey) int {
 	// Checking if batch requests are enabled
 	if !s.config.BatchRequestsEnabled {
 		handleInvalidRequest(w, types.ErrBatchRequestsDisabled, http.StatusBadRequest)
@@ -332,8 +605,8 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 	}
 
 	// Checking if batch requests limit is exceeded
-	if s.config.BatchRequestsLimit > 0 {
-		if len(requests) > int(s.config.BatchRequestsLimit) {
+	if batchRequestsLimit := s.batchRequestsLimit(apiKey); batchRequestsLimit > 0 {
+		if len(requests) > int(batchRequestsLimit) {
 			handleInvalidRequest(w, types.ErrBatchRequestsLimitExceeded, http.StatusRequestEntityTooLarge)
 			return 0
 		}
@@ -341,10 +614,17 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 
 	responses := make([]types.Response, 0, len(requests))
 
-	for _, request := range requests {
-		req := handleRequest{Request: request, HttpRequest: httpRequest}
-		response := s.handler.Handle(req)
-		responses = append(responses, response)
+	// the batch is rate limited by the summed cost of its requests
+	if err := s.rateLimit(w, httpRequest, apiKey, requests...); err != nil {
+		for _, request := range requests {
+			responses = append(responses, types.NewResponse(request, nil, err))
+		}
+	} else {
+		for _, request := range requests {
+			req := handleRequest{Request: request, HttpRequest: httpRequest, apiKey: apiKey}
+			response := s.handler.Handle(req)
+			responses = append(responses, response)
+		}
 	}
 
 	respBytes, _ := json.Marshal(responses)
@@ -356,6 +636,37 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 	return len(respBytes)
 }
 
+// batchRequestsLimit returns the max number of requests of a batch, the API key can override it
+func (s *Server) batchRequestsLimit(apiKey *APIKey) uint {
+	if apiKey != nil && apiKey.MaxBatchSize > 0 {
+		return uint(apiKey.MaxBatchSize)
+	}
+	return s.config.BatchRequestsLimit
+}
+
+// rateLimit consumes the summed cost of the requests from the rate limiter of the client,
+// an error to respond the requests is returned if they are rejected. The Retry-After
+// header is set in the http response if provided
+func (s *Server) rateLimit(w http.ResponseWriter, httpRequest *http.Request, apiKey *APIKey, requests ...types.Request) types.Error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+
+	var cost uint
+	for _, request := range requests {
+		cost += s.rateLimiter.cost(request)
+	}
+	allowed, retryAfter := s.rateLimiter.allow(rateLimitClient(httpRequest, apiKey), cost)
+	if allowed {
+		return nil
+	}
+
+	if w != nil && retryAfter > 0 {
+		w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+	}
+	return newRateLimitError(retryAfter)
+}
+
 func (s *Server) parseRequest(data []byte) (types.Request, error) {
 	var req types.Request
 
@@ -380,6 +691,15 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 	// CORS rule - Allow requests from anywhere
 	s.wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
 
+	apiKey, err := s.authenticate(req)
+	if errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, ErrAPIKeyRequired) {
+		handleInvalidRequest(w, err, http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		handleError(w, err)
+		return
+	}
+
 	// Upgrade the connection to a WS one
 	innerWsConn, err := s.wsUpgrader.Upgrade(w, req, nil)
 	if err != nil {
@@ -427,13 +747,23 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if msgType == websocket.TextMessage || msgType == websocket.BinaryMessage {
-			resp, err := s.handler.HandleWs(message, wsConn, req)
+			var request types.Request
+			if json.Unmarshal(message, &request) == nil {
+				if err := s.rateLimit(nil, req, apiKey, request); err != nil {
+					resp, _ := types.NewResponse(request, nil, err).Bytes()
+					_ = wsConn.WriteMessage(msgType, resp)
+					continue
+				}
+			}
+
+			resp, err := s.handler.HandleWs(message, wsConn, req, apiKey)
 			if err != nil {
 				log.Error(fmt.Sprintf("Unable to handle WS request, %s", err.Error()))
 				_ = wsConn.WriteMessage(msgType, []byte(fmt.Sprintf("WS Handle error: %s", err.Error())))
 			} else {
 				_ = wsConn.WriteMessage(msgType, resp)
 			}
+			wsConn.responseWritten()
 		}
 	}
 }
@@ -482,6 +812,26 @@ func RPCErrorResponseWithData(code int, message string, data []byte, err error,
 	return nil, types.NewRPCErrorWithData(code, message, data)
 }
 
+// statusResponseWriter records the status code and the size of the response written
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int
+}
+
+// WriteHeader records the status code before writing it
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the data before writing it
+func (w *statusResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.written += n
+	return n, err
+}
+
 func (s *Server) combinedLog(r *http.Request, start time.Time, httpStatus, dataLen int) {
 	if !s.config.EnableHttpLog {
 		return
@@ -491,7 +841,7 @@ func (s *Server) combinedLog(r *http.Request, start time.Time, httpStatus, dataL
 		r.RemoteAddr,
 		start.Format("[02/Jan/2006:15:04:05 -0700]"),
 		r.Method,
-		r.URL.Path,
+		redactAPIKeyPath(r.URL.Path),
 		r.Proto,
 		httpStatus,
 		dataLen,
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
@@ -19,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +33,7 @@ const (
 )
 
 type mockedServer struct {
+	t                   *testing.T
 	Config              Config
 	Server              *Server
 	ServerURL           string
@@ -58,11 +61,16 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		APIZKEVM:  true,
 		APITxPool: true,
 		APIWeb3:   true,
+		APIOts:    true,
+		APITrace:  true,
 	}
 
 	var newL2BlockEventHandler state.NewL2BlockEventHandler = func(e state.NewL2BlockEvent) {}
 	st.On("RegisterNewL2BlockEventHandler", mock.IsType(newL2BlockEventHandler)).Once()
 	st.On("StartToMonitorNewL2Blocks").Once()
+	var stateEventHandler state.StateEventHandler = func(e state.StateEvent) {}
+	st.On("RegisterStateEventHandler", mock.IsType(stateEventHandler)).Once()
+	st.On("StartToMonitorStateEvents").Once()
 
 	services := []Service{}
 	if _, ok := apis[APIEth]; ok {
@@ -82,7 +90,7 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	if _, ok := apis[APIZKEVM]; ok {
 		services = append(services, Service{
 			Name:    APIZKEVM,
-			Service: NewZKEVMEndpoints(cfg, pool, st, etherman),
+			Service: NewZKEVMEndpoints(cfg, pool, st, etherman, storage),
 		})
 	}
 
@@ -106,7 +114,21 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 			Service: &Web3Endpoints{},
 		})
 	}
-	server := NewServer(cfg, chainID, pool, st, storage, services)
+
+	if _, ok := apis[APIOts]; ok {
+		services = append(services, Service{
+			Name:    APIOts,
+			Service: NewOtsEndpoints(cfg, st, etherman),
+		})
+	}
+
+	if _, ok := apis[APITrace]; ok {
+		services = append(services, Service{
+			Name:    APITrace,
+			Service: NewTraceEndpoints(cfg, st, etherman),
+		})
+	}
+	server := NewServer(cfg, chainID, pool, st, storage, nil, nil, services)
 
 	go func() {
 		err := server.Start()
@@ -126,12 +148,16 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	ethClient, err := ethclient.Dial(serverURL)
+	// the results of the calls are checked against the OpenRPC document
+	httpClient := &http.Client{Transport: &openRPCResultsTransport{t: t, openRPC: server.handler.openRPC}}
+	rpcClient, err := rpc.DialHTTPWithClient(serverURL, httpClient)
 	require.NoError(t, err)
+	ethClient := ethclient.NewClient(rpcClient)
 
 	serverWebSocketsURL := fmt.Sprintf("ws://%s:%d", cfg.WebSockets.Host, cfg.WebSockets.Port)
 
 	msv := &mockedServer{
+		t:                   t,
 		Config:              cfg,
 		Server:              server,
 		ServerURL:           serverURL,
@@ -159,6 +185,9 @@ func getSequencerDefaultConfig() Config {
 		MaxLogsCount:                 10000,
 		MaxLogsBlockRange:            10000,
 		MaxNativeBlockHashBlockRange: 60000,
+		MaxTraceFilterBlockRange:     100,
+		MaxFeeHistoryBlockCount:      1024,
+		FeeHistoryCacheSize:          2048,
 		WebSockets: WebSocketsConfig{
 			Enabled:   true,
 			Host:      "0.0.0.0",
@@ -207,11 +236,23 @@ func (s *mockedServer) Stop() {
 }
 
 func (s *mockedServer) JSONRPCCall(method string, parameters ...interface{}) (types.Response, error) {
-	return client.JSONRPCCall(s.ServerURL, method, parameters...)
+	res, err := client.JSONRPCCall(s.ServerURL, method, parameters...)
+	if err == nil && res.Error == nil {
+		assertOpenRPCResult(s.t, s.Server.handler.openRPC, method, res.Result)
+	}
+	return res, err
 }
 
 func (s *mockedServer) JSONRPCBatchCall(calls ...client.BatchCall) ([]types.Response, error) {
-	return client.JSONRPCBatchCall(s.ServerURL, calls...)
+	responses, err := client.JSONRPCBatchCall(s.ServerURL, calls...)
+	if err == nil && len(responses) == len(calls) {
+		for i, res := range responses {
+			if res.Error == nil {
+				assertOpenRPCResult(s.t, s.Server.handler.openRPC, calls[i].Method, res.Result)
+			}
+		}
+	}
+	return responses, err
 }
 
 func (s *mockedServer) ChainID() uint64 {
@@ -639,3 +680,99 @@ func TestMaxRequestPerIPPerSec(t *testing.T) {
 	// connection abruptly
 	time.Sleep(time.Second)
 }
+
+func TestGraphQLServer(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.Port = 9127
+	cfg.WebSockets.Enabled = false
+	cfg.BatchRequestsLimit = 2
+	cfg.GraphQL = GraphQLConfig{
+		Enabled:       true,
+		Host:          "0.0.0.0",
+		Port:          9128,
+		MaxQueryDepth: 10,
+		MaxQueryCost:  100,
+	}
+	cfg.RateLimit = RateLimitConfig{
+		Enabled:     true,
+		Rate:        0.001,
+		Burst:       3,
+		DefaultCost: 1,
+	}
+
+	services := []Service{{Name: APIEth, Service: &EthEndpoints{}}}
+	server := NewServer(cfg, chainID, mocks.NewPoolMock(t), mocks.NewStateMock(t), newStorageMock(t), nil, nil, services)
+	go func() {
+		err := server.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	defer func() {
+		require.NoError(t, server.Stop())
+	}()
+
+	serverURL := fmt.Sprintf("http://%s:%d", cfg.GraphQL.Host, cfg.GraphQL.Port)
+	for {
+		res, err := http.Get(serverURL) //nolint:gosec
+		if err == nil && res.StatusCode == http.StatusOK {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type graphQLResponse struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	call := func(body string) (*http.Response, []byte) {
+		res, err := http.Post(serverURL, contentType, bytes.NewBufferString(body)) //nolint:gosec
+		require.NoError(t, err)
+		defer res.Body.Close()
+		resBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, resBody
+	}
+	chainIDQuery := `{"query":"{ chainID }"}`
+
+	// a query consumes 1 of the 3 units
+	res, body := call(chainIDQuery)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var response graphQLResponse
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Empty(t, response.Errors)
+	assert.Equal(t, "0x3e8", response.Data["chainID"])
+
+	// the lists of queries are limited like the batch requests
+	res, _ = call("[" + chainIDQuery + "," + chainIDQuery + "," + chainIDQuery + "]")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	res, body = call("[" + chainIDQuery + "," + chainIDQuery + "]")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var responses []graphQLResponse
+	require.NoError(t, json.Unmarshal(body, &responses))
+	require.Len(t, responses, 2)
+	for _, response := range responses {
+		require.Empty(t, response.Errors)
+	}
+
+	// the queries are rate limited once the units are consumed
+	res, body = call(chainIDQuery)
+	response = graphQLResponse{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Len(t, response.Errors, 1)
+	assert.Contains(t, response.Errors[0].Message, "rate limit exceeded")
+	assert.Equal(t, float64(types.LimitExceededErrorCode), response.Errors[0].Extensions["code"])
+	assert.NotEmpty(t, res.Header.Get("Retry-After"))
+
+	// the queries are hidden when the eth namespace is restricted
+	server.handler.serviceMap[APIEth].restricted = true
+	_, body = call(chainIDQuery)
+	response = graphQLResponse{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, float64(types.NotFoundErrorCode), response.Errors[0].Extensions["code"])
+}
@@ -14,34 +14,41 @@ import (
 // ErrNotFound represent a not found error.
 var ErrNotFound = errors.New("object not found")
 
+// ErrFilterRequiresWSConn indicates the filter can only be created for a web socket connection
+var ErrFilterRequiresWSConn = errors.New("notifications not supported")
+
 // ErrFilterInvalidPayload indicates there is an invalid payload when creating a filter
 var ErrFilterInvalidPayload = errors.New("invalid argument 0: cannot specify both BlockHash and FromBlock/ToBlock, choose one or the other")
 
 // Storage uses memory to store the data
 // related to the json rpc server
 type Storage struct {
-	allFilters                 map[string]*Filter
-	allFiltersWithWSConn       map[*concurrentWsConn]map[string]*Filter
-	blockFiltersWithWSConn     map[string]*Filter
-	logFiltersWithWSConn       map[string]*Filter
-	pendingTxFiltersWithWSConn map[string]*Filter
-
-	blockMutex     *sync.Mutex
-	logMutex       *sync.Mutex
-	pendingTxMutex *sync.Mutex
+	allFilters                  map[string]*Filter
+	allFiltersWithWSConn        map[*concurrentWsConn]map[string]*Filter
+	blockFiltersWithWSConn      map[string]*Filter
+	logFiltersWithWSConn        map[string]*Filter
+	pendingTxFiltersWithWSConn  map[string]*Filter
+	stateEventFiltersWithWSConn map[FilterType]map[string]*Filter
+
+	blockMutex      *sync.Mutex
+	logMutex        *sync.Mutex
+	pendingTxMutex  *sync.Mutex
+	stateEventMutex *sync.Mutex
 }
 
 // NewStorage creates and initializes an instance of Storage
 func NewStorage() *Storage {
 	return &Storage{
-		allFilters:                 make(map[string]*Filter),
-		allFiltersWithWSConn:       make(map[*concurrentWsConn]map[string]*Filter),
-		blockFiltersWithWSConn:     make(map[string]*Filter),
-		logFiltersWithWSConn:       make(map[string]*Filter),
-		pendingTxFiltersWithWSConn: make(map[string]*Filter),
-		blockMutex:                 &sync.Mutex{},
-		logMutex:                   &sync.Mutex{},
-		pendingTxMutex:             &sync.Mutex{},
+		allFilters:                  make(map[string]*Filter),
+		allFiltersWithWSConn:        make(map[*concurrentWsConn]map[string]*Filter),
+		blockFiltersWithWSConn:      make(map[string]*Filter),
+		logFiltersWithWSConn:        make(map[string]*Filter),
+		pendingTxFiltersWithWSConn:  make(map[string]*Filter),
+		stateEventFiltersWithWSConn: make(map[FilterType]map[string]*Filter),
+		blockMutex:                  &sync.Mutex{},
+		logMutex:                    &sync.Mutex{},
+		pendingTxMutex:              &sync.Mutex{},
+		stateEventMutex:             &sync.Mutex{},
 	}
 }
 
@@ -64,6 +71,18 @@ func (s *Storage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string,
 	return s.createFilter(FilterTypePendingTx, nil, wsConn)
 }
 
+// NewStateEventFilter persists a new zkevm subscription to the state events, it
+// requires a web socket connection since the events can only be notified
+func (s *Storage) NewStateEventFilter(wsConn *concurrentWsConn, filterType FilterType) (string, error) {
+	if !stateEventFilterTypes[filterType] {
+		return "", fmt.Errorf("invalid state event filter type: %s", filterType)
+	}
+	if wsConn == nil {
+		return "", ErrFilterRequiresWSConn
+	}
+	return s.createFilter(filterType, nil, wsConn)
+}
+
 // create persists the filter to the memory and provides the filter id
 func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *concurrentWsConn) (string, error) {
 	lastPoll := time.Now().UTC()
@@ -75,9 +94,11 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.stateEventMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.stateEventMutex.Unlock()
 
 	f := &Filter{
 		ID:            id,
@@ -88,6 +109,9 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 		wsQueue:       state.NewQueue[[]byte](),
 		wsQueueSignal: sync.NewCond(&sync.Mutex{}),
 	}
+	if logFilter, ok := parameters.(LogFilter); ok && wsConn != nil {
+		f.initLogsSubscription(logFilter)
+	}
 
 	go state.InfiniteSafeRun(f.SendEnqueuedSubscriptionData, fmt.Sprintf("failed to send enqueued subscription data to filter %v", id), time.Second)
 
@@ -104,6 +128,11 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 			s.logFiltersWithWSConn[id] = f
 		} else if t == FilterTypePendingTx {
 			s.pendingTxFiltersWithWSConn[id] = f
+		} else if stateEventFilterTypes[t] {
+			if _, found := s.stateEventFiltersWithWSConn[t]; !found {
+				s.stateEventFiltersWithWSConn[t] = make(map[string]*Filter)
+			}
+			s.stateEventFiltersWithWSConn[t][id] = f
 		}
 	}
 	return id, nil
@@ -152,14 +181,30 @@ func (s *Storage) GetAllLogFiltersWithWSConn() []*Filter {
 	return filters
 }
 
+// GetAllStateEventFiltersWithWSConn returns an array with all the zkevm
+// subscriptions to the given type of state events
+func (s *Storage) GetAllStateEventFiltersWithWSConn(filterType FilterType) []*Filter {
+	s.stateEventMutex.Lock()
+	defer s.stateEventMutex.Unlock()
+
+	filters := []*Filter{}
+	for _, filter := range s.stateEventFiltersWithWSConn[filterType] {
+		f := filter
+		filters = append(filters, f)
+	}
+	return filters
+}
+
 // GetFilter gets a filter by its id
 func (s *Storage) GetFilter(filterID string) (*Filter, error) {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.stateEventMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.stateEventMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -174,9 +219,11 @@ func (s *Storage) UpdateFilterLastPoll(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.stateEventMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.stateEventMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -192,9 +239,11 @@ func (s *Storage) UninstallFilter(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.stateEventMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.stateEventMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -210,9 +259,11 @@ func (s *Storage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.stateEventMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.stateEventMutex.Unlock()
 
 	filters, found := s.allFiltersWithWSConn[wsConn]
 	if !found {
@@ -234,6 +285,8 @@ func (s *Storage) deleteFilter(filter *Filter) {
 		delete(s.logFiltersWithWSConn, filter.ID)
 	} else if filter.Type == FilterTypePendingTx {
 		delete(s.pendingTxFiltersWithWSConn, filter.ID)
+	} else if stateEventFilterTypes[filter.Type] {
+		delete(s.stateEventFiltersWithWSConn[filter.Type], filter.ID)
 	}
 
 	if filter.WsConn != nil {
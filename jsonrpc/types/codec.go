@@ -10,6 +10,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -535,3 +536,34 @@ func stringToBatchNumber(str string) (BatchNumber, error) {
 	}
 	return BatchNumber(n), nil
 }
+
+// L1InfoRootOrLatest is a root of the L1InfoTree or the latest tag, that
+// refers to the root after the last leaf added
+type L1InfoRootOrLatest struct {
+	root *common.Hash
+}
+
+// UnmarshalJSON automatically decodes the user input for the L1InfoTree root, when a JSON RPC method is called
+func (r *L1InfoRootOrLatest) UnmarshalJSON(buffer []byte) error {
+	str := strings.Trim(string(buffer), "\"")
+	if str == Latest {
+		r.root = nil
+		return nil
+	}
+
+	var root ArgHash
+	if err := root.UnmarshalText([]byte(str)); err != nil {
+		return err
+	}
+	hash := root.Hash()
+	r.root = &hash
+	return nil
+}
+
+// Root returns the root of the L1InfoTree, nil means the latest one
+func (r *L1InfoRootOrLatest) Root() *common.Hash {
+	if r == nil {
+		return nil
+	}
+	return r.root
+}
@@ -6,9 +6,38 @@ import (
 
 // LogFilterRequest represents a log filter request.
 type LogFilterRequest struct {
-	BlockHash *common.Hash  `json:"blockHash,omitempty"`
-	FromBlock *string       `json:"fromBlock,omitempty"`
-	ToBlock   *string       `json:"toBlock,omitempty"`
-	Address   interface{}   `json:"address,omitempty"`
-	Topics    []interface{} `json:"topics,omitempty"`
+	BlockHash  *common.Hash  `json:"blockHash,omitempty"`
+	FromBlock  *string       `json:"fromBlock,omitempty"`
+	ToBlock    *string       `json:"toBlock,omitempty"`
+	Address    interface{}   `json:"address,omitempty"`
+	Topics     []interface{} `json:"topics,omitempty"`
+	Cursor     *LogCursor    `json:"cursor,omitempty"`
+	WithCursor bool          `json:"withCursor,omitempty"`
+}
+
+// LogCursor is the position right after a log notified by a logs subscription,
+// it can be provided back when subscribing again to resume from that log
+type LogCursor struct {
+	BlockNumber ArgUint64 `json:"blockNumber"`
+	LogIndex    ArgUint64 `json:"logIndex"`
+}
+
+// NewLogCursor creates the cursor to resume after the provided log
+func NewLogCursor(l Log) LogCursor {
+	return LogCursor{BlockNumber: l.BlockNumber, LogIndex: l.LogIndex + 1}
+}
+
+// Before checks if the cursor points to a position before the other one
+func (c LogCursor) Before(other LogCursor) bool {
+	if c.BlockNumber != other.BlockNumber {
+		return c.BlockNumber < other.BlockNumber
+	}
+	return c.LogIndex < other.LogIndex
+}
+
+// SubscriptionLog is a log notified by a logs subscription along with
+// the cursor to resume the subscription after it
+type SubscriptionLog struct {
+	Log
+	Cursor LogCursor `json:"cursor"`
 }
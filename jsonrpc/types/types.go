@@ -237,6 +237,96 @@ func (args *TxArgs) ToTransaction(ctx context.Context, st StateInterface, maxCum
 	return sender, tx, nil
 }
 
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of a call
+type OverrideAccount struct {
+	Nonce     *ArgUint64                   `json:"nonce"`
+	Code      *ArgBytes                    `json:"code"`
+	Balance   *ArgBig                      `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the set of accounts to override during the execution of a call
+type StateOverride map[common.Address]OverrideAccount
+
+// ToStateOverride converts the overrides to the state format
+func (so *StateOverride) ToStateOverride() state.StateOverride {
+	if so == nil {
+		return nil
+	}
+	overrides := make(state.StateOverride, len(*so))
+	for address, account := range *so {
+		o := state.OverrideAccount{}
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			o.Nonce = &nonce
+		}
+		if account.Code != nil {
+			o.Code = *account.Code
+		}
+		if account.Balance != nil {
+			o.Balance = (*big.Int)(account.Balance)
+		}
+		if account.State != nil {
+			o.State = *account.State
+		}
+		if account.StateDiff != nil {
+			o.StateDiff = *account.StateDiff
+		}
+		overrides[address] = o
+	}
+	return overrides
+}
+
+// BlockOverrides indicates the overriding fields of the block in which a call is executed
+type BlockOverrides struct {
+	Number *ArgUint64 `json:"number"`
+	Time   *ArgUint64 `json:"time"`
+}
+
+// ToBlockOverride converts the overrides to the state format
+func (bo *BlockOverrides) ToBlockOverride() *state.BlockOverride {
+	if bo == nil {
+		return nil
+	}
+	o := &state.BlockOverride{}
+	if bo.Number != nil {
+		number := uint64(*bo.Number)
+		o.Number = &number
+	}
+	if bo.Time != nil {
+		time := uint64(*bo.Time)
+		o.Time = &time
+	}
+	return o
+}
+
+// AccessListResult is the result of the creation of the access list of a transaction
+type AccessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	Error      string           `json:"error,omitempty"`
+	GasUsed    ArgUint64        `json:"gasUsed"`
+	// Notice says why the access list can't be included in a transaction,
+	// it is kept apart from Error, which is only set when the execution fails
+	Notice string `json:"notice,omitempty"`
+}
+
+// NewAccessListResult creates an access list result from the state result
+func NewAccessListResult(result *state.AccessListResult) AccessListResult {
+	res := AccessListResult{
+		AccessList: result.AccessList,
+		GasUsed:    ArgUint64(result.GasUsed),
+	}
+	if result.Err != nil {
+		res.Error = result.Err.Error()
+	}
+	if !state.IsTypedTxSupported(result.ForkID) {
+		res.Notice = fmt.Sprintf("access list txs are not supported by fork id %d, the access list can't be included in a transaction", result.ForkID)
+	}
+	return res
+}
+
 // Block structure
 type Block struct {
 	ParentHash      common.Hash         `json:"parentHash"`
@@ -695,6 +785,14 @@ func NewLog(l types.Log) Log {
 	}
 }
 
+// FeeHistory structure
+type FeeHistory struct {
+	OldestBlock  ArgUint64  `json:"oldestBlock"`
+	Reward       [][]ArgBig `json:"reward,omitempty"`
+	BaseFee      []ArgBig   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64  `json:"gasUsedRatio"`
+}
+
 // ExitRoots structure
 type ExitRoots struct {
 	BlockNumber     ArgUint64   `json:"blockNumber"`
@@ -703,6 +801,87 @@ type ExitRoots struct {
 	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
 }
 
+// L1InfoTreeLeaf structure
+type L1InfoTreeLeaf struct {
+	Index             ArgUint64   `json:"index"`
+	Hash              common.Hash `json:"hash"`
+	L1InfoRoot        common.Hash `json:"l1InfoRoot"`
+	GlobalExitRoot    common.Hash `json:"globalExitRoot"`
+	MainnetExitRoot   common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot    common.Hash `json:"rollupExitRoot"`
+	PreviousBlockHash common.Hash `json:"previousBlockHash"`
+	Timestamp         ArgUint64   `json:"timestamp"`
+	L1BlockNumber     ArgUint64   `json:"l1BlockNumber"`
+}
+
+// NewL1InfoTreeLeaf creates a L1InfoTreeLeaf instance from the stored entry
+func NewL1InfoTreeLeaf(entry state.L1InfoTreeExitRootStorageEntry) L1InfoTreeLeaf {
+	return L1InfoTreeLeaf{
+		Index:             ArgUint64(entry.L1InfoTreeIndex),
+		Hash:              entry.Hash(),
+		L1InfoRoot:        entry.L1InfoTreeRoot,
+		GlobalExitRoot:    entry.GlobalExitRoot.GlobalExitRoot,
+		MainnetExitRoot:   entry.MainnetExitRoot,
+		RollupExitRoot:    entry.RollupExitRoot,
+		PreviousBlockHash: entry.PreviousBlockHash,
+		Timestamp:         ArgUint64(entry.Timestamp.Unix()),
+		L1BlockNumber:     ArgUint64(entry.BlockNumber),
+	}
+}
+
+// L1InfoTreeProof structure
+type L1InfoTreeProof struct {
+	Index      ArgUint64     `json:"index"`
+	Leaf       common.Hash   `json:"leaf"`
+	L1InfoRoot common.Hash   `json:"l1InfoRoot"`
+	Proof      []common.Hash `json:"proof"`
+}
+
+// Proof statuses of a batch returned by zkevm_getBatchProofStatus
+const (
+	// BatchProofStatusNotStarted means no prover is working on the batch
+	BatchProofStatusNotStarted = "notStarted"
+	// BatchProofStatusGenerating means a prover is generating a proof that includes the batch
+	BatchProofStatusGenerating = "generating"
+	// BatchProofStatusBatchProof means the proof of the batch alone was generated
+	// and is waiting to be aggregated or sent to L1
+	BatchProofStatusBatchProof = "batchProof"
+	// BatchProofStatusAggregated means the batch is included in a generated proof
+	// aggregating several batches, waiting to be aggregated again or sent to L1
+	BatchProofStatusAggregated = "aggregated"
+	// BatchProofStatusFinalProofSent means the final proof including the batch was
+	// sent to L1 and its verification is not confirmed yet
+	BatchProofStatusFinalProofSent = "finalProofSent"
+	// BatchProofStatusVerified means the verification of the batch was confirmed on L1
+	BatchProofStatusVerified = "verified"
+)
+
+// BatchProofStatus structure
+type BatchProofStatus struct {
+	Status            string       `json:"status"`
+	BatchNumber       *ArgUint64   `json:"batchNumber,omitempty"`
+	BatchNumberFinal  *ArgUint64   `json:"batchNumberFinal,omitempty"`
+	Prover            *string      `json:"prover,omitempty"`
+	ProverID          *string      `json:"proverId,omitempty"`
+	GeneratingSince   *ArgUint64   `json:"generatingSince,omitempty"`
+	VerifyBatchTxHash *common.Hash `json:"verifyBatchTxHash,omitempty"`
+}
+
+// PoolTransactionStatus structure
+type PoolTransactionStatus struct {
+	Status       string  `json:"status"`
+	FailedReason *string `json:"failedReason,omitempty"`
+}
+
+// FinalProof structure
+type FinalProof struct {
+	BatchNumber       ArgUint64   `json:"batchNumber"`
+	BatchNumberFinal  ArgUint64   `json:"batchNumberFinal"`
+	ProofID           *string     `json:"proofId"`
+	Proof             ArgBytes    `json:"proof"`
+	VerifyBatchTxHash common.Hash `json:"verifyBatchTxHash"`
+}
+
 // ZKCounters counters for the tx
 type ZKCounters struct {
 	GasUsed              ArgUint64 `json:"gasUsed"`
@@ -768,3 +947,99 @@ func NewZKCountersResponse(zkCounters state.ZKCounters, limits ZKCountersLimits,
 		OOCError:       &oocErrMsg,
 	}
 }
+
+// ClosedBatch structure, notified by the zkevm newBatches subscription
+type ClosedBatch struct {
+	Number         ArgUint64      `json:"number"`
+	Coinbase       common.Address `json:"coinbase"`
+	StateRoot      common.Hash    `json:"stateRoot"`
+	GlobalExitRoot common.Hash    `json:"globalExitRoot"`
+	LocalExitRoot  common.Hash    `json:"localExitRoot"`
+	AccInputHash   common.Hash    `json:"accInputHash"`
+	Timestamp      ArgUint64      `json:"timestamp"`
+}
+
+// VirtualizedBatch structure, notified by the zkevm virtualizedBatches subscription
+type VirtualizedBatch struct {
+	Number              ArgUint64   `json:"number"`
+	SendSequencesTxHash common.Hash `json:"sendSequencesTxHash"`
+	L1BlockNumber       ArgUint64   `json:"l1BlockNumber"`
+}
+
+// VerifiedBatch structure, notified by the zkevm verifiedBatches subscription
+type VerifiedBatch struct {
+	Number            ArgUint64   `json:"number"`
+	StateRoot         common.Hash `json:"stateRoot"`
+	VerifyBatchTxHash common.Hash `json:"verifyBatchTxHash"`
+	L1BlockNumber     ArgUint64   `json:"l1BlockNumber"`
+}
+
+// GlobalExitRoot structure, notified by the zkevm newGlobalExitRoot subscription
+type GlobalExitRoot struct {
+	GlobalExitRoot  common.Hash `json:"globalExitRoot"`
+	MainnetExitRoot common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
+	L1InfoTreeIndex *ArgUint64  `json:"l1InfoTreeIndex"`
+	L1BlockNumber   ArgUint64   `json:"l1BlockNumber"`
+	Timestamp       ArgUint64   `json:"timestamp"`
+}
+
+// ForkIDChange structure, notified by the zkevm forkIdChanged subscription
+type ForkIDChange struct {
+	ForkID          ArgUint64 `json:"forkId"`
+	Version         string    `json:"version"`
+	FromBatchNumber ArgUint64 `json:"fromBatchNumber"`
+	L1BlockNumber   ArgUint64 `json:"l1BlockNumber"`
+}
+
+// NewStateEventResult creates the subscription result notified for a state event
+func NewStateEventResult(e state.StateEvent) interface{} {
+	switch e.Type {
+	case state.StateEventNewBatch:
+		return ClosedBatch{
+			Number:         ArgUint64(e.BatchNumber),
+			Coinbase:       e.Coinbase,
+			StateRoot:      e.StateRoot,
+			GlobalExitRoot: e.GlobalExitRoot,
+			LocalExitRoot:  e.LocalExitRoot,
+			AccInputHash:   e.AccInputHash,
+			Timestamp:      ArgUint64(e.Timestamp.Unix()),
+		}
+	case state.StateEventVirtualizedBatch:
+		return VirtualizedBatch{
+			Number:              ArgUint64(e.BatchNumber),
+			SendSequencesTxHash: e.TxHash,
+			L1BlockNumber:       ArgUint64(e.BlockNumber),
+		}
+	case state.StateEventVerifiedBatch:
+		return VerifiedBatch{
+			Number:            ArgUint64(e.BatchNumber),
+			StateRoot:         e.StateRoot,
+			VerifyBatchTxHash: e.TxHash,
+			L1BlockNumber:     ArgUint64(e.BlockNumber),
+		}
+	case state.StateEventNewGlobalExitRoot:
+		var l1InfoTreeIndex *ArgUint64
+		if e.L1InfoTreeIndex != nil {
+			index := ArgUint64(*e.L1InfoTreeIndex)
+			l1InfoTreeIndex = &index
+		}
+		return GlobalExitRoot{
+			GlobalExitRoot:  e.GlobalExitRoot,
+			MainnetExitRoot: e.MainnetExitRoot,
+			RollupExitRoot:  e.RollupExitRoot,
+			L1InfoTreeIndex: l1InfoTreeIndex,
+			L1BlockNumber:   ArgUint64(e.BlockNumber),
+			Timestamp:       ArgUint64(e.Timestamp.Unix()),
+		}
+	case state.StateEventForkIDChanged:
+		return ForkIDChange{
+			ForkID:          ArgUint64(e.ForkID),
+			Version:         e.Version,
+			FromBatchNumber: ArgUint64(e.BatchNumber),
+			L1BlockNumber:   ArgUint64(e.BlockNumber),
+		}
+	default:
+		return nil
+	}
+}
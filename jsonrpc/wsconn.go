@@ -11,6 +11,10 @@ import (
 type concurrentWsConn struct {
 	wsConn *websocket.Conn
 	mutex  *sync.Mutex
+
+	// afterResponse is the work to start once the response to the request
+	// being handled is written, like the logs backfill of a new subscription
+	afterResponse []func()
 }
 
 // NewConcurrentWsConn creates a new instance of concurrentWsConn
@@ -44,3 +48,23 @@ func (c *concurrentWsConn) Close() error {
 func (c *concurrentWsConn) SetReadLimit(limit int64) {
 	c.wsConn.SetReadLimit(limit)
 }
+
+// runAfterResponse schedules fn to run once the response to the
+// request being handled is written to the connection
+func (c *concurrentWsConn) runAfterResponse(fn func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.afterResponse = append(c.afterResponse, fn)
+}
+
+// responseWritten starts the work waiting for the response to be written
+func (c *concurrentWsConn) responseWritten() {
+	c.mutex.Lock()
+	fns := c.afterResponse
+	c.afterResponse = nil
+	c.mutex.Unlock()
+
+	for _, fn := range fns {
+		go fn()
+	}
+}
@@ -119,6 +119,43 @@ func (mt *L1InfoTree) ComputeMerkleProof(gerIndex uint32, leaves [][32]byte) ([]
 	return siblings, common.BytesToHash(ns[0][0]), nil
 }
 
+// ComputeLevels computes all the nodes of the tree given its leaves, from the level
+// of the leaves to the level of the root, so the merkle proofs of the leaves can be
+// computed by MerkleProofFromLevels without hashing the whole tree again
+func (mt *L1InfoTree) ComputeLevels(leaves [][32]byte) [][][32]byte {
+	levels := make([][][32]byte, 0, mt.height+1)
+	level := append(make([][32]byte, 0, len(leaves)+1), leaves...)
+	if len(level) == 0 {
+		level = append(level, mt.zeroHashes[0])
+	}
+	for h := uint8(0); h < mt.height; h++ {
+		if len(level)%2 == 1 {
+			level = append(level, mt.zeroHashes[h])
+		}
+		levels = append(levels, level)
+		next := make([][32]byte, 0, len(level)/2+1) //nolint:gomnd
+		for i := 0; i < len(level); i += 2 {
+			next = append(next, Hash(level[i], level[i+1]))
+		}
+		level = next
+	}
+	return append(levels, level)
+}
+
+// MerkleProofFromLevels returns the merkle proof of the leaf with the given index and
+// the root of the tree whose levels were computed by ComputeLevels
+func (mt *L1InfoTree) MerkleProofFromLevels(index uint32, levels [][][32]byte) ([][32]byte, common.Hash, error) {
+	if len(levels) != int(mt.height)+1 || index >= uint32(len(levels[0])) {
+		return nil, common.Hash{}, fmt.Errorf("error: index %d out of the tree levels", index)
+	}
+	siblings := make([][32]byte, 0, mt.height)
+	for h := uint8(0); h < mt.height; h++ {
+		siblings = append(siblings, levels[h][index^1])
+		index /= 2 //nolint:gomnd
+	}
+	return siblings, levels[mt.height][0], nil
+}
+
 // AddLeaf adds new leaves to the tree and computes the new root
 func (mt *L1InfoTree) AddLeaf(index uint32, leaf [32]byte) (common.Hash, error) {
 	if index != mt.count {
@@ -3,6 +3,7 @@ package l1infotree_test
 import (
 	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"os"
 	"testing"
 
@@ -83,6 +84,26 @@ func TestComputeMerkleProof(t *testing.T) {
 	}
 }
 
+func TestMerkleProofFromLevels(t *testing.T) {
+	mt, err := l1infotree.NewL1InfoTree(uint8(32), [][32]byte{})
+	require.NoError(t, err)
+	leaves := [][32]byte{}
+	for i := 0; i < 9; i++ {
+		leaves = append(leaves, common.BigToHash(big.NewInt(int64(i+1))))
+		levels := mt.ComputeLevels(leaves)
+		for index := range leaves {
+			expectedSiblings, expectedRoot, err := mt.ComputeMerkleProof(uint32(index), leaves)
+			require.NoError(t, err)
+			siblings, root, err := mt.MerkleProofFromLevels(uint32(index), levels)
+			require.NoError(t, err)
+			require.Equal(t, expectedRoot, root)
+			require.Equal(t, expectedSiblings, siblings)
+		}
+	}
+	_, _, err = mt.MerkleProofFromLevels(10, mt.ComputeLevels(leaves))
+	require.Error(t, err)
+}
+
 func TestAddLeaf(t *testing.T) {
 	data, err := os.ReadFile("../test/vectors/src/merkle-tree/l1-info-tree/proof-vectors.json")
 	require.NoError(t, err)
@@ -0,0 +1,369 @@
+package merkletree
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	poseidon "github.com/iden3/go-iden3-crypto/goldenposeidon"
+)
+
+// maxLevels is the max depth of the state tree, one level per bit of the keys
+const maxLevels = 256
+
+// ErrInvalidProof is returned when a proof doesn't match the state root
+var ErrInvalidProof = errors.New("invalid state tree proof")
+
+// SMTProof is a proof of the value stored for a key in the state tree, or of
+// the absence of the key when the value is zero.
+//
+// The key is the path of the leaf in the tree: the bit i of the path is the
+// bit i/4 of the 64 bits word i%4 of the key, starting by the least significant
+// word. Each sibling is the content of an intermediate node of the path, from
+// the root to the leaf: the 32 bytes hash of the left child followed by the 32
+// bytes hash of the right child.
+//
+// When the key is not in the tree, the path ends in an empty node if IsOld0 is
+// true, or in the leaf of InsKey with the value InsValue otherwise.
+type SMTProof struct {
+	Key      common.Hash     `json:"key"`
+	Value    *hexutil.Big    `json:"value"`
+	Siblings []hexutil.Bytes `json:"siblings"`
+	IsOld0   bool            `json:"isOld0"`
+	InsKey   common.Hash     `json:"insKey"`
+	InsValue *hexutil.Big    `json:"insValue"`
+}
+
+// StorageProof is the proof of a storage slot of an account
+type StorageProof struct {
+	Key   common.Hash  `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof SMTProof     `json:"proof"`
+}
+
+// AccountProof contains the proofs of the balance, nonce, code hash and
+// the requested storage slots of an account for a state root
+type AccountProof struct {
+	Address       common.Address `json:"address"`
+	StateRoot     common.Hash    `json:"stateRoot"`
+	Balance       *hexutil.Big   `json:"balance"`
+	Nonce         *hexutil.Big   `json:"nonce"`
+	CodeHash      common.Hash    `json:"codeHash"`
+	BalanceProof  SMTProof       `json:"balanceProof"`
+	NonceProof    SMTProof       `json:"nonceProof"`
+	CodeHashProof SMTProof       `json:"codeHashProof"`
+	StorageProof  []StorageProof `json:"storageProof"`
+}
+
+// GetAccountProof returns the proofs of the balance, nonce, code hash and the
+// provided storage slots of the account for the state root.
+func (tree *StateTree) GetAccountProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root []byte) (*AccountProof, error) {
+	balanceKey, err := KeyEthAddrBalance(address)
+	if err != nil {
+		return nil, err
+	}
+	nonceKey, err := KeyEthAddrNonce(address)
+	if err != nil {
+		return nil, err
+	}
+	codeHashKey, err := KeyContractCode(address)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &AccountProof{
+		Address:      address,
+		StateRoot:    common.BytesToHash(root),
+		StorageProof: make([]StorageProof, 0, len(storageKeys)),
+	}
+	if proof.BalanceProof, err = tree.GetProof(ctx, balanceKey, root); err != nil {
+		return nil, err
+	}
+	if proof.NonceProof, err = tree.GetProof(ctx, nonceKey, root); err != nil {
+		return nil, err
+	}
+	if proof.CodeHashProof, err = tree.GetProof(ctx, codeHashKey, root); err != nil {
+		return nil, err
+	}
+	proof.Balance = proof.BalanceProof.Value
+	proof.Nonce = proof.NonceProof.Value
+	proof.CodeHash = common.BigToHash(proof.CodeHashProof.Value.ToInt())
+
+	for _, storageKey := range storageKeys {
+		key, err := KeyContractStorage(address, storageKey.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		storageProof, err := tree.GetProof(ctx, key, root)
+		if err != nil {
+			return nil, err
+		}
+		proof.StorageProof = append(proof.StorageProof, StorageProof{
+			Key:   storageKey,
+			Value: storageProof.Value,
+			Proof: storageProof,
+		})
+	}
+	return proof, nil
+}
+
+// GetProof returns the proof of the value stored for the key in the state tree
+func (tree *StateTree) GetProof(ctx context.Context, key []byte, root []byte) (SMTProof, error) {
+	r := scalarToh4(new(big.Int).SetBytes(root))
+	k := scalarToh4(new(big.Int).SetBytes(key))
+	result, err := tree.grpcClient.Get(ctx, &hashdb.GetRequest{
+		Root:    &hashdb.Fea{Fe0: r[0], Fe1: r[1], Fe2: r[2], Fe3: r[3]},
+		Key:     &hashdb.Fea{Fe0: k[0], Fe1: k[1], Fe2: k[2], Fe3: k[3]},
+		Details: true,
+	})
+	if err != nil {
+		return SMTProof{}, err
+	}
+	return newSMTProof(k, result)
+}
+
+// newSMTProof builds a proof from the details returned by the hashdb for a key
+func newSMTProof(key []uint64, result *hashdb.GetResponse) (SMTProof, error) {
+	value, err := hashdbValueToScalar(result.Value)
+	if err != nil {
+		return SMTProof{}, err
+	}
+	insValue, err := hashdbValueToScalar(result.InsValue)
+	if err != nil {
+		return SMTProof{}, err
+	}
+
+	levels := make([]uint64, 0, len(result.Siblings))
+	for level := range result.Siblings {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	siblings := make([]hexutil.Bytes, 0, len(levels))
+	for i, level := range levels {
+		if level != uint64(i) {
+			return SMTProof{}, fmt.Errorf("missing sibling at level %d", i)
+		}
+		node := result.Siblings[level].GetSibling()
+		if len(node) < 8 { //nolint:gomnd
+			return SMTProof{}, fmt.Errorf("invalid sibling at level %d", level)
+		}
+		// the leaf at the end of the path is not a sibling
+		if len(node) > 8 && node[8] == 1 { //nolint:gomnd
+			break
+		}
+		sibling := make([]byte, 0, 2*maxBigIntLen) //nolint:gomnd
+		sibling = append(sibling, h4ToFilledByteSlice(node[0:4])...)
+		sibling = append(sibling, h4ToFilledByteSlice(node[4:8])...)
+		siblings = append(siblings, sibling)
+	}
+
+	proof := SMTProof{
+		Key:      common.BytesToHash(h4ToFilledByteSlice(key)),
+		Value:    (*hexutil.Big)(value),
+		Siblings: siblings,
+		IsOld0:   result.IsOld0,
+		InsValue: (*hexutil.Big)(insValue),
+	}
+	if insKey := result.InsKey; insKey != nil {
+		proof.InsKey = common.BytesToHash(h4ToFilledByteSlice([]uint64{insKey.Fe0, insKey.Fe1, insKey.Fe2, insKey.Fe3}))
+	}
+	return proof, nil
+}
+
+// hashdbValueToScalar converts a value returned by the hashdb into a scalar
+func hashdbValueToScalar(value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	fea, err := string2fea(value)
+	if err != nil {
+		return nil, err
+	}
+	return fea2scalar(fea), nil
+}
+
+// VerifyProof checks that the proof matches the state root
+func VerifyProof(root common.Hash, proof SMTProof) error {
+	if len(proof.Siblings) > maxLevels {
+		return fmt.Errorf("%w: too many siblings", ErrInvalidProof)
+	}
+	key := scalarToh4(new(big.Int).SetBytes(proof.Key.Bytes()))
+	level := len(proof.Siblings)
+
+	var (
+		node []uint64
+		err  error
+	)
+	value := proof.Value.ToInt()
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	switch {
+	case value.Sign() != 0:
+		node, err = hashLeaf(removeKeyBits(key, level), value)
+	case proof.IsOld0:
+		node = []uint64{0, 0, 0, 0}
+	default:
+		insKey := removeKeyBits(scalarToh4(new(big.Int).SetBytes(proof.InsKey.Bytes())), level)
+		if equalH4(insKey, removeKeyBits(key, level)) {
+			return fmt.Errorf("%w: the leaf found for the key has no value", ErrInvalidProof)
+		}
+		insValue := proof.InsValue.ToInt()
+		if insValue == nil {
+			insValue = big.NewInt(0)
+		}
+		node, err = hashLeaf(insKey, insValue)
+	}
+	if err != nil {
+		return err
+	}
+
+	for level := level - 1; level >= 0; level-- {
+		sibling := proof.Siblings[level]
+		if len(sibling) != 2*maxBigIntLen { //nolint:gomnd
+			return fmt.Errorf("%w: invalid sibling length at level %d", ErrInvalidProof, level)
+		}
+		left := scalarToh4(new(big.Int).SetBytes(sibling[:maxBigIntLen]))
+		right := scalarToh4(new(big.Int).SetBytes(sibling[maxBigIntLen:]))
+		if keyBit(key, level) == 0 {
+			left = node
+		} else {
+			right = node
+		}
+		if node, err = hashNode(left, right); err != nil {
+			return err
+		}
+	}
+
+	if !bytes.Equal(h4ToFilledByteSlice(node), root.Bytes()) {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyAccountProof checks that all the proofs of the account match the state
+// root and that they are the proofs of the values reported for the account
+func VerifyAccountProof(root common.Hash, proof *AccountProof) error {
+	balanceKey, err := KeyEthAddrBalance(proof.Address)
+	if err != nil {
+		return err
+	}
+	nonceKey, err := KeyEthAddrNonce(proof.Address)
+	if err != nil {
+		return err
+	}
+	codeHashKey, err := KeyContractCode(proof.Address)
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		name  string
+		key   []byte
+		value *big.Int
+		proof SMTProof
+	}{
+		{"balance", balanceKey, proof.Balance.ToInt(), proof.BalanceProof},
+		{"nonce", nonceKey, proof.Nonce.ToInt(), proof.NonceProof},
+		{"code hash", codeHashKey, proof.CodeHash.Big(), proof.CodeHashProof},
+	}
+	for _, storageProof := range proof.StorageProof {
+		key, err := KeyContractStorage(proof.Address, storageProof.Key.Bytes())
+		if err != nil {
+			return err
+		}
+		checks = append(checks, struct {
+			name  string
+			key   []byte
+			value *big.Int
+			proof SMTProof
+		}{fmt.Sprintf("storage %s", storageProof.Key.String()), key, storageProof.Value.ToInt(), storageProof.Proof})
+	}
+
+	for _, check := range checks {
+		if !bytes.Equal(check.key, check.proof.Key.Bytes()) {
+			return fmt.Errorf("%w: unexpected key for the %s proof", ErrInvalidProof, check.name)
+		}
+		if !equalScalar(check.value, check.proof.Value.ToInt()) {
+			return fmt.Errorf("%w: unexpected value for the %s proof", ErrInvalidProof, check.name)
+		}
+		if err := VerifyProof(root, check.proof); err != nil {
+			return fmt.Errorf("%s proof: %w", check.name, err)
+		}
+	}
+	return nil
+}
+
+// hashLeaf computes the hash of a leaf node storing the value for the remaining key
+func hashLeaf(remainingKey []uint64, value *big.Int) ([]uint64, error) {
+	fea := scalar2fea(value)
+	valueHash, err := poseidon.Hash([poseidon.NROUNDSF]uint64{fea[0], fea[1], fea[2], fea[3], fea[4], fea[5], fea[6], fea[7]}, [poseidon.CAPLEN]uint64{})
+	if err != nil {
+		return nil, err
+	}
+	hash, err := poseidon.Hash([poseidon.NROUNDSF]uint64{
+		remainingKey[0], remainingKey[1], remainingKey[2], remainingKey[3],
+		valueHash[0], valueHash[1], valueHash[2], valueHash[3],
+	}, [poseidon.CAPLEN]uint64{1, 0, 0, 0})
+	if err != nil {
+		return nil, err
+	}
+	return hash[:], nil
+}
+
+// hashNode computes the hash of an intermediate node
+func hashNode(left, right []uint64) ([]uint64, error) {
+	hash, err := poseidon.Hash([poseidon.NROUNDSF]uint64{
+		left[0], left[1], left[2], left[3],
+		right[0], right[1], right[2], right[3],
+	}, [poseidon.CAPLEN]uint64{})
+	if err != nil {
+		return nil, err
+	}
+	return hash[:], nil
+}
+
+// keyBit returns the bit of the key that selects the child at the level
+func keyBit(key []uint64, level int) uint64 {
+	return (key[level%4] >> (level / 4)) & 1 //nolint:gomnd
+}
+
+// removeKeyBits removes the bits of the key used by the first nBits levels
+func removeKeyBits(key []uint64, nBits int) []uint64 {
+	fullLevels := nBits / 4           //nolint:gomnd
+	remainingKey := make([]uint64, 4) //nolint:gomnd
+	for i := range remainingKey {
+		n := fullLevels
+		if fullLevels*4+i < nBits { //nolint:gomnd
+			n++
+		}
+		remainingKey[i] = key[i] >> n
+	}
+	return remainingKey
+}
+
+func equalH4(a, b []uint64) bool {
+	for i := 0; i < 4; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalScalar(a, b *big.Int) bool {
+	if a == nil {
+		a = big.NewInt(0)
+	}
+	if b == nil {
+		b = big.NewInt(0)
+	}
+	return a.Cmp(b) == 0
+}
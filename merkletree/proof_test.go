@@ -0,0 +1,230 @@
+package merkletree
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testVectorSMTRaw struct {
+	Keys         []string `json:"keys"`
+	Values       []string `json:"values"`
+	ExpectedRoot string   `json:"expectedRoot"`
+}
+
+// memoryHashDB is a minimal in memory implementation of the hashdb Get
+// operation, storing the tree nodes the same way the hashdb does
+type memoryHashDB struct {
+	hashdb.HashDBServiceClient
+	nodes  map[[4]uint64][]uint64
+	values map[[4]uint64]*big.Int
+	root   []uint64
+}
+
+func newMemoryHashDB(t *testing.T, leaves map[[4]uint64]*big.Int) *memoryHashDB {
+	db := &memoryHashDB{nodes: make(map[[4]uint64][]uint64), values: make(map[[4]uint64]*big.Int)}
+	keys := make([][4]uint64, 0, len(leaves))
+	for key, value := range leaves {
+		if value.Sign() != 0 {
+			keys = append(keys, key)
+			db.values[key] = value
+		}
+	}
+	root, err := db.build(keys, 0)
+	require.NoError(t, err)
+	db.root = root
+	return db
+}
+
+func (db *memoryHashDB) build(keys [][4]uint64, level int) ([]uint64, error) {
+	switch len(keys) {
+	case 0:
+		return []uint64{0, 0, 0, 0}, nil
+	case 1:
+		remainingKey := removeKeyBits(keys[0][:], level)
+		hash, err := hashLeaf(remainingKey, db.values[keys[0]])
+		if err != nil {
+			return nil, err
+		}
+		db.nodes[[4]uint64{hash[0], hash[1], hash[2], hash[3]}] = append(append(remainingKey, 0, 0, 0, 0), 1, 0, 0, 0)
+		return hash, nil
+	}
+
+	var left, right [][4]uint64
+	for _, key := range keys {
+		if keyBit(key[:], level) == 0 {
+			left = append(left, key)
+		} else {
+			right = append(right, key)
+		}
+	}
+	leftHash, err := db.build(left, level+1)
+	if err != nil {
+		return nil, err
+	}
+	rightHash, err := db.build(right, level+1)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashNode(leftHash, rightHash)
+	if err != nil {
+		return nil, err
+	}
+	db.nodes[[4]uint64{hash[0], hash[1], hash[2], hash[3]}] = append(append(append([]uint64{}, leftHash...), rightHash...), 0, 0, 0, 0)
+	return hash, nil
+}
+
+// Get walks the path of the key from the root, returning the visited
+// intermediate nodes as siblings like the hashdb does
+func (db *memoryHashDB) Get(ctx context.Context, in *hashdb.GetRequest, opts ...grpc.CallOption) (*hashdb.GetResponse, error) {
+	key := []uint64{in.Key.Fe0, in.Key.Fe1, in.Key.Fe2, in.Key.Fe3}
+	node := []uint64{in.Root.Fe0, in.Root.Fe1, in.Root.Fe2, in.Root.Fe3}
+	res := &hashdb.GetResponse{Siblings: make(map[uint64]*hashdb.SiblingList), IsOld0: true, Value: "0"}
+
+	for level := 0; !equalH4(node, []uint64{0, 0, 0, 0}); level++ {
+		content, found := db.nodes[[4]uint64{node[0], node[1], node[2], node[3]}]
+		if !found {
+			return nil, errors.New("node not found")
+		}
+		if content[8] == 1 {
+			// leaf, the full key is found looking for the stored key with the same remaining key
+			for storedKey, value := range db.values {
+				if !equalH4(removeKeyBits(storedKey[:], level), content[0:4]) || !sameBits(storedKey[:], key, level) {
+					continue
+				}
+				if equalH4(storedKey[:], key) {
+					res.Value = value.Text(16) //nolint:gomnd
+				} else {
+					res.InsKey = &hashdb.Fea{Fe0: storedKey[0], Fe1: storedKey[1], Fe2: storedKey[2], Fe3: storedKey[3]}
+					res.InsValue = value.Text(16) //nolint:gomnd
+				}
+				res.IsOld0 = false
+			}
+			break
+		}
+		res.Siblings[uint64(level)] = &hashdb.SiblingList{Sibling: content}
+		if keyBit(key, level) == 0 {
+			node = content[0:4]
+		} else {
+			node = content[4:8]
+		}
+	}
+	return res, nil
+}
+
+func sameBits(a, b []uint64, levels int) bool {
+	for level := 0; level < levels; level++ {
+		if keyBit(a, level) != keyBit(b, level) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSMTProof(t *testing.T) {
+	data, err := os.ReadFile("test/vectors/src/merkle-tree/smt-raw.json")
+	require.NoError(t, err)
+	var testVectors []testVectorSMTRaw
+	require.NoError(t, json.Unmarshal(data, &testVectors))
+
+	for ti, testVector := range testVectors {
+		t.Run(fmt.Sprintf("%d", ti), func(t *testing.T) {
+			leaves := make(map[[4]uint64]*big.Int)
+			for i := range testVector.Keys {
+				key, ok := new(big.Int).SetString(testVector.Keys[i], 10) //nolint:gomnd
+				require.True(t, ok)
+				value, ok := new(big.Int).SetString(testVector.Values[i], 10) //nolint:gomnd
+				require.True(t, ok)
+				h4 := scalarToh4(key)
+				leaves[[4]uint64{h4[0], h4[1], h4[2], h4[3]}] = value
+			}
+			db := newMemoryHashDB(t, leaves)
+			root := common.BytesToHash(h4ToFilledByteSlice(db.root))
+			require.Equal(t, testVector.ExpectedRoot, root.String())
+
+			tree := NewStateTree(db)
+			// the keys of the tree and a key that is not in the tree
+			keys := [][4]uint64{{7, 7, 7, 7}}
+			for key := range leaves {
+				keys = append(keys, key)
+			}
+			for _, key := range keys {
+				proof, err := tree.GetProof(context.Background(), h4ToFilledByteSlice(key[:]), root.Bytes())
+				require.NoError(t, err)
+
+				expectedValue := leaves[key]
+				if expectedValue == nil {
+					expectedValue = big.NewInt(0)
+				}
+				assert.Equal(t, 0, expectedValue.Cmp(proof.Value.ToInt()))
+				require.NoError(t, VerifyProof(root, proof))
+
+				// the proof is not valid for other values
+				forgedProof := proof
+				forgedProof.Value = (*hexutil.Big)(new(big.Int).Add(expectedValue, big.NewInt(1)))
+				assert.ErrorIs(t, VerifyProof(root, forgedProof), ErrInvalidProof)
+			}
+		})
+	}
+}
+
+func TestAccountProof(t *testing.T) {
+	address := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+	slot := common.HexToHash("0x1")
+
+	leaves := make(map[[4]uint64]*big.Int)
+	addLeaf := func(key []byte, err error, value *big.Int) {
+		require.NoError(t, err)
+		h4 := scalarToh4(new(big.Int).SetBytes(key))
+		leaves[[4]uint64{h4[0], h4[1], h4[2], h4[3]}] = value
+	}
+	balanceKey, err := KeyEthAddrBalance(address)
+	addLeaf(balanceKey, err, big.NewInt(1000))
+	nonceKey, err := KeyEthAddrNonce(address)
+	addLeaf(nonceKey, err, big.NewInt(3))
+	storageKey, err := KeyContractStorage(address, slot.Bytes())
+	addLeaf(storageKey, err, big.NewInt(42))
+	otherBalanceKey, err := KeyEthAddrBalance(common.HexToAddress("0x1"))
+	addLeaf(otherBalanceKey, err, big.NewInt(5))
+
+	db := newMemoryHashDB(t, leaves)
+	root := common.BytesToHash(h4ToFilledByteSlice(db.root))
+	tree := NewStateTree(db)
+
+	proof, err := tree.GetAccountProof(context.Background(), address, []common.Hash{slot, common.HexToHash("0x2")}, root.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), proof.Balance.ToInt().Int64())
+	assert.Equal(t, int64(3), proof.Nonce.ToInt().Int64())
+	assert.Equal(t, common.Hash{}, proof.CodeHash)
+	require.Len(t, proof.StorageProof, 2)
+	assert.Equal(t, int64(42), proof.StorageProof[0].Value.ToInt().Int64())
+	assert.Equal(t, int64(0), proof.StorageProof[1].Value.ToInt().Int64())
+	require.NoError(t, VerifyAccountProof(root, proof))
+
+	// the proof round trips through its JSON representation
+	data, err := json.Marshal(proof)
+	require.NoError(t, err)
+	var decoded AccountProof
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NoError(t, VerifyAccountProof(root, &decoded))
+
+	// the reported values must match the proofs
+	decoded.Balance = (*hexutil.Big)(big.NewInt(2000))
+	assert.ErrorIs(t, VerifyAccountProof(root, &decoded), ErrInvalidProof)
+
+	// the proofs must belong to the account
+	decoded.Balance = proof.Balance
+	decoded.Address = common.HexToAddress("0x1")
+	assert.ErrorIs(t, VerifyAccountProof(root, &decoded), ErrInvalidProof)
+}
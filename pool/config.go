@@ -43,6 +43,65 @@ type Config struct {
 
 	// ForkID is the current fork ID of the chain
 	ForkID uint64 `mapstructure:"ForkID"`
+
+	// PolicyFeeds is the config for the periodic load of policy address lists
+	PolicyFeeds PolicyFeedsCfg `mapstructure:"PolicyFeeds"`
+
+	// PreExecution is the config for the pre-execution of the txs added to the pool
+	PreExecution PreExecutionCfg `mapstructure:"PreExecution"`
+}
+
+// PreExecutionCfg contains the configuration properties for the pre-execution of the txs
+type PreExecutionCfg struct {
+	// Async enables accepting the txs once they pass the validations that don't require
+	// executing them. The txs are stored as queued and pre-executed in background, becoming
+	// pending or failed accordingly to the result of the pre-execution
+	Async bool `mapstructure:"Async"`
+
+	// Workers is the number of txs pre-executed concurrently in async mode
+	Workers int `mapstructure:"Workers"`
+
+	// QueueSize is the max number of accepted txs waiting to be pre-executed in async mode
+	QueueSize int `mapstructure:"QueueSize"`
+
+	// RejectWhenFull rejects the new txs with ErrPreExecutionQueueFull when the queue is full,
+	// otherwise the new txs are pre-executed synchronously until the queue has room again
+	RejectWhenFull bool `mapstructure:"RejectWhenFull"`
+
+	// ClaimTimeout is the time a queued tx stays claimed by the node that queued it. Once
+	// expired, the tx is claimed again by any node pre-executing txs, so the txs queued by
+	// a node that stopped are not lost. It must be greater than the time a tx waits in the queue
+	ClaimTimeout types.Duration `mapstructure:"ClaimTimeout"`
+}
+
+// PolicyFeedsCfg contains the configuration properties for the policy feeds
+type PolicyFeedsCfg struct {
+	// IntervalToRefresh is the time to wait between two loads of the policy feeds
+	IntervalToRefresh types.Duration `mapstructure:"IntervalToRefresh"`
+
+	// HTTPTimeout is the max time to wait for a feed served over HTTP
+	HTTPTimeout types.Duration `mapstructure:"HTTPTimeout"`
+
+	// Feeds is the list of sources to load the policy address lists from
+	Feeds []PolicyFeedCfg `mapstructure:"Feeds"`
+}
+
+// PolicyFeedCfg maps a source of addresses to a policy
+type PolicyFeedCfg struct {
+	// Source is a local file path or an http(s) URL serving the list of addresses.
+	// Addresses can be separated by commas or new lines, lines starting with # are ignored
+	Source string `mapstructure:"Source"`
+
+	// Policy is the name of the policy the addresses are applied to (send_tx or deploy)
+	Policy PolicyName `mapstructure:"Policy"`
+
+	// Action is the rule applied to the listed addresses (allow or deny).
+	// Feeds sharing the same policy must have the same action
+	Action string `mapstructure:"Action"`
+
+	// AllowEmpty allows the feed to serve no addresses and clear them from the
+	// policy, otherwise an empty feed is considered a failure to load it
+	AllowEmpty bool `mapstructure:"AllowEmpty"`
 }
 
 // EffectiveGasPriceCfg contains the configuration properties for the effective gas price
@@ -85,4 +85,8 @@ var (
 
 	// ErrSenderDisallowedDeploy is returned when deploy transactions are disallowed by policy
 	ErrSenderDisallowedDeploy = errors.New("sender disallowed deploy by policy")
+
+	// ErrPreExecutionQueueFull is returned when the pool runs the pre-execution of the
+	// txs asynchronously and there are too many txs waiting to be pre-executed
+	ErrPreExecutionQueueFull = errors.New("pool is busy, too many txs waiting to be pre-executed, try again later")
 )
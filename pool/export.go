@@ -0,0 +1,197 @@
+package pool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ExportFormat is the encoding used to export pool txs
+type ExportFormat string
+
+const (
+	// ExportFormatJSON exports one JSON object per line
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatRLP exports a stream of RLP encoded txs
+	ExportFormatRLP ExportFormat = "rlp"
+)
+
+var (
+	// ErrUnknownExportFormat is returned when the export format is not supported
+	ErrUnknownExportFormat = errors.New("unknown export format")
+	// ErrUnexportableTxStatus is returned when the status of an exported tx is neither pending nor queued
+	ErrUnexportableTxStatus = errors.New("only pending and queued txs can be exported")
+)
+
+// IsExportFormat tests if a string represents a supported ExportFormat
+func IsExportFormat(format string) bool {
+	return format == string(ExportFormatJSON) || format == string(ExportFormatRLP)
+}
+
+// ExportedTx is the representation of a pool tx used to migrate it between pools
+type ExportedTx struct {
+	Hash       common.Hash      `json:"hash"`
+	RawTx      hexutil.Bytes    `json:"rawTx"`
+	IP         string           `json:"ip"`
+	ReceivedAt time.Time        `json:"receivedAt"`
+	ZKCounters state.ZKCounters `json:"zkCounters"`
+	Status     TxStatus         `json:"status,omitempty"`
+}
+
+// rlpExportedTx is the RLP representation of an ExportedTx
+type rlpExportedTx struct {
+	RawTx      []byte
+	IP         string
+	ReceivedAt uint64
+	ZKCounters state.ZKCounters
+	Status     string `rlp:"optional"`
+}
+
+// NewExportedTx creates the exported representation of a pool tx
+func NewExportedTx(tx Transaction) (ExportedTx, error) {
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return ExportedTx{}, err
+	}
+	return ExportedTx{
+		Hash:       tx.Hash(),
+		RawTx:      rawTx,
+		IP:         tx.IP,
+		ReceivedAt: tx.ReceivedAt.UTC(),
+		ZKCounters: tx.ZKCounters,
+		Status:     tx.Status,
+	}, nil
+}
+
+// Transaction returns the pool tx represented by the exported tx. The txs
+// exported without a status are considered pending
+func (e ExportedTx) Transaction() (Transaction, error) {
+	status := e.Status
+	if status == "" {
+		status = TxStatusPending
+	}
+	if status != TxStatusPending && status != TxStatusQueued {
+		return Transaction{}, ErrUnexportableTxStatus
+	}
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(e.RawTx); err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{
+		Transaction: tx,
+		Status:      status,
+		ZKCounters:  e.ZKCounters,
+		ReceivedAt:  e.ReceivedAt,
+		IP:          e.IP,
+	}, nil
+}
+
+// WriteExportedTxs writes the pool txs to w using the provided format
+func WriteExportedTxs(w io.Writer, format ExportFormat, txs []Transaction) error {
+	if !IsExportFormat(string(format)) {
+		return ErrUnknownExportFormat
+	}
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, tx := range txs {
+		exportedTx, err := NewExportedTx(tx)
+		if err != nil {
+			return err
+		}
+		switch format {
+		case ExportFormatJSON:
+			err = encoder.Encode(exportedTx)
+		case ExportFormatRLP:
+			err = rlp.Encode(bw, rlpExportedTx{
+				RawTx:      exportedTx.RawTx,
+				IP:         exportedTx.IP,
+				ReceivedAt: uint64(exportedTx.ReceivedAt.UnixNano()),
+				ZKCounters: exportedTx.ZKCounters,
+				Status:     string(exportedTx.Status),
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadExportedTxs reads the txs written by WriteExportedTxs from r and calls
+// fn for each of them, stopping at the first error returned by fn
+func ReadExportedTxs(r io.Reader, format ExportFormat, fn func(ExportedTx) error) error {
+	switch format {
+	case ExportFormatJSON:
+		decoder := json.NewDecoder(bufio.NewReader(r))
+		for {
+			var exportedTx ExportedTx
+			err := decoder.Decode(&exportedTx)
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if err = fn(exportedTx); err != nil {
+				return err
+			}
+		}
+	case ExportFormatRLP:
+		stream := rlp.NewStream(bufio.NewReader(r), 0)
+		for {
+			var decoded rlpExportedTx
+			err := stream.Decode(&decoded)
+			if errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			exportedTx := ExportedTx{
+				RawTx:      decoded.RawTx,
+				IP:         decoded.IP,
+				ReceivedAt: time.Unix(0, int64(decoded.ReceivedAt)).UTC(),
+				ZKCounters: decoded.ZKCounters,
+				Status:     TxStatus(decoded.Status),
+			}
+			// txs that can't be decoded are reported by ImportTx
+			var tx types.Transaction
+			if tx.UnmarshalBinary(decoded.RawTx) == nil {
+				exportedTx.Hash = tx.Hash()
+			}
+			if err = fn(exportedTx); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrUnknownExportFormat
+	}
+}
+
+// ImportTx validates an exported tx against the current state and adds it to
+// the pool keeping its IP, received time and zkCounters. The queued txs are
+// kept queued to be pre-executed by StartPreExecutingTxs when the pre-execution
+// is async, otherwise they are pre-executed before storing them as pending
+func (p *Pool) ImportTx(ctx context.Context, exportedTx ExportedTx) error {
+	poolTx, err := exportedTx.Transaction()
+	if errors.Is(err, ErrUnexportableTxStatus) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("failed to decode tx: %w", err)
+	}
+	if err := p.validateTx(ctx, poolTx); err != nil {
+		return err
+	}
+	if poolTx.Status == TxStatusQueued && !p.cfg.PreExecution.Async {
+		return p.StoreTx(ctx, poolTx.Transaction, poolTx.IP, false)
+	}
+	return p.storage.AddTx(ctx, poolTx)
+}
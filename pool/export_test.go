@@ -0,0 +1,87 @@
+package pool
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportedTxsRoundTrip(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.NewEIP155Signer(big.NewInt(1000))
+
+	to := common.HexToAddress("0x1")
+	txs := []Transaction{}
+	for i := 0; i < 4; i++ {
+		tx := types.NewTransaction(uint64(i), to, big.NewInt(int64(i)), 21000, big.NewInt(1000000000), []byte{byte(i)})
+		signedTx, err := types.SignTx(tx, signer, privateKey)
+		require.NoError(t, err)
+		poolTx := NewTransaction(*signedTx, "127.0.0.1", i == 0)
+		poolTx.ReceivedAt = time.Date(2023, 10, 1, 12, 0, i, 123456789, time.UTC)
+		poolTx.ZKCounters = state.ZKCounters{GasUsed: 21000, UsedSteps: uint32(100 + i), UsedKeccakHashes: 2}
+		if i == 3 {
+			poolTx.Status = TxStatusQueued
+			poolTx.ZKCounters = state.ZKCounters{}
+		}
+		txs = append(txs, *poolTx)
+	}
+
+	for _, format := range []ExportFormat{ExportFormatJSON, ExportFormatRLP} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteExportedTxs(&buf, format, txs))
+
+			imported := []Transaction{}
+			err := ReadExportedTxs(&buf, format, func(exportedTx ExportedTx) error {
+				poolTx, err := exportedTx.Transaction()
+				require.NoError(t, err)
+				assert.Equal(t, poolTx.Hash(), exportedTx.Hash)
+				imported = append(imported, poolTx)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, imported, len(txs))
+
+			for i, tx := range txs {
+				assert.Equal(t, tx.Hash(), imported[i].Hash())
+				assert.Equal(t, tx.IP, imported[i].IP)
+				assert.True(t, tx.ReceivedAt.Equal(imported[i].ReceivedAt))
+				assert.Equal(t, tx.ZKCounters, imported[i].ZKCounters)
+				assert.Equal(t, tx.Status, imported[i].Status)
+				assert.False(t, imported[i].IsWIP)
+			}
+		})
+	}
+}
+
+func TestExportedTxWithoutStatus(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1000000000), nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(1000)), privateKey)
+	require.NoError(t, err)
+	rawTx, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+
+	poolTx, err := ExportedTx{RawTx: rawTx}.Transaction()
+	require.NoError(t, err)
+	assert.Equal(t, TxStatusPending, poolTx.Status)
+
+	_, err = ExportedTx{RawTx: rawTx, Status: TxStatusFailed}.Transaction()
+	require.ErrorIs(t, err, ErrUnexportableTxStatus)
+}
+
+func TestExportedTxsUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	require.ErrorIs(t, WriteExportedTxs(&buf, "xml", []Transaction{{}}), ErrUnknownExportFormat)
+	require.ErrorIs(t, ReadExportedTxs(&buf, "xml", func(ExportedTx) error { return nil }), ErrUnknownExportFormat)
+}
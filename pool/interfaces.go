@@ -21,6 +21,8 @@ type storage interface {
 	GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error)
 	GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]Transaction, error)
 	GetTxsByStatus(ctx context.Context, state TxStatus, limit uint64) ([]Transaction, error)
+	ClaimQueuedTxs(ctx context.Context, claimTimeout time.Duration, limit uint64) ([]Transaction, error)
+	UpdateQueuedTxToPending(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) (bool, error)
 	GetNonWIPPendingTxs(ctx context.Context) ([]Transaction, error)
 	IsTxPending(ctx context.Context, hash common.Hash) (bool, error)
 	SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error
@@ -40,6 +42,7 @@ type storage interface {
 	MinL2GasPriceSince(ctx context.Context, timestamp time.Time) (uint64, error)
 	policy
 	GetEarliestProcessedTx(ctx context.Context) (common.Hash, error)
+	SubscribeTxEvents(ctx context.Context) (<-chan TxEvent, error)
 }
 
 type stateInterface interface {
@@ -57,4 +60,5 @@ type policy interface {
 	DescribePolicies(ctx context.Context) ([]Policy, error)
 	DescribePolicy(ctx context.Context, name PolicyName) (Policy, error)
 	ListAcl(ctx context.Context, policy PolicyName, query []common.Address) ([]common.Address, error)
+	ReplacePolicyAcl(ctx context.Context, policy PolicyName, allow bool, addresses []common.Address) (PolicyDiff, error)
 }
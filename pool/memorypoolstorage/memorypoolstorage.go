@@ -0,0 +1,598 @@
+package memorypoolstorage
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// storedTx is a pool tx kept in memory with the extra data stored by the pool
+type storedTx struct {
+	pool.Transaction
+	from common.Address
+	// seq is the order of insertion, used to sort txs with the same sorting key
+	seq uint64
+	// claimedAt is the time a queued tx was claimed to be pre-executed
+	claimedAt time.Time
+}
+
+// gasPrice is an entry of the gas prices history
+type gasPrice struct {
+	l2GasPrice uint64
+	l1GasPrice uint64
+	timestamp  time.Time
+}
+
+// MemoryPoolStorage is an implementation of the pool storage that keeps
+// the data in memory. It's intended for tests and local environments
+// where running a postgres database is not desired
+type MemoryPoolStorage struct {
+	mu        sync.RWMutex
+	txs       map[common.Hash]*storedTx
+	nextSeq   uint64
+	gasPrices []gasPrice
+	blocked   map[common.Address]struct{}
+	policies  map[pool.PolicyName]bool
+	acl       map[pool.PolicyName]map[common.Address]struct{}
+	listeners []chan pool.TxEvent
+}
+
+// txEventsBufferSize is the size of the channels returned by SubscribeTxEvents
+const txEventsBufferSize = 1000
+
+// NewMemoryPoolStorage creates and initializes an instance of MemoryPoolStorage
+// with the same default policies created by the pool db migrations
+func NewMemoryPoolStorage() *MemoryPoolStorage {
+	return &MemoryPoolStorage{
+		txs:     make(map[common.Hash]*storedTx),
+		blocked: make(map[common.Address]struct{}),
+		policies: map[pool.PolicyName]bool{
+			pool.SendTx: false,
+			pool.Deploy: false,
+		},
+		acl: map[pool.PolicyName]map[common.Address]struct{}{
+			pool.SendTx: {},
+			pool.Deploy: {},
+		},
+	}
+}
+
+// AddTx adds a transaction to the pool with the provided status, replacing
+// the stored one if a transaction with the same hash already exists
+func (m *MemoryPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) error {
+	from, err := state.GetSender(tx.Transaction)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seq := m.nextSeq
+	stored, found := m.txs[tx.Hash()]
+	if found {
+		seq = stored.seq
+	} else {
+		m.nextSeq++
+	}
+	if !found || stored.Status != tx.Status {
+		m.notifyTxEvent(pool.TxEvent{Hash: tx.Hash(), Status: tx.Status})
+	}
+	tx.FailedReason = nil
+	tx.PreprocessedStateRoot = common.Hash{}
+	stored = &storedTx{Transaction: tx, from: from, seq: seq}
+	// the queued txs are claimed by the node adding them, that pre-executes them
+	if tx.Status == pool.TxStatusQueued {
+		stored.claimedAt = time.Now()
+	}
+	m.txs[tx.Hash()] = stored
+	return nil
+}
+
+// SubscribeTxEvents returns a channel receiving an event each time a tx is added to the
+// pool or its status changes. The events are dropped if the channel is full. The channel
+// is closed when the context is done
+func (m *MemoryPoolStorage) SubscribeTxEvents(ctx context.Context) (<-chan pool.TxEvent, error) {
+	events := make(chan pool.TxEvent, txEventsBufferSize)
+
+	m.mu.Lock()
+	m.listeners = append(m.listeners, events)
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, listener := range m.listeners {
+			if listener == events {
+				m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+				break
+			}
+		}
+		close(events)
+	}()
+	return events, nil
+}
+
+// notifyTxEvent sends the event to the subscribed listeners, the caller must hold the lock
+func (m *MemoryPoolStorage) notifyTxEvent(event pool.TxEvent) {
+	for _, listener := range m.listeners {
+		select {
+		case listener <- event:
+		default:
+		}
+	}
+}
+
+// sortedTxs returns the stored txs matching the filter sorted by less, the
+// order of insertion is used to sort the txs that are equal for less
+func (m *MemoryPoolStorage) sortedTxs(filter func(tx *storedTx) bool, less func(a, b *storedTx) bool) []*storedTx {
+	txs := make([]*storedTx, 0, len(m.txs))
+	for _, tx := range m.txs {
+		if filter(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if less != nil {
+			if less(txs[i], txs[j]) {
+				return true
+			}
+			if less(txs[j], txs[i]) {
+				return false
+			}
+		}
+		return txs[i].seq < txs[j].seq
+	})
+	return txs
+}
+
+// GetTxsByStatus returns an array of transactions filtered by status
+// limit parameter is used to limit amount txs from the db,
+// if limit = 0, then there is no limit
+func (m *MemoryPoolStorage) GetTxsByStatus(ctx context.Context, status pool.TxStatus, limit uint64) ([]pool.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.Status == status
+	}, func(a, b *storedTx) bool {
+		return a.GasPrice().Cmp(b.GasPrice()) > 0
+	})
+	if limit > 0 && uint64(len(sorted)) > limit {
+		sorted = sorted[:limit]
+	}
+
+	txs := make([]pool.Transaction, 0, len(sorted))
+	for _, tx := range sorted {
+		txs = append(txs, tx.Transaction)
+	}
+	return txs, nil
+}
+
+// ClaimQueuedTxs claims up to limit queued txs that are not claimed or whose claim
+// is older than claimTimeout, so they are pre-executed by a single node
+func (m *MemoryPoolStorage) ClaimQueuedTxs(ctx context.Context, claimTimeout time.Duration, limit uint64) ([]pool.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.Status == pool.TxStatusQueued && now.Sub(tx.claimedAt) > claimTimeout
+	}, func(a, b *storedTx) bool {
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	})
+	if uint64(len(sorted)) > limit {
+		sorted = sorted[:limit]
+	}
+
+	txs := make([]pool.Transaction, 0, len(sorted))
+	for _, tx := range sorted {
+		tx.claimedAt = now
+		txs = append(txs, tx.Transaction)
+	}
+	return txs, nil
+}
+
+// UpdateQueuedTxToPending stores a pre-executed queued tx as pending with its zkCounters.
+// It returns false without changes if the tx is not queued anymore, because it was
+// replaced, deleted or its status changed while it was being pre-executed
+func (m *MemoryPoolStorage) UpdateQueuedTxToPending(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, found := m.txs[hash]
+	if !found || tx.Status != pool.TxStatusQueued {
+		return false, nil
+	}
+	m.notifyTxEvent(pool.TxEvent{Hash: hash, Status: pool.TxStatusPending})
+	tx.Status = pool.TxStatusPending
+	tx.ZKCounters = zkCounters
+	return true, nil
+}
+
+// GetNonWIPPendingTxs returns an array of transactions
+func (m *MemoryPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return !tx.IsWIP && tx.Status == pool.TxStatusPending
+	}, nil)
+
+	txs := make([]pool.Transaction, 0, len(sorted))
+	for _, tx := range sorted {
+		txs = append(txs, tx.Transaction)
+	}
+	return txs, nil
+}
+
+// GetPendingTxHashesSince returns the pending tx since the given time.
+func (m *MemoryPoolStorage) GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.Status == pool.TxStatusPending && !tx.ReceivedAt.Before(since)
+	}, nil)
+
+	hashes := make([]common.Hash, 0, len(sorted))
+	for _, tx := range sorted {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes, nil
+}
+
+// GetTxs gets txs with the lowest nonce
+func (m *MemoryPoolStorage) GetTxs(ctx context.Context, filterStatus pool.TxStatus, minGasPrice, limit uint64) ([]*pool.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	minGasPriceBigInt := new(big.Int).SetUint64(minGasPrice)
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.Status == filterStatus && tx.GasPrice().Cmp(minGasPriceBigInt) >= 0
+	}, func(a, b *storedTx) bool {
+		return a.Nonce() < b.Nonce()
+	})
+	if uint64(len(sorted)) > limit {
+		sorted = sorted[:limit]
+	}
+
+	txs := make([]*pool.Transaction, 0, len(sorted))
+	for _, tx := range sorted {
+		poolTx := tx.Transaction
+		poolTx.FailedReason = nil
+		txs = append(txs, &poolTx)
+	}
+	return txs, nil
+}
+
+// CountTransactionsByStatus get number of transactions
+// accordingly to the provided statuses
+func (m *MemoryPoolStorage) CountTransactionsByStatus(ctx context.Context, status ...pool.TxStatus) (uint64, error) {
+	return m.countTxs(func(tx *storedTx) bool {
+		return hasStatus(tx, status)
+	}), nil
+}
+
+// CountTransactionsByFromAndStatus get number of transactions
+// accordingly to the from address and provided statuses
+func (m *MemoryPoolStorage) CountTransactionsByFromAndStatus(ctx context.Context, from common.Address, status ...pool.TxStatus) (uint64, error) {
+	return m.countTxs(func(tx *storedTx) bool {
+		return tx.from == from && hasStatus(tx, status)
+	}), nil
+}
+
+func (m *MemoryPoolStorage) countTxs(filter func(tx *storedTx) bool) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var counter uint64
+	for _, tx := range m.txs {
+		if filter(tx) {
+			counter++
+		}
+	}
+	return counter
+}
+
+func hasStatus(tx *storedTx, status []pool.TxStatus) bool {
+	for _, s := range status {
+		if tx.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateTxStatus updates a transaction status accordingly to the
+// provided status and hash
+func (m *MemoryPoolStorage) UpdateTxStatus(ctx context.Context, updateInfo pool.TxStatusUpdateInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, found := m.txs[updateInfo.Hash]
+	if !found {
+		return nil
+	}
+	if tx.Status != updateInfo.NewStatus {
+		m.notifyTxEvent(pool.TxEvent{Hash: updateInfo.Hash, Status: updateInfo.NewStatus})
+	}
+	tx.Status = updateInfo.NewStatus
+	tx.IsWIP = updateInfo.IsWIP
+	if updateInfo.FailedReason != nil {
+		failedReason := *updateInfo.FailedReason
+		tx.FailedReason = &failedReason
+	}
+	return nil
+}
+
+// UpdateTxsStatus updates transactions status accordingly to the provided status and hashes
+func (m *MemoryPoolStorage) UpdateTxsStatus(ctx context.Context, updateInfos []pool.TxStatusUpdateInfo) error {
+	for _, updateInfo := range updateInfos {
+		if err := m.UpdateTxStatus(ctx, updateInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTransactionsByHashes deletes txs by their hashes
+func (m *MemoryPoolStorage) DeleteTransactionsByHashes(ctx context.Context, hashes []common.Hash) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, hash := range hashes {
+		delete(m.txs, hash)
+	}
+	return nil
+}
+
+// DeleteFailedTransactionsOlderThan deletes all failed transactions older than the given date
+func (m *MemoryPoolStorage) DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for hash, tx := range m.txs {
+		if tx.Status == pool.TxStatusFailed && tx.ReceivedAt.Before(date) {
+			delete(m.txs, hash)
+		}
+	}
+	return nil
+}
+
+// SetGasPrices sets the latest l2 and l1 gas prices
+func (m *MemoryPoolStorage) SetGasPrices(ctx context.Context, l2GasPrice, l1GasPrice uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gasPrices = append(m.gasPrices, gasPrice{
+		l2GasPrice: l2GasPrice,
+		l1GasPrice: l1GasPrice,
+		timestamp:  time.Now().UTC(),
+	})
+	return nil
+}
+
+// GetGasPrices returns the latest l2 and l1 gas prices
+func (m *MemoryPoolStorage) GetGasPrices(ctx context.Context) (uint64, uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.gasPrices) == 0 {
+		return 0, 0, nil
+	}
+	last := m.gasPrices[len(m.gasPrices)-1]
+	return last.l2GasPrice, last.l1GasPrice, nil
+}
+
+// DeleteGasPricesHistoryOlderThan deletes all gas prices older than the given date except the last one
+func (m *MemoryPoolStorage) DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.gasPrices) == 0 {
+		return nil
+	}
+	gasPrices := make([]gasPrice, 0, len(m.gasPrices))
+	for i, gp := range m.gasPrices {
+		if i == len(m.gasPrices)-1 || !gp.timestamp.Before(date) {
+			gasPrices = append(gasPrices, gp)
+		}
+	}
+	m.gasPrices = gasPrices
+	return nil
+}
+
+// MinL2GasPriceSince returns the min L2 gas price after given timestamp
+func (m *MemoryPoolStorage) MinL2GasPriceSince(ctx context.Context, timestamp time.Time) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var minGasPrice uint64
+	for _, gp := range m.gasPrices {
+		if gp.timestamp.Before(timestamp) {
+			continue
+		}
+		if minGasPrice == 0 || gp.l2GasPrice < minGasPrice {
+			minGasPrice = gp.l2GasPrice
+		}
+	}
+	if minGasPrice == 0 {
+		return 0, state.ErrNotFound
+	}
+	return minGasPrice, nil
+}
+
+// IsTxPending determines if the tx associated to the given hash is pending or
+// not.
+func (m *MemoryPoolStorage) IsTxPending(ctx context.Context, hash common.Hash) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tx, found := m.txs[hash]
+	return found && tx.Status == pool.TxStatusPending, nil
+}
+
+// GetTxsByFromAndNonce get all the transactions from the pool with the same from and nonce
+func (m *MemoryPoolStorage) GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]pool.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.from == from && tx.Nonce() == nonce
+	}, nil)
+
+	txs := make([]pool.Transaction, 0, len(sorted))
+	for _, tx := range sorted {
+		txs = append(txs, tx.Transaction)
+	}
+	return txs, nil
+}
+
+// GetTxFromAddressFromByHash gets tx from address by hash
+func (m *MemoryPoolStorage) GetTxFromAddressFromByHash(ctx context.Context, hash common.Hash) (common.Address, uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tx, found := m.txs[hash]
+	if !found {
+		return common.Address{}, 0, pool.ErrNotFound
+	}
+	return tx.from, tx.Nonce(), nil
+}
+
+// GetNonce gets the nonce to the provided address accordingly to the txs in the pool
+func (m *MemoryPoolStorage) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var nonce *uint64
+	for _, tx := range m.txs {
+		if tx.from != address || !hasStatus(tx, []pool.TxStatus{pool.TxStatusQueued, pool.TxStatusPending, pool.TxStatusSelected}) {
+			continue
+		}
+		if txNonce := tx.Nonce(); nonce == nil || txNonce > *nonce {
+			nonce = &txNonce
+		}
+	}
+	if nonce == nil {
+		return 0, nil
+	}
+	return *nonce + 1, nil
+}
+
+// GetTransactionByHash gets a transaction in the pool by its hash
+func (m *MemoryPoolStorage) GetTransactionByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tx, found := m.txs[hash]
+	if !found {
+		return nil, pool.ErrNotFound
+	}
+	return &pool.Transaction{
+		ReceivedAt:   tx.ReceivedAt,
+		Status:       tx.Status,
+		Transaction:  tx.Transaction.Transaction,
+		IsWIP:        tx.IsWIP,
+		IP:           tx.IP,
+		FailedReason: tx.FailedReason,
+	}, nil
+}
+
+// GetTransactionByL2Hash gets a transaction in the pool by its l2 hash.
+// The pool doesn't store the l2 hash of the txs, so ErrNotFound is always returned
+func (m *MemoryPoolStorage) GetTransactionByL2Hash(ctx context.Context, hash common.Hash) (*pool.Transaction, error) {
+	return nil, pool.ErrNotFound
+}
+
+// DeleteTransactionByHash deletes tx by its hash
+func (m *MemoryPoolStorage) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.txs, hash)
+	return nil
+}
+
+// GetTxZkCountersByHash gets a transaction zkcounters by its hash
+func (m *MemoryPoolStorage) GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tx, found := m.txs[hash]
+	if !found {
+		return nil, pool.ErrNotFound
+	}
+	zkCounters := tx.ZKCounters
+	return &zkCounters, nil
+}
+
+// MarkWIPTxsAsPending updates WIP status to non WIP
+func (m *MemoryPoolStorage) MarkWIPTxsAsPending(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tx := range m.txs {
+		tx.IsWIP = false
+	}
+	return nil
+}
+
+// UpdateTxWIPStatus updates a transaction wip status accordingly to the
+// provided WIP status and hash
+func (m *MemoryPoolStorage) UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isWIP bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tx, found := m.txs[hash]; found {
+		tx.IsWIP = isWIP
+	}
+	return nil
+}
+
+// AddBlockedAddresses adds addresses to the list of blocked addresses
+func (m *MemoryPoolStorage) AddBlockedAddresses(ctx context.Context, addresses []common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, a := range addresses {
+		m.blocked[a] = struct{}{}
+	}
+	return nil
+}
+
+// GetAllAddressesBlocked get all addresses blocked
+func (m *MemoryPoolStorage) GetAllAddressesBlocked(ctx context.Context) ([]common.Address, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var addrs []common.Address
+	for a := range m.blocked {
+		addrs = append(addrs, a)
+	}
+	return addrs, nil
+}
+
+// GetEarliestProcessedTx gets the earliest processed tx from the pool. Mainly used for cleanup
+func (m *MemoryPoolStorage) GetEarliestProcessedTx(ctx context.Context) (common.Hash, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sorted := m.sortedTxs(func(tx *storedTx) bool {
+		return tx.Status == pool.TxStatusSelected
+	}, func(a, b *storedTx) bool {
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	})
+	if len(sorted) == 0 {
+		return common.Hash{}, nil
+	}
+	return sorted[0].Hash(), nil
+}
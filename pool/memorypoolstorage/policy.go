@@ -0,0 +1,169 @@
+package memorypoolstorage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CheckPolicy returns the rule for the named policy and address. If the address is associated with the policy, the rule
+// will be the setting for the policy. If the address is no associated with the policy, the rule will be the opposite of
+// the policy setting.
+func (m *MemoryPoolStorage) CheckPolicy(ctx context.Context, policy pool.PolicyName, address common.Address) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	allow, found := m.policies[policy]
+	if !found {
+		return false, nil
+	}
+	if _, listed := m.acl[policy][address]; listed {
+		return allow, nil
+	}
+	return !allow, nil
+}
+
+// UpdatePolicy sets the allow/deny rule for the named policy
+func (m *MemoryPoolStorage) UpdatePolicy(ctx context.Context, policy pool.PolicyName, allow bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, found := m.policies[policy]; found {
+		m.policies[policy] = allow
+	}
+	return nil
+}
+
+// AddAddressesToPolicy adds addresses to the named policy
+func (m *MemoryPoolStorage) AddAddressesToPolicy(ctx context.Context, policy pool.PolicyName, addresses []common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.aclFor(policy)
+	for _, a := range addresses {
+		m.acl[policy][a] = struct{}{}
+	}
+	return nil
+}
+
+// RemoveAddressesFromPolicy removes addresses from the named policy
+func (m *MemoryPoolStorage) RemoveAddressesFromPolicy(ctx context.Context, policy pool.PolicyName, addresses []common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, a := range addresses {
+		delete(m.acl[policy], a)
+	}
+	return nil
+}
+
+// ClearPolicy removes _all_ addresses from the named policy
+func (m *MemoryPoolStorage) ClearPolicy(ctx context.Context, policy pool.PolicyName) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.acl, policy)
+	return nil
+}
+
+// DescribePolicies return all the policies
+func (m *MemoryPoolStorage) DescribePolicies(ctx context.Context) ([]pool.Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []pool.Policy
+	for _, name := range []pool.PolicyName{pool.SendTx, pool.Deploy} {
+		if allow, found := m.policies[name]; found {
+			list = append(list, pool.Policy{Name: name, Allow: allow})
+		}
+	}
+	return list, nil
+}
+
+// DescribePolicy returns the named policy
+func (m *MemoryPoolStorage) DescribePolicy(ctx context.Context, name pool.PolicyName) (pool.Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	allow, found := m.policies[name]
+	if !found {
+		return pool.Policy{}, pool.ErrNotFound
+	}
+	return pool.Policy{Name: name, Allow: allow}, nil
+}
+
+// ListAcl returns a list of the addresses associated with the policy
+func (m *MemoryPoolStorage) ListAcl(ctx context.Context, policy pool.PolicyName, query []common.Address) ([]common.Address, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var addresses []common.Address
+	if len(query) > 0 {
+		for _, a := range query {
+			if _, found := m.acl[policy][a]; found {
+				addresses = append(addresses, a)
+			}
+		}
+		return addresses, nil
+	}
+	for a := range m.acl[policy] {
+		addresses = append(addresses, a)
+	}
+	sortAddresses(addresses)
+	return addresses, nil
+}
+
+// ReplacePolicyAcl atomically sets the allow/deny rule of the named policy and
+// replaces its addresses with the provided ones, returning the applied changes
+func (m *MemoryPoolStorage) ReplacePolicyAcl(ctx context.Context, policy pool.PolicyName, allow bool, addresses []common.Address) (pool.PolicyDiff, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	diff := pool.PolicyDiff{Policy: policy, Allow: allow}
+	previousAllow, found := m.policies[policy]
+	if !found {
+		return diff, pool.ErrNotFound
+	}
+	diff.PreviousAllow = previousAllow
+	m.policies[policy] = allow
+
+	current := m.aclFor(policy)
+	wanted := make(map[common.Address]struct{}, len(addresses))
+	for _, a := range addresses {
+		if _, found := wanted[a]; found {
+			continue
+		}
+		wanted[a] = struct{}{}
+		if _, found := current[a]; !found {
+			diff.Added = append(diff.Added, a)
+		}
+	}
+	for a := range current {
+		if _, found := wanted[a]; !found {
+			diff.Removed = append(diff.Removed, a)
+		}
+	}
+	sortAddresses(diff.Removed)
+
+	m.acl[policy] = wanted
+	return diff, nil
+}
+
+// aclFor returns the addresses of the named policy, creating the set if needed
+func (m *MemoryPoolStorage) aclFor(policy pool.PolicyName) map[common.Address]struct{} {
+	acl, found := m.acl[policy]
+	if !found {
+		acl = make(map[common.Address]struct{})
+		m.acl[policy] = acl
+	}
+	return acl
+}
+
+func sortAddresses(addresses []common.Address) {
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+}
@@ -3,11 +3,13 @@ package pgpoolstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,6 +18,13 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const (
+	// txEventsChannel is the postgres channel notified by the pool.transaction trigger
+	txEventsChannel = "pool_transaction"
+	// txEventsBufferSize is the size of the channels returned by SubscribeTxEvents
+	txEventsBufferSize = 1000
+)
+
 // PostgresPoolStorage is an implementation of the Pool interface
 // that uses a postgres database to store the data
 type PostgresPoolStorage struct {
@@ -75,10 +84,11 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address,
 			is_wip,
 			ip,
-			failed_reason
+			failed_reason,
+			claimed_at
 		) 
 		VALUES 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, NULL)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, NULL, CASE WHEN $4::VARCHAR = $20::VARCHAR THEN NOW() END)
 			ON CONFLICT (hash) DO UPDATE SET 
 			encoded = $2,
 			decoded = $3,
@@ -98,7 +108,8 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address = $17,
 			is_wip = $18,
 			ip = $19,
-			failed_reason = NULL
+			failed_reason = NULL,
+			claimed_at = CASE WHEN $4::VARCHAR = $20::VARCHAR THEN NOW() END
 	`
 
 	// Get FromAddress from the JSON data
@@ -127,7 +138,9 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 		tx.ReceivedAt,
 		fromAddress,
 		tx.IsWIP,
-		tx.IP); err != nil {
+		tx.IP,
+		// the queued txs are claimed by the node adding them, that pre-executes them
+		pool.TxStatusQueued); err != nil {
 		return err
 	}
 	return nil
@@ -168,6 +181,53 @@ func (p *PostgresPoolStorage) GetTxsByStatus(ctx context.Context, status pool.Tx
 	return txs, nil
 }
 
+// ClaimQueuedTxs claims up to limit queued txs that are not claimed or whose claim
+// is older than claimTimeout, so they are pre-executed by a single node. The rows
+// being claimed by other nodes are skipped
+func (p *PostgresPoolStorage) ClaimQueuedTxs(ctx context.Context, claimTimeout time.Duration, limit uint64) ([]pool.Transaction, error) {
+	const sql = `UPDATE pool.transaction SET claimed_at = NOW()
+		WHERE hash IN (
+			SELECT hash FROM pool.transaction
+			 WHERE status = $1 AND (claimed_at IS NULL OR claimed_at < NOW() - $2::FLOAT8 * INTERVAL '1 second')
+			 ORDER BY received_at
+			 LIMIT $3
+			   FOR UPDATE SKIP LOCKED)
+		RETURNING encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
+			used_arithmetics, used_binaries, used_steps, used_sha256_hashes, failed_reason`
+	rows, err := p.db.Query(ctx, sql, pool.TxStatusQueued, claimTimeout.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make([]pool.Transaction, 0, limit)
+	for rows.Next() {
+		tx, err := scanTx(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, *tx)
+	}
+
+	return txs, rows.Err()
+}
+
+// UpdateQueuedTxToPending stores a pre-executed queued tx as pending with its zkCounters.
+// It returns false without changes if the tx is not queued anymore, because it was
+// replaced, deleted or its status changed while it was being pre-executed
+func (p *PostgresPoolStorage) UpdateQueuedTxToPending(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) (bool, error) {
+	const sql = `UPDATE pool.transaction SET status = $1, cumulative_gas_used = $2, used_keccak_hashes = $3, used_poseidon_hashes = $4,
+		used_poseidon_paddings = $5, used_mem_aligns = $6, used_arithmetics = $7, used_binaries = $8, used_steps = $9, used_sha256_hashes = $10
+		WHERE hash = $11 AND status = $12`
+	ct, err := p.db.Exec(ctx, sql, pool.TxStatusPending, zkCounters.GasUsed, zkCounters.UsedKeccakHashes, zkCounters.UsedPoseidonHashes,
+		zkCounters.UsedPoseidonPaddings, zkCounters.UsedMemAligns, zkCounters.UsedArithmetics, zkCounters.UsedBinaries, zkCounters.UsedSteps,
+		zkCounters.UsedSha256Hashes_V2, hash.Hex(), pool.TxStatusQueued)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
 // GetNonWIPPendingTxs returns an array of transactions
 func (p *PostgresPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
 	var (
@@ -559,8 +619,8 @@ func (p *PostgresPoolStorage) GetNonce(ctx context.Context, address common.Addre
 	sql := `SELECT MAX(nonce)
               FROM pool.transaction
              WHERE from_address = $1
-               AND status IN  ($2, $3)`
-	rows, err := p.db.Query(ctx, sql, address.String(), pool.TxStatusPending, pool.TxStatusSelected)
+               AND status IN  ($2, $3, $4)`
+	rows, err := p.db.Query(ctx, sql, address.String(), pool.TxStatusQueued, pool.TxStatusPending, pool.TxStatusSelected)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return 0, nil
 	} else if err != nil {
@@ -595,12 +655,13 @@ func (p *PostgresPoolStorage) GetTransactionByHash(ctx context.Context, hash com
 		encoded, status, ip string
 		receivedAt          time.Time
 		isWIP               bool
+		failedReason        *string
 	)
 
-	sql := `SELECT encoded, status, received_at, is_wip, ip
+	sql := `SELECT encoded, status, received_at, is_wip, ip, failed_reason
 	          FROM pool.transaction
 			 WHERE hash = $1`
-	err := p.db.QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip)
+	err := p.db.QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip, &failedReason)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, pool.ErrNotFound
 	} else if err != nil {
@@ -618,11 +679,12 @@ func (p *PostgresPoolStorage) GetTransactionByHash(ctx context.Context, hash com
 	}
 
 	poolTx := &pool.Transaction{
-		ReceivedAt:  receivedAt,
-		Status:      pool.TxStatus(status),
-		Transaction: *tx,
-		IsWIP:       isWIP,
-		IP:          ip,
+		ReceivedAt:   receivedAt,
+		Status:       pool.TxStatus(status),
+		Transaction:  *tx,
+		IsWIP:        isWIP,
+		IP:           ip,
+		FailedReason: failedReason,
 	}
 
 	return poolTx, nil
@@ -810,3 +872,50 @@ func (p *PostgresPoolStorage) GetEarliestProcessedTx(ctx context.Context) (commo
 
 	return common.HexToHash(txnHash), nil
 }
+
+// SubscribeTxEvents returns a channel receiving an event each time a tx is added to the pool or
+// its status changes, as notified by postgres. The events are dropped if the channel is full.
+// The channel is closed when the context is done or the listening connection is lost
+func (p *PostgresPoolStorage) SubscribeTxEvents(ctx context.Context) (<-chan pool.TxEvent, error) {
+	poolConn, err := p.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the connection keeps listening until it's closed, so it's not returned to the pool
+	conn := poolConn.Hijack()
+	if _, err := conn.Exec(ctx, "LISTEN "+txEventsChannel); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+
+	events := make(chan pool.TxEvent, txEventsBufferSize)
+	go func() {
+		defer close(events)
+		defer func(conn *pgx.Conn) {
+			_ = conn.Close(context.Background())
+		}(conn)
+
+		for {
+			notification, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("error waiting for pool tx notifications: %v", err)
+				}
+				return
+			}
+
+			var event pool.TxEvent
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				log.Errorf("error decoding pool tx notification %s: %v", notification.Payload, err)
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+				log.Debugf("pool tx event dropped, channel is full: %s", event.Hash.String())
+			}
+		}
+	}()
+
+	return events, nil
+}
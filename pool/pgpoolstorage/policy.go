@@ -1,9 +1,11 @@
 package pgpoolstorage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/pool"
@@ -200,3 +202,88 @@ func (p *PostgresPoolStorage) ListAcl(
 	}
 	return addresses, nil
 }
+
+// ReplacePolicyAcl atomically sets the allow/deny rule of the named policy and
+// replaces its addresses with the provided ones, returning the applied changes
+func (p *PostgresPoolStorage) ReplacePolicyAcl(ctx context.Context, policy pool.PolicyName, allow bool, addresses []common.Address) (pool.PolicyDiff, error) {
+	diff := pool.PolicyDiff{Policy: policy, Allow: allow}
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return diff, err
+	}
+	defer func(tx pgx.Tx, ctx context.Context) {
+		_ = tx.Rollback(ctx)
+	}(tx, ctx)
+
+	const getPolicySQL = "SELECT allow FROM pool.policy WHERE name = $1 FOR UPDATE"
+	err = tx.QueryRow(ctx, getPolicySQL, string(policy)).Scan(&diff.PreviousAllow)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return diff, pool.ErrNotFound
+	} else if err != nil {
+		return diff, err
+	}
+
+	if diff.PreviousAllow != allow {
+		const updatePolicySQL = "UPDATE pool.policy SET allow = $1 WHERE name = $2"
+		if _, err = tx.Exec(ctx, updatePolicySQL, allow, string(policy)); err != nil {
+			return diff, err
+		}
+	}
+
+	const getAclSQL = "SELECT address FROM pool.acl WHERE policy = $1"
+	rows, err := tx.Query(ctx, getAclSQL, string(policy))
+	if err != nil {
+		return diff, err
+	}
+	current := make(map[common.Address]struct{})
+	for rows.Next() {
+		var addr string
+		if err = rows.Scan(&addr); err != nil {
+			rows.Close()
+			return diff, err
+		}
+		current[common.HexToAddress(addr)] = struct{}{}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return diff, err
+	}
+
+	wanted := make(map[common.Address]struct{}, len(addresses))
+	for _, a := range addresses {
+		if _, found := wanted[a]; found {
+			continue
+		}
+		wanted[a] = struct{}{}
+		if _, found := current[a]; !found {
+			diff.Added = append(diff.Added, a)
+		}
+	}
+	for a := range current {
+		if _, found := wanted[a]; !found {
+			diff.Removed = append(diff.Removed, a)
+		}
+	}
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		return bytes.Compare(diff.Removed[i].Bytes(), diff.Removed[j].Bytes()) < 0
+	})
+
+	const removeSQL = "DELETE FROM pool.acl WHERE policy = $1 AND address = $2"
+	for _, a := range diff.Removed {
+		if _, err = tx.Exec(ctx, removeSQL, string(policy), a.Hex()); err != nil {
+			return diff, err
+		}
+	}
+	const addSQL = "INSERT INTO pool.acl (policy, address) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	for _, a := range diff.Added {
+		if _, err = tx.Exec(ctx, addSQL, string(policy), a.Hex()); err != nil {
+			return diff, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return diff, err
+	}
+	return diff, nil
+}
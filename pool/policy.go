@@ -41,3 +41,17 @@ func IsPolicy(name string) bool {
 	}
 	return false
 }
+
+// PolicyDiff describes the changes applied to a policy when its acl is replaced
+type PolicyDiff struct {
+	Policy        PolicyName       `json:"policy"`
+	PreviousAllow bool             `json:"previousAllow"`
+	Allow         bool             `json:"allow"`
+	Added         []common.Address `json:"added"`
+	Removed       []common.Address `json:"removed"`
+}
+
+// IsEmpty returns true if the diff doesn't contain any change
+func (d *PolicyDiff) IsEmpty() bool {
+	return d.PreviousAllow == d.Allow && len(d.Added) == 0 && len(d.Removed) == 0
+}
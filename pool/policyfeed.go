@@ -0,0 +1,272 @@
+package pool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	policyFeedActionAllow = "allow"
+	policyFeedActionDeny  = "deny"
+
+	// maxPolicyFeedBytesSize is the max size of the content served by a policy feed
+	maxPolicyFeedBytesSize = 64 * 1024 * 1024
+)
+
+// policyFeed loads the addresses of a policy from a local file or an HTTP URL
+type policyFeed struct {
+	cfg    PolicyFeedCfg
+	client *http.Client
+
+	// etag and addresses keep the last content served over HTTP, so it
+	// is not downloaded again while the ETag doesn't change
+	etag      string
+	addresses []common.Address
+}
+
+// newPolicyFeeds creates the policy feeds for the provided configuration
+func newPolicyFeeds(cfg PolicyFeedsCfg) ([]*policyFeed, error) {
+	client := &http.Client{Timeout: cfg.HTTPTimeout.Duration}
+	actions := make(map[PolicyName]string)
+	feeds := make([]*policyFeed, 0, len(cfg.Feeds))
+	for _, feedCfg := range cfg.Feeds {
+		if feedCfg.Source == "" {
+			return nil, fmt.Errorf("policy feed without source")
+		}
+		if !IsPolicy(string(feedCfg.Policy)) {
+			return nil, fmt.Errorf("invalid policy name %q for policy feed %s", feedCfg.Policy, feedCfg.Source)
+		}
+		if feedCfg.Action != policyFeedActionAllow && feedCfg.Action != policyFeedActionDeny {
+			return nil, fmt.Errorf("invalid action %q for policy feed %s, supported actions are %s and %s",
+				feedCfg.Action, feedCfg.Source, policyFeedActionAllow, policyFeedActionDeny)
+		}
+		if action, found := actions[feedCfg.Policy]; found && action != feedCfg.Action {
+			return nil, fmt.Errorf("policy feeds for policy %s have different actions", feedCfg.Policy)
+		}
+		actions[feedCfg.Policy] = feedCfg.Action
+		feeds = append(feeds, &policyFeed{cfg: feedCfg, client: client})
+	}
+	return feeds, nil
+}
+
+// isHTTP returns true if the source of the feed is an http(s) URL
+func (f *policyFeed) isHTTP() bool {
+	u, err := url.Parse(f.cfg.Source)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// load returns the addresses currently served by the feed source
+func (f *policyFeed) load(ctx context.Context) ([]common.Address, error) {
+	if !f.isHTTP() {
+		fd, err := os.Open(f.cfg.Source)
+		if err != nil {
+			return nil, err
+		}
+		defer func(fd *os.File) {
+			_ = fd.Close()
+		}(fd)
+		return f.parse(fd)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.cfg.Source, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(res.Body)
+
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		if f.etag == "" {
+			return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+		}
+		return f.addresses, nil
+	case http.StatusOK:
+		addresses, err := f.parse(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		f.etag = res.Header.Get("ETag")
+		f.addresses = addresses
+		return addresses, nil
+	default:
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+}
+
+// parse reads the addresses served by the feed source. A feed bigger than the max
+// size is rejected instead of being cut, and an empty feed is rejected unless the
+// feed is configured to allow it, so a truncated or empty source can't wipe a policy
+func (f *policyFeed) parse(r io.Reader) ([]common.Address, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxPolicyFeedBytesSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxPolicyFeedBytesSize {
+		return nil, fmt.Errorf("policy feed exceeds the max size of %d bytes", maxPolicyFeedBytesSize)
+	}
+	addresses, err := parsePolicyFeed(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 && !f.cfg.AllowEmpty {
+		return nil, fmt.Errorf("policy feed has no addresses, set AllowEmpty to allow clearing the policy")
+	}
+	return addresses, nil
+}
+
+// parsePolicyFeed reads the addresses of a policy feed. Addresses can be separated
+// by commas or new lines, empty lines and lines starting with # are ignored. An
+// invalid address fails the whole feed, so a corrupted source can't wipe a policy
+func parsePolicyFeed(r io.Reader) ([]common.Address, error) {
+	var addresses []common.Address
+	scanner := bufio.NewScanner(r)
+	// a feed can be a single line of comma separated addresses
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxPolicyFeedBytesSize+1)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, cell := range strings.Split(line, ",") {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			if !common.IsHexAddress(cell) {
+				return nil, fmt.Errorf("invalid address %q at line %d", cell, lineNumber)
+			}
+			addresses = append(addresses, common.HexToAddress(cell))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// StartRefreshingPolicyFeedsPeriodically will make this instance of the pool
+// to load periodically(accordingly to the configuration) the policy feeds and
+// apply the loaded addresses to their policies
+func (p *Pool) StartRefreshingPolicyFeedsPeriodically(ctx context.Context) error {
+	if len(p.cfg.PolicyFeeds.Feeds) == 0 {
+		return nil
+	}
+	if p.cfg.PolicyFeeds.IntervalToRefresh.Duration <= 0 {
+		return fmt.Errorf("invalid policy feeds refresh interval %v, it must be greater than zero", p.cfg.PolicyFeeds.IntervalToRefresh.Duration)
+	}
+	feeds, err := newPolicyFeeds(p.cfg.PolicyFeeds)
+	if err != nil {
+		return err
+	}
+
+	p.refreshPolicyFeeds(ctx, feeds)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.cfg.PolicyFeeds.IntervalToRefresh.Duration):
+				p.refreshPolicyFeeds(ctx, feeds)
+			}
+		}
+	}()
+	return nil
+}
+
+// refreshPolicyFeeds loads the policy feeds and replaces the addresses of each policy
+// with the ones of its feeds. A policy is left untouched if any of its feeds fails
+func (p *Pool) refreshPolicyFeeds(ctx context.Context, feeds []*policyFeed) {
+	type policyUpdate struct {
+		allow     bool
+		addresses []common.Address
+		failed    bool
+	}
+
+	policies := []PolicyName{}
+	updates := make(map[PolicyName]*policyUpdate)
+	for _, feed := range feeds {
+		update, found := updates[feed.cfg.Policy]
+		if !found {
+			update = &policyUpdate{allow: feed.cfg.Action == policyFeedActionAllow}
+			updates[feed.cfg.Policy] = update
+			policies = append(policies, feed.cfg.Policy)
+		}
+		if update.failed {
+			continue
+		}
+		addresses, err := feed.load(ctx)
+		if err != nil {
+			log.Errorf("failed to load policy feed %s: %v", feed.cfg.Source, err)
+			update.failed = true
+			continue
+		}
+		update.addresses = append(update.addresses, addresses...)
+	}
+
+	for _, policy := range policies {
+		update := updates[policy]
+		if update.failed {
+			log.Warnf("policy %s not updated because some of its feeds failed to load", policy)
+			continue
+		}
+		diff, err := p.storage.ReplacePolicyAcl(ctx, policy, update.allow, update.addresses)
+		if err != nil {
+			log.Errorf("failed to apply policy feeds to policy %s: %v", policy, err)
+			continue
+		}
+		if diff.IsEmpty() {
+			continue
+		}
+		log.Infof("policy %s updated from feeds, allow: %t, added addresses: %d, removed addresses: %d",
+			policy, diff.Allow, len(diff.Added), len(diff.Removed))
+		p.logPolicyDiff(ctx, diff)
+	}
+}
+
+// logPolicyDiff stores the changes applied to a policy in the event log
+func (p *Pool) logPolicyDiff(ctx context.Context, diff PolicyDiff) {
+	payload, err := json.Marshal(diff)
+	if err != nil {
+		log.Errorf("error marshaling policy diff: %v", err)
+		return
+	}
+	event := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Pool,
+		Level:       event.Level_Notice,
+		EventID:     event.EventID_PolicyFeedUpdated,
+		Description: fmt.Sprintf("policy %s: %d added, %d removed", diff.Policy, len(diff.Added), len(diff.Removed)),
+		Json:        string(payload),
+	}
+	if err := p.eventLog.LogEvent(ctx, event); err != nil {
+		log.Errorf("error adding event: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package pool
+
+import (
+	"bufio"
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	feedAddress1 = "0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D"
+	feedAddress2 = "0x9e5c8b8d2eE3B5bB0e5fA8E61b1F73E4eB2F4a4F"
+	feedAddress3 = "0x1a2B3c4D5e6F708192a3B4c5D6e7F8091A2b3C4d"
+)
+
+func TestParsePolicyFeed(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		content       string
+		expected      []common.Address
+		expectedError bool
+	}{
+		{
+			desc:     "empty feed",
+			content:  "",
+			expected: nil,
+		},
+		{
+			desc:    "one address per line with comments",
+			content: "# sanctioned\n" + feedAddress1 + "\n\n  " + feedAddress2 + "  \n",
+			expected: []common.Address{
+				common.HexToAddress(feedAddress1),
+				common.HexToAddress(feedAddress2),
+			},
+		},
+		{
+			desc:    "csv rows",
+			content: feedAddress1 + "," + feedAddress2 + ",\n" + feedAddress3,
+			expected: []common.Address{
+				common.HexToAddress(feedAddress1),
+				common.HexToAddress(feedAddress2),
+				common.HexToAddress(feedAddress3),
+			},
+		},
+		{
+			desc:          "invalid address",
+			content:       feedAddress1 + "\n<html>not found</html>\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			addresses, err := parsePolicyFeed(strings.NewReader(tc.content))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, addresses)
+		})
+	}
+}
+
+func TestParsePolicyFeedLongLine(t *testing.T) {
+	const addressesCount = 5000
+	cells := make([]string, 0, addressesCount)
+	for i := 0; i < addressesCount; i++ {
+		cells = append(cells, common.BigToAddress(big.NewInt(int64(i+1))).Hex())
+	}
+	content := strings.Join(cells, ",")
+	require.Greater(t, len(content), bufio.MaxScanTokenSize)
+
+	addresses, err := parsePolicyFeed(strings.NewReader(content))
+	require.NoError(t, err)
+	require.Len(t, addresses, addressesCount)
+	assert.Equal(t, common.BigToAddress(big.NewInt(addressesCount)), addresses[addressesCount-1])
+}
+
+func TestNewPolicyFeeds(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		feeds         []PolicyFeedCfg
+		expectedError bool
+	}{
+		{
+			desc: "valid feeds",
+			feeds: []PolicyFeedCfg{
+				{Source: "/tmp/sanctioned.csv", Policy: SendTx, Action: "deny"},
+				{Source: "https://example.com/blocked.csv", Policy: SendTx, Action: "deny"},
+				{Source: "/tmp/deployers.csv", Policy: Deploy, Action: "allow"},
+			},
+		},
+		{
+			desc:          "missing source",
+			feeds:         []PolicyFeedCfg{{Policy: SendTx, Action: "deny"}},
+			expectedError: true,
+		},
+		{
+			desc:          "unknown policy",
+			feeds:         []PolicyFeedCfg{{Source: "/tmp/a.csv", Policy: "unknown", Action: "deny"}},
+			expectedError: true,
+		},
+		{
+			desc:          "unknown action",
+			feeds:         []PolicyFeedCfg{{Source: "/tmp/a.csv", Policy: SendTx, Action: "block"}},
+			expectedError: true,
+		},
+		{
+			desc: "different actions for the same policy",
+			feeds: []PolicyFeedCfg{
+				{Source: "/tmp/a.csv", Policy: SendTx, Action: "deny"},
+				{Source: "/tmp/b.csv", Policy: SendTx, Action: "allow"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			feeds, err := newPolicyFeeds(PolicyFeedsCfg{Feeds: tc.feeds})
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, feeds, len(tc.feeds))
+		})
+	}
+}
+
+func TestPolicyFeedLoadFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocked.csv")
+	require.NoError(t, os.WriteFile(path, []byte(feedAddress1+"\n"+feedAddress2+"\n"), 0600))
+
+	feeds, err := newPolicyFeeds(PolicyFeedsCfg{Feeds: []PolicyFeedCfg{{Source: path, Policy: SendTx, Action: "deny"}}})
+	require.NoError(t, err)
+	require.False(t, feeds[0].isHTTP())
+
+	addresses, err := feeds[0].load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress(feedAddress1), common.HexToAddress(feedAddress2)}, addresses)
+
+	require.NoError(t, os.WriteFile(path, []byte(feedAddress3), 0600))
+	addresses, err = feeds[0].load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress(feedAddress3)}, addresses)
+}
+
+func TestPolicyFeedLoadFromHTTP(t *testing.T) {
+	const etag = `"v1"`
+	content := feedAddress1 + "\n" + feedAddress2
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	feeds, err := newPolicyFeeds(PolicyFeedsCfg{Feeds: []PolicyFeedCfg{{Source: server.URL, Policy: SendTx, Action: "deny"}}})
+	require.NoError(t, err)
+	require.True(t, feeds[0].isHTTP())
+
+	expected := []common.Address{common.HexToAddress(feedAddress1), common.HexToAddress(feedAddress2)}
+	for i := 0; i < 3; i++ {
+		addresses, err := feeds[0].load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, expected, addresses)
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, notModified)
+}
+
+func TestPolicyFeedLoadFromHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	feeds, err := newPolicyFeeds(PolicyFeedsCfg{Feeds: []PolicyFeedCfg{{Source: server.URL, Policy: SendTx, Action: "deny"}}})
+	require.NoError(t, err)
+
+	_, err = feeds[0].load(context.Background())
+	require.Error(t, err)
+}
+
+func TestPolicyFeedLoadEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocked.csv")
+	require.NoError(t, os.WriteFile(path, []byte("# nothing blocked\n"), 0600))
+
+	feeds, err := newPolicyFeeds(PolicyFeedsCfg{Feeds: []PolicyFeedCfg{
+		{Source: path, Policy: SendTx, Action: "deny"},
+		{Source: path, Policy: SendTx, Action: "deny", AllowEmpty: true},
+	}})
+	require.NoError(t, err)
+
+	_, err = feeds[0].load(context.Background())
+	require.Error(t, err)
+
+	addresses, err := feeds[1].load(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+}
+
+func TestStartRefreshingPolicyFeedsWithoutInterval(t *testing.T) {
+	p := &Pool{cfg: Config{PolicyFeeds: PolicyFeedsCfg{Feeds: []PolicyFeedCfg{{Source: "/tmp/a.csv", Policy: SendTx, Action: "deny"}}}}}
+	err := p.StartRefreshingPolicyFeedsPeriodically(context.Background())
+	require.Error(t, err)
+}
@@ -50,6 +50,8 @@ type Pool struct {
 	gasPrices               GasPrices
 	gasPricesMux            *sync.RWMutex
 	effectiveGasPrice       *EffectiveGasPrice
+	preExecutionQueue       chan Transaction
+	preExecutionSlots       chan struct{}
 }
 
 type preExecutionResponse struct {
@@ -85,6 +87,10 @@ func NewPool(cfg Config, batchConstraintsCfg state.BatchConstraintsCfg, s storag
 		gasPricesMux:            new(sync.RWMutex),
 		effectiveGasPrice:       NewEffectiveGasPrice(cfg.EffectiveGasPrice),
 	}
+	if cfg.PreExecution.Async {
+		p.preExecutionQueue = make(chan Transaction, cfg.PreExecution.QueueSize)
+		p.preExecutionSlots = make(chan struct{}, cfg.PreExecution.QueueSize)
+	}
 	p.refreshGasPrices()
 	go func(cfg *Config, p *Pool) {
 		for {
@@ -176,28 +182,46 @@ func (p *Pool) StartPollingMinSuggestedGasPrice(ctx context.Context) {
 	}()
 }
 
-// AddTx adds a transaction to the pool with the pending state
+// AddTx adds a transaction to the pool with the pending state. When the pre-execution
+// is async, the tx is added with the queued state and pre-executed in background
 func (p *Pool) AddTx(ctx context.Context, tx types.Transaction, ip string) error {
 	poolTx := NewTransaction(tx, ip, false)
 	if err := p.validateTx(ctx, *poolTx); err != nil {
 		return err
 	}
 
+	if p.cfg.PreExecution.Async {
+		return p.queueTx(ctx, *poolTx)
+	}
 	return p.StoreTx(ctx, tx, ip, false)
 }
 
 // StoreTx adds a transaction to the pool with the pending state
 func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isWIP bool) error {
+	zkCounters, err := p.preExecuteAndValidateTx(ctx, tx, ip)
+	if err != nil {
+		return err
+	}
+
+	poolTx := NewTransaction(tx, ip, isWIP)
+	poolTx.ZKCounters = zkCounters
+
+	return p.storage.AddTx(ctx, *poolTx)
+}
+
+// preExecuteAndValidateTx executes a transaction to calculate its zkCounters and
+// validates the results of the execution, returning the zkCounters used by the tx
+func (p *Pool) preExecuteAndValidateTx(ctx context.Context, tx types.Transaction, ip string) (state.ZKCounters, error) {
 	// Execute transaction to calculate its zkCounters
 	preExecutionResponse, err := p.preExecuteTx(ctx, tx)
 	if errors.Is(err, runtime.ErrIntrinsicInvalidBatchGasLimit) {
-		return ErrGasLimit
+		return state.ZKCounters{}, ErrGasLimit
 	} else if preExecutionResponse.isExecutorLevelError {
 		// Do not add tx to the pool
-		return err
+		return state.ZKCounters{}, err
 	} else if err != nil {
 		log.Errorf("Pre execution error: %v", err)
-		return err
+		return state.ZKCounters{}, err
 	}
 
 	if preExecutionResponse.OOCError != nil {
@@ -216,7 +240,7 @@ func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isW
 			log.Errorf("error adding event: %v", err)
 		}
 		// Do not add tx to the pool
-		return fmt.Errorf("failed to add tx to the pool: %w", preExecutionResponse.OOCError)
+		return state.ZKCounters{}, fmt.Errorf("failed to add tx to the pool: %w", preExecutionResponse.OOCError)
 	} else if preExecutionResponse.OOGError != nil {
 		event := &event.Event{
 			ReceivedAt:  time.Now(),
@@ -236,18 +260,15 @@ func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isW
 
 	gasPrices, err := p.GetGasPrices(ctx)
 	if err != nil {
-		return err
+		return state.ZKCounters{}, err
 	}
 
 	err = p.ValidateBreakEvenGasPrice(ctx, tx, preExecutionResponse.txResponse.GasUsed, gasPrices)
 	if err != nil {
-		return err
+		return state.ZKCounters{}, err
 	}
 
-	poolTx := NewTransaction(tx, ip, isWIP)
-	poolTx.ZKCounters = preExecutionResponse.usedZkCounters
-
-	return p.storage.AddTx(ctx, *poolTx)
+	return preExecutionResponse.usedZkCounters, nil
 }
 
 // ValidateBreakEvenGasPrice validates the effective gas price
@@ -470,6 +470,77 @@ func Test_GetPendingTxs(t *testing.T) {
 	}
 }
 
+func Test_AddTxAsyncPreExecution(t *testing.T) {
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	require.NoError(t, err)
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, metrics.SynchronizerCallerLabel, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+	asyncCfg := cfg
+	asyncCfg.PreExecution = pool.PreExecutionCfg{Async: true, Workers: 1, QueueSize: 1, RejectWhenFull: true, ClaimTimeout: cfgTypes.NewDuration(time.Minute)}
+	p := setupPool(t, asyncCfg, bc, s, st, chainID.Uint64(), ctx, eventLog)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	signedTxs := make([]*ethTypes.Transaction, 0, 2)
+	for i := 0; i < 2; i++ {
+		tx := ethTypes.NewTransaction(uint64(i), common.Address{}, big.NewInt(10), gasLimit, gasPrice, []byte{})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		signedTxs = append(signedTxs, signedTx)
+	}
+
+	// the workers are not started yet, so the first tx fills the queue
+	require.NoError(t, p.AddTx(ctx, *signedTxs[0], ip))
+	err = p.AddTx(ctx, *signedTxs[1], ip)
+	require.ErrorIs(t, err, pool.ErrPreExecutionQueueFull)
+
+	queuedTx, err := p.GetTransactionByHash(ctx, signedTxs[0].Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusQueued, queuedTx.Status)
+
+	require.NoError(t, p.StartPreExecutingTxs(ctx))
+	require.Eventually(t, func() bool {
+		tx, err := p.GetTransactionByHash(ctx, signedTxs[0].Hash())
+		return err == nil && tx.Status == pool.TxStatusPending
+	}, 10*time.Second, 100*time.Millisecond)
+
+	zkCounters, err := p.GetTxZkCountersByHash(ctx, signedTxs[0].Hash())
+	require.NoError(t, err)
+	assert.Greater(t, zkCounters.UsedSteps, uint32(0))
+
+	// the queue has room again once the tx is pre-executed
+	require.NoError(t, p.AddTx(ctx, *signedTxs[1], ip))
+}
+
 func Test_GetPendingTxsZeroPassed(t *testing.T) {
 	initOrResetDB(t)
 
@@ -2098,3 +2169,45 @@ func Test_PolicyAcl(t *testing.T) {
 		}
 	}
 }
+
+func Test_ReplacePolicyAcl(t *testing.T) {
+	initOrResetDB(t)
+
+	ctx := context.Background()
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	addr1 := common.HexToAddress("0x1")
+	addr2 := common.HexToAddress("0x2")
+	addr3 := common.HexToAddress("0x3")
+
+	require.NoError(t, s.AddAddressesToPolicy(ctx, pool.SendTx, []common.Address{addr1, addr2}))
+
+	diff, err := s.ReplacePolicyAcl(ctx, pool.SendTx, false, []common.Address{addr2, addr3, addr3})
+	require.NoError(t, err)
+	assert.False(t, diff.PreviousAllow)
+	assert.False(t, diff.Allow)
+	assert.Equal(t, []common.Address{addr3}, diff.Added)
+	assert.Equal(t, []common.Address{addr1}, diff.Removed)
+
+	acl, err := s.ListAcl(ctx, pool.SendTx, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Address{addr2, addr3}, acl)
+
+	// applying the same list again doesn't change anything
+	diff, err = s.ReplacePolicyAcl(ctx, pool.SendTx, false, []common.Address{addr3, addr2})
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	diff, err = s.ReplacePolicyAcl(ctx, pool.SendTx, true, []common.Address{addr3, addr2})
+	require.NoError(t, err)
+	assert.False(t, diff.IsEmpty())
+	policy, err := s.DescribePolicy(ctx, pool.SendTx)
+	require.NoError(t, err)
+	assert.True(t, policy.Allow)
+
+	// the other policies are not modified
+	acl, err = s.ListAcl(ctx, pool.Deploy, nil)
+	require.NoError(t, err)
+	assert.Empty(t, acl)
+}
@@ -0,0 +1,128 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// queueTx stores a validated tx as queued and sends it to the pre-execution workers.
+// When the queue is full the tx is rejected or pre-executed synchronously, accordingly
+// to the configuration
+func (p *Pool) queueTx(ctx context.Context, poolTx Transaction) error {
+	select {
+	case p.preExecutionSlots <- struct{}{}:
+	default:
+		if p.cfg.PreExecution.RejectWhenFull {
+			log.Infof("%v: %v", ErrPreExecutionQueueFull.Error(), poolTx.Hash().String())
+			return ErrPreExecutionQueueFull
+		}
+		return p.StoreTx(ctx, poolTx.Transaction, poolTx.IP, false)
+	}
+
+	poolTx.Status = TxStatusQueued
+	if err := p.storage.AddTx(ctx, poolTx); err != nil {
+		<-p.preExecutionSlots
+		return err
+	}
+	// it never blocks, the queue has room for all the txs holding a slot
+	p.preExecutionQueue <- poolTx
+	return nil
+}
+
+// StartPreExecutingTxs starts the workers pre-executing the queued txs when the
+// pre-execution is async. The queued txs whose claim expired, because the node that
+// queued them stopped before pre-executing them, are claimed and queued again
+func (p *Pool) StartPreExecutingTxs(ctx context.Context) error {
+	if !p.cfg.PreExecution.Async {
+		return nil
+	}
+	if p.cfg.PreExecution.Workers <= 0 || p.cfg.PreExecution.QueueSize <= 0 {
+		return fmt.Errorf("invalid pre-execution config, workers and queue size must be greater than 0")
+	}
+	if p.cfg.PreExecution.ClaimTimeout.Duration <= 0 {
+		return fmt.Errorf("invalid pre-execution config, claim timeout must be greater than 0")
+	}
+
+	for i := 0; i < p.cfg.PreExecution.Workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case poolTx := <-p.preExecutionQueue:
+					p.preExecuteQueuedTx(ctx, poolTx)
+					<-p.preExecutionSlots
+				}
+			}
+		}()
+	}
+
+	go func() {
+		// the claims are checked twice per timeout, so an expired claim waits half of it at most
+		ticker := time.NewTicker(p.cfg.PreExecution.ClaimTimeout.Duration / 2) //nolint:gomnd
+		defer ticker.Stop()
+		for {
+			p.queueExpiredTxs(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// queueExpiredTxs claims the queued txs whose claim expired, up to the room left
+// in the queue, and sends them to the pre-execution workers
+func (p *Pool) queueExpiredTxs(ctx context.Context) {
+	room := cap(p.preExecutionSlots) - len(p.preExecutionSlots)
+	if room <= 0 {
+		return
+	}
+	queuedTxs, err := p.storage.ClaimQueuedTxs(ctx, p.cfg.PreExecution.ClaimTimeout.Duration, uint64(room))
+	if err != nil {
+		log.Errorf("failed to claim the queued txs: %v", err)
+		return
+	}
+	if len(queuedTxs) > 0 {
+		log.Infof("queuing %d txs claimed to be pre-executed", len(queuedTxs))
+	}
+	for _, poolTx := range queuedTxs {
+		select {
+		case <-ctx.Done():
+			return
+		case p.preExecutionSlots <- struct{}{}:
+			p.preExecutionQueue <- poolTx
+		}
+	}
+}
+
+// preExecuteQueuedTx pre-executes a queued tx, storing it as pending with its zkCounters
+// if the pre-execution succeeds or as failed with the reason of the failure otherwise
+func (p *Pool) preExecuteQueuedTx(ctx context.Context, poolTx Transaction) {
+	zkCounters, err := p.preExecuteAndValidateTx(ctx, poolTx.Transaction, poolTx.IP)
+	if err != nil {
+		log.Infof("queued tx %s failed the pre-execution: %v", poolTx.Hash().String(), err)
+		failedReason := err.Error()
+		err = p.storage.UpdateTxStatus(ctx, TxStatusUpdateInfo{
+			Hash:         poolTx.Hash(),
+			NewStatus:    TxStatusFailed,
+			FailedReason: &failedReason,
+		})
+		if err != nil {
+			log.Errorf("failed to update the status of the queued tx %s: %v", poolTx.Hash().String(), err)
+		}
+		return
+	}
+
+	updated, err := p.storage.UpdateQueuedTxToPending(ctx, poolTx.Hash(), zkCounters)
+	if err != nil {
+		log.Errorf("failed to store the pre-executed tx %s: %v", poolTx.Hash().String(), err)
+	} else if !updated {
+		log.Infof("pre-executed tx %s is not queued anymore, skipping it", poolTx.Hash().String())
+	}
+}
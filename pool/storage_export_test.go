@@ -0,0 +1,4 @@
+package pool
+
+// Storage exposes the pool storage interface to the storage conformance tests
+type Storage = storage
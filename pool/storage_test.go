@@ -0,0 +1,504 @@
+package pool_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/pool/memorypoolstorage"
+	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageConformanceTests are run against every implementation of the pool
+// storage, so all of them behave the same way
+var storageConformanceTests = []struct {
+	name string
+	run  func(t *testing.T, s pool.Storage)
+}{
+	{name: "AddAndGetTx", run: testStorageAddAndGetTx},
+	{name: "StatusTransitions", run: testStorageStatusTransitions},
+	{name: "WIP", run: testStorageWIP},
+	{name: "Nonces", run: testStorageNonces},
+	{name: "Deletes", run: testStorageDeletes},
+	{name: "GasPrices", run: testStorageGasPrices},
+	{name: "Policies", run: testStoragePolicies},
+	{name: "TxEvents", run: testStorageTxEvents},
+	{name: "ClaimQueuedTxs", run: testStorageClaimQueuedTxs},
+	{name: "UpdateQueuedTxToPending", run: testStorageUpdateQueuedTxToPending},
+}
+
+func TestStorageConformance(t *testing.T) {
+	implementations := []struct {
+		name       string
+		newStorage func(t *testing.T) pool.Storage
+	}{
+		{
+			name: "memory",
+			newStorage: func(t *testing.T) pool.Storage {
+				return memorypoolstorage.NewMemoryPoolStorage()
+			},
+		},
+		{
+			name: "postgres",
+			newStorage: func(t *testing.T) pool.Storage {
+				initOrResetDB(t)
+				s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+				require.NoError(t, err)
+				return s
+			},
+		},
+	}
+
+	for _, implementation := range implementations {
+		t.Run(implementation.name, func(t *testing.T) {
+			for _, tc := range storageConformanceTests {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, implementation.newStorage(t))
+				})
+			}
+		})
+	}
+}
+
+// newStorageTestTx creates a pool tx signed by the test sender
+func newStorageTestTx(t *testing.T, nonce uint64, gasPrice int64, receivedAt time.Time) pool.Transaction {
+	privateKey, err := crypto.HexToECDSA(senderPrivateKey[2:])
+	require.NoError(t, err)
+
+	tx := ethTypes.NewTransaction(nonce, common.HexToAddress("0x1"), big.NewInt(0), gasLimit, big.NewInt(gasPrice), []byte{})
+	signedTx, err := ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(chainID), privateKey)
+	require.NoError(t, err)
+
+	poolTx := pool.NewTransaction(*signedTx, "127.0.0.1", false)
+	poolTx.ReceivedAt = receivedAt
+	poolTx.ZKCounters = state.ZKCounters{GasUsed: gasLimit, UsedSteps: uint32(nonce + 1)}
+	return *poolTx
+}
+
+func txHashes(txs []pool.Transaction) []common.Hash {
+	hashes := make([]common.Hash, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
+
+func testStorageAddAndGetTx(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	require.NoError(t, s.AddTx(ctx, tx))
+
+	stored, err := s.GetTransactionByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), stored.Hash())
+	assert.Equal(t, pool.TxStatusPending, stored.Status)
+	assert.Equal(t, "127.0.0.1", stored.IP)
+	assert.True(t, receivedAt.Equal(stored.ReceivedAt))
+	assert.False(t, stored.IsWIP)
+
+	zkCounters, err := s.GetTxZkCountersByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, tx.ZKCounters, *zkCounters)
+
+	from, nonce, err := s.GetTxFromAddressFromByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(senderAddress), from)
+	assert.Equal(t, uint64(0), nonce)
+
+	pending, err := s.IsTxPending(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	// adding the same tx again replaces the stored one
+	tx.IsWIP = true
+	require.NoError(t, s.AddTx(ctx, tx))
+	stored, err = s.GetTransactionByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.True(t, stored.IsWIP)
+	count, err := s.CountTransactionsByStatus(ctx, pool.TxStatusPending)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	_, err = s.GetTransactionByHash(ctx, common.HexToHash("0x1"))
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+	_, err = s.GetTxZkCountersByHash(ctx, common.HexToHash("0x1"))
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+}
+
+func testStorageStatusTransitions(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx1 := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	tx2 := newStorageTestTx(t, 1, 3000000000, receivedAt)
+	tx3 := newStorageTestTx(t, 2, 2000000000, receivedAt)
+	for _, tx := range []pool.Transaction{tx1, tx2, tx3} {
+		require.NoError(t, s.AddTx(ctx, tx))
+	}
+
+	// pending txs are sorted by gas price
+	txs, err := s.GetTxsByStatus(ctx, pool.TxStatusPending, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{tx2.Hash(), tx3.Hash(), tx1.Hash()}, txHashes(txs))
+	txs, err = s.GetTxsByStatus(ctx, pool.TxStatusPending, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{tx2.Hash(), tx3.Hash()}, txHashes(txs))
+
+	failedReason := "failed"
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx1.Hash(), NewStatus: pool.TxStatusFailed, FailedReason: &failedReason}))
+	require.NoError(t, s.UpdateTxsStatus(ctx, []pool.TxStatusUpdateInfo{
+		{Hash: tx2.Hash(), NewStatus: pool.TxStatusSelected},
+		{Hash: tx3.Hash(), NewStatus: pool.TxStatusInvalid},
+	}))
+
+	txs, err = s.GetTxsByStatus(ctx, pool.TxStatusFailed, 0)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, tx1.Hash(), txs[0].Hash())
+	require.NotNil(t, txs[0].FailedReason)
+	assert.Equal(t, failedReason, *txs[0].FailedReason)
+	stored, err := s.GetTransactionByHash(ctx, tx1.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, stored.FailedReason)
+	assert.Equal(t, failedReason, *stored.FailedReason)
+
+	// the failed reason is kept when it's not provided
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx1.Hash(), NewStatus: pool.TxStatusFailed}))
+	txs, err = s.GetTxsByStatus(ctx, pool.TxStatusFailed, 0)
+	require.NoError(t, err)
+	require.NotNil(t, txs[0].FailedReason)
+
+	for status, expected := range map[pool.TxStatus]uint64{
+		pool.TxStatusPending: 0, pool.TxStatusSelected: 1, pool.TxStatusInvalid: 1, pool.TxStatusFailed: 1,
+	} {
+		count, err := s.CountTransactionsByStatus(ctx, status)
+		require.NoError(t, err)
+		assert.Equal(t, expected, count, status)
+	}
+	count, err := s.CountTransactionsByStatus(ctx, pool.TxStatusSelected, pool.TxStatusFailed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	count, err = s.CountTransactionsByFromAndStatus(ctx, common.HexToAddress(senderAddress), pool.TxStatusSelected, pool.TxStatusInvalid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	count, err = s.CountTransactionsByFromAndStatus(ctx, common.HexToAddress("0x1"), pool.TxStatusSelected)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	pending, err := s.IsTxPending(ctx, tx2.Hash())
+	require.NoError(t, err)
+	assert.False(t, pending)
+}
+
+func testStorageWIP(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx1 := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	tx2 := newStorageTestTx(t, 1, 1000000000, receivedAt)
+	require.NoError(t, s.AddTx(ctx, tx1))
+	require.NoError(t, s.AddTx(ctx, tx2))
+
+	require.NoError(t, s.UpdateTxWIPStatus(ctx, tx1.Hash(), true))
+	txs, err := s.GetNonWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{tx2.Hash()}, txHashes(txs))
+
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx2.Hash(), NewStatus: pool.TxStatusPending, IsWIP: true}))
+	txs, err = s.GetNonWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	require.NoError(t, s.MarkWIPTxsAsPending(ctx))
+	txs, err = s.GetNonWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Hash{tx1.Hash(), tx2.Hash()}, txHashes(txs))
+}
+
+func testStorageNonces(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	sender := common.HexToAddress(senderAddress)
+
+	nonce, err := s.GetNonce(ctx, sender)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), nonce)
+
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx1 := newStorageTestTx(t, 2, 1000000000, receivedAt)
+	tx2 := newStorageTestTx(t, 0, 2000000000, receivedAt)
+	tx3 := newStorageTestTx(t, 1, 3000000000, receivedAt)
+	tx4 := newStorageTestTx(t, 1, 4000000000, receivedAt)
+	for _, tx := range []pool.Transaction{tx1, tx2, tx3, tx4} {
+		require.NoError(t, s.AddTx(ctx, tx))
+	}
+
+	nonce, err = s.GetNonce(ctx, sender)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+
+	// invalid and failed txs are not taken into account for the nonce
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx1.Hash(), NewStatus: pool.TxStatusInvalid}))
+	nonce, err = s.GetNonce(ctx, sender)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nonce)
+
+	txs, err := s.GetTxsByFromAndNonce(ctx, sender, 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Hash{tx3.Hash(), tx4.Hash()}, txHashes(txs))
+
+	poolTxs, err := s.GetTxs(ctx, pool.TxStatusPending, 2500000000, 10)
+	require.NoError(t, err)
+	require.Len(t, poolTxs, 2)
+	for _, poolTx := range poolTxs {
+		assert.Equal(t, uint64(1), poolTx.Nonce())
+	}
+	poolTxs, err = s.GetTxs(ctx, pool.TxStatusPending, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, poolTxs, 1)
+	assert.Equal(t, tx2.Hash(), poolTxs[0].Hash())
+	assert.Equal(t, tx2.ZKCounters, poolTxs[0].ZKCounters)
+}
+
+func testStorageDeletes(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	tx1 := newStorageTestTx(t, 0, 1000000000, now.Add(-2*time.Hour))
+	tx2 := newStorageTestTx(t, 1, 1000000000, now.Add(-time.Hour))
+	tx3 := newStorageTestTx(t, 2, 1000000000, now)
+	tx4 := newStorageTestTx(t, 3, 1000000000, now)
+	for _, tx := range []pool.Transaction{tx1, tx2, tx3, tx4} {
+		require.NoError(t, s.AddTx(ctx, tx))
+	}
+
+	hashes, err := s.GetPendingTxHashesSince(ctx, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Hash{tx2.Hash(), tx3.Hash(), tx4.Hash()}, hashes)
+
+	earliest, err := s.GetEarliestProcessedTx(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}, earliest)
+	require.NoError(t, s.UpdateTxsStatus(ctx, []pool.TxStatusUpdateInfo{
+		{Hash: tx1.Hash(), NewStatus: pool.TxStatusFailed},
+		{Hash: tx2.Hash(), NewStatus: pool.TxStatusSelected},
+		{Hash: tx3.Hash(), NewStatus: pool.TxStatusSelected},
+	}))
+	earliest, err = s.GetEarliestProcessedTx(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, tx2.Hash(), earliest)
+
+	require.NoError(t, s.DeleteFailedTransactionsOlderThan(ctx, now.Add(-time.Hour)))
+	_, err = s.GetTransactionByHash(ctx, tx1.Hash())
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+
+	require.NoError(t, s.DeleteTransactionByHash(ctx, tx2.Hash()))
+	require.NoError(t, s.DeleteTransactionsByHashes(ctx, []common.Hash{tx3.Hash(), common.HexToHash("0x1")}))
+	count, err := s.CountTransactionsByStatus(ctx, pool.TxStatusPending, pool.TxStatusSelected, pool.TxStatusFailed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+	_, err = s.GetTransactionByHash(ctx, tx4.Hash())
+	require.NoError(t, err)
+}
+
+func testStorageGasPrices(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	start := time.Now().UTC().Add(-time.Second)
+
+	l2GasPrice, l1GasPrice, err := s.GetGasPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), l2GasPrice)
+	assert.Equal(t, uint64(0), l1GasPrice)
+	_, err = s.MinL2GasPriceSince(ctx, start)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+
+	require.NoError(t, s.SetGasPrices(ctx, 3, 30))
+	require.NoError(t, s.SetGasPrices(ctx, 1, 10))
+	require.NoError(t, s.SetGasPrices(ctx, 2, 20))
+
+	l2GasPrice, l1GasPrice, err = s.GetGasPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), l2GasPrice)
+	assert.Equal(t, uint64(20), l1GasPrice)
+
+	minGasPrice, err := s.MinL2GasPriceSince(ctx, start)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), minGasPrice)
+
+	// the latest gas price is kept when deleting the history
+	require.NoError(t, s.DeleteGasPricesHistoryOlderThan(ctx, time.Now().UTC().Add(time.Hour)))
+	minGasPrice, err = s.MinL2GasPriceSince(ctx, start)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), minGasPrice)
+	l2GasPrice, l1GasPrice, err = s.GetGasPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), l2GasPrice)
+	assert.Equal(t, uint64(20), l1GasPrice)
+}
+
+func testStoragePolicies(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	addr1 := common.HexToAddress("0x1")
+	addr2 := common.HexToAddress("0x2")
+	addr3 := common.HexToAddress("0x3")
+
+	policies, err := s.DescribePolicies(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []pool.Policy{{Name: pool.SendTx}, {Name: pool.Deploy}}, policies)
+
+	// policies start as deny lists without addresses
+	allow, err := s.CheckPolicy(ctx, pool.SendTx, addr1)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	require.NoError(t, s.AddAddressesToPolicy(ctx, pool.SendTx, []common.Address{addr1, addr2}))
+	allow, err = s.CheckPolicy(ctx, pool.SendTx, addr1)
+	require.NoError(t, err)
+	assert.False(t, allow)
+	allow, err = s.CheckPolicy(ctx, pool.Deploy, addr1)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	acl, err := s.ListAcl(ctx, pool.SendTx, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Address{addr1, addr2}, acl)
+
+	require.NoError(t, s.RemoveAddressesFromPolicy(ctx, pool.SendTx, []common.Address{addr1}))
+	allow, err = s.CheckPolicy(ctx, pool.SendTx, addr1)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	diff, err := s.ReplacePolicyAcl(ctx, pool.SendTx, true, []common.Address{addr3})
+	require.NoError(t, err)
+	assert.Equal(t, pool.PolicyDiff{
+		Policy: pool.SendTx, PreviousAllow: false, Allow: true,
+		Added: []common.Address{addr3}, Removed: []common.Address{addr2},
+	}, diff)
+	policy, err := s.DescribePolicy(ctx, pool.SendTx)
+	require.NoError(t, err)
+	assert.True(t, policy.Allow)
+	allow, err = s.CheckPolicy(ctx, pool.SendTx, addr3)
+	require.NoError(t, err)
+	assert.True(t, allow)
+	allow, err = s.CheckPolicy(ctx, pool.SendTx, addr2)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	require.NoError(t, s.ClearPolicy(ctx, pool.SendTx))
+	acl, err = s.ListAcl(ctx, pool.SendTx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, acl)
+}
+
+func testStorageTxEvents(t *testing.T, s pool.Storage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := s.SubscribeTxEvents(ctx)
+	require.NoError(t, err)
+
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	require.NoError(t, s.AddTx(ctx, tx))
+	// updates keeping the status are not notified
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx.Hash(), NewStatus: pool.TxStatusPending, IsWIP: true}))
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx.Hash(), NewStatus: pool.TxStatusSelected}))
+
+	expected := []pool.TxEvent{
+		{Hash: tx.Hash(), Status: pool.TxStatusPending},
+		{Hash: tx.Hash(), Status: pool.TxStatusSelected},
+	}
+	for _, expectedEvent := range expected {
+		select {
+		case event := <-events:
+			assert.Equal(t, expectedEvent, event)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timeout waiting for tx event", expectedEvent.Status)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "events channel not closed")
+	}
+}
+
+func testStorageClaimQueuedTxs(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	tx1 := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	tx2 := newStorageTestTx(t, 1, 1000000000, receivedAt.Add(time.Second))
+	tx3 := newStorageTestTx(t, 2, 1000000000, receivedAt.Add(2*time.Second))
+	for _, tx := range []pool.Transaction{tx1, tx2, tx3} {
+		tx.Status = pool.TxStatusQueued
+		require.NoError(t, s.AddTx(ctx, tx))
+	}
+	// the pending txs are never claimed
+	require.NoError(t, s.AddTx(ctx, newStorageTestTx(t, 3, 1000000000, receivedAt)))
+
+	// the txs are claimed by the node adding them
+	txs, err := s.ClaimQueuedTxs(ctx, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	// once the claims expire, the txs are claimed again in the order they were received
+	time.Sleep(1100 * time.Millisecond)
+	txs, err = s.ClaimQueuedTxs(ctx, time.Second, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{tx1.Hash(), tx2.Hash()}, txHashes(txs))
+	assert.Equal(t, pool.TxStatusQueued, txs[0].Status)
+
+	txs, err = s.ClaimQueuedTxs(ctx, time.Second, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{tx3.Hash()}, txHashes(txs))
+
+	txs, err = s.ClaimQueuedTxs(ctx, time.Second, 10)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+}
+
+func testStorageUpdateQueuedTxToPending(t *testing.T, s pool.Storage) {
+	ctx := context.Background()
+	receivedAt := time.Now().UTC().Truncate(time.Second)
+	queuedTx := newStorageTestTx(t, 0, 1000000000, receivedAt)
+	queuedTx.Status = pool.TxStatusQueued
+	require.NoError(t, s.AddTx(ctx, queuedTx))
+	failedTx := newStorageTestTx(t, 1, 1000000000, receivedAt)
+	failedTx.Status = pool.TxStatusQueued
+	require.NoError(t, s.AddTx(ctx, failedTx))
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: failedTx.Hash(), NewStatus: pool.TxStatusFailed}))
+	deletedTx := newStorageTestTx(t, 2, 1000000000, receivedAt)
+
+	zkCounters := state.ZKCounters{GasUsed: 21000, UsedSteps: 100}
+	updated, err := s.UpdateQueuedTxToPending(ctx, queuedTx.Hash(), zkCounters)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	tx, err := s.GetTransactionByHash(ctx, queuedTx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusPending, tx.Status)
+	storedZKCounters, err := s.GetTxZkCountersByHash(ctx, queuedTx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, zkCounters, *storedZKCounters)
+
+	// the txs that are not queued anymore are not resurrected
+	updated, err = s.UpdateQueuedTxToPending(ctx, queuedTx.Hash(), zkCounters)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	updated, err = s.UpdateQueuedTxToPending(ctx, failedTx.Hash(), zkCounters)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	tx, err = s.GetTransactionByHash(ctx, failedTx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusFailed, tx.Status)
+	updated, err = s.UpdateQueuedTxToPending(ctx, deletedTx.Hash(), zkCounters)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	_, err = s.GetTransactionByHash(ctx, deletedTx.Hash())
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+}
@@ -9,6 +9,8 @@ import (
 )
 
 const (
+	// TxStatusQueued represents a tx accepted by the pool that is waiting to be pre-executed
+	TxStatusQueued TxStatus = "queued"
 	// TxStatusPending represents a tx that has not been processed
 	TxStatusPending TxStatus = "pending"
 	// TxStatusInvalid represents an invalid tx
@@ -35,6 +37,13 @@ type TxStatusUpdateInfo struct {
 	FailedReason *string
 }
 
+// TxEvent is emitted by the pool storage when a tx is added to the pool
+// or its status changes
+type TxEvent struct {
+	Hash   common.Hash `json:"hash"`
+	Status TxStatus    `json:"status"`
+}
+
 // Transaction represents a pool tx
 type Transaction struct {
 	types.Transaction
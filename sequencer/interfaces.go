@@ -28,6 +28,7 @@ type txPool interface {
 	GetDefaultMinGasPriceAllowed() uint64
 	GetL1AndL2GasPrice() (uint64, uint64)
 	GetEarliestProcessedTx(ctx context.Context) (common.Hash, error)
+	SubscribeTxEvents(ctx context.Context) (<-chan pool.TxEvent, error)
 }
 
 // etherman contains the methods required to interact with ethereum.
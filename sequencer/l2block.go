@@ -329,9 +329,13 @@ func (f *finalizer) storeL2Block(ctx context.Context, l2Block *L2Block) error {
 	forkID := f.stateIntf.GetForkIDByBatchNumber(f.wipBatch.batchNumber)
 
 	txsEGPLog := []*state.EffectiveGasPriceLog{}
-	for _, tx := range l2Block.transactions {
+	for i, tx := range l2Block.transactions {
 		egpLog := tx.EGPLog
 		txsEGPLog = append(txsEGPLog, &egpLog)
+		// The sender is already known, so the state doesn't need to recover it to index the tx
+		if i < len(blockResponse.TransactionResponses) {
+			blockResponse.TransactionResponses[i].From = tx.From
+		}
 	}
 
 	// Store L2 block in the state
@@ -257,6 +257,36 @@ func (_m *PoolMock) MarkWIPTxsAsPending(ctx context.Context) error {
 	return r0
 }
 
+// SubscribeTxEvents provides a mock function with given fields: ctx
+func (_m *PoolMock) SubscribeTxEvents(ctx context.Context) (<-chan pool.TxEvent, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeTxEvents")
+	}
+
+	var r0 <-chan pool.TxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan pool.TxEvent, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan pool.TxEvent); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan pool.TxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateTxStatus provides a mock function with given fields: ctx, hash, newStatus, isWIP, failedReason
 func (_m *PoolMock) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus pool.TxStatus, isWIP bool, failedReason *string) error {
 	ret := _m.Called(ctx, hash, newStatus, isWIP, failedReason)
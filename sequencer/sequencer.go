@@ -191,10 +191,46 @@ func (s *Sequencer) expireOldWorkerTxs(ctx context.Context) {
 	}
 }
 
-// loadFromPool keeps loading transactions from the pool
+// loadFromPool keeps loading transactions from the pool. The pending txs are loaded as soon
+// as the pool notifies a new one, polling the pool every LoadPoolTxsCheckInterval as fallback
 func (s *Sequencer) loadFromPool(ctx context.Context) {
+	var txEvents <-chan pool.TxEvent
+	pollTimer := time.NewTimer(s.cfg.LoadPoolTxsCheckInterval.Duration)
+	defer pollTimer.Stop()
 	for {
-		time.Sleep(s.cfg.LoadPoolTxsCheckInterval.Duration)
+		if txEvents == nil {
+			var err error
+			txEvents, err = s.pool.SubscribeTxEvents(ctx)
+			if err != nil {
+				log.Warnf("error subscribing to pool tx events, polling the pool instead, error: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-pollTimer.C:
+		case event, ok := <-txEvents:
+			if !ok {
+				// wait for the next poll before subscribing again, so a subscription
+				// that keeps closing doesn't take a new connection on every iteration
+				log.Warnf("pool tx events subscription closed, polling the pool until it's restored")
+				txEvents = nil
+				select {
+				case <-ctx.Done():
+					return
+				case <-pollTimer.C:
+				}
+				break
+			}
+			if event.Status != pool.TxStatusPending {
+				continue
+			}
+			drainTxEvents(txEvents)
+			if !pollTimer.Stop() {
+				<-pollTimer.C
+			}
+		}
 
 		poolTransactions, err := s.pool.GetNonWIPPendingTxs(ctx)
 		if err != nil && err != pool.ErrNotFound {
@@ -207,6 +243,22 @@ func (s *Sequencer) loadFromPool(ctx context.Context) {
 				log.Errorf("error adding transaction to worker, error: %v", err)
 			}
 		}
+		pollTimer.Reset(s.cfg.LoadPoolTxsCheckInterval.Duration)
+	}
+}
+
+// drainTxEvents discards the events already queued in the channel, so a burst
+// of new txs is loaded from the pool at once
+func drainTxEvents(txEvents <-chan pool.TxEvent) {
+	for {
+		select {
+		case _, ok := <-txEvents:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
 	}
 }
 
@@ -0,0 +1,112 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/fakevm"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// AccessListResult is the result of creating the access list of a transaction
+type AccessListResult struct {
+	AccessList types.AccessList
+	GasUsed    uint64
+	// Err is the error of the execution of the transaction, if any
+	Err error
+	// ForkID is the fork used to execute the transaction
+	ForkID uint64
+}
+
+// CreateAccessList executes the given unsigned transaction generating its full trace
+// to build the list of the addresses and storage slots it accesses. The sender, the
+// receiver, the created contracts and the precompiled contracts are not included
+// in the list as they are always accessed
+func (s *State) CreateAccessList(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*AccessListResult, error) {
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, true, &TraceConfig{DisableStorage: true}, nil, nil, dbTx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := response.BlockResponses[0].TransactionResponses[0]
+	excluded := map[common.Address]struct{}{senderAddress: {}}
+	if tx.To() != nil {
+		excluded[*tx.To()] = struct{}{}
+	} else {
+		excluded[r.CreateAddress] = struct{}{}
+	}
+	for _, address := range fakevm.PrecompiledAddressesBerlin {
+		excluded[address] = struct{}{}
+	}
+
+	result := &AccessListResult{
+		AccessList: accessListFromTrace(r.FullTrace.Steps, excluded),
+		GasUsed:    r.GasUsed,
+		ForkID:     response.ForkID,
+	}
+	if r.RomError != nil {
+		result.Err = r.RomError
+		if errors.Is(r.RomError, runtime.ErrExecutionReverted) {
+			result.Err = ConstructErrorFromRevert(r.RomError, r.ReturnValue)
+		}
+	}
+	return result, nil
+}
+
+// accessListFromTrace collects the addresses and storage slots accessed by the steps of a trace
+func accessListFromTrace(steps []instrumentation.Step, excluded map[common.Address]struct{}) types.AccessList {
+	accessed := map[common.Address]map[common.Hash]struct{}{}
+	addAddress := func(address common.Address) {
+		if _, found := excluded[address]; found {
+			return
+		}
+		if _, found := accessed[address]; !found {
+			accessed[address] = map[common.Hash]struct{}{}
+		}
+	}
+
+	for _, step := range steps {
+		stackLen := len(step.Stack)
+		switch step.OpCode {
+		case "SLOAD", "SSTORE":
+			if stackLen >= 1 {
+				// the storage slots of the excluded addresses are included
+				// as they aren't accessed by default
+				address := step.Contract.Address
+				if _, found := accessed[address]; !found {
+					accessed[address] = map[common.Hash]struct{}{}
+				}
+				accessed[address][common.BigToHash(step.Stack[stackLen-1])] = struct{}{}
+			}
+		case "EXTCODECOPY", "EXTCODEHASH", "EXTCODESIZE", "BALANCE", "SELFDESTRUCT":
+			if stackLen >= 1 {
+				addAddress(common.BigToAddress(step.Stack[stackLen-1]))
+			}
+		case "DELEGATECALL", "CALL", "STATICCALL", "CALLCODE":
+			if stackLen >= 5 { //nolint:gomnd
+				addAddress(common.BigToAddress(step.Stack[stackLen-2]))
+			}
+		}
+	}
+
+	accessList := make(types.AccessList, 0, len(accessed))
+	for address, slots := range accessed {
+		tuple := types.AccessTuple{Address: address, StorageKeys: make([]common.Hash, 0, len(slots))}
+		for slot := range slots {
+			tuple.StorageKeys = append(tuple.StorageKeys, slot)
+		}
+		sort.Slice(tuple.StorageKeys, func(i, j int) bool {
+			return tuple.StorageKeys[i].Big().Cmp(tuple.StorageKeys[j].Big()) < 0
+		})
+		accessList = append(accessList, tuple)
+	}
+	sort.Slice(accessList, func(i, j int) bool {
+		return accessList[i].Address.Big().Cmp(accessList[j].Address.Big()) < 0
+	})
+	return accessList
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessListFromTrace(t *testing.T) {
+	sender := common.HexToAddress("0x1000")
+	receiver := common.HexToAddress("0x2000")
+	called := common.HexToAddress("0x3000")
+	checked := common.HexToAddress("0x4000")
+	precompile := common.HexToAddress("0x2")
+
+	addressToBig := func(address common.Address) *big.Int {
+		return new(big.Int).SetBytes(address.Bytes())
+	}
+	steps := []instrumentation.Step{
+		{OpCode: "SLOAD", Contract: instrumentation.Contract{Address: receiver}, Stack: []*big.Int{big.NewInt(2)}},
+		{OpCode: "SSTORE", Contract: instrumentation.Contract{Address: receiver}, Stack: []*big.Int{big.NewInt(7), big.NewInt(1)}},
+		{OpCode: "SLOAD", Contract: instrumentation.Contract{Address: receiver}, Stack: []*big.Int{big.NewInt(1)}},
+		// gas, address, value, argsOffset, argsSize, retOffset, retSize with gas on top
+		{OpCode: "CALL", Contract: instrumentation.Contract{Address: receiver}, Stack: []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), addressToBig(called), big.NewInt(100000)}},
+		{OpCode: "STATICCALL", Contract: instrumentation.Contract{Address: receiver}, Stack: []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), addressToBig(precompile), big.NewInt(100000)}},
+		{OpCode: "SLOAD", Contract: instrumentation.Contract{Address: called}, Stack: []*big.Int{big.NewInt(3)}},
+		{OpCode: "BALANCE", Contract: instrumentation.Contract{Address: called}, Stack: []*big.Int{addressToBig(checked)}},
+		{OpCode: "EXTCODESIZE", Contract: instrumentation.Contract{Address: called}, Stack: []*big.Int{addressToBig(sender)}},
+		{OpCode: "ADD", Contract: instrumentation.Contract{Address: called}, Stack: []*big.Int{addressToBig(common.HexToAddress("0x5000")), big.NewInt(1)}},
+	}
+	excluded := map[common.Address]struct{}{sender: {}, receiver: {}, precompile: {}}
+
+	expected := types.AccessList{
+		{Address: receiver, StorageKeys: []common.Hash{common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2))}},
+		{Address: called, StorageKeys: []common.Hash{common.BigToHash(big.NewInt(3))}},
+		{Address: checked, StorageKeys: []common.Hash{}},
+	}
+	assert.Equal(t, expected, accessListFromTrace(steps, excluded))
+	assert.Equal(t, types.AccessList{}, accessListFromTrace(nil, excluded))
+}
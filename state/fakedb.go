@@ -16,6 +16,9 @@ type FakeDB struct {
 	State     *State
 	stateRoot []byte
 	refund    uint64
+	// overrides are applied on top of the state read from the tree, so the
+	// tracers see the same state the traced execution saw
+	overrides StateOverride
 }
 
 // SetStateRoot is the stateRoot setter.
@@ -40,6 +43,9 @@ func (f *FakeDB) AddBalance(common.Address, *big.Int) {
 
 // GetBalance returns the balance of the given address.
 func (f *FakeDB) GetBalance(address common.Address) *big.Int {
+	if balance, found := f.overrides.balance(address); found {
+		return new(big.Int).Set(balance)
+	}
 	ctx := context.Background()
 	balance, err := f.State.GetTree().GetBalance(ctx, address, f.stateRoot)
 
@@ -62,7 +68,7 @@ func (f *FakeDB) GetNonce(address common.Address) uint64 {
 	}
 
 	log.Debugf("FakeDB GetNonce for address %v", address)
-	return nonce.Uint64()
+	return f.overrides.nonce(address, nonce.Uint64())
 }
 
 // SetNonce not implemented
@@ -72,6 +78,11 @@ func (f *FakeDB) SetNonce(common.Address, uint64) {
 
 // GetCodeHash gets the hash for the code at a given address
 func (f *FakeDB) GetCodeHash(address common.Address) common.Hash {
+	if hash, found, err := f.overrides.codeHash(address); err != nil {
+		log.Errorf("error on FakeDB GetCodeHash for the overridden code of address %v, err: %v", address, err)
+	} else if found {
+		return hash
+	}
 	ctx := context.Background()
 	hash, err := f.State.GetTree().GetCodeHash(ctx, address, f.stateRoot)
 	if err != nil {
@@ -84,6 +95,9 @@ func (f *FakeDB) GetCodeHash(address common.Address) common.Hash {
 
 // GetCode returns the SC code of the given address.
 func (f *FakeDB) GetCode(address common.Address) []byte {
+	if code, found := f.overrides.code(address); found {
+		return code
+	}
 	ctx := context.Background()
 	code, err := f.State.GetTree().GetCode(ctx, address, f.stateRoot)
 
@@ -131,6 +145,9 @@ func (f *FakeDB) GetCommittedState(common.Address, common.Hash) common.Hash {
 
 // GetState retrieves a value from the given account's storage trie.
 func (f *FakeDB) GetState(address common.Address, hash common.Hash) common.Hash {
+	if value, found := f.overrides.storage(address, hash); found {
+		return value
+	}
 	ctx := context.Background()
 	storage, err := f.State.GetTree().GetStorageAt(ctx, address, hash.Big(), f.stateRoot)
 
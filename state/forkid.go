@@ -26,6 +26,13 @@ type ForkIDInterval struct {
 	BlockNumber     uint64
 }
 
+// IsTypedTxSupported returns whether the txs with a type other than legacy,
+// like the access list txs, can be processed in the provided fork. None of the
+// current forks support them
+func IsTypedTxSupported(_ uint64) bool {
+	return false
+}
+
 // UpdateForkIDIntervalsInMemory updates the forkID intervals in memory
 func (s *State) UpdateForkIDIntervalsInMemory(intervals []ForkIDInterval) {
 	s.storage.UpdateForkIDIntervalsInMemory(intervals)
@@ -200,8 +200,9 @@ func (s *State) SetGenesis(ctx context.Context, block Block, genesis Genesis, m
 
 	storeTxsEGPData := []StoreTxEGPData{}
 	txsL2Hash := []common.Hash{}
+	txsSenders := []common.Address{}
 
-	err = s.AddL2Block(ctx, batch.BatchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx)
+	err = s.AddL2Block(ctx, batch.BatchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/0xPolygonHermez/zkevm-node/l1infotree"
 	"github.com/0xPolygonHermez/zkevm-node/log"
@@ -97,3 +98,86 @@ func (s *State) GetCurrentL1InfoRoot(ctx context.Context, dbTx pgx.Tx) (common.H
 	root, _, _ := s.l1InfoTree.GetCurrentRootCountAndSiblings()
 	return root, nil
 }
+
+// l1InfoTreeLevelsCache keeps the levels of the latest L1InfoTree a merkle proof was
+// computed for, so the proofs of the leaves of the same tree don't need to load and
+// hash all its leaves again
+type l1InfoTreeLevelsCache struct {
+	mu     sync.Mutex
+	mt     *l1infotree.L1InfoTree
+	root   common.Hash
+	count  uint32
+	levels [][][32]byte
+}
+
+// GetL1InfoTreeMerkleProof returns the merkle proof of the leaf with the
+// provided index in the L1InfoTree with the provided root, or in the latest
+// one when the root is nil, and the root the proof was computed for
+func (s *State) GetL1InfoTreeMerkleProof(ctx context.Context, l1InfoTreeIndex uint32, l1InfoRoot *common.Hash, dbTx pgx.Tx) ([][32]byte, common.Hash, error) {
+	var lastLeaf L1InfoTreeExitRootStorageEntry
+	if l1InfoRoot == nil {
+		latestIndex, err := s.GetLatestIndex(ctx, dbTx)
+		if err != nil {
+			return nil, ZeroHash, err
+		}
+		lastLeaf, err = s.GetL1InfoRootLeafByIndex(ctx, latestIndex, dbTx)
+		if err != nil {
+			return nil, ZeroHash, err
+		}
+	} else {
+		var err error
+		lastLeaf, err = s.GetL1InfoRootLeafByL1InfoRoot(ctx, *l1InfoRoot, dbTx)
+		if err != nil {
+			return nil, ZeroHash, err
+		}
+		// the root is unknown
+		if lastLeaf.L1InfoTreeRoot != *l1InfoRoot {
+			return nil, ZeroHash, ErrNotFound
+		}
+	}
+	// the leaf was added after the root
+	if l1InfoTreeIndex > lastLeaf.L1InfoTreeIndex {
+		return nil, ZeroHash, ErrNotFound
+	}
+
+	c := &s.l1InfoTreeLevels
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mt == nil {
+		mt, err := l1infotree.NewL1InfoTree(uint8(32), [][32]byte{}) //nolint:gomnd
+		if err != nil {
+			return nil, ZeroHash, err
+		}
+		c.mt = mt
+	}
+
+	levels := c.levels
+	if levels == nil || c.root != lastLeaf.L1InfoTreeRoot {
+		leaves, err := s.GetLeafsByL1InfoRoot(ctx, lastLeaf.L1InfoTreeRoot, dbTx)
+		if err != nil {
+			return nil, ZeroHash, err
+		}
+		count := lastLeaf.L1InfoTreeIndex + 1
+		if uint32(len(leaves)) != count {
+			return nil, ZeroHash, fmt.Errorf("error: %d leaves found for the l1InfoRoot %s of the leaf %d", len(leaves), lastLeaf.L1InfoTreeRoot.String(), lastLeaf.L1InfoTreeIndex)
+		}
+		leavesHashes := make([][32]byte, 0, len(leaves))
+		for _, leaf := range leaves {
+			leavesHashes = append(leavesHashes, leaf.Hash())
+		}
+		levels = c.mt.ComputeLevels(leavesHashes)
+		// the proofs are mostly requested for the latest tree, so an older one is not kept
+		if l1InfoRoot == nil || count >= c.count {
+			c.root, c.count, c.levels = lastLeaf.L1InfoTreeRoot, count, levels
+		}
+	}
+
+	proof, root, err := c.mt.MerkleProofFromLevels(l1InfoTreeIndex, levels)
+	if err != nil {
+		return nil, ZeroHash, err
+	}
+	if root != lastLeaf.L1InfoTreeRoot {
+		return nil, ZeroHash, fmt.Errorf("error: l1InfoRoot mismatch. L1InfoRoot: %s, calculatedL1InfoRoot: %s", lastLeaf.L1InfoTreeRoot.String(), root.String())
+	}
+	return proof, root, nil
+}
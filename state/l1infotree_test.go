@@ -3,7 +3,9 @@ package state_test
 import (
 	"context"
 	"math"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/l1infotree"
@@ -147,3 +149,90 @@ func TestAddL1InfoTreeLeafIfNil(t *testing.T) {
 	require.Equal(t, addLeaf.L1InfoTreeRoot, common.HexToHash("0xea536769cad1a63ffb1ea52ae772983905c3f0e2f8914e6c0e2af956637e480c"))
 	require.Equal(t, addLeaf.L1InfoTreeIndex, uint32(0))
 }
+
+func TestGetL1InfoTreeMerkleProof(t *testing.T) {
+	mockStorage := mocks.NewStorageMock(t)
+	stateCfg := state.Config{
+		ForkIDIntervals: []state.ForkIDInterval{{
+			FromBatchNumber: 0,
+			ToBatchNumber:   math.MaxUint64,
+			ForkId:          uint64(state.FORKID_ETROG),
+			Version:         "",
+		}},
+	}
+	ctx := context.Background()
+	testState := state.NewState(stateCfg, mockStorage, nil, nil, nil, nil)
+
+	mt, err := l1infotree.NewL1InfoTree(uint8(32), nil)
+	require.NoError(t, err)
+	leaves := []state.L1InfoTreeExitRootStorageEntry{}
+	for i := 0; i < 3; i++ {
+		leaf := state.L1InfoTreeLeaf{
+			GlobalExitRoot: state.GlobalExitRoot{
+				GlobalExitRoot: common.BigToHash(big.NewInt(int64(i + 1))),
+				Timestamp:      time.Unix(int64(i), 0),
+			},
+			PreviousBlockHash: common.BigToHash(big.NewInt(int64(i + 10))),
+		}
+		root, err := mt.AddLeaf(uint32(i), leaf.Hash())
+		require.NoError(t, err)
+		leaves = append(leaves, state.L1InfoTreeExitRootStorageEntry{L1InfoTreeLeaf: leaf, L1InfoTreeRoot: root, L1InfoTreeIndex: uint32(i)})
+	}
+	latestRoot := leaves[2].L1InfoTreeRoot
+
+	mockStorage.EXPECT().GetLatestIndex(ctx, nil).Return(uint32(2), nil).Once()
+	mockStorage.EXPECT().GetL1InfoRootLeafByIndex(ctx, uint32(2), nil).Return(leaves[2], nil).Once()
+	mockStorage.EXPECT().GetLeafsByL1InfoRoot(ctx, latestRoot, nil).Return(leaves, nil).Once()
+
+	proof, root, err := testState.GetL1InfoTreeMerkleProof(ctx, 1, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, latestRoot, root)
+	require.Len(t, proof, 32)
+
+	// the root is rebuilt from the leaf and its siblings
+	node := leaves[1].Hash()
+	index := 1
+	for _, sibling := range proof {
+		if index%2 == 1 {
+			node = l1infotree.Hash(sibling, node)
+		} else {
+			node = l1infotree.Hash(node, sibling)
+		}
+		index /= 2
+	}
+	require.Equal(t, latestRoot, common.Hash(node))
+
+	// the tree is cached, so its leaves are not loaded again
+	mockStorage.EXPECT().GetL1InfoRootLeafByL1InfoRoot(ctx, latestRoot, nil).Return(leaves[2], nil).Once()
+	cachedProof, root, err := testState.GetL1InfoTreeMerkleProof(ctx, 1, &latestRoot, nil)
+	require.NoError(t, err)
+	require.Equal(t, latestRoot, root)
+	require.Equal(t, proof, cachedProof)
+
+	// the tree of a previous root is built from its own leaves
+	previousRoot := leaves[1].L1InfoTreeRoot
+	mockStorage.EXPECT().GetL1InfoRootLeafByL1InfoRoot(ctx, previousRoot, nil).Return(leaves[1], nil).Once()
+	mockStorage.EXPECT().GetLeafsByL1InfoRoot(ctx, previousRoot, nil).Return(leaves[:2], nil).Once()
+	_, root, err = testState.GetL1InfoTreeMerkleProof(ctx, 1, &previousRoot, nil)
+	require.NoError(t, err)
+	require.Equal(t, previousRoot, root)
+
+	// the leaf is not in the tree of a previous root
+	previousRoot = leaves[0].L1InfoTreeRoot
+	mockStorage.EXPECT().GetL1InfoRootLeafByL1InfoRoot(ctx, previousRoot, nil).Return(leaves[0], nil).Once()
+	_, _, err = testState.GetL1InfoTreeMerkleProof(ctx, 1, &previousRoot, nil)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	// the root is unknown
+	unknownRoot := common.HexToHash("0x1")
+	mockStorage.EXPECT().GetL1InfoRootLeafByL1InfoRoot(ctx, unknownRoot, nil).Return(state.L1InfoTreeExitRootStorageEntry{}, nil).Once()
+	_, _, err = testState.GetL1InfoTreeMerkleProof(ctx, 0, &unknownRoot, nil)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	// the latest tree is still cached
+	mockStorage.EXPECT().GetLatestIndex(ctx, nil).Return(uint32(2), nil).Once()
+	mockStorage.EXPECT().GetL1InfoRootLeafByIndex(ctx, uint32(2), nil).Return(leaves[2], nil).Once()
+	cachedProof, _, err = testState.GetL1InfoTreeMerkleProof(ctx, 1, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, proof, cachedProof)
+}
@@ -130,6 +130,54 @@ func (_c *StorageMock_AddBlock_Call) RunAndReturn(run func(context.Context, *sta
 	return _c
 }
 
+// AddFinalProof provides a mock function with given fields: ctx, proof, dbTx
+func (_m *StorageMock) AddFinalProof(ctx context.Context, proof *state.FinalProof, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, proof, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFinalProof")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *state.FinalProof, pgx.Tx) error); ok {
+		r0 = rf(ctx, proof, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_AddFinalProof_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddFinalProof'
+type StorageMock_AddFinalProof_Call struct {
+	*mock.Call
+}
+
+// AddFinalProof is a helper method to define mock.On call
+//   - ctx context.Context
+//   - proof *state.FinalProof
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) AddFinalProof(ctx interface{}, proof interface{}, dbTx interface{}) *StorageMock_AddFinalProof_Call {
+	return &StorageMock_AddFinalProof_Call{Call: _e.mock.On("AddFinalProof", ctx, proof, dbTx)}
+}
+
+func (_c *StorageMock_AddFinalProof_Call) Run(run func(ctx context.Context, proof *state.FinalProof, dbTx pgx.Tx)) *StorageMock_AddFinalProof_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*state.FinalProof), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_AddFinalProof_Call) Return(_a0 error) *StorageMock_AddFinalProof_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_AddFinalProof_Call) RunAndReturn(run func(context.Context, *state.FinalProof, pgx.Tx) error) *StorageMock_AddFinalProof_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddForcedBatch provides a mock function with given fields: ctx, forcedBatch, tx
 func (_m *StorageMock) AddForcedBatch(ctx context.Context, forcedBatch *state.ForcedBatch, tx pgx.Tx) error {
 	ret := _m.Called(ctx, forcedBatch, tx)
@@ -418,17 +466,17 @@ func (_c *StorageMock_AddL1InfoRootToExitRoot_Call) RunAndReturn(run func(contex
 	return _c
 }
 
-// AddL2Block provides a mock function with given fields: ctx, batchNumber, l2Block, receipts, txsL2Hash, txsEGPData, dbTx
-func (_m *StorageMock) AddL2Block(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx) error {
-	ret := _m.Called(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsEGPData, dbTx)
+// AddL2Block provides a mock function with given fields: ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, txsEGPData, dbTx
+func (_m *StorageMock) AddL2Block(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsSenders []common.Address, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, txsEGPData, dbTx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddL2Block")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, *state.L2Block, []*types.Receipt, []common.Hash, []state.StoreTxEGPData, pgx.Tx) error); ok {
-		r0 = rf(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsEGPData, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *state.L2Block, []*types.Receipt, []common.Hash, []common.Address, []state.StoreTxEGPData, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, txsEGPData, dbTx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -447,15 +495,16 @@ type StorageMock_AddL2Block_Call struct {
 //   - l2Block *state.L2Block
 //   - receipts []*types.Receipt
 //   - txsL2Hash []common.Hash
+//   - txsSenders []common.Address
 //   - txsEGPData []state.StoreTxEGPData
 //   - dbTx pgx.Tx
-func (_e *StorageMock_Expecter) AddL2Block(ctx interface{}, batchNumber interface{}, l2Block interface{}, receipts interface{}, txsL2Hash interface{}, txsEGPData interface{}, dbTx interface{}) *StorageMock_AddL2Block_Call {
-	return &StorageMock_AddL2Block_Call{Call: _e.mock.On("AddL2Block", ctx, batchNumber, l2Block, receipts, txsL2Hash, txsEGPData, dbTx)}
+func (_e *StorageMock_Expecter) AddL2Block(ctx interface{}, batchNumber interface{}, l2Block interface{}, receipts interface{}, txsL2Hash interface{}, txsSenders interface{}, txsEGPData interface{}, dbTx interface{}) *StorageMock_AddL2Block_Call {
+	return &StorageMock_AddL2Block_Call{Call: _e.mock.On("AddL2Block", ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, txsEGPData, dbTx)}
 }
 
-func (_c *StorageMock_AddL2Block_Call) Run(run func(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx)) *StorageMock_AddL2Block_Call {
+func (_c *StorageMock_AddL2Block_Call) Run(run func(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsSenders []common.Address, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx)) *StorageMock_AddL2Block_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(*state.L2Block), args[3].([]*types.Receipt), args[4].([]common.Hash), args[5].([]state.StoreTxEGPData), args[6].(pgx.Tx))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(*state.L2Block), args[3].([]*types.Receipt), args[4].([]common.Hash), args[5].([]common.Address), args[6].([]state.StoreTxEGPData), args[7].(pgx.Tx))
 	})
 	return _c
 }
@@ -465,7 +514,7 @@ func (_c *StorageMock_AddL2Block_Call) Return(_a0 error) *StorageMock_AddL2Block
 	return _c
 }
 
-func (_c *StorageMock_AddL2Block_Call) RunAndReturn(run func(context.Context, uint64, *state.L2Block, []*types.Receipt, []common.Hash, []state.StoreTxEGPData, pgx.Tx) error) *StorageMock_AddL2Block_Call {
+func (_c *StorageMock_AddL2Block_Call) RunAndReturn(run func(context.Context, uint64, *state.L2Block, []*types.Receipt, []common.Hash, []common.Address, []state.StoreTxEGPData, pgx.Tx) error) *StorageMock_AddL2Block_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2008,6 +2057,64 @@ func (_c *StorageMock_GetBlockNumVirtualBatchByBatchNum_Call) RunAndReturn(run f
 	return _c
 }
 
+// GetContractCreationTxHash provides a mock function with given fields: ctx, address, dbTx
+func (_m *StorageMock) GetContractCreationTxHash(ctx context.Context, address common.Address, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, address, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractCreationTxHash")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, address, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, address, dbTx)
+	} else {
+		r0 = ret.Get(0).(common.Hash)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetContractCreationTxHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetContractCreationTxHash'
+type StorageMock_GetContractCreationTxHash_Call struct {
+	*mock.Call
+}
+
+// GetContractCreationTxHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - address common.Address
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetContractCreationTxHash(ctx interface{}, address interface{}, dbTx interface{}) *StorageMock_GetContractCreationTxHash_Call {
+	return &StorageMock_GetContractCreationTxHash_Call{Call: _e.mock.On("GetContractCreationTxHash", ctx, address, dbTx)}
+}
+
+func (_c *StorageMock_GetContractCreationTxHash_Call) Run(run func(ctx context.Context, address common.Address, dbTx pgx.Tx)) *StorageMock_GetContractCreationTxHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetContractCreationTxHash_Call) Return(_a0 common.Hash, _a1 error) *StorageMock_GetContractCreationTxHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetContractCreationTxHash_Call) RunAndReturn(run func(context.Context, common.Address, pgx.Tx) (common.Hash, error)) *StorageMock_GetContractCreationTxHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetDSBatches provides a mock function with given fields: ctx, firstBatchNumber, lastBatchNumber, readWIPBatch, dbTx
 func (_m *StorageMock) GetDSBatches(ctx context.Context, firstBatchNumber uint64, lastBatchNumber uint64, readWIPBatch bool, dbTx pgx.Tx) ([]*state.DSBatch, error) {
 	ret := _m.Called(ctx, firstBatchNumber, lastBatchNumber, readWIPBatch, dbTx)
@@ -2380,6 +2487,127 @@ func (_c *StorageMock_GetExitRootByGlobalExitRoot_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// GetFinalProof provides a mock function with given fields: ctx, batchNumber, batchNumberFinal, dbTx
+func (_m *StorageMock) GetFinalProof(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	ret := _m.Called(ctx, batchNumber, batchNumberFinal, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFinalProof")
+	}
+
+	var r0 *state.FinalProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) (*state.FinalProof, error)); ok {
+		return rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) *state.FinalProof); ok {
+		r0 = rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.FinalProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, batchNumberFinal, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetFinalProof_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFinalProof'
+type StorageMock_GetFinalProof_Call struct {
+	*mock.Call
+}
+
+// GetFinalProof is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - batchNumberFinal uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetFinalProof(ctx interface{}, batchNumber interface{}, batchNumberFinal interface{}, dbTx interface{}) *StorageMock_GetFinalProof_Call {
+	return &StorageMock_GetFinalProof_Call{Call: _e.mock.On("GetFinalProof", ctx, batchNumber, batchNumberFinal, dbTx)}
+}
+
+func (_c *StorageMock_GetFinalProof_Call) Run(run func(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, dbTx pgx.Tx)) *StorageMock_GetFinalProof_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetFinalProof_Call) Return(_a0 *state.FinalProof, _a1 error) *StorageMock_GetFinalProof_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetFinalProof_Call) RunAndReturn(run func(context.Context, uint64, uint64, pgx.Tx) (*state.FinalProof, error)) *StorageMock_GetFinalProof_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFinalProofByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StorageMock) GetFinalProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFinalProofByBatchNumber")
+	}
+
+	var r0 *state.FinalProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.FinalProof, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.FinalProof); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.FinalProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetFinalProofByBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFinalProofByBatchNumber'
+type StorageMock_GetFinalProofByBatchNumber_Call struct {
+	*mock.Call
+}
+
+// GetFinalProofByBatchNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetFinalProofByBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetFinalProofByBatchNumber_Call {
+	return &StorageMock_GetFinalProofByBatchNumber_Call{Call: _e.mock.On("GetFinalProofByBatchNumber", ctx, batchNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetFinalProofByBatchNumber_Call) Run(run func(ctx context.Context, batchNumber uint64, dbTx pgx.Tx)) *StorageMock_GetFinalProofByBatchNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetFinalProofByBatchNumber_Call) Return(_a0 *state.FinalProof, _a1 error) *StorageMock_GetFinalProofByBatchNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetFinalProofByBatchNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (*state.FinalProof, error)) *StorageMock_GetFinalProofByBatchNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFirstL2BlockNumberForBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetFirstL2BlockNumberForBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -2818,6 +3046,66 @@ func (_c *StorageMock_GetForkIDs_Call) RunAndReturn(run func(context.Context, pg
 	return _c
 }
 
+// GetGeneratedProofByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StorageMock) GetGeneratedProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Proof, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGeneratedProofByBatchNumber")
+	}
+
+	var r0 *state.Proof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.Proof, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.Proof); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.Proof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetGeneratedProofByBatchNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetGeneratedProofByBatchNumber'
+type StorageMock_GetGeneratedProofByBatchNumber_Call struct {
+	*mock.Call
+}
+
+// GetGeneratedProofByBatchNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetGeneratedProofByBatchNumber(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetGeneratedProofByBatchNumber_Call {
+	return &StorageMock_GetGeneratedProofByBatchNumber_Call{Call: _e.mock.On("GetGeneratedProofByBatchNumber", ctx, batchNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetGeneratedProofByBatchNumber_Call) Run(run func(ctx context.Context, batchNumber uint64, dbTx pgx.Tx)) *StorageMock_GetGeneratedProofByBatchNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetGeneratedProofByBatchNumber_Call) Return(_a0 *state.Proof, _a1 error) *StorageMock_GetGeneratedProofByBatchNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetGeneratedProofByBatchNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (*state.Proof, error)) *StorageMock_GetGeneratedProofByBatchNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetL1InfoRootLeafByIndex provides a mock function with given fields: ctx, l1InfoTreeIndex, dbTx
 func (_m *StorageMock) GetL1InfoRootLeafByIndex(ctx context.Context, l1InfoTreeIndex uint32, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
 	ret := _m.Called(ctx, l1InfoTreeIndex, dbTx)
@@ -2934,6 +3222,64 @@ func (_c *StorageMock_GetL1InfoRootLeafByL1InfoRoot_Call) RunAndReturn(run func(
 	return _c
 }
 
+// GetL1InfoTreeLeafByGlobalExitRoot provides a mock function with given fields: ctx, globalExitRoot, dbTx
+func (_m *StorageMock) GetL1InfoTreeLeafByGlobalExitRoot(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	ret := _m.Called(ctx, globalExitRoot, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1InfoTreeLeafByGlobalExitRoot")
+	}
+
+	var r0 state.L1InfoTreeExitRootStorageEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error)); ok {
+		return rf(ctx, globalExitRoot, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) state.L1InfoTreeExitRootStorageEntry); ok {
+		r0 = rf(ctx, globalExitRoot, dbTx)
+	} else {
+		r0 = ret.Get(0).(state.L1InfoTreeExitRootStorageEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, globalExitRoot, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetL1InfoTreeLeafByGlobalExitRoot'
+type StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call struct {
+	*mock.Call
+}
+
+// GetL1InfoTreeLeafByGlobalExitRoot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - globalExitRoot common.Hash
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetL1InfoTreeLeafByGlobalExitRoot(ctx interface{}, globalExitRoot interface{}, dbTx interface{}) *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call {
+	return &StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call{Call: _e.mock.On("GetL1InfoTreeLeafByGlobalExitRoot", ctx, globalExitRoot, dbTx)}
+}
+
+func (_c *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call) Run(run func(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx)) *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Hash), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call) Return(_a0 state.L1InfoTreeExitRootStorageEntry, _a1 error) *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call) RunAndReturn(run func(context.Context, common.Hash, pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error)) *StorageMock_GetL1InfoTreeLeafByGlobalExitRoot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetL2BlockByHash provides a mock function with given fields: ctx, hash, dbTx
 func (_m *StorageMock) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, hash, dbTx)
@@ -3530,12 +3876,72 @@ func (_c *StorageMock_GetL2TxHashByTxHash_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
-// GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
-func (_m *StorageMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
-	ret := _m.Called(ctx, dbTx)
+// GetL2TxHashesByL2BlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StorageMock) GetL2TxHashesByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (map[common.Hash]common.Hash, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLastBatchNumber")
+		panic("no return value specified for GetL2TxHashesByL2BlockNumber")
+	}
+
+	var r0 map[common.Hash]common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (map[common.Hash]common.Hash, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) map[common.Hash]common.Hash); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Hash]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetL2TxHashesByL2BlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetL2TxHashesByL2BlockNumber'
+type StorageMock_GetL2TxHashesByL2BlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetL2TxHashesByL2BlockNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetL2TxHashesByL2BlockNumber(ctx interface{}, blockNumber interface{}, dbTx interface{}) *StorageMock_GetL2TxHashesByL2BlockNumber_Call {
+	return &StorageMock_GetL2TxHashesByL2BlockNumber_Call{Call: _e.mock.On("GetL2TxHashesByL2BlockNumber", ctx, blockNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetL2TxHashesByL2BlockNumber_Call) Run(run func(ctx context.Context, blockNumber uint64, dbTx pgx.Tx)) *StorageMock_GetL2TxHashesByL2BlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetL2TxHashesByL2BlockNumber_Call) Return(_a0 map[common.Hash]common.Hash, _a1 error) *StorageMock_GetL2TxHashesByL2BlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetL2TxHashesByL2BlockNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (map[common.Hash]common.Hash, error)) *StorageMock_GetL2TxHashesByL2BlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastBatchNumber")
 	}
 
 	var r0 uint64
@@ -6400,6 +6806,66 @@ func (_c *StorageMock_GetTransactionReceipt_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetTransactionReceiptsByL2BlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StorageMock) GetTransactionReceiptsByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionReceiptsByL2BlockNumber")
+	}
+
+	var r0 []*types.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]*types.Receipt, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []*types.Receipt); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTransactionReceiptsByL2BlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionReceiptsByL2BlockNumber'
+type StorageMock_GetTransactionReceiptsByL2BlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetTransactionReceiptsByL2BlockNumber is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTransactionReceiptsByL2BlockNumber(ctx interface{}, blockNumber interface{}, dbTx interface{}) *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call {
+	return &StorageMock_GetTransactionReceiptsByL2BlockNumber_Call{Call: _e.mock.On("GetTransactionReceiptsByL2BlockNumber", ctx, blockNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call) Run(run func(ctx context.Context, blockNumber uint64, dbTx pgx.Tx)) *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call) Return(_a0 []*types.Receipt, _a1 error) *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) ([]*types.Receipt, error)) *StorageMock_GetTransactionReceiptsByL2BlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTransactionsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Transaction, []uint8, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -6469,6 +6935,65 @@ func (_c *StorageMock_GetTransactionsByBatchNumber_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// GetTxHashBySenderAndNonce provides a mock function with given fields: ctx, sender, nonce, dbTx
+func (_m *StorageMock) GetTxHashBySenderAndNonce(ctx context.Context, sender common.Address, nonce uint64, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, sender, nonce, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxHashBySenderAndNonce")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, sender, nonce, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, sender, nonce, dbTx)
+	} else {
+		r0 = ret.Get(0).(common.Hash)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, sender, nonce, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTxHashBySenderAndNonce_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxHashBySenderAndNonce'
+type StorageMock_GetTxHashBySenderAndNonce_Call struct {
+	*mock.Call
+}
+
+// GetTxHashBySenderAndNonce is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sender common.Address
+//   - nonce uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTxHashBySenderAndNonce(ctx interface{}, sender interface{}, nonce interface{}, dbTx interface{}) *StorageMock_GetTxHashBySenderAndNonce_Call {
+	return &StorageMock_GetTxHashBySenderAndNonce_Call{Call: _e.mock.On("GetTxHashBySenderAndNonce", ctx, sender, nonce, dbTx)}
+}
+
+func (_c *StorageMock_GetTxHashBySenderAndNonce_Call) Run(run func(ctx context.Context, sender common.Address, nonce uint64, dbTx pgx.Tx)) *StorageMock_GetTxHashBySenderAndNonce_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(uint64), args[3].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTxHashBySenderAndNonce_Call) Return(_a0 common.Hash, _a1 error) *StorageMock_GetTxHashBySenderAndNonce_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTxHashBySenderAndNonce_Call) RunAndReturn(run func(context.Context, common.Address, uint64, pgx.Tx) (common.Hash, error)) *StorageMock_GetTxHashBySenderAndNonce_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTxsByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetTxsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*types.Transaction, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -6589,6 +7114,130 @@ func (_c *StorageMock_GetTxsByBlockNumber_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetTxsHashesByAddressAfter provides a mock function with given fields: ctx, address, blockNumber, limit, dbTx
+func (_m *StorageMock) GetTxsHashesByAddressAfter(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	ret := _m.Called(ctx, address, blockNumber, limit, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsHashesByAddressAfter")
+	}
+
+	var r0 []common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)); ok {
+		return rf(ctx, address, blockNumber, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) []common.Hash); ok {
+		r0 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTxsHashesByAddressAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsHashesByAddressAfter'
+type StorageMock_GetTxsHashesByAddressAfter_Call struct {
+	*mock.Call
+}
+
+// GetTxsHashesByAddressAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - address common.Address
+//   - blockNumber uint64
+//   - limit uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTxsHashesByAddressAfter(ctx interface{}, address interface{}, blockNumber interface{}, limit interface{}, dbTx interface{}) *StorageMock_GetTxsHashesByAddressAfter_Call {
+	return &StorageMock_GetTxsHashesByAddressAfter_Call{Call: _e.mock.On("GetTxsHashesByAddressAfter", ctx, address, blockNumber, limit, dbTx)}
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressAfter_Call) Run(run func(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx)) *StorageMock_GetTxsHashesByAddressAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(uint64), args[3].(uint64), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressAfter_Call) Return(_a0 []common.Hash, _a1 error) *StorageMock_GetTxsHashesByAddressAfter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressAfter_Call) RunAndReturn(run func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)) *StorageMock_GetTxsHashesByAddressAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTxsHashesByAddressBefore provides a mock function with given fields: ctx, address, blockNumber, limit, dbTx
+func (_m *StorageMock) GetTxsHashesByAddressBefore(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	ret := _m.Called(ctx, address, blockNumber, limit, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsHashesByAddressBefore")
+	}
+
+	var r0 []common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)); ok {
+		return rf(ctx, address, blockNumber, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) []common.Hash); ok {
+		r0 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, address, blockNumber, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTxsHashesByAddressBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsHashesByAddressBefore'
+type StorageMock_GetTxsHashesByAddressBefore_Call struct {
+	*mock.Call
+}
+
+// GetTxsHashesByAddressBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - address common.Address
+//   - blockNumber uint64
+//   - limit uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTxsHashesByAddressBefore(ctx interface{}, address interface{}, blockNumber interface{}, limit interface{}, dbTx interface{}) *StorageMock_GetTxsHashesByAddressBefore_Call {
+	return &StorageMock_GetTxsHashesByAddressBefore_Call{Call: _e.mock.On("GetTxsHashesByAddressBefore", ctx, address, blockNumber, limit, dbTx)}
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressBefore_Call) Run(run func(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx)) *StorageMock_GetTxsHashesByAddressBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Address), args[2].(uint64), args[3].(uint64), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressBefore_Call) Return(_a0 []common.Hash, _a1 error) *StorageMock_GetTxsHashesByAddressBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTxsHashesByAddressBefore_Call) RunAndReturn(run func(context.Context, common.Address, uint64, uint64, pgx.Tx) ([]common.Hash, error)) *StorageMock_GetTxsHashesByAddressBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTxsHashesByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetTxsHashesByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]common.Hash, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -6770,6 +7419,66 @@ func (_c *StorageMock_GetTxsOlderThanNL1BlocksUntilTxHash_Call) RunAndReturn(run
 	return _c
 }
 
+// GetTxsWithoutAddresses provides a mock function with given fields: ctx, limit, dbTx
+func (_m *StorageMock) GetTxsWithoutAddresses(ctx context.Context, limit uint64, dbTx pgx.Tx) (map[common.Hash]string, error) {
+	ret := _m.Called(ctx, limit, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsWithoutAddresses")
+	}
+
+	var r0 map[common.Hash]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (map[common.Hash]string, error)); ok {
+		return rf(ctx, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) map[common.Hash]string); ok {
+		r0 = rf(ctx, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Hash]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetTxsWithoutAddresses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxsWithoutAddresses'
+type StorageMock_GetTxsWithoutAddresses_Call struct {
+	*mock.Call
+}
+
+// GetTxsWithoutAddresses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetTxsWithoutAddresses(ctx interface{}, limit interface{}, dbTx interface{}) *StorageMock_GetTxsWithoutAddresses_Call {
+	return &StorageMock_GetTxsWithoutAddresses_Call{Call: _e.mock.On("GetTxsWithoutAddresses", ctx, limit, dbTx)}
+}
+
+func (_c *StorageMock_GetTxsWithoutAddresses_Call) Run(run func(ctx context.Context, limit uint64, dbTx pgx.Tx)) *StorageMock_GetTxsWithoutAddresses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetTxsWithoutAddresses_Call) Return(_a0 map[common.Hash]string, _a1 error) *StorageMock_GetTxsWithoutAddresses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetTxsWithoutAddresses_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (map[common.Hash]string, error)) *StorageMock_GetTxsWithoutAddresses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetVerifiedBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -6830,6 +7539,66 @@ func (_c *StorageMock_GetVerifiedBatch_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// GetVerifiedBatchContaining provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StorageMock) GetVerifiedBatchContaining(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVerifiedBatchContaining")
+	}
+
+	var r0 *state.VerifiedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.VerifiedBatch, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.VerifiedBatch); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.VerifiedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_GetVerifiedBatchContaining_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVerifiedBatchContaining'
+type StorageMock_GetVerifiedBatchContaining_Call struct {
+	*mock.Call
+}
+
+// GetVerifiedBatchContaining is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) GetVerifiedBatchContaining(ctx interface{}, batchNumber interface{}, dbTx interface{}) *StorageMock_GetVerifiedBatchContaining_Call {
+	return &StorageMock_GetVerifiedBatchContaining_Call{Call: _e.mock.On("GetVerifiedBatchContaining", ctx, batchNumber, dbTx)}
+}
+
+func (_c *StorageMock_GetVerifiedBatchContaining_Call) Run(run func(ctx context.Context, batchNumber uint64, dbTx pgx.Tx)) *StorageMock_GetVerifiedBatchContaining_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_GetVerifiedBatchContaining_Call) Return(_a0 *state.VerifiedBatch, _a1 error) *StorageMock_GetVerifiedBatchContaining_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_GetVerifiedBatchContaining_Call) RunAndReturn(run func(context.Context, uint64, pgx.Tx) (*state.VerifiedBatch, error)) *StorageMock_GetVerifiedBatchContaining_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetVirtualBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VirtualBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -8052,6 +8821,121 @@ func (_c *StorageMock_StoreGenesisBatch_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// SubscribeStateEvents provides a mock function with given fields: ctx
+func (_m *StorageMock) SubscribeStateEvents(ctx context.Context) (<-chan state.StateEvent, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeStateEvents")
+	}
+
+	var r0 <-chan state.StateEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan state.StateEvent, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan state.StateEvent); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan state.StateEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_SubscribeStateEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscribeStateEvents'
+type StorageMock_SubscribeStateEvents_Call struct {
+	*mock.Call
+}
+
+// SubscribeStateEvents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *StorageMock_Expecter) SubscribeStateEvents(ctx interface{}) *StorageMock_SubscribeStateEvents_Call {
+	return &StorageMock_SubscribeStateEvents_Call{Call: _e.mock.On("SubscribeStateEvents", ctx)}
+}
+
+func (_c *StorageMock_SubscribeStateEvents_Call) Run(run func(ctx context.Context)) *StorageMock_SubscribeStateEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *StorageMock_SubscribeStateEvents_Call) Return(_a0 <-chan state.StateEvent, _a1 error) *StorageMock_SubscribeStateEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_SubscribeStateEvents_Call) RunAndReturn(run func(context.Context) (<-chan state.StateEvent, error)) *StorageMock_SubscribeStateEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TryLockTxsAddressesBackfill provides a mock function with given fields: ctx, dbTx
+func (_m *StorageMock) TryLockTxsAddressesBackfill(ctx context.Context, dbTx pgx.Tx) (bool, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TryLockTxsAddressesBackfill")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (bool, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) bool); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StorageMock_TryLockTxsAddressesBackfill_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TryLockTxsAddressesBackfill'
+type StorageMock_TryLockTxsAddressesBackfill_Call struct {
+	*mock.Call
+}
+
+// TryLockTxsAddressesBackfill is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) TryLockTxsAddressesBackfill(ctx interface{}, dbTx interface{}) *StorageMock_TryLockTxsAddressesBackfill_Call {
+	return &StorageMock_TryLockTxsAddressesBackfill_Call{Call: _e.mock.On("TryLockTxsAddressesBackfill", ctx, dbTx)}
+}
+
+func (_c *StorageMock_TryLockTxsAddressesBackfill_Call) Run(run func(ctx context.Context, dbTx pgx.Tx)) *StorageMock_TryLockTxsAddressesBackfill_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_TryLockTxsAddressesBackfill_Call) Return(_a0 bool, _a1 error) *StorageMock_TryLockTxsAddressesBackfill_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StorageMock_TryLockTxsAddressesBackfill_Call) RunAndReturn(run func(context.Context, pgx.Tx) (bool, error)) *StorageMock_TryLockTxsAddressesBackfill_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateBatchAsChecked provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StorageMock) UpdateBatchAsChecked(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -8149,6 +9033,56 @@ func (_c *StorageMock_UpdateBatchL2Data_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
+// UpdateFinalProofStatus provides a mock function with given fields: ctx, batchNumber, batchNumberFinal, status, dbTx
+func (_m *StorageMock) UpdateFinalProofStatus(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, status state.FinalProofStatus, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, batchNumberFinal, status, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFinalProofStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, state.FinalProofStatus, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, batchNumberFinal, status, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_UpdateFinalProofStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateFinalProofStatus'
+type StorageMock_UpdateFinalProofStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateFinalProofStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchNumber uint64
+//   - batchNumberFinal uint64
+//   - status state.FinalProofStatus
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) UpdateFinalProofStatus(ctx interface{}, batchNumber interface{}, batchNumberFinal interface{}, status interface{}, dbTx interface{}) *StorageMock_UpdateFinalProofStatus_Call {
+	return &StorageMock_UpdateFinalProofStatus_Call{Call: _e.mock.On("UpdateFinalProofStatus", ctx, batchNumber, batchNumberFinal, status, dbTx)}
+}
+
+func (_c *StorageMock_UpdateFinalProofStatus_Call) Run(run func(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, status state.FinalProofStatus, dbTx pgx.Tx)) *StorageMock_UpdateFinalProofStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint64), args[3].(state.FinalProofStatus), args[4].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_UpdateFinalProofStatus_Call) Return(_a0 error) *StorageMock_UpdateFinalProofStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_UpdateFinalProofStatus_Call) RunAndReturn(run func(context.Context, uint64, uint64, state.FinalProofStatus, pgx.Tx) error) *StorageMock_UpdateFinalProofStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateForkID provides a mock function with given fields: ctx, forkID, dbTx
 func (_m *StorageMock) UpdateForkID(ctx context.Context, forkID state.ForkIDInterval, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, forkID, dbTx)
@@ -8326,6 +9260,57 @@ func (_c *StorageMock_UpdateGeneratedProof_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// UpdateTxAddresses provides a mock function with given fields: ctx, txHash, from, to, nonce, dbTx
+func (_m *StorageMock) UpdateTxAddresses(ctx context.Context, txHash common.Hash, from common.Address, to *common.Address, nonce *uint64, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, txHash, from, to, nonce, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTxAddresses")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, common.Address, *common.Address, *uint64, pgx.Tx) error); ok {
+		r0 = rf(ctx, txHash, from, to, nonce, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StorageMock_UpdateTxAddresses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTxAddresses'
+type StorageMock_UpdateTxAddresses_Call struct {
+	*mock.Call
+}
+
+// UpdateTxAddresses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - txHash common.Hash
+//   - from common.Address
+//   - to *common.Address
+//   - nonce *uint64
+//   - dbTx pgx.Tx
+func (_e *StorageMock_Expecter) UpdateTxAddresses(ctx interface{}, txHash interface{}, from interface{}, to interface{}, nonce interface{}, dbTx interface{}) *StorageMock_UpdateTxAddresses_Call {
+	return &StorageMock_UpdateTxAddresses_Call{Call: _e.mock.On("UpdateTxAddresses", ctx, txHash, from, to, nonce, dbTx)}
+}
+
+func (_c *StorageMock_UpdateTxAddresses_Call) Run(run func(ctx context.Context, txHash common.Hash, from common.Address, to *common.Address, nonce *uint64, dbTx pgx.Tx)) *StorageMock_UpdateTxAddresses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(common.Hash), args[2].(common.Address), args[3].(*common.Address), args[4].(*uint64), args[5].(pgx.Tx))
+	})
+	return _c
+}
+
+func (_c *StorageMock_UpdateTxAddresses_Call) Return(_a0 error) *StorageMock_UpdateTxAddresses_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StorageMock_UpdateTxAddresses_Call) RunAndReturn(run func(context.Context, common.Hash, common.Address, *common.Address, *uint64, pgx.Tx) error) *StorageMock_UpdateTxAddresses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateWIPBatch provides a mock function with given fields: ctx, receipt, dbTx
 func (_m *StorageMock) UpdateWIPBatch(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, receipt, dbTx)
@@ -0,0 +1,228 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// lastBlockStoragePos is the storage position of the system smart contract
+// where the number of the last processed L2 block is stored
+const lastBlockStoragePos = 0
+
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of an unsigned transaction. State and StateDiff can't be set at
+// the same time: State replaces the whole account storage while StateDiff
+// overrides only the provided slots.
+//
+// The executor ignores zero nonces and empty codes, so an account can't be
+// overridden to have nonce zero or no code.
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      []byte
+	Balance   *big.Int
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+}
+
+// StateOverride is the set of accounts to override during the execution of
+// an unsigned transaction, indexed by address
+type StateOverride map[common.Address]OverrideAccount
+
+// BlockOverride indicates the overriding fields of the block in which an
+// unsigned transaction is executed
+type BlockOverride struct {
+	Number *uint64
+	Time   *uint64
+}
+
+// Validate checks that the overrides can be applied
+func (so StateOverride) Validate() error {
+	for address, account := range so {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both state and stateDiff overrides", address.String())
+		}
+	}
+	return nil
+}
+
+// nonce returns the nonce the address has once the overrides are applied
+func (so StateOverride) nonce(address common.Address, loadedNonce uint64) uint64 {
+	if account, found := so[address]; found && account.Nonce != nil && *account.Nonce != 0 {
+		return *account.Nonce
+	}
+	return loadedNonce
+}
+
+// balance returns the overridden balance of the address, if any
+func (so StateOverride) balance(address common.Address) (*big.Int, bool) {
+	if account, found := so[address]; found && account.Balance != nil {
+		return account.Balance, true
+	}
+	return nil, false
+}
+
+// code returns the overridden code of the address, if any
+func (so StateOverride) code(address common.Address) ([]byte, bool) {
+	if account, found := so[address]; found && len(account.Code) > 0 {
+		return account.Code, true
+	}
+	return nil, false
+}
+
+// codeHash returns the hash the overridden code of the address has in the state tree, if any
+func (so StateOverride) codeHash(address common.Address) (common.Hash, bool, error) {
+	code, found := so.code(address)
+	if !found {
+		return common.Hash{}, false, nil
+	}
+	hash, err := merkletree.HashContractBytecode(code)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	return common.HexToHash(merkletree.H4ToString(hash)), true, nil
+}
+
+// storage returns the overridden value of a storage slot of the address, if any.
+// The slots not provided are zero when the whole storage of the account is overridden
+func (so StateOverride) storage(address common.Address, slot common.Hash) (common.Hash, bool) {
+	account, found := so[address]
+	if !found {
+		return common.Hash{}, false
+	}
+	if account.State != nil {
+		return account.State[slot], true
+	}
+	value, found := account.StateDiff[slot]
+	return value, found
+}
+
+// withBlockNumber returns a copy of the overrides that also sets the number of the
+// block processed before the unsigned transaction in the system smart contract,
+// so the transaction is executed as part of the overridden block number
+func (so StateOverride) withBlockNumber(blockOverride *BlockOverride) StateOverride {
+	if blockOverride == nil || blockOverride.Number == nil {
+		return so
+	}
+
+	overrides := make(StateOverride, len(so)+1)
+	for address, account := range so {
+		overrides[address] = account
+	}
+
+	systemSC := common.HexToAddress(SystemSC)
+	account := overrides[systemSC]
+	lastBlockNumber := common.BigToHash(new(big.Int).SetUint64(*blockOverride.Number - 1))
+	slot := common.BigToHash(big.NewInt(lastBlockStoragePos))
+	if account.State != nil {
+		account.State = copyStorageOverride(account.State)
+		account.State[slot] = lastBlockNumber
+	} else {
+		account.StateDiff = copyStorageOverride(account.StateDiff)
+		account.StateDiff[slot] = lastBlockNumber
+	}
+	overrides[systemSC] = account
+	return overrides
+}
+
+func copyStorageOverride(storage map[common.Hash]common.Hash) map[common.Hash]common.Hash {
+	c := make(map[common.Hash]common.Hash, len(storage)+1)
+	for k, v := range storage {
+		c[k] = v
+	}
+	return c
+}
+
+func storageOverrideToExecutor(storage map[common.Hash]common.Hash) map[string]string {
+	if storage == nil {
+		return nil
+	}
+	s := make(map[string]string, len(storage))
+	for k, v := range storage {
+		s[k.String()] = v.String()
+	}
+	return s
+}
+
+// toExecutorV1 converts the overrides into the executor request format before ETROG
+func (so StateOverride) toExecutorV1() map[string]*executor.OverrideAccount {
+	if len(so) == 0 {
+		return nil
+	}
+	overrides := make(map[string]*executor.OverrideAccount, len(so))
+	for address, account := range so {
+		o := &executor.OverrideAccount{
+			Code:      account.Code,
+			State:     storageOverrideToExecutor(account.State),
+			StateDiff: storageOverrideToExecutor(account.StateDiff),
+		}
+		if account.Nonce != nil {
+			o.Nonce = *account.Nonce
+		}
+		if account.Balance != nil {
+			o.Balance = account.Balance.Bytes()
+		}
+		overrides[address.String()] = o
+	}
+	return overrides
+}
+
+// toExecutorV2 converts the overrides into the executor request format after ETROG
+func (so StateOverride) toExecutorV2() map[string]*executor.OverrideAccountV2 {
+	if len(so) == 0 {
+		return nil
+	}
+	overrides := make(map[string]*executor.OverrideAccountV2, len(so))
+	for address, account := range so {
+		o := &executor.OverrideAccountV2{
+			Code:      account.Code,
+			State:     storageOverrideToExecutor(account.State),
+			StateDiff: storageOverrideToExecutor(account.StateDiff),
+		}
+		if account.Nonce != nil {
+			o.Nonce = *account.Nonce
+		}
+		if account.Balance != nil {
+			o.Balance = account.Balance.Bytes()
+		}
+		overrides[address.String()] = o
+	}
+	return overrides
+}
+
+// timestampV1 returns the timestamp of the batch used to execute an unsigned
+// transaction before ETROG
+func (bo *BlockOverride) timestampV1(defaultTimestamp uint64) uint64 {
+	if bo != nil && bo.Time != nil {
+		return *bo.Time
+	}
+	return defaultTimestamp
+}
+
+// deltaTimestampV2 returns the delta timestamp of the L2 block used to execute an
+// unsigned transaction after ETROG on top of the provided block, and the limit
+// timestamp of the batch
+func (bo *BlockOverride) deltaTimestampV2(l2Block *L2Block, now uint64) (uint32, uint64, error) {
+	if bo == nil || bo.Time == nil {
+		return uint32(now - l2Block.Time()), now, nil
+	}
+	if *bo.Time < l2Block.Time() {
+		return 0, 0, fmt.Errorf("block timestamp %d is lower than the timestamp of the parent block %d", *bo.Time, l2Block.Time())
+	}
+	timestampLimit := now
+	if *bo.Time > timestampLimit {
+		timestampLimit = *bo.Time
+	}
+	return uint32(*bo.Time - l2Block.Time()), timestampLimit, nil
+}
+
+// Validate checks that the overrides can be applied
+func (bo *BlockOverride) Validate() error {
+	if bo != nil && bo.Number != nil && *bo.Number == 0 {
+		return fmt.Errorf("block number can't be overridden to the genesis block")
+	}
+	return nil
+}
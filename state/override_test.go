@@ -0,0 +1,120 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateOverride(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	nonce := uint64(5)
+	stateOverride := StateOverride{
+		address: OverrideAccount{
+			Nonce:     &nonce,
+			Code:      []byte{0x60, 0x01},
+			Balance:   big.NewInt(1000),
+			StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")},
+		},
+	}
+	require.NoError(t, stateOverride.Validate())
+
+	assert.Equal(t, nonce, stateOverride.nonce(address, 1))
+	assert.Equal(t, uint64(1), stateOverride.nonce(common.HexToAddress("0x2"), 1))
+	balance, overridden := stateOverride.balance(address)
+	assert.True(t, overridden)
+	assert.Equal(t, int64(1000), balance.Int64())
+	code, overridden := stateOverride.code(address)
+	assert.True(t, overridden)
+	assert.Equal(t, []byte{0x60, 0x01}, code)
+
+	executorOverride := stateOverride.toExecutorV2()
+	require.Len(t, executorOverride, 1)
+	account := executorOverride[address.String()]
+	require.NotNil(t, account)
+	assert.Equal(t, nonce, account.Nonce)
+	assert.Equal(t, big.NewInt(1000).Bytes(), account.Balance)
+	assert.Equal(t, []byte{0x60, 0x01}, account.Code)
+	assert.Nil(t, account.State)
+	assert.Equal(t, map[string]string{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()}, account.StateDiff)
+
+	// the block number is overridden in the system smart contract
+	number := uint64(100)
+	withBlockNumber := stateOverride.withBlockNumber(&BlockOverride{Number: &number})
+	require.Len(t, withBlockNumber, 2)
+	assert.Equal(t, common.HexToHash("0x63"), withBlockNumber[common.HexToAddress(SystemSC)].StateDiff[common.Hash{}])
+	assert.Len(t, stateOverride, 1)
+
+	invalidOverride := StateOverride{
+		address: OverrideAccount{
+			State:     map[common.Hash]common.Hash{},
+			StateDiff: map[common.Hash]common.Hash{},
+		},
+	}
+	assert.Error(t, invalidOverride.Validate())
+
+	var noOverride StateOverride
+	require.NoError(t, noOverride.Validate())
+	assert.Nil(t, noOverride.toExecutorV1())
+	assert.Nil(t, noOverride.withBlockNumber(nil))
+}
+
+func TestFakeDBStateOverride(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	replaced := common.HexToAddress("0x2")
+	fakeDB := &FakeDB{overrides: StateOverride{
+		address: OverrideAccount{
+			Code:      []byte{0x60, 0x01},
+			Balance:   big.NewInt(1000),
+			StateDiff: map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x2")},
+		},
+		replaced: OverrideAccount{
+			State: map[common.Hash]common.Hash{common.HexToHash("0x1"): common.HexToHash("0x3")},
+		},
+	}}
+
+	// the overridden values are returned without reading the state tree
+	assert.Equal(t, int64(1000), fakeDB.GetBalance(address).Int64())
+	assert.Equal(t, []byte{0x60, 0x01}, fakeDB.GetCode(address))
+	assert.Equal(t, 2, fakeDB.GetCodeSize(address))
+	codeHash, err := merkletree.HashContractBytecode([]byte{0x60, 0x01})
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash(merkletree.H4ToString(codeHash)), fakeDB.GetCodeHash(address))
+	assert.Equal(t, common.HexToHash("0x2"), fakeDB.GetState(address, common.HexToHash("0x1")))
+
+	// the whole storage is replaced by the state override
+	assert.Equal(t, common.HexToHash("0x3"), fakeDB.GetState(replaced, common.HexToHash("0x1")))
+	assert.Equal(t, common.Hash{}, fakeDB.GetState(replaced, common.HexToHash("0x2")))
+}
+
+func TestBlockOverride(t *testing.T) {
+	l2Block := NewL2BlockWithHeader(NewL2Header(&types.Header{Number: big.NewInt(10), Time: 1000}))
+
+	var noOverride *BlockOverride
+	require.NoError(t, noOverride.Validate())
+	assert.Equal(t, uint64(1100), noOverride.timestampV1(1100))
+	deltaTimestamp, timestampLimit, err := noOverride.deltaTimestampV2(l2Block, 1100)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), deltaTimestamp)
+	assert.Equal(t, uint64(1100), timestampLimit)
+
+	timestamp := uint64(2000)
+	blockOverride := &BlockOverride{Time: &timestamp}
+	assert.Equal(t, timestamp, blockOverride.timestampV1(1100))
+	deltaTimestamp, timestampLimit, err = blockOverride.deltaTimestampV2(l2Block, 1100)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1000), deltaTimestamp)
+	assert.Equal(t, timestamp, timestampLimit)
+
+	timestamp = 900
+	_, _, err = blockOverride.deltaTimestampV2(l2Block, 1100)
+	assert.Error(t, err)
+
+	number := uint64(0)
+	assert.Error(t, (&BlockOverride{Number: &number}).Validate())
+}
@@ -139,6 +139,23 @@ func (p *PostgresStorage) GetLeafsByL1InfoRoot(ctx context.Context, l1InfoRoot c
 	return entries, nil
 }
 
+// GetL1InfoTreeLeafByGlobalExitRoot returns the first leaf of the L1InfoTree
+// added for the global exit root
+func (p *PostgresStorage) GetL1InfoTreeLeafByGlobalExitRoot(ctx context.Context, globalExitRoot common.Hash, dbTx pgx.Tx) (state.L1InfoTreeExitRootStorageEntry, error) {
+	const getL1InfoTreeLeafByGlobalExitRootSQL = `SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root, prev_block_hash, l1_info_root, l1_info_tree_index
+		FROM state.exit_root 
+		WHERE l1_info_tree_index IS NOT NULL AND global_exit_root = $1
+		ORDER BY l1_info_tree_index ASC
+		LIMIT 1`
+
+	e := p.getExecQuerier(dbTx)
+	entry, err := scanL1InfoTreeExitRootStorageEntry(e.QueryRow(ctx, getL1InfoTreeLeafByGlobalExitRootSQL, globalExitRoot))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entry, state.ErrNotFound
+	}
+	return entry, err
+}
+
 func scanL1InfoTreeExitRootStorageEntry(row pgx.Row) (state.L1InfoTreeExitRootStorageEntry, error) {
 	entry := state.L1InfoTreeExitRootStorageEntry{}
 
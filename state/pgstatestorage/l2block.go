@@ -147,7 +147,7 @@ func (p *PostgresStorage) GetL2BlockTransactionCountByNumber(ctx context.Context
 }
 
 // AddL2Block adds a new L2 block to the State Store
-func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx) error {
+func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2Block *state.L2Block, receipts []*types.Receipt, txsL2Hash []common.Hash, txsSenders []common.Address, txsEGPData []state.StoreTxEGPData, dbTx pgx.Tx) error {
 	//TODO: Optmize this function using only one SQL (with several values) to insert all the txs, receips and logs
 	log.Debugf("[AddL2Block] adding L2 block %d", l2Block.NumberU64())
 	start := time.Now()
@@ -212,14 +212,15 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 
 			logTxsL2Hash += fmt.Sprintf("tx[%d] txHash: %s, txHashL2: %s\n", idx, tx.Hash().String(), txsL2Hash[idx].String())
 
-			txRow := []interface{}{tx.Hash().String(), encoded, decoded, l2Block.Number().Uint64(), txsEGPData[idx].EffectivePercentage, egpLogBytes}
+			from, to := txAddresses(txsSenders[idx], tx.To())
+			txRow := []interface{}{tx.Hash().String(), encoded, decoded, l2Block.Number().Uint64(), txsEGPData[idx].EffectivePercentage, egpLogBytes, from, to, tx.Nonce()}
 			if forkId >= state.FORKID_ETROG {
 				txRow = append(txRow, txsL2Hash[idx].String())
 			}
 			txRows = append(txRows, txRow)
 		}
 
-		txFields := []string{"hash", "encoded", "decoded", "l2_block_num", "effective_percentage", "egp_log"}
+		txFields := []string{"hash", "encoded", "decoded", "l2_block_num", "effective_percentage", "egp_log", "from_address", "to_address", "nonce"}
 		if forkId >= state.FORKID_ETROG {
 			txFields = append(txFields, "l2_hash")
 		}
@@ -236,6 +237,10 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 
 		var logs []*types.Log
 		for _, receipt := range receipts {
+			for _, l := range receipt.Logs {
+				// the block number is stored with the log to index it by range
+				l.BlockNumber = l2Block.NumberU64()
+			}
 			logs = append(logs, receipt.Logs...)
 		}
 		p.AddLogs(ctx, logs, dbTx)
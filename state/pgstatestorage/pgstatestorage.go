@@ -3,6 +3,8 @@ package pgstatestorage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/state"
@@ -122,12 +124,11 @@ func (p *PostgresStorage) GetStateRootByBatchNumber(ctx context.Context, batchNu
 // GetLogsByBlockNumber get all the logs from a specific block ordered by log index
 func (p *PostgresStorage) GetLogsByBlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Log, error) {
 	const query = `
-      SELECT t.l2_block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+      SELECT l.block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
         FROM state.log l
-       INNER JOIN state.transaction t ON t.hash = l.tx_hash
-       INNER JOIN state.l2block b ON b.block_num = t.l2_block_num
-       INNER JOIN state.receipt r ON r.tx_hash = t.hash
-       WHERE b.block_num = $1
+       INNER JOIN state.l2block b ON b.block_num = l.block_num
+       INNER JOIN state.receipt r ON r.tx_hash = l.tx_hash
+       WHERE l.block_num = $1
        ORDER BY l.log_index ASC`
 
 	q := p.getExecQuerier(dbTx)
@@ -139,78 +140,30 @@ func (p *PostgresStorage) GetLogsByBlockNumber(ctx context.Context, blockNumber
 	return scanLogs(rows)
 }
 
-// GetLogs returns the logs that match the filter
+// GetLogs returns the logs that match the filter.
+//
+// The logs are indexed by block number alone and combined with the address
+// and each topic, so only the filters that are set are added to the query
+// to let the planner pick those indexes instead of scanning the whole range.
 func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, blockHash *common.Hash, since *time.Time, dbTx pgx.Tx) ([]*types.Log, error) {
-	// query parts
-	const queryCount = `SELECT count(*) `
-	const querySelect = `SELECT t.l2_block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3 `
-
-	const queryBody = `FROM state.log l
-       INNER JOIN state.transaction t ON t.hash = l.tx_hash
-       INNER JOIN state.l2block b ON b.block_num = t.l2_block_num
-       INNER JOIN state.receipt r ON r.tx_hash = t.hash
-       WHERE (l.address = any($1) OR $1 IS NULL)
-         AND (l.topic0 = any($2) OR $2 IS NULL)
-         AND (l.topic1 = any($3) OR $3 IS NULL)
-         AND (l.topic2 = any($4) OR $4 IS NULL)
-         AND (l.topic3 = any($5) OR $5 IS NULL)
-         AND (b.created_at >= $6 OR $6 IS NULL) `
-
-	const queryFilterByBlockHash = `AND b.block_hash = $7 `
-	const queryFilterByBlockNumbers = `AND b.block_num BETWEEN $7 AND $8 `
-
-	const queryOrder = `ORDER BY b.block_num ASC, l.log_index ASC`
-
-	// count queries
-	const queryToCountLogsByBlockHash = "" +
-		queryCount +
-		queryBody +
-		queryFilterByBlockHash
-	const queryToCountLogsByBlockNumbers = "" +
-		queryCount +
-		queryBody +
-		queryFilterByBlockNumbers
-
-	// select queries
-	const queryToSelectLogsByBlockHash = "" +
-		querySelect +
-		queryBody +
-		queryFilterByBlockHash +
-		queryOrder
-	const queryToSelectLogsByBlockNumbers = "" +
-		querySelect +
-		queryBody +
-		queryFilterByBlockNumbers +
-		queryOrder
-
-	args := []interface{}{}
+	const querySelect = `SELECT l.block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+        FROM state.log l
+       INNER JOIN state.l2block b ON b.block_num = l.block_num
+       INNER JOIN state.receipt r ON r.tx_hash = l.tx_hash
+       WHERE `
 
-	// address filter
-	if len(addresses) > 0 {
-		args = append(args, p.addressesToHex(addresses))
-	} else {
-		args = append(args, nil)
-	}
+	const queryOrder = ` ORDER BY l.block_num ASC, l.log_index ASC`
 
-	// topic filters
-	for i := 0; i < maxTopics; i++ {
-		if len(topics) > i && len(topics[i]) > 0 {
-			args = append(args, p.hashesToHex(topics[i]))
-		} else {
-			args = append(args, nil)
-		}
+	args := []interface{}{}
+	conditions := []string{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
 	}
 
-	// since filter
-	args = append(args, since)
-
 	// block filter
-	var queryToCount string
-	var queryToSelect string
 	if blockHash != nil {
-		args = append(args, blockHash.String())
-		queryToCount = queryToCountLogsByBlockHash
-		queryToSelect = queryToSelectLogsByBlockHash
+		addCondition("b.block_hash = $%d", blockHash.String())
 	} else {
 		if toBlock < fromBlock {
 			return nil, state.ErrInvalidBlockRange
@@ -221,29 +174,50 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 			return nil, state.ErrMaxLogsBlockRangeLimitExceeded
 		}
 
-		args = append(args, fromBlock, toBlock)
-		queryToCount = queryToCountLogsByBlockNumbers
-		queryToSelect = queryToSelectLogsByBlockNumbers
+		addCondition("l.block_num >= $%d", fromBlock)
+		addCondition("l.block_num <= $%d", toBlock)
 	}
 
-	q := p.getExecQuerier(dbTx)
-	if p.cfg.MaxLogsCount > 0 {
-		var count uint64
-		err := q.QueryRow(ctx, queryToCount, args...).Scan(&count)
-		if err != nil {
-			return nil, err
-		}
+	// address filter
+	if len(addresses) > 0 {
+		addCondition("l.address = any($%d)", p.addressesToHex(addresses))
+	}
 
-		if count > p.cfg.MaxLogsCount {
-			return nil, state.ErrMaxLogsCountLimitExceeded
+	// topic filters
+	for i := 0; i < maxTopics; i++ {
+		if len(topics) > i && len(topics[i]) > 0 {
+			addCondition(fmt.Sprintf("l.topic%d = any($%%d)", i), p.hashesToHex(topics[i]))
 		}
 	}
 
-	rows, err := q.Query(ctx, queryToSelect, args...)
+	// since filter
+	if since != nil {
+		addCondition("b.created_at >= $%d", *since)
+	}
+
+	query := querySelect + strings.Join(conditions, " AND ") + queryOrder
+
+	// instead of counting all the matching logs, one more log than allowed
+	// is requested to know if the limit was exceeded
+	if p.cfg.MaxLogsCount > 0 {
+		query += fmt.Sprintf(" LIMIT %d", p.cfg.MaxLogsCount+1)
+	}
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	return scanLogs(rows)
+	logs, err := scanLogs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.cfg.MaxLogsCount > 0 && uint64(len(logs)) > p.cfg.MaxLogsCount {
+		return nil, state.ErrMaxLogsCountLimitExceeded
+	}
+
+	return logs, nil
 }
 
 func (p *PostgresStorage) addressesToHex(addresses []common.Address) []string {
@@ -2,6 +2,7 @@ package pgstatestorage_test
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"math"
 	"math/big"
@@ -24,6 +25,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/test/testutils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/assert"
@@ -197,12 +199,13 @@ func TestGetBatchByL2BlockNumber(t *testing.T) {
 	numTxs := len(transactions)
 	storeTxsEGPData := make([]state.StoreTxEGPData, numTxs)
 	txsL2Hash := make([]common.Hash, numTxs)
+	txsSenders := make([]common.Address, numTxs)
 	for i := range transactions {
 		storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
 		txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i))
 	}
 
-	err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx)
+	err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 	require.NoError(t, err)
 	result, err := pgStateStorage.BatchNumberByL2BlockNumber(ctx, l2Block.Number().Uint64(), dbTx)
 	require.NoError(t, err)
@@ -719,12 +722,13 @@ func TestGetLastVerifiedL2BlockNumberUntilL1Block(t *testing.T) {
 		numTxs := len(l2Block.Transactions())
 		storeTxsEGPData := make([]state.StoreTxEGPData, numTxs)
 		txsL2Hash := make([]common.Hash, numTxs)
+		txsSenders := make([]common.Address, numTxs)
 		for i := range l2Block.Transactions() {
 			storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: uint8(0)}
 			txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i))
 		}
 
-		err = testState.AddL2Block(ctx, batchNumber, l2Block, []*types.Receipt{}, txsL2Hash, storeTxsEGPData, dbTx)
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, []*types.Receipt{}, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 		require.NoError(t, err)
 
 		virtualBatch := state.VirtualBatch{BlockNumber: blockNumber, BatchNumber: batchNumber, Coinbase: addr, SequencerAddr: addr, TxHash: hash}
@@ -943,12 +947,13 @@ func TestGetLogs(t *testing.T) {
 		numTxs := len(transactions)
 		storeTxsEGPData := make([]state.StoreTxEGPData, numTxs)
 		txsL2Hash := make([]common.Hash, numTxs)
+		txsSenders := make([]common.Address, numTxs)
 		for i := range transactions {
 			storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
 			txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i))
 		}
 
-		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx)
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 		require.NoError(t, err)
 	}
 
@@ -1002,6 +1007,105 @@ func TestGetLogs(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetTransactionReceiptsByL2BlockNumber(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	err = testState.AddBlock(ctx, block, dbTx)
+	require.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1, FALSE)", batchNumber)
+	require.NoError(t, err)
+
+	transactions := []*types.Transaction{}
+	receipts := []*types.Receipt{}
+	logIndex := uint(0)
+	for i := 0; i < 3; i++ {
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       nil,
+			Value:    new(big.Int),
+			Gas:      0,
+			GasPrice: big.NewInt(0),
+		})
+
+		// the second tx has no logs
+		logs := []*types.Log{}
+		for j := 0; i != 1 && j < 2; j++ {
+			logs = append(logs, &types.Log{TxHash: tx.Hash(), Index: logIndex, Topics: []common.Hash{common.HexToHash(fmt.Sprintf("0x%d", logIndex))}})
+			logIndex++
+		}
+
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: uint64(i),
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       big.NewInt(1),
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  uint(i),
+			Status:            types.ReceiptStatusSuccessful,
+			Logs:              logs,
+		}
+		transactions = append(transactions, tx)
+		receipts = append(receipts, receipt)
+	}
+
+	header := state.NewL2Header(&types.Header{
+		Number:     big.NewInt(1),
+		ParentHash: state.ZeroHash,
+		Coinbase:   state.ZeroAddress,
+		Root:       state.ZeroHash,
+		GasUsed:    1,
+		GasLimit:   10,
+		Time:       uint64(time.Now().Unix()),
+	})
+	l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, trie.NewStackTrie(nil))
+	for _, receipt := range receipts {
+		receipt.BlockHash = l2Block.Hash()
+	}
+
+	storeTxsEGPData := make([]state.StoreTxEGPData, len(transactions))
+	txsL2Hash := make([]common.Hash, len(transactions))
+	txsSenders := make([]common.Address, len(transactions))
+	for i := range transactions {
+		storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
+		txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i+1))
+	}
+	err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
+	require.NoError(t, err)
+
+	blockReceipts, err := testState.GetTransactionReceiptsByL2BlockNumber(ctx, l2Block.NumberU64(), dbTx)
+	require.NoError(t, err)
+	require.Len(t, blockReceipts, len(receipts))
+	for i, receipt := range blockReceipts {
+		expected, err := testState.GetTransactionReceipt(ctx, transactions[i].Hash(), dbTx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, receipt)
+	}
+	assert.Len(t, blockReceipts[0].Logs, 2)
+	assert.Len(t, blockReceipts[1].Logs, 0)
+	assert.Len(t, blockReceipts[2].Logs, 2)
+
+	l2Hashes, err := testState.GetL2TxHashesByL2BlockNumber(ctx, l2Block.NumberU64(), dbTx)
+	require.NoError(t, err)
+	require.Len(t, l2Hashes, len(transactions))
+	for i, tx := range transactions {
+		assert.Equal(t, txsL2Hash[i], l2Hashes[tx.Hash()])
+	}
+
+	// a block without txs has no receipts
+	blockReceipts, err = testState.GetTransactionReceiptsByL2BlockNumber(ctx, l2Block.NumberU64()+1, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, blockReceipts)
+}
+
 func TestGetNativeBlockHashesInRange(t *testing.T) {
 	initOrResetDB()
 
@@ -1074,12 +1178,13 @@ func TestGetNativeBlockHashesInRange(t *testing.T) {
 		numTxs := len(transactions)
 		storeTxsEGPData := make([]state.StoreTxEGPData, numTxs)
 		txsL2Hash := make([]common.Hash, numTxs)
+		txsSenders := make([]common.Address, numTxs)
 		for i := range transactions {
 			storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
 			txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i))
 		}
 
-		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx)
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 		require.NoError(t, err)
 
 		nativeBlockHashes = append(nativeBlockHashes, l2Block.Header().Root)
@@ -1434,3 +1539,113 @@ func TestGetLastGER(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, common.HexToHash("0x2").String(), ger.String())
 }
+
+func TestGetTxsByAddress(t *testing.T) {
+	initOrResetDB()
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	err = testState.AddBlock(ctx, block, dbTx)
+	require.NoError(t, err)
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1,FALSE)", batchNumber)
+	require.NoError(t, err)
+
+	keyA, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyB, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addressA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addressB := crypto.PubkeyToAddress(keyB.PublicKey)
+	contractAddress := common.HexToAddress("0x1234")
+
+	// block 1: A sends to B, block 2: A deploys a contract, block 3: B sends to A
+	signer := types.NewEIP155Signer(big.NewInt(int64(stateCfg.ChainID)))
+	txs := []*types.Transaction{}
+	for _, txData := range []struct {
+		key   *ecdsa.PrivateKey
+		nonce uint64
+		to    *common.Address
+	}{{keyA, 0, &addressB}, {keyA, 1, nil}, {keyB, 0, &addressA}} {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: txData.nonce, To: txData.to, Value: new(big.Int), GasPrice: new(big.Int)}), signer, txData.key)
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	senders := []common.Address{addressA, addressA, addressB}
+	for i, tx := range txs {
+		blockNumber := big.NewInt(int64(i + 1))
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			PostState:         state.ZeroHash.Bytes(),
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       blockNumber,
+			TxHash:            tx.Hash(),
+			Status:            types.ReceiptStatusSuccessful,
+		}
+		if tx.To() == nil {
+			receipt.ContractAddress = contractAddress
+		}
+		header := state.NewL2Header(&types.Header{Number: blockNumber, GasLimit: 10})
+		l2Block := state.NewL2Block(header, []*types.Transaction{tx}, []*state.L2Header{}, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		receipt.BlockHash = l2Block.Hash()
+
+		storeTxsEGPData := []state.StoreTxEGPData{{EffectivePercentage: state.MaxEffectivePercentage}}
+		err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, []*types.Receipt{receipt}, []common.Hash{tx.Hash()}, []common.Address{senders[i]}, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	hashes, err := pgStateStorage.GetTxsHashesByAddressBefore(ctx, addressA, 4, 10, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{txs[2].Hash(), txs[1].Hash(), txs[0].Hash()}, hashes)
+
+	hashes, err = pgStateStorage.GetTxsHashesByAddressBefore(ctx, addressA, 3, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{txs[1].Hash()}, hashes)
+
+	hashes, err = pgStateStorage.GetTxsHashesByAddressAfter(ctx, addressB, 0, 10, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{txs[0].Hash(), txs[2].Hash()}, hashes)
+
+	hashes, err = pgStateStorage.GetTxsHashesByAddressAfter(ctx, contractAddress, 2, 10, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, hashes)
+
+	hash, err := pgStateStorage.GetTxHashBySenderAndNonce(ctx, addressA, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txs[1].Hash(), hash)
+	_, err = pgStateStorage.GetTxHashBySenderAndNonce(ctx, addressB, 1, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+
+	hash, err = pgStateStorage.GetContractCreationTxHash(ctx, contractAddress, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txs[1].Hash(), hash)
+
+	// the txs stored before the address columns were added are indexed by the backfill
+	_, err = dbTx.Exec(ctx, "UPDATE state.transaction SET from_address = NULL, to_address = NULL, nonce = NULL")
+	require.NoError(t, err)
+	locked, err := pgStateStorage.TryLockTxsAddressesBackfill(ctx, dbTx)
+	require.NoError(t, err)
+	require.True(t, locked)
+	pending, err := pgStateStorage.GetTxsWithoutAddresses(ctx, 10, dbTx)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	for txHash, encoded := range pending {
+		tx, err := state.DecodeTx(encoded)
+		require.NoError(t, err)
+		require.Equal(t, txHash, tx.Hash())
+		sender, err := state.GetSender(*tx)
+		require.NoError(t, err)
+		nonce := tx.Nonce()
+		require.NoError(t, pgStateStorage.UpdateTxAddresses(ctx, txHash, sender, tx.To(), &nonce, dbTx))
+	}
+	pending, err = pgStateStorage.GetTxsWithoutAddresses(ctx, 10, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	hash, err = pgStateStorage.GetTxHashBySenderAndNonce(ctx, addressB, 0, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, txs[2].Hash(), hash)
+}
@@ -189,6 +189,106 @@ func (p *PostgresStorage) DeleteUngeneratedProofs(ctx context.Context, dbTx pgx.
 	return err
 }
 
+// GetGeneratedProofByBatchNumber returns the widest proof stored for a range
+// of batches that includes the provided batch number, it's generating when
+// its GeneratingSince is set.
+func (p *PostgresStorage) GetGeneratedProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Proof, error) {
+	const getGeneratedProofByBatchNumberSQL = `
+		SELECT 
+			p.batch_num, 
+			p.batch_num_final,
+			p.proof,
+			p.proof_id,
+			p.input_prover,
+			p.prover,
+			p.prover_id,
+			p.generating_since,
+			p.created_at,
+			p.updated_at
+		FROM state.proof p
+		WHERE p.batch_num <= $1 AND p.batch_num_final >= $1
+		ORDER BY p.batch_num_final - p.batch_num DESC
+		LIMIT 1
+		`
+
+	var proof *state.Proof = &state.Proof{}
+	var proofData, inputProver *string
+
+	e := p.getExecQuerier(dbTx)
+	row := e.QueryRow(ctx, getGeneratedProofByBatchNumberSQL, batchNumber)
+	err := row.Scan(&proof.BatchNumber, &proof.BatchNumberFinal, &proofData, &proof.ProofID, &inputProver, &proof.Prover, &proof.ProverID, &proof.GeneratingSince, &proof.CreatedAt, &proof.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the proof and its input are not set while it's generating
+	if proofData != nil {
+		proof.Proof = *proofData
+	}
+	if inputProver != nil {
+		proof.InputProver = *inputProver
+	}
+
+	return proof, nil
+}
+
+// AddFinalProof stores the final proof sent to L1 for a range of batches,
+// replacing the one sent before for the same range if any.
+func (p *PostgresStorage) AddFinalProof(ctx context.Context, proof *state.FinalProof, dbTx pgx.Tx) error {
+	const addFinalProofSQL = `
+		INSERT INTO state.final_proof (batch_num, batch_num_final, proof, proof_id, status, created_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (batch_num, batch_num_final) DO UPDATE SET proof = EXCLUDED.proof, proof_id = EXCLUDED.proof_id, status = EXCLUDED.status, created_at = EXCLUDED.created_at`
+	e := p.getExecQuerier(dbTx)
+	now := time.Now().UTC().Round(time.Microsecond)
+	_, err := e.Exec(ctx, addFinalProofSQL, proof.BatchNumber, proof.BatchNumberFinal, proof.Proof, proof.ProofID, proof.Status, now)
+	return err
+}
+
+// UpdateFinalProofStatus updates the status of the final proof sent to L1 for the range of batches
+func (p *PostgresStorage) UpdateFinalProofStatus(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, status state.FinalProofStatus, dbTx pgx.Tx) error {
+	const updateFinalProofStatusSQL = "UPDATE state.final_proof SET status = $3 WHERE batch_num = $1 AND batch_num_final = $2"
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, updateFinalProofStatusSQL, batchNumber, batchNumberFinal, status)
+	return err
+}
+
+// GetFinalProof returns the final proof sent to L1 for the range of batches
+func (p *PostgresStorage) GetFinalProof(ctx context.Context, batchNumber uint64, batchNumberFinal uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	const getFinalProofSQL = `
+		SELECT batch_num, batch_num_final, proof, proof_id, status, created_at
+		  FROM state.final_proof
+		 WHERE batch_num = $1 AND batch_num_final = $2`
+
+	return p.scanFinalProof(p.getExecQuerier(dbTx).QueryRow(ctx, getFinalProofSQL, batchNumber, batchNumberFinal))
+}
+
+// GetFinalProofByBatchNumber returns the last final proof sent to L1 for a
+// range of batches that includes the provided batch number
+func (p *PostgresStorage) GetFinalProofByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.FinalProof, error) {
+	const getFinalProofByBatchNumberSQL = `
+		SELECT batch_num, batch_num_final, proof, proof_id, status, created_at
+		  FROM state.final_proof
+		 WHERE batch_num <= $1 AND batch_num_final >= $1
+		 ORDER BY created_at DESC
+		 LIMIT 1`
+
+	return p.scanFinalProof(p.getExecQuerier(dbTx).QueryRow(ctx, getFinalProofByBatchNumberSQL, batchNumber))
+}
+
+func (p *PostgresStorage) scanFinalProof(row pgx.Row) (*state.FinalProof, error) {
+	var proof state.FinalProof
+	err := row.Scan(&proof.BatchNumber, &proof.BatchNumberFinal, &proof.Proof, &proof.ProofID, &proof.Status, &proof.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
 func toPostgresInterval(duration string) (string, error) {
 	unit := duration[len(duration)-1]
 	var pgUnit string
@@ -0,0 +1,65 @@
+package pgstatestorage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// stateEventsChannel is the postgres channel notified by the batch lifecycle triggers
+	stateEventsChannel = "state_event"
+	// stateEventsBufferSize is the size of the channels returned by SubscribeStateEvents
+	stateEventsBufferSize = 1000
+)
+
+// SubscribeStateEvents returns a channel receiving an event each time a batch is closed, virtualized
+// or verified, a global exit root is added or the fork id changes, as notified by postgres. The events
+// are dropped if the channel is full. The channel is closed when the context is done or the listening
+// connection is lost
+func (p *PostgresStorage) SubscribeStateEvents(ctx context.Context) (<-chan state.StateEvent, error) {
+	poolConn, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the connection keeps listening until it's closed, so it's not returned to the pool
+	conn := poolConn.Hijack()
+	if _, err := conn.Exec(ctx, "LISTEN "+stateEventsChannel); err != nil {
+		_ = conn.Close(context.Background())
+		return nil, err
+	}
+
+	events := make(chan state.StateEvent, stateEventsBufferSize)
+	go func() {
+		defer close(events)
+		defer func(conn *pgx.Conn) {
+			_ = conn.Close(context.Background())
+		}(conn)
+
+		for {
+			notification, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Errorf("error waiting for state event notifications: %v", err)
+				}
+				return
+			}
+
+			var event state.StateEvent
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				log.Errorf("error decoding state event notification %s: %v", notification.Payload, err)
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+				log.Warnf("state event dropped, channel is full: %s %d", event.Type, event.BatchNumber)
+			}
+		}
+	}()
+
+	return events, nil
+}
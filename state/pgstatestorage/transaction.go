@@ -311,6 +311,130 @@ func (p *PostgresStorage) GetTransactionReceipt(ctx context.Context, transaction
 	return &receipt, nil
 }
 
+// GetTransactionReceiptsByL2BlockNumber gets the receipts of all the transactions of the
+// provided L2 block, sorted by tx index. The receipts and their logs are loaded with a
+// fixed number of queries regardless of the number of transactions in the block
+func (p *PostgresStorage) GetTransactionReceiptsByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) ([]*types.Receipt, error) {
+	const getReceiptsSQL = `
+		SELECT
+			r.tx_index,
+			r.tx_hash,
+			r.type,
+			r.post_state,
+			r.status,
+			r.cumulative_gas_used,
+			r.gas_used,
+			r.contract_address,
+			r.effective_gas_price,
+			b.block_hash
+		  FROM state.receipt r
+		 INNER JOIN state.transaction t
+			ON t.hash = r.tx_hash
+		 INNER JOIN state.l2block b
+			ON b.block_num = t.l2_block_num
+		 WHERE t.l2_block_num = $1
+		 ORDER BY r.tx_index ASC`
+
+	const getLogsSQL = `
+		SELECT t.l2_block_num, b.block_hash, l.tx_hash, r.tx_index, l.log_index, l.address, l.data, l.topic0, l.topic1, l.topic2, l.topic3
+		  FROM state.log l
+		 INNER JOIN state.transaction t ON t.hash = l.tx_hash
+		 INNER JOIN state.l2block b ON b.block_num = t.l2_block_num
+		 INNER JOIN state.receipt r ON r.tx_hash = t.hash
+		 WHERE t.l2_block_num = $1
+		 ORDER BY l.log_index ASC`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getReceiptsSQL, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]*types.Receipt, 0, len(rows.RawValues()))
+	receiptsByTxHash := make(map[common.Hash]*types.Receipt)
+	for rows.Next() {
+		var txHash, contractAddress, l2BlockHash string
+		var effectiveGasPrice *uint64
+		receipt := types.Receipt{}
+		err := rows.Scan(&receipt.TransactionIndex,
+			&txHash,
+			&receipt.Type,
+			&receipt.PostState,
+			&receipt.Status,
+			&receipt.CumulativeGasUsed,
+			&receipt.GasUsed,
+			&contractAddress,
+			&effectiveGasPrice,
+			&l2BlockHash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt.TxHash = common.HexToHash(txHash)
+		receipt.ContractAddress = common.HexToAddress(contractAddress)
+		receipt.BlockNumber = big.NewInt(0).SetUint64(blockNumber)
+		receipt.BlockHash = common.HexToHash(l2BlockHash)
+		if effectiveGasPrice != nil {
+			receipt.EffectiveGasPrice = big.NewInt(0).SetUint64(*effectiveGasPrice)
+		}
+		receipt.Logs = []*types.Log{}
+		receipts = append(receipts, &receipt)
+		receiptsByTxHash[receipt.TxHash] = &receipt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(receipts) == 0 {
+		return receipts, nil
+	}
+
+	logRows, err := q.Query(ctx, getLogsSQL, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := scanLogs(logRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		if receipt, found := receiptsByTxHash[log.TxHash]; found {
+			receipt.Logs = append(receipt.Logs, log)
+		}
+	}
+
+	for _, receipt := range receipts {
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	}
+
+	return receipts, nil
+}
+
+// GetL2TxHashesByL2BlockNumber gets the L2 hashes of the transactions of the provided L2 block
+// indexed by the transaction hash. The transactions without L2 hash are not included
+func (p *PostgresStorage) GetL2TxHashesByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (map[common.Hash]common.Hash, error) {
+	const getL2TxHashesSQL = "SELECT hash, l2_hash FROM state.transaction WHERE l2_block_num = $1 AND l2_hash IS NOT NULL"
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getL2TxHashesSQL, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	l2Hashes := make(map[common.Hash]common.Hash)
+	for rows.Next() {
+		var hash, l2Hash string
+		if err := rows.Scan(&hash, &l2Hash); err != nil {
+			return nil, err
+		}
+		l2Hashes[common.HexToHash(hash)] = common.HexToHash(l2Hash)
+	}
+	return l2Hashes, rows.Err()
+}
+
 // GetTransactionByL2BlockHashAndIndex gets a transaction accordingly to the block hash and transaction index provided.
 // since we only have a single transaction per l2 block, any index different from 0 will return a not found result
 func (p *PostgresStorage) GetTransactionByL2BlockHashAndIndex(ctx context.Context, blockHash common.Hash, index uint64, dbTx pgx.Tx) (*types.Transaction, error) {
@@ -558,8 +682,8 @@ func (p *PostgresStorage) AddReceipts(ctx context.Context, receipts []*types.Rec
 
 // AddLog adds a new log to the State Store
 func (p *PostgresStorage) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx) error {
-	const addLogSQL = `INSERT INTO state.log (tx_hash, log_index, address, data, topic0, topic1, topic2, topic3)
-	                                  VALUES (     $1,        $2,      $3,   $4,     $5,     $6,     $7,     $8)`
+	const addLogSQL = `INSERT INTO state.log (tx_hash, log_index, address, data, topic0, topic1, topic2, topic3, block_num)
+	                                  VALUES (     $1,        $2,      $3,   $4,     $5,     $6,     $7,     $8,        $9)`
 
 	var topicsAsHex [maxTopics]*string
 	for i := 0; i < len(l.Topics); i++ {
@@ -570,7 +694,7 @@ func (p *PostgresStorage) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx)
 	e := p.getExecQuerier(dbTx)
 	_, err := e.Exec(ctx, addLogSQL,
 		l.TxHash.String(), l.Index, l.Address.String(), hex.EncodeToHex(l.Data),
-		topicsAsHex[0], topicsAsHex[1], topicsAsHex[2], topicsAsHex[3])
+		topicsAsHex[0], topicsAsHex[1], topicsAsHex[2], topicsAsHex[3], l.BlockNumber)
 	return err
 }
 
@@ -588,12 +712,12 @@ func (p *PostgresStorage) AddLogs(ctx context.Context, logs []*types.Log, dbTx p
 			topicHex := log.Topics[i].String()
 			topicsAsHex[i] = &topicHex
 		}
-		logRow := []interface{}{log.TxHash.String(), log.Index, log.Address.String(), hex.EncodeToHex(log.Data), topicsAsHex[0], topicsAsHex[1], topicsAsHex[2], topicsAsHex[3]}
+		logRow := []interface{}{log.TxHash.String(), log.Index, log.Address.String(), hex.EncodeToHex(log.Data), topicsAsHex[0], topicsAsHex[1], topicsAsHex[2], topicsAsHex[3], log.BlockNumber}
 		logsRows = append(logsRows, logRow)
 	}
 
 	_, err := dbTx.CopyFrom(ctx, pgx.Identifier{"state", "log"},
-		[]string{"tx_hash", "log_index", "address", "data", "topic0", "topic1", "topic2", "topic3"},
+		[]string{"tx_hash", "log_index", "address", "data", "topic0", "topic1", "topic2", "topic3", "block_num"},
 		pgx.CopyFromRows(logsRows))
 
 	return err
@@ -645,3 +769,171 @@ func (p *PostgresStorage) GetL2TxHashByTxHash(ctx context.Context, hash common.H
 	l2Hash := common.HexToHash(*l2HashHex)
 	return &l2Hash, nil
 }
+
+// txAddresses returns the sender and the receiver of a tx in the format they are
+// stored in the address columns of the transaction table, the receiver is nil for
+// the contract creations
+func txAddresses(from common.Address, to *common.Address) (string, *string) {
+	if to == nil {
+		return from.String(), nil
+	}
+	toHex := to.String()
+	return from.String(), &toHex
+}
+
+// GetTxsHashesByAddressBefore gets the hashes of the txs sent by, sent to or creating the provided
+// address in the L2 blocks before the provided block number, sorted from the newest to the oldest.
+// The txs of the oldest block of the page are all returned, even if that exceeds the limit
+func (p *PostgresStorage) GetTxsHashesByAddressBefore(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	const getTxsHashesByAddressBeforeSQL = `
+		WITH page AS (
+			SELECT t.l2_block_num
+			  FROM state.transaction t
+			 INNER JOIN state.receipt r
+			    ON r.tx_hash = t.hash
+			 WHERE (t.from_address = $1 OR t.to_address = $1 OR r.contract_address = $1)
+			   AND t.l2_block_num < $2
+			 ORDER BY t.l2_block_num DESC, r.tx_index DESC
+			 LIMIT $3)
+		SELECT t.hash
+		  FROM state.transaction t
+		 INNER JOIN state.receipt r
+		    ON r.tx_hash = t.hash
+		 WHERE (t.from_address = $1 OR t.to_address = $1 OR r.contract_address = $1)
+		   AND t.l2_block_num < $2
+		   AND t.l2_block_num >= (SELECT MIN(l2_block_num) FROM page)
+		 ORDER BY t.l2_block_num DESC, r.tx_index DESC`
+
+	return p.getTxsHashes(ctx, getTxsHashesByAddressBeforeSQL, dbTx, address.String(), blockNumber, limit)
+}
+
+// GetTxsHashesByAddressAfter gets the hashes of the txs sent by, sent to or creating the provided
+// address in the L2 blocks after the provided block number, sorted from the oldest to the newest.
+// The txs of the newest block of the page are all returned, even if that exceeds the limit
+func (p *PostgresStorage) GetTxsHashesByAddressAfter(ctx context.Context, address common.Address, blockNumber uint64, limit uint64, dbTx pgx.Tx) ([]common.Hash, error) {
+	const getTxsHashesByAddressAfterSQL = `
+		WITH page AS (
+			SELECT t.l2_block_num
+			  FROM state.transaction t
+			 INNER JOIN state.receipt r
+			    ON r.tx_hash = t.hash
+			 WHERE (t.from_address = $1 OR t.to_address = $1 OR r.contract_address = $1)
+			   AND t.l2_block_num > $2
+			 ORDER BY t.l2_block_num ASC, r.tx_index ASC
+			 LIMIT $3)
+		SELECT t.hash
+		  FROM state.transaction t
+		 INNER JOIN state.receipt r
+		    ON r.tx_hash = t.hash
+		 WHERE (t.from_address = $1 OR t.to_address = $1 OR r.contract_address = $1)
+		   AND t.l2_block_num > $2
+		   AND t.l2_block_num <= (SELECT MAX(l2_block_num) FROM page)
+		 ORDER BY t.l2_block_num ASC, r.tx_index ASC`
+
+	return p.getTxsHashes(ctx, getTxsHashesByAddressAfterSQL, dbTx, address.String(), blockNumber, limit)
+}
+
+func (p *PostgresStorage) getTxsHashes(ctx context.Context, sql string, dbTx pgx.Tx, args ...interface{}) ([]common.Hash, error) {
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make([]common.Hash, 0)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, common.HexToHash(hash))
+	}
+
+	return hashes, rows.Err()
+}
+
+// GetTxHashBySenderAndNonce gets the hash of the tx sent by the provided address with the provided nonce
+func (p *PostgresStorage) GetTxHashBySenderAndNonce(ctx context.Context, sender common.Address, nonce uint64, dbTx pgx.Tx) (common.Hash, error) {
+	const getTxHashBySenderAndNonceSQL = "SELECT hash FROM state.transaction WHERE from_address = $1 AND nonce = $2"
+
+	var hash string
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getTxHashBySenderAndNonceSQL, sender.String(), nonce).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.Hash{}, state.ErrNotFound
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.HexToHash(hash), nil
+}
+
+// GetContractCreationTxHash gets the hash of the tx that deployed the provided contract address
+func (p *PostgresStorage) GetContractCreationTxHash(ctx context.Context, address common.Address, dbTx pgx.Tx) (common.Hash, error) {
+	const getContractCreationTxHashSQL = "SELECT tx_hash FROM state.receipt WHERE contract_address = $1 LIMIT 1"
+
+	var hash string
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getContractCreationTxHashSQL, address.String()).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.Hash{}, state.ErrNotFound
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.HexToHash(hash), nil
+}
+
+// txsAddressesBackfillLockID identifies the advisory lock taken by the instance
+// backfilling the address columns of the transaction table
+const txsAddressesBackfillLockID = 0x7478616464727331
+
+// TryLockTxsAddressesBackfill tries to take the lock that allows a single instance to
+// backfill the address columns of the transaction table, the lock is held until the
+// provided db tx ends. It returns false if another instance holds it
+func (p *PostgresStorage) TryLockTxsAddressesBackfill(ctx context.Context, dbTx pgx.Tx) (bool, error) {
+	const tryLockTxsAddressesBackfillSQL = "SELECT pg_try_advisory_xact_lock($1)"
+
+	var locked bool
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, tryLockTxsAddressesBackfillSQL, txsAddressesBackfillLockID).Scan(&locked)
+	return locked, err
+}
+
+// GetTxsWithoutAddresses gets the encoded form of up to limit txs whose address columns
+// were not filled yet, indexed by tx hash. They are the txs stored before the address
+// columns were added. The rows are locked until the provided db tx ends and the rows
+// locked by other db txs are skipped
+func (p *PostgresStorage) GetTxsWithoutAddresses(ctx context.Context, limit uint64, dbTx pgx.Tx) (map[common.Hash]string, error) {
+	const getTxsWithoutAddressesSQL = "SELECT hash, encoded FROM state.transaction WHERE from_address IS NULL LIMIT $1 FOR UPDATE SKIP LOCKED"
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getTxsWithoutAddressesSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make(map[common.Hash]string)
+	for rows.Next() {
+		var hash, encoded string
+		if err := rows.Scan(&hash, &encoded); err != nil {
+			return nil, err
+		}
+		txs[common.HexToHash(hash)] = encoded
+	}
+
+	return txs, rows.Err()
+}
+
+// UpdateTxAddresses fills the address columns of the tx with the provided hash, the
+// receiver is nil for the contract creations and the nonce is nil if it's unknown
+func (p *PostgresStorage) UpdateTxAddresses(ctx context.Context, txHash common.Hash, from common.Address, to *common.Address, nonce *uint64, dbTx pgx.Tx) error {
+	const updateTxAddressesSQL = "UPDATE state.transaction SET from_address = $2, to_address = $3, nonce = $4 WHERE hash = $1"
+
+	fromHex, toHex := txAddresses(from, to)
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, updateTxAddressesSQL, txHash.String(), fromHex, toHex, nonce)
+	return err
+}
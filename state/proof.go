@@ -20,3 +20,23 @@ type Proof struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
+
+// FinalProofStatus is the status of a final proof sent to L1
+type FinalProofStatus string
+
+const (
+	// FinalProofStatusSent means the final proof was sent to L1 and its verification is not confirmed yet
+	FinalProofStatusSent FinalProofStatus = "sent"
+	// FinalProofStatusVerified means the verification of the final proof was confirmed on L1
+	FinalProofStatusVerified FinalProofStatus = "verified"
+)
+
+// FinalProof is the final proof of a range of batches sent to L1 to verify them
+type FinalProof struct {
+	BatchNumber      uint64
+	BatchNumberFinal uint64
+	Proof            string
+	ProofID          *string
+	Status           FinalProofStatus
+	CreatedAt        time.Time
+}
@@ -36,9 +36,12 @@ type State struct {
 	tree           *merkletree.StateTree
 	eventLog       *event.EventLog
 	l1InfoTree     *l1infotree.L1InfoTree
+	// l1InfoTreeLevels keeps the last tree the merkle proofs were computed for
+	l1InfoTreeLevels l1InfoTreeLevelsCache
 
 	newL2BlockEvents        chan NewL2BlockEvent
 	newL2BlockEventHandlers []NewL2BlockEventHandler
+	stateEventHandlers      []StateEventHandler
 }
 
 // NewState creates a new State
@@ -56,6 +59,7 @@ func NewState(cfg Config, storage storage, executorClient executor.ExecutorServi
 		eventLog:                eventLog,
 		newL2BlockEvents:        make(chan NewL2BlockEvent, newL2BlockEventBufferSize),
 		newL2BlockEventHandlers: []NewL2BlockEventHandler{},
+		stateEventHandlers:      []StateEventHandler{},
 		l1InfoTree:              mt,
 	}
 
@@ -107,6 +111,14 @@ func (s *State) GetStorageAt(ctx context.Context, address common.Address, positi
 	return s.tree.GetStorageAt(ctx, address, position, root.Bytes())
 }
 
+// GetAccountProof returns the state tree proofs of the account and the provided storage slots
+func (s *State) GetAccountProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root common.Hash) (*merkletree.AccountProof, error) {
+	if s.tree == nil {
+		return nil, ErrStateTreeNil
+	}
+	return s.tree.GetAccountProof(ctx, address, storageKeys, root.Bytes())
+}
+
 // GetLastStateRoot returns the latest state root
 func (s *State) GetLastStateRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error) {
 	lastBlockHeader, err := s.GetLastL2BlockHeader(ctx, dbTx)
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateEventType is the type of the changes of the batch lifecycle notified by the state
+type StateEventType string
+
+const (
+	// StateEventNewBatch is notified when a trusted batch is closed
+	StateEventNewBatch StateEventType = "newBatch"
+	// StateEventVirtualizedBatch is notified when a batch sequence is synchronized from L1
+	StateEventVirtualizedBatch StateEventType = "virtualizedBatch"
+	// StateEventVerifiedBatch is notified when a batch verification is synchronized from L1
+	StateEventVerifiedBatch StateEventType = "verifiedBatch"
+	// StateEventNewGlobalExitRoot is notified when a global exit root is synchronized from L1
+	StateEventNewGlobalExitRoot StateEventType = "newGlobalExitRoot"
+	// StateEventForkIDChanged is notified when a new fork id is synchronized from L1
+	StateEventForkIDChanged StateEventType = "forkIdChanged"
+)
+
+// StateEvent is emitted by the state storage when the sequencer or the synchronizer persist
+// a change of the batch lifecycle, only the fields related to the event type are set
+type StateEvent struct {
+	Type StateEventType `json:"type"`
+
+	// BatchNumber is the batch closed, virtualized or verified, or the first batch of a fork id
+	BatchNumber uint64 `json:"batchNumber"`
+	// TxHash is the L1 tx that virtualized or verified the batch
+	TxHash common.Hash `json:"txHash"`
+	// BlockNumber is the L1 block of the synchronized data
+	BlockNumber    uint64         `json:"blockNumber"`
+	StateRoot      common.Hash    `json:"stateRoot"`
+	LocalExitRoot  common.Hash    `json:"localExitRoot"`
+	GlobalExitRoot common.Hash    `json:"globalExitRoot"`
+	AccInputHash   common.Hash    `json:"accInputHash"`
+	Coinbase       common.Address `json:"coinbase"`
+	Timestamp      time.Time      `json:"timestamp"`
+
+	MainnetExitRoot common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
+	L1InfoTreeIndex *uint32     `json:"l1InfoTreeIndex"`
+
+	ForkID  uint64 `json:"forkId"`
+	Version string `json:"version"`
+}
+
+// StateEventHandler represent a func that will be called by the
+// state when a StateEvent is notified
+type StateEventHandler func(e StateEvent)
+
+// StartToMonitorStateEvents starts a go routine that listens to the changes of the
+// batch lifecycle notified by the database and executes the handlers registered to
+// be executed for each of them. The changes are notified once committed, so they
+// are received even if they are persisted by another instance, like the synchronizer
+func (s *State) StartToMonitorStateEvents() {
+	go InfiniteSafeRun(s.monitorStateEvents, "fail to monitor state events: %v:", time.Second)
+}
+
+// RegisterStateEventHandler add the provided handler to the list of handlers
+// that will be triggered when a state event is notified
+func (s *State) RegisterStateEventHandler(h StateEventHandler) {
+	log.Info("state event handler registered")
+	s.stateEventHandlers = append(s.stateEventHandlers, h)
+}
+
+func (s *State) monitorStateEvents() {
+	stateEvents, err := s.SubscribeStateEvents(context.Background())
+	if err != nil {
+		log.Errorf("failed to subscribe to state events: %v", err)
+		return
+	}
+
+	for event := range stateEvents {
+		log.Debugf("[monitorStateEvents] %s event detected for batch %d", event.Type, event.BatchNumber)
+		// the handlers are executed sequentially to keep the events in order
+		for _, handler := range s.stateEventHandlers {
+			SafeRun(func() { handler(event) }, "failed and recovered in StateEventHandler: %v")
+		}
+	}
+	log.Warn("state events subscription closed, subscribing again")
+}
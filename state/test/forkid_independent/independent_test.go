@@ -654,12 +654,13 @@ func TestAddGetL2Block(t *testing.T) {
 	numTxs := len(transactions)
 	storeTxsEGPData := make([]state.StoreTxEGPData, numTxs)
 	txsL2Hash := make([]common.Hash, numTxs)
+	txsSenders := make([]common.Address, numTxs)
 	for i := range transactions {
 		storeTxsEGPData[i] = state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}
 		txsL2Hash[i] = common.HexToHash(fmt.Sprintf("0x%d", i))
 	}
 
-	err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx)
+	err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx)
 	require.NoError(t, err)
 	result, err := testState.GetL2BlockByHash(ctx, l2Block.Hash(), dbTx)
 	require.NoError(t, err)
@@ -82,32 +82,8 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	var response *ProcessTransactionResponse
 	var startTime, endTime time.Time
 	if forkId < FORKID_ETROG {
-		traceConfigRequest := &executor.TraceConfig{
-			TxHashToGenerateFullTrace: transactionHash.Bytes(),
-			// set the defaults to the maximum information we can have.
-			// this is needed to process custom tracers later
-			DisableStorage:   cFalse,
-			DisableStack:     cFalse,
-			EnableMemory:     cTrue,
-			EnableReturnData: cTrue,
-		}
-
-		// if the default tracer is used, then we review the information
-		// we want to have in the trace related to the parameters we received.
-		if traceConfig.IsDefaultTracer() {
-			if traceConfig.DisableStorage {
-				traceConfigRequest.DisableStorage = cTrue
-			}
-			if traceConfig.DisableStack {
-				traceConfigRequest.DisableStack = cTrue
-			}
-			if !traceConfig.EnableMemory {
-				traceConfigRequest.EnableMemory = cFalse
-			}
-			if !traceConfig.EnableReturnData {
-				traceConfigRequest.EnableReturnData = cFalse
-			}
-		}
+		traceConfigRequest := traceConfig.toExecutorV1(transactionHash)
+
 		// generate batch l2 data for the transaction
 		batchL2Data, err := EncodeTransactions(txsToEncode, effectivePercentage, forkId)
 		if err != nil {
@@ -161,32 +137,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		}
 		response = convertedResponse.BlockResponses[0].TransactionResponses[0]
 	} else {
-		traceConfigRequestV2 := &executor.TraceConfigV2{
-			TxHashToGenerateFullTrace: transactionHash.Bytes(),
-			// set the defaults to the maximum information we can have.
-			// this is needed to process custom tracers later
-			DisableStorage:   cFalse,
-			DisableStack:     cFalse,
-			EnableMemory:     cTrue,
-			EnableReturnData: cTrue,
-		}
-
-		// if the default tracer is used, then we review the information
-		// we want to have in the trace related to the parameters we received.
-		if traceConfig.IsDefaultTracer() {
-			if traceConfig.DisableStorage {
-				traceConfigRequestV2.DisableStorage = cTrue
-			}
-			if traceConfig.DisableStack {
-				traceConfigRequestV2.DisableStack = cTrue
-			}
-			if !traceConfig.EnableMemory {
-				traceConfigRequestV2.EnableMemory = cFalse
-			}
-			if !traceConfig.EnableReturnData {
-				traceConfigRequestV2.EnableReturnData = cFalse
-			}
-		}
+		traceConfigRequestV2 := traceConfig.toExecutorV2(transactionHash)
 
 		// build the raw batch so we can get the index l1 info tree for the l2 block
 		rawBatch, err := DecodeBatchV2(batch.BatchL2Data)
@@ -300,6 +251,79 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("tx hash not found in executor response")
 	}
 
+	senderAddress, err := GetSender(*tx)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerContext := &tracers.Context{
+		BlockHash:   receipt.BlockHash,
+		BlockNumber: receipt.BlockNumber,
+		TxIndex:     int(receipt.TransactionIndex),
+		TxHash:      transactionHash,
+	}
+
+	return s.buildExecutionResult(response, tx, senderAddress, oldStateRoot, batch.StateRoot, *receipt, tracerContext, traceConfig, nil, endTime.Sub(startTime))
+}
+
+// DebugCall executes the given unsigned tx on top of the state of the given L2 block
+// to generate its trace. The state and block overrides, if any, are applied only to
+// this execution. The tracers reading the state, like the prestateTracer, read it
+// from the L2 block with the same overrides applied
+func (s *State) DebugCall(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, traceConfig TraceConfig, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	if err := stateOverride.Validate(); err != nil {
+		return nil, err
+	}
+	if err := blockOverride.Validate(); err != nil {
+		return nil, err
+	}
+
+	var l2Block *L2Block
+	var err error
+	if l2BlockNumber == nil {
+		l2Block, err = s.GetLastL2Block(ctx, dbTx)
+	} else {
+		l2Block, err = s.GetL2BlockByNumber(ctx, *l2BlockNumber, dbTx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blockNumber := l2Block.NumberU64()
+	startTime := time.Now()
+	processBatchResponse, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, &blockNumber, true, &traceConfig, stateOverride, blockOverride, dbTx)
+	endTime := time.Now()
+	if err != nil {
+		return nil, err
+	}
+	response := processBatchResponse.BlockResponses[0].TransactionResponses[0]
+
+	// the unsigned tx is not stored, so the receipt needed by the
+	// default tracer is built from the execution response
+	receipt := types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      response.TxHash,
+		GasUsed:     response.GasUsed,
+		BlockHash:   l2Block.Hash(),
+		BlockNumber: l2Block.Number(),
+	}
+	if response.RomError != nil {
+		receipt.Status = types.ReceiptStatusFailed
+	}
+
+	tracerContext := &tracers.Context{
+		BlockHash:   l2Block.Hash(),
+		BlockNumber: l2Block.Number(),
+		TxIndex:     0,
+		TxHash:      response.TxHash,
+	}
+
+	return s.buildExecutionResult(response, tx, senderAddress, l2Block.Root(), l2Block.Root(), receipt, tracerContext, traceConfig, stateOverride.withBlockNumber(blockOverride), endTime.Sub(startTime))
+}
+
+// buildExecutionResult builds the trace of an executed tx using the tracer of the trace config.
+// The tracers reading the state read it from stateRoot with the state overrides applied
+func (s *State) buildExecutionResult(response *ProcessTransactionResponse, tx *types.Transaction, senderAddress common.Address, oldStateRoot, stateRoot common.Hash, receipt types.Receipt, tracerContext *tracers.Context, traceConfig TraceConfig, stateOverride StateOverride, elapsed time.Duration) (*runtime.ExecutionResult, error) {
 	result := &runtime.ExecutionResult{
 		CreateAddress: response.CreateAddress,
 		GasLeft:       response.GasLeft,
@@ -310,11 +334,6 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		Err:           response.RomError,
 	}
 
-	senderAddress, err := GetSender(*tx)
-	if err != nil {
-		return nil, err
-	}
-
 	context := instrumentation.Context{
 		From:         senderAddress.String(),
 		Input:        tx.Data(),
@@ -323,7 +342,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		Output:       result.ReturnValue,
 		GasPrice:     tx.GasPrice().String(),
 		OldStateRoot: oldStateRoot,
-		Time:         uint64(endTime.Sub(startTime)),
+		Time:         uint64(elapsed),
 		GasUsed:      result.GasUsed,
 	}
 
@@ -346,13 +365,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 
 	// select and prepare tracer
 	var tracer tracers.Tracer
-	tracerContext := &tracers.Context{
-		BlockHash:   receipt.BlockHash,
-		BlockNumber: receipt.BlockNumber,
-		TxIndex:     int(receipt.TransactionIndex),
-		TxHash:      transactionHash,
-	}
-
+	var err error
 	if traceConfig.IsDefaultTracer() {
 		structLoggerCfg := structlogger.Config{
 			EnableMemory:     traceConfig.EnableMemory,
@@ -361,7 +374,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			EnableReturnData: traceConfig.EnableReturnData,
 		}
 		tracer := structlogger.NewStructLogger(structLoggerCfg)
-		traceResult, err := tracer.ParseTrace(result, *receipt)
+		traceResult, err := tracer.ParseTrace(result, receipt)
 		if err != nil {
 			return nil, err
 		}
@@ -379,6 +392,12 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create callTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create callTracer, err: %v", err)
 		}
+	} else if traceConfig.IsFlatCallTracer() {
+		tracer, err = native.NewFlatCallTracer(tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create flatCallTracer, err: %v", err)
+			return nil, fmt.Errorf("failed to create flatCallTracer, err: %v", err)
+		}
 	} else if traceConfig.IsNoopTracer() {
 		tracer, err = native.NewNoopTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -401,7 +420,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return nil, fmt.Errorf("invalid tracer: %v, err: %v", traceConfig.Tracer, err)
 	}
 
-	fakeDB := &FakeDB{State: s, stateRoot: batch.StateRoot.Bytes()}
+	fakeDB := &FakeDB{State: s, stateRoot: stateRoot.Bytes(), overrides: stateOverride}
 	evm := fakevm.NewFakeEVM(fakevm.BlockContext{BlockNumber: big.NewInt(1)}, fakevm.TxContext{GasPrice: gasPrice}, fakeDB, params.TestChainConfig, fakevm.Config{Debug: true, Tracer: tracer})
 
 	traceResult, err := s.buildTrace(evm, result, tracer)
@@ -415,6 +434,51 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	return result, nil
 }
 
+// toExecutorV1 converts the trace config into the executor request format
+// before ETROG to generate the full trace of the given tx
+func (t *TraceConfig) toExecutorV1(txHash common.Hash) *executor.TraceConfig {
+	traceConfig := &executor.TraceConfig{
+		TxHashToGenerateFullTrace: txHash.Bytes(),
+		// set the defaults to the maximum information we can have.
+		// this is needed to process custom tracers later
+		DisableStorage:   cFalse,
+		DisableStack:     cFalse,
+		EnableMemory:     cTrue,
+		EnableReturnData: cTrue,
+	}
+
+	// if the default tracer is used, then we review the information
+	// we want to have in the trace related to the parameters we received.
+	if t.IsDefaultTracer() {
+		if t.DisableStorage {
+			traceConfig.DisableStorage = cTrue
+		}
+		if t.DisableStack {
+			traceConfig.DisableStack = cTrue
+		}
+		if !t.EnableMemory {
+			traceConfig.EnableMemory = cFalse
+		}
+		if !t.EnableReturnData {
+			traceConfig.EnableReturnData = cFalse
+		}
+	}
+	return traceConfig
+}
+
+// toExecutorV2 converts the trace config into the executor request format
+// after ETROG to generate the full trace of the given tx
+func (t *TraceConfig) toExecutorV2(txHash common.Hash) *executor.TraceConfigV2 {
+	traceConfig := t.toExecutorV1(txHash)
+	return &executor.TraceConfigV2{
+		TxHashToGenerateFullTrace: traceConfig.TxHashToGenerateFullTrace,
+		DisableStorage:            traceConfig.DisableStorage,
+		DisableStack:              traceConfig.DisableStack,
+		EnableMemory:              traceConfig.EnableMemory,
+		EnableReturnData:          traceConfig.EnableReturnData,
+	}
+}
+
 // ParseTheTraceUsingTheTracer parses the given trace with the given tracer.
 func (s *State) buildTrace(evm *fakevm.FakeEVM, result *runtime.ExecutionResult, tracer tracers.Tracer) (json.RawMessage, error) {
 	trace := result.FullTrace
@@ -32,6 +32,26 @@ func GetSender(tx types.Transaction) (common.Address, error) {
 	return sender, nil
 }
 
+// txSender returns the sender of the processed tx, recovering it from the signature
+// only when the caller has not provided it
+func txSender(processedTx *ProcessTransactionResponse) common.Address {
+	if processedTx.From != ZeroAddress {
+		return processedTx.From
+	}
+	return indexedSender(processedTx.Tx)
+}
+
+// indexedSender recovers the sender of the tx to index it, it is the zero address if
+// it can't be recovered
+func indexedSender(tx types.Transaction) common.Address {
+	sender, err := GetSender(tx)
+	if err != nil {
+		log.Warnf("failed to get the sender of tx %v to index it: %v", tx.Hash().String(), err)
+		return ZeroAddress
+	}
+	return sender
+}
+
 // RlpFieldsToLegacyTx parses the rlp fields slice into a type.LegacyTx
 // in this specific order:
 //
@@ -185,9 +205,10 @@ func (s *State) StoreTransactions(ctx context.Context, batchNumber uint64, proce
 				storeTxsEGPData[0].EGPLog = txsEGPLog[i]
 			}
 			txsL2Hash := []common.Hash{processedTx.TxHashL2_V2}
+			txsSenders := []common.Address{txSender(processedTx)}
 
 			// Store L2 block and its transaction
-			if err := s.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx); err != nil {
+			if err := s.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx); err != nil {
 				return err
 			}
 		}
@@ -234,6 +255,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 	storeTxsEGPData := make([]StoreTxEGPData, 0, numTxs)
 	receipts := make([]*types.Receipt, 0, numTxs)
 	txsL2Hash := make([]common.Hash, 0, numTxs)
+	txsSenders := make([]common.Address, 0, numTxs)
 
 	for i, txResponse := range l2Block.TransactionResponses {
 		// if the transaction has an intrinsic invalid tx error it means
@@ -248,6 +270,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 		txResp := *txResponse
 		transactions = append(transactions, &txResp.Tx)
 		txsL2Hash = append(txsL2Hash, txResp.TxHashL2_V2)
+		txsSenders = append(txsSenders, txSender(&txResp))
 
 		storeTxEGPData := StoreTxEGPData{EGPLog: nil, EffectivePercentage: uint8(txResponse.EffectivePercentage)}
 		if txsEGPLog != nil {
@@ -270,7 +293,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 	}
 
 	// Store L2 block and its transactions
-	if err := s.AddL2Block(ctx, batchNumber, block, receipts, txsL2Hash, storeTxsEGPData, dbTx); err != nil {
+	if err := s.AddL2Block(ctx, batchNumber, block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx); err != nil {
 		return err
 	}
 
@@ -281,7 +304,7 @@ func (s *State) StoreL2Block(ctx context.Context, batchNumber uint64, l2Block *P
 
 // PreProcessUnsignedTransaction processes the unsigned transaction in order to calculate its zkCounters
 func (s *State) PreProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, sender common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, l2BlockNumber, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, l2BlockNumber, false, nil, nil, nil, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -296,7 +319,7 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 		return nil, err
 	}
 
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, nil, nil, nil, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -305,9 +328,17 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 }
 
 // ProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+// The state and block overrides, if any, are applied only to this execution.
+func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	if err := stateOverride.Validate(); err != nil {
+		return nil, err
+	}
+	if err := blockOverride.Validate(); err != nil {
+		return nil, err
+	}
+
 	result := new(runtime.ExecutionResult)
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, nil, stateOverride, blockOverride, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -329,7 +360,7 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 }
 
 // internalProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, traceConfig *TraceConfig, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var l2Block *L2Block
 	var err error
 	if l2BlockNumber == nil {
@@ -348,15 +379,15 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 
 	forkID := s.GetForkIDByBatchNumber(batch.BatchNumber)
 	if forkID < FORKID_ETROG {
-		return s.internalProcessUnsignedTransactionV1(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, dbTx)
+		return s.internalProcessUnsignedTransactionV1(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, traceConfig, stateOverride, blockOverride, dbTx)
 	} else {
-		return s.internalProcessUnsignedTransactionV2(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, dbTx)
+		return s.internalProcessUnsignedTransactionV2(ctx, tx, senderAddress, *batch, *l2Block, forkID, noZKEVMCounters, traceConfig, stateOverride, blockOverride, dbTx)
 	}
 }
 
 // internalProcessUnsignedTransactionV1 processes the given unsigned transaction.
 // pre ETROG
-func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, traceConfig *TraceConfig, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var attempts = 1
 
 	if s.executorClient == nil {
@@ -375,12 +406,13 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 	if l2Block.NumberU64() == latestL2BlockNumber {
 		timestamp = uint64(time.Now().Unix())
 	}
+	timestamp = blockOverride.timestampV1(timestamp)
 
 	loadedNonce, err := s.tree.GetNonce(ctx, senderAddress, l2Block.Root().Bytes())
 	if err != nil {
 		return nil, err
 	}
-	nonce := loadedNonce.Uint64()
+	nonce := stateOverride.nonce(senderAddress, loadedNonce.Uint64())
 
 	batchL2Data, err := EncodeUnsignedTransaction(*tx, s.cfg.ChainID, &nonce, forkID)
 	if err != nil {
@@ -400,6 +432,7 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 		ChainId:          s.cfg.ChainID,
 		UpdateMerkleTree: cFalse,
 		ContextId:        uuid.NewString(),
+		StateOverride:    stateOverride.withBlockNumber(blockOverride).toExecutorV1(),
 
 		// v1 fields
 		GlobalExitRoot: l2Block.GlobalExitRoot().Bytes(),
@@ -408,6 +441,13 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 	if noZKEVMCounters {
 		processBatchRequestV1.NoCounters = cTrue
 	}
+	if traceConfig != nil {
+		txHash, err := unsignedTxHash(batchL2Data, forkID)
+		if err != nil {
+			return nil, err
+		}
+		processBatchRequestV1.TraceConfig = traceConfig.toExecutorV1(txHash)
+	}
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.From]: %v", processBatchRequestV1.From)
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.OldBatchNum]: %v", processBatchRequestV1.OldBatchNum)
 	log.Debugf("internalProcessUnsignedTransactionV1[processBatchRequestV1.OldStateRoot]: %v", hex.EncodeToHex(processBatchRequestV1.OldStateRoot))
@@ -484,7 +524,7 @@ func (s *State) internalProcessUnsignedTransactionV1(ctx context.Context, tx *ty
 
 // internalProcessUnsignedTransactionV2 processes the given unsigned transaction.
 // post ETROG
-func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
+func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *types.Transaction, senderAddress common.Address, batch Batch, l2Block L2Block, forkID uint64, noZKEVMCounters bool, traceConfig *TraceConfig, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	var attempts = 1
 
 	if s.executorClient == nil {
@@ -498,9 +538,12 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 	if err != nil {
 		return nil, err
 	}
-	nonce := loadedNonce.Uint64()
+	nonce := stateOverride.nonce(senderAddress, loadedNonce.Uint64())
 
-	deltaTimestamp := uint32(uint64(time.Now().Unix()) - l2Block.Time())
+	deltaTimestamp, timestampLimit, err := blockOverride.deltaTimestampV2(&l2Block, uint64(time.Now().Unix()))
+	if err != nil {
+		return nil, err
+	}
 	transactions := s.BuildChangeL2Block(deltaTimestamp, uint32(0))
 
 	batchL2Data, err := EncodeUnsignedTransaction(*tx, s.cfg.ChainID, &nonce, forkID)
@@ -523,10 +566,11 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 		ChainId:          s.cfg.ChainID,
 		UpdateMerkleTree: cFalse,
 		ContextId:        uuid.NewString(),
+		StateOverride:    stateOverride.withBlockNumber(blockOverride).toExecutorV2(),
 
 		// v2 fields
 		L1InfoRoot:             l2Block.BlockInfoRoot().Bytes(),
-		TimestampLimit:         uint64(time.Now().Unix()),
+		TimestampLimit:         timestampLimit,
 		SkipFirstChangeL2Block: cFalse,
 		SkipWriteBlockInfoRoot: cTrue,
 		ExecutionMode:          executor.ExecutionMode0,
@@ -534,6 +578,13 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 	if noZKEVMCounters {
 		processBatchRequestV2.NoCounters = cTrue
 	}
+	if traceConfig != nil {
+		txHash, err := unsignedTxHash(batchL2Data, forkID)
+		if err != nil {
+			return nil, err
+		}
+		processBatchRequestV2.TraceConfig = traceConfig.toExecutorV2(txHash)
+	}
 
 	log.Debugf("internalProcessUnsignedTransactionV2[processBatchRequestV2.From]: %v", processBatchRequestV2.From)
 	log.Debugf("internalProcessUnsignedTransactionV2[processBatchRequestV2.OldBatchNum]: %v", processBatchRequestV2.OldBatchNum)
@@ -622,6 +673,70 @@ func (s *State) internalProcessUnsignedTransactionV2(ctx context.Context, tx *ty
 	return response, nil
 }
 
+// unsignedTxHash returns the hash the executor computes for an encoded unsigned transaction
+func unsignedTxHash(batchL2Data []byte, forkID uint64) (common.Hash, error) {
+	txs, _, _, err := DecodeTxs(batchL2Data, forkID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(txs) != 1 {
+		return common.Hash{}, fmt.Errorf("unexpected number of encoded unsigned transactions: %d", len(txs))
+	}
+	return txs[0].Hash(), nil
+}
+
+// BackfillTxsAddresses fills the sender, receiver and nonce columns of the txs stored
+// before these columns were added, so they can be searched by address. It returns
+// once all the txs are indexed or when another instance is already indexing them.
+// The txs that can't be decoded are logged and indexed with the zero address as sender
+func (s *State) BackfillTxsAddresses(ctx context.Context) error {
+	const batchSize = 1000
+	for {
+		dbTx, err := s.BeginStateTransaction(ctx)
+		if err != nil {
+			return err
+		}
+
+		locked, err := s.TryLockTxsAddressesBackfill(ctx, dbTx)
+		if err != nil {
+			_ = dbTx.Rollback(ctx)
+			return err
+		}
+		if !locked {
+			log.Infof("the addresses of the txs are being indexed by another instance")
+			return dbTx.Rollback(ctx)
+		}
+
+		txs, err := s.GetTxsWithoutAddresses(ctx, batchSize, dbTx)
+		if err != nil {
+			_ = dbTx.Rollback(ctx)
+			return err
+		}
+		if len(txs) == 0 {
+			return dbTx.Rollback(ctx)
+		}
+
+		for txHash, encoded := range txs {
+			from, to, nonce := ZeroAddress, (*common.Address)(nil), (*uint64)(nil)
+			tx, err := DecodeTx(encoded)
+			if err != nil {
+				log.Warnf("failed to decode tx %v to index its addresses, skipping it: %v", txHash.String(), err)
+			} else {
+				txNonce := tx.Nonce()
+				from, to, nonce = indexedSender(*tx), tx.To(), &txNonce
+			}
+			if err := s.UpdateTxAddresses(ctx, txHash, from, to, nonce, dbTx); err != nil {
+				_ = dbTx.Rollback(ctx)
+				return err
+			}
+		}
+		if err := dbTx.Commit(ctx); err != nil {
+			return err
+		}
+		log.Infof("addresses of %d txs indexed", len(txs))
+	}
+}
+
 // isContractCreation checks if the tx is a contract creation
 func (s *State) isContractCreation(tx *types.Transaction) bool {
 	return tx.To() == nil && len(tx.Data()) > 0
@@ -669,9 +784,10 @@ func (s *State) StoreTransaction(ctx context.Context, batchNumber uint64, proces
 
 	storeTxsEGPData := []StoreTxEGPData{{EGPLog: egpLog, EffectivePercentage: uint8(processedTx.EffectivePercentage)}}
 	txsL2Hash := []common.Hash{processedTx.TxHashL2_V2}
+	txsSenders := []common.Address{txSender(processedTx)}
 
 	// Store L2 block and its transaction
-	if err := s.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, storeTxsEGPData, dbTx); err != nil {
+	if err := s.AddL2Block(ctx, batchNumber, l2Block, receipts, txsL2Hash, txsSenders, storeTxsEGPData, dbTx); err != nil {
 		return nil, err
 	}
 
@@ -693,12 +809,20 @@ func CheckSupersetBatchTransactions(existingTxHashes []common.Hash, processedTxs
 	return nil
 }
 
-// EstimateGas for a transaction
-func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
+// EstimateGas for a transaction. The state and block overrides, if any, are
+// applied to all the executions done to estimate the gas.
+func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride StateOverride, blockOverride *BlockOverride, dbTx pgx.Tx) (uint64, []byte, error) {
 	const ethTransferGas = 21000
 
 	ctx := context.Background()
 
+	if err := stateOverride.Validate(); err != nil {
+		return 0, nil, err
+	}
+	if err := blockOverride.Validate(); err != nil {
+		return 0, nil, err
+	}
+
 	var l2Block *L2Block
 	var err error
 	if l2BlockNumber == nil {
@@ -725,7 +849,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 	if err != nil {
 		return 0, nil, err
 	}
-	nonce := loadedNonce.Uint64()
+	nonce := stateOverride.nonce(senderAddress, loadedNonce.Uint64())
 
 	highEnd := MaxTxGasLimit
 
@@ -733,11 +857,14 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 	// of the account afford
 	isGasPriceSet := transaction.GasPrice().BitLen() != 0
 	if isGasPriceSet {
-		senderBalance, err := s.tree.GetBalance(ctx, senderAddress, l2Block.Root().Bytes())
-		if errors.Is(err, ErrNotFound) {
-			senderBalance = big.NewInt(0)
-		} else if err != nil {
-			return 0, nil, err
+		senderBalance, overridden := stateOverride.balance(senderAddress)
+		if !overridden {
+			senderBalance, err = s.tree.GetBalance(ctx, senderAddress, l2Block.Root().Bytes())
+			if errors.Is(err, ErrNotFound) {
+				senderBalance = big.NewInt(0)
+			} else if err != nil {
+				return 0, nil, err
+			}
 		}
 
 		availableBalance := new(big.Int).Set(senderBalance)
@@ -776,7 +903,10 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 	if lowEnd == ethTransferGas && transaction.To() != nil {
 		receiver := *transaction.To()
 		// check if the receiver address is not a smart contract
-		code, err := s.tree.GetCode(ctx, receiver, l2Block.Root().Bytes())
+		code, overridden := stateOverride.code(receiver)
+		if !overridden {
+			code, err = s.tree.GetCode(ctx, receiver, l2Block.Root().Bytes())
+		}
 		if err != nil {
 			log.Warnf("error while getting code for address %v: %v", receiver.String(), err)
 		} else if len(code) == 0 {
@@ -799,9 +929,9 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 	var gasUsed uint64
 	var returnValue []byte
 	if forkID < FORKID_ETROG {
-		failed, reverted, gasUsed, returnValue, err = s.internalTestGasEstimationTransactionV1(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, highEnd, nonce, false)
+		failed, reverted, gasUsed, returnValue, err = s.internalTestGasEstimationTransactionV1(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, highEnd, nonce, stateOverride, blockOverride, false)
 	} else {
-		failed, reverted, gasUsed, returnValue, err = s.internalTestGasEstimationTransactionV2(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, highEnd, nonce, false)
+		failed, reverted, gasUsed, returnValue, err = s.internalTestGasEstimationTransactionV2(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, highEnd, nonce, stateOverride, blockOverride, false)
 	}
 
 	if failed {
@@ -834,9 +964,9 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 
 		log.Debugf("Estimate gas. Trying to execute TX with %v gas", mid)
 		if forkID < FORKID_ETROG {
-			failed, reverted, _, _, err = s.internalTestGasEstimationTransactionV1(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, mid, nonce, true)
+			failed, reverted, _, _, err = s.internalTestGasEstimationTransactionV1(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, mid, nonce, stateOverride, blockOverride, true)
 		} else {
-			failed, reverted, _, _, err = s.internalTestGasEstimationTransactionV2(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, mid, nonce, true)
+			failed, reverted, _, _, err = s.internalTestGasEstimationTransactionV2(ctx, batch, l2Block, latestL2BlockNumber, transaction, forkID, senderAddress, mid, nonce, stateOverride, blockOverride, true)
 		}
 		executionTime := time.Since(txExecutionStart)
 		totalExecutionTime += executionTime
@@ -870,11 +1000,12 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 // before ETROG
 func (s *State) internalTestGasEstimationTransactionV1(ctx context.Context, batch *Batch, l2Block *L2Block, latestL2BlockNumber uint64,
 	transaction *types.Transaction, forkID uint64, senderAddress common.Address,
-	gas uint64, nonce uint64, shouldOmitErr bool) (failed, reverted bool, gasUsed uint64, returnValue []byte, err error) {
+	gas uint64, nonce uint64, stateOverride StateOverride, blockOverride *BlockOverride, shouldOmitErr bool) (failed, reverted bool, gasUsed uint64, returnValue []byte, err error) {
 	timestamp := l2Block.Time()
 	if l2Block.NumberU64() == latestL2BlockNumber {
 		timestamp = uint64(time.Now().Unix())
 	}
+	timestamp = blockOverride.timestampV1(timestamp)
 
 	tx := types.NewTx(&types.LegacyTx{
 		Nonce:    nonce,
@@ -903,6 +1034,7 @@ func (s *State) internalTestGasEstimationTransactionV1(ctx context.Context, batc
 		ChainId:          s.cfg.ChainID,
 		UpdateMerkleTree: cFalse,
 		ContextId:        uuid.NewString(),
+		StateOverride:    stateOverride.withBlockNumber(blockOverride).toExecutorV1(),
 
 		// v1 fields
 		GlobalExitRoot: batch.GlobalExitRoot.Bytes(),
@@ -966,8 +1098,11 @@ func (s *State) internalTestGasEstimationTransactionV1(ctx context.Context, batc
 // after ETROG
 func (s *State) internalTestGasEstimationTransactionV2(ctx context.Context, batch *Batch, l2Block *L2Block, latestL2BlockNumber uint64,
 	transaction *types.Transaction, forkID uint64, senderAddress common.Address,
-	gas uint64, nonce uint64, shouldOmitErr bool) (failed, reverted bool, gasUsed uint64, returnValue []byte, err error) {
-	deltaTimestamp := uint32(uint64(time.Now().Unix()) - l2Block.Time())
+	gas uint64, nonce uint64, stateOverride StateOverride, blockOverride *BlockOverride, shouldOmitErr bool) (failed, reverted bool, gasUsed uint64, returnValue []byte, err error) {
+	deltaTimestamp, timestampLimit, err := blockOverride.deltaTimestampV2(l2Block, uint64(time.Now().Unix()))
+	if err != nil {
+		return false, false, gasUsed, nil, err
+	}
 	transactions := s.BuildChangeL2Block(deltaTimestamp, uint32(0))
 
 	tx := types.NewTx(&types.LegacyTx{
@@ -999,14 +1134,19 @@ func (s *State) internalTestGasEstimationTransactionV2(ctx context.Context, batc
 		ChainId:          s.cfg.ChainID,
 		UpdateMerkleTree: cFalse,
 		ContextId:        uuid.NewString(),
+		StateOverride:    stateOverride.withBlockNumber(blockOverride).toExecutorV2(),
 
 		// v2 fields
 		L1InfoRoot:             l2Block.BlockInfoRoot().Bytes(),
-		TimestampLimit:         uint64(time.Now().Unix()),
+		TimestampLimit:         timestampLimit,
 		SkipFirstChangeL2Block: cTrue,
 		SkipWriteBlockInfoRoot: cTrue,
 		ExecutionMode:          executor.ExecutionMode0,
 	}
+	// the block overrides are applied by the change L2 block
+	if blockOverride != nil {
+		processBatchRequestV2.SkipFirstChangeL2Block = cFalse
+	}
 
 	log.Debugf("EstimateGas[processBatchRequestV2.From]: %v", processBatchRequestV2.From)
 	log.Debugf("EstimateGas[processBatchRequestV2.OldBatchNum]: %v", processBatchRequestV2.OldBatchNum)
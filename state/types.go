@@ -114,6 +114,9 @@ type ProcessTransactionResponse struct {
 	ChangesStateRoot bool
 	// Tx is the whole transaction object
 	Tx types.Transaction
+	// From is the sender of the tx, set by the callers that already know it so that
+	// it doesn't need to be recovered from the signature when storing the tx
+	From common.Address
 	// FullTrace contains the call trace.
 	FullTrace instrumentation.FullTrace
 	// EffectiveGasPrice effective gas price used for the tx
@@ -283,6 +286,11 @@ func (t *TraceConfig) IsCallTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "callTracer"
 }
 
+// IsFlatCallTracer returns true when should use flatCallTracer
+func (t *TraceConfig) IsFlatCallTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "flatCallTracer"
+}
+
 // IsNoopTracer returns true when should use noopTracer
 func (t *TraceConfig) IsNoopTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "noopTracer"